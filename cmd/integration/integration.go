@@ -48,6 +48,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/service"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/wait"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
 	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler"
 	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/factory"
 
@@ -89,6 +90,14 @@ func (fakeKubeletClient) HealthCheck(host string) (health.Status, error) {
 	return health.Healthy, nil
 }
 
+func (fakeKubeletClient) GetNodeConditions(host string) ([]api.NodeCondition, error) {
+	return []api.NodeCondition{}, nil
+}
+
+func (fakeKubeletClient) GetConnectionInfo(host string) (string, uint, http.RoundTripper, error) {
+	return "http", 10250, http.DefaultTransport, nil
+}
+
 type delegateHandler struct {
 	delegate http.Handler
 }
@@ -192,10 +201,10 @@ func startComponents(manifestURL string) (apiServerURL string) {
 	cfg1 := config.NewPodConfig(config.PodConfigNotificationSnapshotAndUpdates)
 	config.NewSourceEtcd(config.EtcdKeyForHost(machineList[0]), etcdClient, cfg1.Channel("etcd"))
 	config.NewSourceURL(manifestURL, 5*time.Second, cfg1.Channel("url"))
-	myKubelet := kubelet.NewIntegrationTestKubelet(machineList[0], testRootDir, &fakeDocker1)
+	myKubelet := kubelet.NewIntegrationTestKubelet(machineList[0], testRootDir, &fakeDocker1, volume.ProbeVolumePlugins())
 	go util.Forever(func() { myKubelet.Run(cfg1.Updates()) }, 0)
 	go util.Forever(func() {
-		kubelet.ListenAndServeKubeletServer(myKubelet, cfg1.Channel("http"), net.ParseIP("127.0.0.1"), 10250, true)
+		kubelet.ListenAndServeKubeletServer(myKubelet, cfg1.Channel("http"), "etcd", net.ParseIP("127.0.0.1"), 10250, nil, nil, true)
 	}, 0)
 
 	// Kubelet (machine)
@@ -203,10 +212,10 @@ func startComponents(manifestURL string) (apiServerURL string) {
 	// have a place they can schedule.
 	cfg2 := config.NewPodConfig(config.PodConfigNotificationSnapshotAndUpdates)
 	config.NewSourceEtcd(config.EtcdKeyForHost(machineList[1]), etcdClient, cfg2.Channel("etcd"))
-	otherKubelet := kubelet.NewIntegrationTestKubelet(machineList[1], testRootDir, &fakeDocker2)
+	otherKubelet := kubelet.NewIntegrationTestKubelet(machineList[1], testRootDir, &fakeDocker2, volume.ProbeVolumePlugins())
 	go util.Forever(func() { otherKubelet.Run(cfg2.Updates()) }, 0)
 	go util.Forever(func() {
-		kubelet.ListenAndServeKubeletServer(otherKubelet, cfg2.Channel("http"), net.ParseIP("127.0.0.1"), 10251, true)
+		kubelet.ListenAndServeKubeletServer(otherKubelet, cfg2.Channel("http"), "etcd", net.ParseIP("127.0.0.1"), 10251, nil, nil, true)
 	}, 0)
 
 	return apiServer.URL
@@ -73,6 +73,7 @@ var (
 	etcdConfigFile          = flag.String("etcd_config", "", "The config file for the etcd client. Mutually exclusive with -etcd_servers.")
 	corsAllowedOriginList   util.StringList
 	allowPrivileged         = flag.Bool("allow_privileged", false, "If true, allow privileged containers.")
+	hostDirPolicyFile       = flag.String("host_dir_policy_file", "", "File with the host directories the pods of each namespace may mount, in json format. If empty, every host directory is allowed.")
 	portalNet               util.IPNet // TODO: make this a list
 	enableLogsSupport       = flag.Bool("enable_logs_support", true, "Enables server endpoint for log collection")
 	kubeletConfig           = client.KubeletConfig{
@@ -122,8 +123,17 @@ func main() {
 		glog.Fatalf("specify either -etcd_servers or -etcd_config")
 	}
 
+	var hostDirPolicy capabilities.HostDirPolicy
+	if *hostDirPolicyFile != "" {
+		policy, err := capabilities.LoadHostDirPolicy(*hostDirPolicyFile)
+		if err != nil {
+			glog.Fatalf("Failed to load the host directory policy: %v", err)
+		}
+		hostDirPolicy = policy
+	}
 	capabilities.Initialize(capabilities.Capabilities{
 		AllowPrivileged: *allowPrivileged,
+		HostDirPolicy:   hostDirPolicy,
 	})
 
 	cloud := cloudprovider.InitCloudProvider(*cloudProvider, *cloudConfigFile)
@@ -21,7 +21,11 @@ limitations under the License.
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
+	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"net"
 	"net/http"
@@ -31,6 +35,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authenticator"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/capabilities"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
@@ -39,9 +45,13 @@ import (
 	_ "github.com/GoogleCloudPlatform/kubernetes/pkg/healthz"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet"
 	kconfig "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/config"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/hooks"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/master/ports"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/version/verflag"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
+	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/auth/authenticator/request/union"
+	x509request "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/auth/authenticator/request/x509"
 	"github.com/coreos/go-etcd/etcd"
 	"github.com/fsouza/go-dockerclient"
 	"github.com/golang/glog"
@@ -66,13 +76,33 @@ var (
 	etcdConfigFile          = flag.String("etcd_config", "", "The config file for the etcd client. Mutually exclusive with -etcd_servers")
 	rootDirectory           = flag.String("root_dir", defaultRootDir, "Directory path for managing kubelet files (volume mounts,etc).")
 	allowPrivileged         = flag.Bool("allow_privileged", false, "If true, allow containers to request privileged mode. [default=false]")
+	hostDirPolicyFile       = flag.String("host_dir_policy_file", "", "File with the host directories the pods of each namespace may mount, in json format. If empty, every host directory is allowed.")
 	registryPullQPS         = flag.Float64("registry_qps", 0.0, "If > 0, limit registry pull QPS to this value.  If 0, unlimited. [default=0.0]")
 	registryBurst           = flag.Int("registry_burst", 10, "Maximum size of a bursty pulls, temporarily allows pulls to burst to this number, while still not exceeding registry_qps.  Only used if --registry_qps > 0")
 	runonce                 = flag.Bool("runonce", false, "If true, exit after spawning pods from local manifests or remote urls. Exclusive with --etcd_servers and --enable-server")
 	enableDebuggingHandlers = flag.Bool("enable_debugging_handlers", true, "Enables server endpoints for log collection and local running of containers and commands")
 	minimumGCAge            = flag.Duration("minimum_container_ttl_duration", 0, "Minimum age for a finished container before it is garbage collected.  Examples: '300ms', '10s' or '2h45m'")
 	maxContainerCount       = flag.Int("maximum_dead_containers_per_container", 5, "Maximum number of old instances of a container to retain per container.  Each container takes up some disk space.  Default: 5.")
+	maxContainerBackOff     = flag.Duration("maximum_container_backoff", 5*time.Minute, "Maximum delay before restarting a crashing container. The delay starts at 10s and doubles on every crash.  Default: 5m.")
+	containerBackOffReset   = flag.Duration("container_backoff_reset", 10*time.Minute, "A container which ran at least this long before exiting is restarted without delay and its back-off is reset.  0 means never reset.  Default: 10m.")
 	authPath                = flag.String("auth_path", "", "Path to .kubernetes_auth file, specifying how to authenticate to API server.")
+	statsHistory            = flag.Duration("stats_history", 5*time.Minute, "How long the aggregated usage of the pods, sampled every 10s, is kept and served at /stats/pod/<namespace>/<name>.  0 means only the current usage is served.  Default: 5m.")
+	hooksConfig             = flag.String("hooks_config", "", "Path to a JSON file listing the node hooks run around the lifecycle of containers.  If empty, the builtin lxcfs, diskquota, sriov and blkio hooks are run.")
+	dockerRoot              = flag.String("docker_root", "/var/lib/docker", "Path to the docker root directory, whose filesystem usage triggers the image garbage collection.")
+	imageGCHighThreshold    = flag.Int("image_gc_high_threshold", 90, "The percent of disk usage of the docker root directory at or above which unused images are garbage collected.  Default: 90.")
+	imageGCLowThreshold     = flag.Int("image_gc_low_threshold", 80, "The percent of disk usage of the docker root directory down to which unused images are garbage collected.  Must be below --image_gc_high_threshold.  Default: 80.")
+	minimumImageTTL         = flag.Duration("minimum_image_ttl_duration", 2*time.Minute, "Minimum time since an image was last used by a container, or pulled, before it is garbage collected.  Default: 2m.")
+	evictionHard            = flag.String("eviction_hard", "", "Comma separated thresholds of memory.available and data.available, below which pods are evicted at once, e.g. 'memory.available<100Mi,data.available<5%'.  If empty, no hard threshold is set.")
+	evictionSoft            = flag.String("eviction_soft", "", "Comma separated thresholds of memory.available and data.available, below which pods are evicted after the grace period of the threshold, e.g. 'memory.available<300Mi'.  If empty, no soft threshold is set.")
+	evictionSoftGracePeriod = flag.String("eviction_soft_grace_period", "", "Comma separated grace periods of the soft eviction thresholds, e.g. 'memory.available=1m30s'.  Every soft threshold requires one.")
+	evictionTransition      = flag.Duration("eviction_pressure_transition_period", 5*time.Minute, "How long the node reports memory or disk pressure after an eviction threshold was last crossed.  Default: 5m.")
+	tlsCertFile             = flag.String("tls_cert_file", "", "File containing x509 Certificate for HTTPS.  (CA cert, if any, concatenated after server cert). If empty, the info server is served over HTTP.")
+	tlsPrivateKeyFile       = flag.String("tls_private_key_file", "", "File containing x509 private key matching --tls_cert_file.")
+	clientCAFile            = flag.String("client_ca_file", "", "If set, any request presenting a client certificate signed by one of the authorities in the client-ca-file is authenticated with an identity corresponding to the CommonName of the client certificate.  Requires --tls_cert_file.")
+	tokenAuthFile           = flag.String("token_auth_file", "", "If set, the file that will be used to secure the info server via token authentication.  Requires --tls_cert_file.")
+	authorizationMode       = flag.String("authorization_mode", "AlwaysAllow", "Selects how to do authorization on the info server when --client_ca_file or --token_auth_file is set: "+strings.Join(apiserver.AuthorizationModeChoices, ","))
+	authorizationPolicyFile = flag.String("authorization_policy_file", "", "File with authorization policy in csv format, used with --authorization_mode=ABAC, on the info server.")
+	containerRelistPeriod   = flag.Duration("container_relist_period", time.Minute, "The kubelet keeps the docker containers in memory, updated from the docker events, and lists them all again at this period in case events were lost.  0 means the containers are listed from docker on every sync.  Default: 1m.")
 	apiServerList           util.StringList
 )
 
@@ -131,6 +161,61 @@ func getApiserverClient() (*client.Client, error) {
 	}
 }
 
+// getTLSOptions returns the options to serve the info server over TLS with, or nil to serve it over HTTP.
+func getTLSOptions() (*kubelet.TLSOptions, error) {
+	if *tlsCertFile == "" {
+		if *clientCAFile != "" {
+			return nil, fmt.Errorf("--client_ca_file requires --tls_cert_file")
+		}
+		return nil, nil
+	}
+	return &kubelet.TLSOptions{
+		Config: &tls.Config{
+			// Client certificates are verified by the authenticator, so that the
+			// clients without one may still authenticate with a token.
+			ClientAuth: tls.RequestClientCert,
+		},
+		CertFile: *tlsCertFile,
+		KeyFile:  *tlsPrivateKeyFile,
+	}, nil
+}
+
+// getKubeletAuth returns the authenticator and authorizer of the info server, or nil if no
+// authentication is configured.
+func getKubeletAuth() (kubelet.AuthInterface, error) {
+	var authenticators []authenticator.Request
+	if *clientCAFile != "" {
+		data, err := ioutil.ReadFile(*clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", *clientCAFile)
+		}
+		authenticators = append(authenticators, x509request.New(x509.VerifyOptions{Roots: roots}))
+	}
+	if *tokenAuthFile != "" {
+		// The bearer tokens must not be sent in clear text.
+		if *tlsCertFile == "" {
+			return nil, fmt.Errorf("--token_auth_file requires --tls_cert_file")
+		}
+		tokenAuthenticator, err := apiserver.NewAuthenticatorFromTokenFile(*tokenAuthFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, tokenAuthenticator)
+	}
+	if len(authenticators) == 0 {
+		return nil, nil
+	}
+	authorizer, err := apiserver.NewAuthorizerFromAuthorizationConfig(*authorizationMode, *authorizationPolicyFile)
+	if err != nil {
+		return nil, err
+	}
+	return kubelet.NewKubeletAuth(union.New(authenticators...), authorizer), nil
+}
+
 func main() {
 	flag.Parse()
 	util.InitLogs()
@@ -153,10 +238,12 @@ func main() {
 	etcd.SetLogger(util.NewLogger("etcd "))
 
 	// Make an API client if possible.
+	var apiClient *client.Client
 	if len(apiServerList) < 1 {
 		glog.Info("No api servers specified.")
 	} else {
-		if apiClient, err := getApiserverClient(); err != nil {
+		var err error
+		if apiClient, err = getApiserverClient(); err != nil {
 			glog.Errorf("Unable to make apiserver client: %v", err)
 		} else {
 			// Send events to APIserver if there is a client.
@@ -168,8 +255,17 @@ func main() {
 	// Log the events locally too.
 	record.StartLogging(glog.Infof)
 
+	var hostDirPolicy capabilities.HostDirPolicy
+	if *hostDirPolicyFile != "" {
+		policy, err := capabilities.LoadHostDirPolicy(*hostDirPolicyFile)
+		if err != nil {
+			glog.Fatalf("Failed to load the host directory policy: %v", err)
+		}
+		hostDirPolicy = policy
+	}
 	capabilities.Initialize(capabilities.Capabilities{
 		AllowPrivileged: *allowPrivileged,
+		HostDirPolicy:   hostDirPolicy,
 	})
 
 	dockerClient, err := docker.NewClient(getDockerEndpoint())
@@ -190,6 +286,15 @@ func main() {
 	// source of all configuration
 	cfg := kconfig.NewPodConfig(kconfig.PodConfigNotificationSnapshotAndUpdates)
 
+	// Run the pods saved before the restart until their sources send them.
+	if !*runonce {
+		checkpoint, err := kubelet.LoadCheckpoint(*rootDirectory)
+		if err != nil {
+			glog.Errorf("Failed to load the checkpoint of the pods: %v", err)
+		}
+		cfg.Restore(checkpoint)
+	}
+
 	// define file config source
 	if *config != "" {
 		kconfig.NewSourceFile(*config, *fileCheckFrequency, cfg.Channel("file"))
@@ -212,7 +317,13 @@ func main() {
 		}
 	}
 
-	if etcdClient != nil {
+	// Prefer the pods bound to the host through the apiserver over reading etcd directly.
+	// Both sources serve the same pods, so they share the source name the containers
+	// are named after and a node switching between them keeps its running containers.
+	if apiClient != nil {
+		glog.Infof("Watching for pods from the apiserver at %v", apiServerList)
+		kconfig.NewSourceApiserver(apiClient, hostname, cfg.Channel("etcd"))
+	} else if etcdClient != nil {
 		glog.Infof("Watching for etcd configs at %v", etcdClient.GetCluster())
 		kconfig.NewSourceEtcd(kconfig.EtcdKeyForHost(hostname), etcdClient, cfg.Channel("etcd"))
 	}
@@ -220,7 +331,12 @@ func main() {
 	// TODO: block until all sources have delivered at least one update to the channel, or break the sync loop
 	// up into "per source" synchronizations
 
-	k := kubelet.NewMainKubelet(
+	evictionThresholds, err := kubelet.ParseEvictionThresholds(*evictionHard, *evictionSoft, *evictionSoftGracePeriod)
+	if err != nil {
+		glog.Fatalf("Invalid eviction thresholds: %v", err)
+	}
+
+	k, err := kubelet.NewMainKubelet(
 		getHostname(),
 		dockerClient,
 		etcdClient,
@@ -230,7 +346,39 @@ func main() {
 		float32(*registryPullQPS),
 		*registryBurst,
 		*minimumGCAge,
-		*maxContainerCount)
+		*maxContainerCount,
+		*maxContainerBackOff,
+		*containerBackOffReset,
+		*statsHistory,
+		*dockerRoot,
+		kubelet.ImageGCPolicy{
+			HighThresholdPercent: *imageGCHighThreshold,
+			LowThresholdPercent:  *imageGCLowThreshold,
+			MinAge:               *minimumImageTTL,
+		},
+		evictionThresholds,
+		*evictionTransition,
+		*containerRelistPeriod,
+		volume.ProbeVolumePlugins())
+	if err != nil {
+		glog.Fatalf("Error creating kubelet: %v", err)
+	}
+
+	// Keep the containers of the pods of the sources which are not ready yet.
+	k.SetSourcesReady(cfg.SeenAllSources)
+
+	if apiClient != nil {
+		// Fetch the secrets of the secret volumes from the apiserver.
+		k.SetKubeClient(apiClient)
+	}
+
+	if *hooksConfig != "" {
+		m, err := hooks.NewManagerFromFile(*hooksConfig, k.BuiltinHooks())
+		if err != nil {
+			glog.Fatalf("Error loading hooks: %v", err)
+		}
+		k.SetHooks(m)
+	}
 
 	k.BirthCry()
 
@@ -240,6 +388,9 @@ func main() {
 			if err != nil {
 				glog.Errorf("Garbage collect failed: %v", err)
 			}
+			if err := k.GarbageCollectImages(); err != nil {
+				glog.Errorf("Image garbage collect failed: %v", err)
+			}
 		}, time.Minute*1)
 	}()
 
@@ -259,7 +410,10 @@ func main() {
 	// TODO: These should probably become more plugin-ish: register a factory func
 	// in each checker's init(), iterate those here.
 	health.AddHealthChecker(health.NewExecHealthChecker(k))
-	health.AddHealthChecker(health.NewHTTPHealthChecker(&http.Client{}))
+	// HTTPS probes target pod IPs, which the serving certificates are not issued for.
+	health.AddHealthChecker(health.NewHTTPHealthChecker(&http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}))
 	health.AddHealthChecker(&health.TCPHealthChecker{})
 
 	// process pods and exit.
@@ -275,8 +429,16 @@ func main() {
 
 	// start the kubelet server
 	if *enableServer {
+		tlsOptions, err := getTLSOptions()
+		if err != nil {
+			glog.Fatalf("Invalid TLS Config: %v", err)
+		}
+		auth, err := getKubeletAuth()
+		if err != nil {
+			glog.Fatalf("Invalid Authentication Config: %v", err)
+		}
 		go util.Forever(func() {
-			kubelet.ListenAndServeKubeletServer(k, cfg.Channel("http"), net.IP(address), *port, *enableDebuggingHandlers)
+			kubelet.ListenAndServeKubeletServer(k, cfg.Channel("http"), "etcd", net.IP(address), *port, tlsOptions, auth, *enableDebuggingHandlers)
 		}, 0)
 	}
 
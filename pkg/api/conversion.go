@@ -30,9 +30,11 @@ func init() {
 		// Convert ContainerManifest to BoundPod
 		func(in *ContainerManifest, out *BoundPod, s conversion.Scope) error {
 			out.Spec.Containers = in.Containers
+			out.Spec.InitContainers = in.InitContainers
 			out.Spec.Volumes = in.Volumes
 			out.Spec.RestartPolicy = in.RestartPolicy
 			out.Spec.NetworkMode = in.NetworkMode
+			out.Spec.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
 			out.Name = in.ID
 			out.UID = in.UUID
 			// TODO(dchen1107): Move this conversion to pkg/api/v1beta[123]/conversion.go
@@ -43,13 +45,21 @@ func init() {
 					ctr.TerminationMessagePath = TerminationMessagePathDefault
 				}
 			}
+			for i := range out.Spec.InitContainers {
+				ctr := &out.Spec.InitContainers[i]
+				if len(ctr.TerminationMessagePath) == 0 {
+					ctr.TerminationMessagePath = TerminationMessagePathDefault
+				}
+			}
 			return nil
 		},
 		func(in *BoundPod, out *ContainerManifest, s conversion.Scope) error {
 			out.Containers = in.Spec.Containers
+			out.InitContainers = in.Spec.InitContainers
 			out.Volumes = in.Spec.Volumes
 			out.RestartPolicy = in.Spec.RestartPolicy
 			out.NetworkMode = in.Spec.NetworkMode
+			out.TerminationGracePeriodSeconds = in.Spec.TerminationGracePeriodSeconds
 			out.Version = "v1beta2"
 			out.ID = in.Name
 			out.UUID = in.UID
@@ -59,6 +69,12 @@ func init() {
 					ctr.TerminationMessagePath = TerminationMessagePathDefault
 				}
 			}
+			for i := range out.InitContainers {
+				ctr := &out.InitContainers[i]
+				if len(ctr.TerminationMessagePath) == 0 {
+					ctr.TerminationMessagePath = TerminationMessagePathDefault
+				}
+			}
 			return nil
 		},
 
@@ -92,6 +108,7 @@ func init() {
 			out.Name = in.Name
 			out.Namespace = in.Namespace
 			out.CreationTimestamp = in.CreationTimestamp
+			out.DeletionTimestamp = in.DeletionTimestamp
 			return nil
 		},
 
@@ -103,10 +120,14 @@ func init() {
 			if err := s.Convert(&in.Containers, &out.Containers, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.InitContainers, &out.InitContainers, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.RestartPolicy, &out.RestartPolicy, 0); err != nil {
 				return err
 			}
 			out.NetworkMode = in.NetworkMode
+			out.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
 			out.Version = "v1beta2"
 			return nil
 		},
@@ -117,10 +138,14 @@ func init() {
 			if err := s.Convert(&in.Containers, &out.Containers, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.InitContainers, &out.InitContainers, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.RestartPolicy, &out.RestartPolicy, 0); err != nil {
 				return err
 			}
 			out.NetworkMode = in.NetworkMode
+			out.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
 			return nil
 		},
 	)
@@ -18,6 +18,7 @@ package api
 
 import (
 	"strings"
+	"time"
 )
 
 // TODO: Address these per #1502
@@ -41,3 +42,54 @@ func IsPullIfNotPresent(p PullPolicy) bool {
 func pullPoliciesEqual(p1, p2 PullPolicy) bool {
 	return strings.ToLower(string(p1)) == strings.ToLower(string(p2))
 }
+
+// IsPodReady returns true if the pod reports the Ready condition.
+func IsPodReady(pod *Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Kind == PodReady {
+			return c.Status == ConditionTrue
+		}
+	}
+	return false
+}
+
+// IsPodBestEffort returns true if no container of the pod sets a memory, cpu or disk limit.
+// Best-effort pods are the first ones evicted when a node is under pressure.
+func IsPodBestEffort(spec *PodSpec) bool {
+	for _, c := range spec.Containers {
+		if c.Memory != 0 || c.CPU != 0 || c.Core != 0 || c.Disk != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNodeUnderPressure returns true if the node reports the given pressure condition.
+func IsNodeUnderPressure(node *Minion, kind NodeConditionKind) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Kind == kind {
+			return c.Status == ConditionTrue
+		}
+	}
+	return false
+}
+
+// ConfigSourceAnnotationKey is the annotation of the source a kubelet read a pod from.
+// A pod which carries it in the apiserver is the read-only mirror of a pod a kubelet
+// runs from its own files or URL.
+const ConfigSourceAnnotationKey = "kubernetes/config.source"
+
+// IsMirrorPod returns true if the pod mirrors a pod run by a kubelet from its own source.
+func IsMirrorPod(pod *Pod) bool {
+	_, found := pod.Annotations[ConfigSourceAnnotationKey]
+	return found
+}
+
+// TerminationGracePeriod returns how long the pod of spec has to terminate gracefully.
+func TerminationGracePeriod(spec *PodSpec) time.Duration {
+	seconds := TerminationGracePeriodSecondsDefault
+	if spec.TerminationGracePeriodSeconds != nil {
+		seconds = *spec.TerminationGracePeriodSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
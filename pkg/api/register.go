@@ -41,6 +41,8 @@ func init() {
 		&Binding{},
 		&Event{},
 		&EventList{},
+		&Secret{},
+		&SecretList{},
 		&ContainerManifest{},
 		&ContainerManifestList{},
 		&BoundPod{},
@@ -64,6 +66,8 @@ func (*ServerOp) IsAnAPIObject()                  {}
 func (*ServerOpList) IsAnAPIObject()              {}
 func (*Event) IsAnAPIObject()                     {}
 func (*EventList) IsAnAPIObject()                 {}
+func (*Secret) IsAnAPIObject()                    {}
+func (*SecretList) IsAnAPIObject()                {}
 func (*ContainerManifest) IsAnAPIObject()         {}
 func (*ContainerManifestList) IsAnAPIObject()     {}
 func (*BoundPod) IsAnAPIObject()                  {}
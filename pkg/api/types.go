@@ -106,6 +106,12 @@ type ObjectMeta struct {
 	// Clients may not set this value. It is represented in RFC3339 form and is in UTC.
 	CreationTimestamp util.Time `json:"creationTimestamp,omitempty" yaml:"creationTimestamp,omitempty"`
 
+	// DeletionTimestamp is the time after which this object will be removed, set by the server
+	// when a graceful deletion is requested. Until then the object is terminating: a pod's
+	// containers are stopped by its kubelet and it is no longer an endpoint of its services.
+	// Clients may not set this value. It is represented in RFC3339 form and is in UTC.
+	DeletionTimestamp *util.Time `json:"deletionTimestamp,omitempty" yaml:"deletionTimestamp,omitempty"`
+
 	// Labels are key value pairs that may be used to scope and select individual resources.
 	// TODO: replace map[string]string with labels.LabelSet type
 	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
@@ -123,6 +129,9 @@ const (
 	NamespaceAll string = ""
 	// TerminationMessagePathDefault means the default path to capture the application termination message running in a container
 	TerminationMessagePathDefault string = "/dev/termination-log"
+	// TerminationGracePeriodSecondsDefault is how long a pod has to terminate gracefully when its
+	// spec does not say
+	TerminationGracePeriodSecondsDefault int64 = 30
 )
 
 // Volume represents a named volume in a pod that may be accessed by any containers in the pod.
@@ -152,6 +161,15 @@ type VolumeSource struct {
 	GCEPersistentDisk *GCEPersistentDisk `json:"persistentDisk" yaml:"persistentDisk"`
 	// GitRepo represents a git repository at a particular revision.
 	GitRepo *GitRepo `json:"gitRepo" yaml:"gitRepo"`
+	// DownwardAPI represents fields of the pod written to files.
+	DownwardAPI *DownwardAPIVolumeSource `json:"downwardAPI,omitempty" yaml:"downwardAPI,omitempty"`
+	// Secret represents a secret of the namespace of the pod, mounted on tmpfs.
+	Secret *SecretSource `json:"secret,omitempty" yaml:"secret,omitempty"`
+	// NFS represents an NFS export mounted on the host that shares a pod's lifetime.
+	NFS *NFS `json:"nfs,omitempty" yaml:"nfs,omitempty"`
+	// LocalQuotaVolume represents a directory of the data filesystem of the host, limited by
+	// its own quota, which shares a pod's lifetime.
+	LocalQuotaVolume *LocalQuotaVolume `json:"localQuotaVolume,omitempty" yaml:"localQuotaVolume,omitempty"`
 }
 
 // HostDir represents bare host directory volume.
@@ -199,7 +217,55 @@ type GitRepo struct {
 	Repository string `yaml:"repository" json:"repository"`
 	// Commit hash, this is optional
 	Revision string `yaml:"revision" json:"revision"`
-	// TODO: Consider credentials here.
+	// Name of the secret in the namespace of the pod holding the "username" and "password"
+	// of the repository, optional. The credentials of the node keyring are used without it.
+	SecretName string `yaml:"secretName,omitempty" json:"secretName,omitempty"`
+	// Subdirectory of the repository exposed in the volume, optional.
+	Directory string `yaml:"directory,omitempty" json:"directory,omitempty"`
+	// Number of commits of a shallow clone, all of the history when 0.
+	Depth int `yaml:"depth,omitempty" json:"depth,omitempty"`
+	// Checks out the submodules recursively.
+	Submodules bool `yaml:"submodules,omitempty" json:"submodules,omitempty"`
+	// Seconds between the fetches of the repository, never refreshed when 0.
+	RefreshSeconds int `yaml:"refreshSeconds,omitempty" json:"refreshSeconds,omitempty"`
+}
+
+// DownwardAPIVolumeSource represents a volume of files holding fields of the pod.
+type DownwardAPIVolumeSource struct {
+	// Items are the files of the volume.
+	Items []DownwardAPIVolumeFile `json:"items" yaml:"items"`
+}
+
+// DownwardAPIVolumeFile is a file holding a field of the pod.
+type DownwardAPIVolumeFile struct {
+	// Required: path of the file relative to the volume, without '..'.
+	Path string `json:"path" yaml:"path"`
+	// Required: the field of the pod held by the file.
+	FieldRef ObjectFieldSelector `json:"fieldRef" yaml:"fieldRef"`
+}
+
+// NFS represents an NFS export mounted for the lifetime of a pod.
+type NFS struct {
+	// Required: server is the hostname or IP address of the NFS server.
+	Server string `json:"server" yaml:"server"`
+	// Required: path is the path exported by the NFS server.
+	Path string `json:"path" yaml:"path"`
+	// Optional: Defaults to false (read/write). ReadOnly here will force
+	// the ReadOnly setting in VolumeMounts.
+	ReadOnly bool `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+}
+
+// LocalQuotaVolume represents a directory of the data filesystem of the host, whose size is
+// limited by a project quota. It is kept across the restarts of the containers of the pod.
+type LocalQuotaVolume struct {
+	// Required: Disk space size in GB.
+	Disk int `json:"disk" yaml:"disk"`
+}
+
+// SecretSource represents a volume holding the data of a secret, one file per key.
+type SecretSource struct {
+	// Required: name of the secret, in the namespace of the pod.
+	SecretName string `json:"secretName" yaml:"secretName"`
 }
 
 // Port represents a network port in a single container
@@ -233,6 +299,22 @@ type EnvVar struct {
 	Name string `json:"name" yaml:"name"`
 	// Optional: defaults to "".
 	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Optional: the source of the value, in which case Value must be empty.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty" yaml:"valueFrom,omitempty"`
+}
+
+// EnvVarSource represents a source for the value of an EnvVar.
+type EnvVarSource struct {
+	// Required: selects a field of the pod.
+	FieldRef *ObjectFieldSelector `json:"fieldRef" yaml:"fieldRef"`
+}
+
+// ObjectFieldSelector selects a field of the pod: metadata.name, metadata.namespace,
+// metadata.labels, metadata.labels['<key>'], metadata.annotations, res.network.address,
+// res.network.gateway, res.network.macAddress, res.network.vlanID or res.cpuSet.
+type ObjectFieldSelector struct {
+	// Required: path of the field.
+	FieldPath string `json:"fieldPath" yaml:"fieldPath"`
 }
 
 // HTTPGetAction describes an action based on HTTP Get requests.
@@ -243,6 +325,28 @@ type HTTPGetAction struct {
 	Port util.IntOrString `json:"port,omitempty" yaml:"port,omitempty"`
 	// Optional: Host name to connect to, defaults to the pod IP.
 	Host string `json:"host,omitempty" yaml:"host,omitempty"`
+	// Optional: Scheme to use for connecting to the host, defaults to HTTP.
+	Scheme URIScheme `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	// Optional: Custom headers to set in the request.
+	HTTPHeaders []HTTPHeader `json:"httpHeaders,omitempty" yaml:"httpHeaders,omitempty"`
+}
+
+// URIScheme identifies the scheme used for connection to a host for Get actions.
+type URIScheme string
+
+const (
+	// URISchemeHTTP means that the scheme used will be http://
+	URISchemeHTTP URIScheme = "HTTP"
+	// URISchemeHTTPS means that the scheme used will be https://
+	URISchemeHTTPS URIScheme = "HTTPS"
+)
+
+// HTTPHeader describes a custom header to be used in HTTP probes.
+type HTTPHeader struct {
+	// The header field name.
+	Name string `json:"name" yaml:"name"`
+	// The header field value.
+	Value string `json:"value" yaml:"value"`
 }
 
 // TCPSocketAction describes an action based on opening a socket
@@ -271,6 +375,16 @@ type LivenessProbe struct {
 	Exec *ExecAction `yaml:"exec,omitempty" json:"exec,omitempty"`
 	// Length of time before health checking is activated.  In seconds.
 	InitialDelaySeconds int64 `yaml:"initialDelaySeconds,omitempty" json:"initialDelaySeconds,omitempty"`
+	// Optional: Length of time before the probe times out, defaults to 1 second.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+	// Optional: How often to run the probe, defaults to 10 seconds.
+	PeriodSeconds int64 `json:"periodSeconds,omitempty" yaml:"periodSeconds,omitempty"`
+	// Optional: Minimum consecutive successes for the probe to be considered successful
+	// after having failed, defaults to 1.
+	SuccessThreshold int `json:"successThreshold,omitempty" yaml:"successThreshold,omitempty"`
+	// Optional: Minimum consecutive failures for the probe to be considered failed
+	// after having succeeded, defaults to 3.
+	FailureThreshold int `json:"failureThreshold,omitempty" yaml:"failureThreshold,omitempty"`
 }
 
 // PullPolicy describes a policy for if/when to pull a container image
@@ -308,7 +422,9 @@ type Container struct {
 	Disk          int            `yaml:"disk,omitempty" json:"disk,omitempty" description:"Disk space size in GB"`
 	VolumeMounts  []VolumeMount  `json:"volumeMounts,omitempty" yaml:"volumeMounts,omitempty"`
 	LivenessProbe *LivenessProbe `json:"livenessProbe,omitempty" yaml:"livenessProbe,omitempty"`
-	Lifecycle     *Lifecycle     `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	// Optional: the container receives service traffic only while this probe succeeds.
+	ReadinessProbe *LivenessProbe `json:"readinessProbe,omitempty" yaml:"readinessProbe,omitempty"`
+	Lifecycle      *Lifecycle     `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
 	// Optional: Defaults to /dev/termination-log
 	TerminationMessagePath string `json:"terminationMessagePath,omitempty" yaml:"terminationMessagePath,omitempty"`
 	// Optional: Default to false.
@@ -376,9 +492,63 @@ const (
 	PodFailed PodPhase = "Failed"
 )
 
+// ProbeResultType is the outcome of a single run of a probe.
+type ProbeResultType string
+
+// These are the valid results of a probe.
+const (
+	ProbeSuccess ProbeResultType = "Success"
+	ProbeFailure ProbeResultType = "Failure"
+	ProbeUnknown ProbeResultType = "Unknown"
+)
+
+// ProbeResult describes the most recent runs of a probe of a container.
+type ProbeResult struct {
+	// Result of the last run.
+	Result ProbeResultType `json:"result,omitempty" yaml:"result,omitempty"`
+	// Optional: details about the last run, such as the error of a probe which could not be run.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Number of consecutive successful runs up to the last one.
+	ConsecutiveSuccesses int `json:"consecutiveSuccesses,omitempty" yaml:"consecutiveSuccesses,omitempty"`
+	// Number of consecutive failed runs up to the last one.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty" yaml:"consecutiveFailures,omitempty"`
+	// Time of the last run.
+	LastProbeTime time.Time `json:"lastProbeTime,omitempty" yaml:"lastProbeTime,omitempty"`
+}
+
+// PodConditionKind is a valid value for PodCondition.Kind
+type PodConditionKind string
+
+// These are valid conditions of pod.
+const (
+	// PodReady means the pod is able to service requests and should be added to the
+	// load balancing pools of all matching services.
+	PodReady PodConditionKind = "Ready"
+)
+
+// ConditionStatus is the status of a condition.
+type ConditionStatus string
+
+// These are valid condition statuses.
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// PodCondition describes one aspect of the current state of a pod.
+type PodCondition struct {
+	Kind   PodConditionKind `json:"kind" yaml:"kind"`
+	Status ConditionStatus  `json:"status" yaml:"status"`
+}
+
 type ContainerStateWaiting struct {
 	// Reason could be pulling image,
 	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// Message gives details about the reason, e.g. the back-off delay of a crashing container.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// NextRetryAt is the time at which a backed-off container will be restarted.
+	NextRetryAt time.Time `json:"nextRetryAt,omitempty" yaml:"nextRetryAt,omitempty"`
 }
 
 type ContainerStateRunning struct {
@@ -415,6 +585,11 @@ type ContainerStatus struct {
 	PodIP string `json:"podIP,omitempty" yaml:"podIP,omitempty"`
 	// TODO(dchen1107): Need to decide how to represent this in v1beta3
 	Image string `yaml:"image" json:"image"`
+	// Ready is true when the container is running and passes its readiness probe.
+	Ready bool `json:"ready" yaml:"ready"`
+	// Optional: results of the liveness and readiness probes of the container.
+	Liveness  *ProbeResult `json:"liveness,omitempty" yaml:"liveness,omitempty"`
+	Readiness *ProbeResult `json:"readiness,omitempty" yaml:"readiness,omitempty"`
 }
 
 // PodInfo contains one entry for every container with available info.
@@ -473,12 +648,25 @@ type PodSpec struct {
 	NetworkMode   string        `json:"networkMode,omitempty" yaml:"networkMode,omitempty"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node
 	NodeSelector map[string]string `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
+	// Optional duration in seconds the pod needs to terminate gracefully: the containers run their
+	// PreStop handlers and are sent SIGTERM, and are killed once it is over. Defaults to
+	// TerminationGracePeriodSecondsDefault, 0 kills the containers at once.
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" yaml:"terminationGracePeriodSeconds,omitempty"`
+	// Optional: containers which run to completion one after another, in the network container
+	// and with the volumes of the pod, before Containers are started. A failed init container
+	// is retried according to RestartPolicy.
+	InitContainers []Container `json:"initContainers,omitempty" yaml:"initContainers,omitempty"`
 }
 
 // PodStatus represents information about the status of a pod. Status may trail the actual
 // state of a system.
 type PodStatus struct {
-	Phase PodPhase `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Phase      PodPhase       `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Conditions []PodCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	// A brief CamelCase reason why the pod is in this state, e.g. "Evicted".
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// A human readable message indicating details about why the pod is in this state.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
 
 	// Host is the name of the node that this Pod is currently bound to, or empty if no
 	// assignment has been done.
@@ -676,6 +864,33 @@ type NodeSpec struct {
 type NodeStatus struct {
 	// Queried from cloud provider, if available.
 	HostIP string `json:"hostIP,omitempty" yaml:"hostIP,omitempty"`
+	// Conditions reported by the kubelet of the node.
+	Conditions []NodeCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+}
+
+// NodeConditionKind is a valid value for NodeCondition.Kind
+type NodeConditionKind string
+
+// These are valid conditions of node.
+const (
+	// NodeMemoryPressure means the available memory of the node is below an eviction threshold
+	// of the kubelet. No best-effort pod is scheduled to the node.
+	NodeMemoryPressure NodeConditionKind = "MemoryPressure"
+	// NodeDiskPressure means the available space of the data filesystem of the node is below an
+	// eviction threshold of the kubelet. No pod is scheduled to the node.
+	NodeDiskPressure NodeConditionKind = "DiskPressure"
+)
+
+// NodeCondition describes one aspect of the current state of a node.
+type NodeCondition struct {
+	Kind   NodeConditionKind `json:"kind" yaml:"kind"`
+	Status ConditionStatus   `json:"status" yaml:"status"`
+	// Optional: a brief CamelCase reason for the last transition of the condition.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// Optional: a human readable message with details about the last transition.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Time of the last transition of the status of the condition.
+	LastTransitionTime time.Time `json:"lastTransitionTime,omitempty" yaml:"lastTransitionTime,omitempty"`
 }
 
 // NodeResources is an object for conveying resource information about a node.
@@ -970,6 +1185,28 @@ type EventList struct {
 	Items []Event `yaml:"items" json:"items"`
 }
 
+// Secret holds secret data, such as credentials, for the pods of its namespace. The data is
+// only written to the tmpfs volumes of the pods which mount it.
+type Secret struct {
+	TypeMeta   `json:",inline" yaml:",inline"`
+	ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Data maps keys, which name the files of the volumes, to the secret values, encoded in base64
+	// in JSON and YAML.
+	Data util.ByteMap `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+// MaxSecretSize is the maximum total size of the data of a secret.
+const MaxSecretSize = 1 * 1024 * 1024
+
+// SecretList is a list of secrets.
+type SecretList struct {
+	TypeMeta `json:",inline" yaml:",inline"`
+	ListMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Items []Secret `json:"items" yaml:"items"`
+}
+
 // ContainerManifest corresponds to the Container Manifest format, documented at:
 // https://developers.google.com/compute/docs/containers/container_vms#container_manifest
 // This is used as the representation of Kubernetes workloads.
@@ -988,6 +1225,14 @@ type ContainerManifest struct {
 	Containers    []Container   `yaml:"containers" json:"containers"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" yaml:"restartPolicy,omitempty"`
 	NetworkMode   string        `json:"networkMode,omitempty" yaml:"networkMode,omitempty"`
+	// Optional duration in seconds the pod needs to terminate gracefully: the containers run their
+	// PreStop handlers and are sent SIGTERM, and are killed once it is over. Defaults to
+	// TerminationGracePeriodSecondsDefault, 0 kills the containers at once.
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" yaml:"terminationGracePeriodSeconds,omitempty"`
+	// Optional: containers which run to completion one after another, in the network container
+	// and with the volumes of the pod, before Containers are started. A failed init container
+	// is retried according to RestartPolicy.
+	InitContainers []Container `json:"initContainers,omitempty" yaml:"initContainers,omitempty"`
 }
 
 // ContainerManifestList is used to communicate container manifests to kubelet.
@@ -75,6 +75,7 @@ func init() {
 			out.ID = in.Name
 			out.UID = in.UID
 			out.CreationTimestamp = in.CreationTimestamp
+			out.DeletionTimestamp = in.DeletionTimestamp
 			out.SelfLink = in.SelfLink
 			if len(in.ResourceVersion) > 0 {
 				v, err := strconv.ParseUint(in.ResourceVersion, 10, 64)
@@ -90,6 +91,7 @@ func init() {
 			out.Name = in.ID
 			out.UID = in.UID
 			out.CreationTimestamp = in.CreationTimestamp
+			out.DeletionTimestamp = in.DeletionTimestamp
 			out.SelfLink = in.SelfLink
 			if in.ResourceVersion != 0 {
 				out.ResourceVersion = strconv.FormatUint(in.ResourceVersion, 10)
@@ -104,7 +106,7 @@ func init() {
 			out.Value = in.Value
 			out.Key = in.Name
 			out.Name = in.Name
-			return nil
+			return s.Convert(&in.ValueFrom, &out.ValueFrom, 0)
 		},
 		func(in *EnvVar, out *newer.EnvVar, s conversion.Scope) error {
 			out.Value = in.Value
@@ -113,7 +115,7 @@ func init() {
 			} else {
 				out.Name = in.Key
 			}
-			return nil
+			return s.Convert(&in.ValueFrom, &out.ValueFrom, 0)
 		},
 
 		// Path & MountType are deprecated.
@@ -176,6 +178,9 @@ func init() {
 			if err := s.Convert(&in.Info, &out.Info, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Conditions, &out.Conditions, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.Network, &out.Network, 0); err != nil {
 				return err
 			}
@@ -183,6 +188,8 @@ func init() {
 			out.HostIP = in.HostIP
 			out.PodIP = in.PodIP
 			out.CpuSet = in.CpuSet
+			out.Reason = in.Reason
+			out.Message = in.Message
 			return nil
 		},
 		func(in *PodState, out *newer.PodStatus, s conversion.Scope) error {
@@ -192,6 +199,9 @@ func init() {
 			if err := s.Convert(&in.Info, &out.Info, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Conditions, &out.Conditions, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.Network, &out.Network, 0); err != nil {
 				return err
 			}
@@ -200,6 +210,8 @@ func init() {
 			out.HostIP = in.HostIP
 			out.PodIP = in.PodIP
 			out.CpuSet = in.CpuSet
+			out.Reason = in.Reason
+			out.Message = in.Message
 			return nil
 		},
 
@@ -386,10 +398,14 @@ func init() {
 			if err := s.Convert(&in.Containers, &out.Containers, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.InitContainers, &out.InitContainers, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.RestartPolicy, &out.RestartPolicy, 0); err != nil {
 				return err
 			}
 			out.NetworkMode = in.NetworkMode
+			out.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
 			out.Version = "v1beta2"
 			return nil
 		},
@@ -400,10 +416,14 @@ func init() {
 			if err := s.Convert(&in.Containers, &out.Containers, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.InitContainers, &out.InitContainers, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.RestartPolicy, &out.RestartPolicy, 0); err != nil {
 				return err
 			}
 			out.NetworkMode = in.NetworkMode
+			out.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
 			return nil
 		},
 
@@ -468,6 +488,10 @@ func init() {
 				return err
 			}
 
+			if err := s.Convert(&in.Status.Conditions, &out.Conditions, 0); err != nil {
+				return err
+			}
+
 			out.HostIP = in.Status.HostIP
 			return s.Convert(&in.Spec.Capacity, &out.NodeResources.Capacity, 0)
 		},
@@ -484,6 +508,9 @@ func init() {
 			if err := s.Convert(&in.VMs, &out.Spec.VMs, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Conditions, &out.Status.Conditions, 0); err != nil {
+				return err
+			}
 			out.Status.HostIP = in.HostIP
 			return s.Convert(&in.NodeResources.Capacity, &out.Spec.Capacity, 0)
 		},
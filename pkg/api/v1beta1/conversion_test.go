@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -192,3 +193,32 @@ func TestMinionListConversionToOld(t *testing.T) {
 		t.Errorf("Expected: %#v, got %#v", e, a)
 	}
 }
+
+func TestSecretConversion(t *testing.T) {
+	newSecret := &newer.Secret{
+		ObjectMeta: newer.ObjectMeta{Name: "foo"},
+		Data:       map[string][]byte{"token": []byte("secret"), "empty": {}, "unset": nil},
+	}
+
+	data, err := v1beta1.Codec.Encode(newSecret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var old struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &old); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := map[string]interface{}{"token": "c2VjcmV0", "empty": "", "unset": nil}, old.Data; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected: %#v, got %#v", e, a)
+	}
+
+	got, err := v1beta1.Codec.Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := newSecret, got; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected: %#v, got %#v", e, a)
+	}
+}
@@ -57,11 +57,13 @@ type ContainerManifest struct {
 	// TODO: UUID on Manifext is deprecated in the future once we are done
 	// with the API refactory. It is required for now to determine the instance
 	// of a Pod.
-	UUID          string        `yaml:"uuid,omitempty" json:"uuid,omitempty" description:"manifest UUID"`
-	Volumes       []Volume      `yaml:"volumes" json:"volumes" description:"list of volumes that can be mounted by containers belonging to the pod"`
-	Containers    []Container   `yaml:"containers" json:"containers" description:"list of containers belonging to the pod"`
-	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" yaml:"restartPolicy,omitempty" description:"restart policy for all containers within the pod; one of RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever"`
-	NetworkMode   string        `json:"networkMode,omitempty" yaml:"networkMode,omitempty" description:"network mode for pod"`
+	UUID                          string        `yaml:"uuid,omitempty" json:"uuid,omitempty" description:"manifest UUID"`
+	Volumes                       []Volume      `yaml:"volumes" json:"volumes" description:"list of volumes that can be mounted by containers belonging to the pod"`
+	Containers                    []Container   `yaml:"containers" json:"containers" description:"list of containers belonging to the pod"`
+	RestartPolicy                 RestartPolicy `json:"restartPolicy,omitempty" yaml:"restartPolicy,omitempty" description:"restart policy for all containers within the pod; one of RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever"`
+	NetworkMode                   string        `json:"networkMode,omitempty" yaml:"networkMode,omitempty" description:"network mode for pod"`
+	TerminationGracePeriodSeconds *int64        `json:"terminationGracePeriodSeconds,omitempty" yaml:"terminationGracePeriodSeconds,omitempty" description:"optional duration in seconds the pod needs to terminate gracefully; the containers run their PreStop handlers and are sent SIGTERM, and are killed once it is over; defaults to 30 seconds, 0 kills the containers at once"`
+	InitContainers                []Container   `json:"initContainers,omitempty" yaml:"initContainers,omitempty" description:"ordered list of containers which run to completion in the network container of the pod and with its volumes before the containers are started; a failed init container is retried according to the restart policy"`
 }
 
 // ContainerManifestList is used to communicate container manifests to kubelet.
@@ -97,6 +99,15 @@ type VolumeSource struct {
 	GCEPersistentDisk *GCEPersistentDisk `yaml:"persistentDisk" json:"persistentDisk" description:"GCE disk resource attached to the host machine on demand"`
 	// GitRepo represents a git repository at a particular revision.
 	GitRepo *GitRepo `json:"gitRepo" yaml:"gitRepo" description:"git repository at a particular revision"`
+	// DownwardAPI represents fields of the pod written to files.
+	DownwardAPI *DownwardAPIVolumeSource `json:"downwardAPI,omitempty" yaml:"downwardAPI,omitempty" description:"fields of the pod written to files; labels and annotations are kept up to date"`
+	// Secret represents a secret of the namespace of the pod, mounted on tmpfs.
+	Secret *SecretSource `json:"secret,omitempty" yaml:"secret,omitempty" description:"secret of the namespace of the pod, mounted on tmpfs"`
+	// NFS represents an NFS export mounted on the host that shares a pod's lifetime.
+	NFS *NFS `json:"nfs,omitempty" yaml:"nfs,omitempty" description:"NFS export mounted on the host for the lifetime of the pod"`
+	// LocalQuotaVolume represents a directory of the data filesystem of the host, limited by
+	// its own quota, which shares a pod's lifetime.
+	LocalQuotaVolume *LocalQuotaVolume `json:"localQuotaVolume,omitempty" yaml:"localQuotaVolume,omitempty" description:"quota-limited directory of the data filesystem of the host for the lifetime of the pod"`
 }
 
 // HostDir represents bare host directory volume.
@@ -145,6 +156,55 @@ type GitRepo struct {
 	Repository string `yaml:"repository" json:"repository" description:"repository URL"`
 	// Commit hash, this is optional
 	Revision string `yaml:"revision" json:"revision" description:"commit hash for the specified revision"`
+	// Name of the secret in the namespace of the pod holding the "username" and "password"
+	// of the repository, optional. The credentials of the node keyring are used without it.
+	SecretName string `yaml:"secretName,omitempty" json:"secretName,omitempty" description:"name of the secret holding the credentials of the repository"`
+	// Subdirectory of the repository exposed in the volume, optional.
+	Directory string `yaml:"directory,omitempty" json:"directory,omitempty" description:"subdirectory of the repository exposed in the volume"`
+	// Number of commits of a shallow clone, all of the history when 0.
+	Depth int `yaml:"depth,omitempty" json:"depth,omitempty" description:"number of commits of a shallow clone; all of the history when 0"`
+	// Checks out the submodules recursively.
+	Submodules bool `yaml:"submodules,omitempty" json:"submodules,omitempty" description:"checks out the submodules recursively"`
+	// Seconds between the fetches of the repository, never refreshed when 0.
+	RefreshSeconds int `yaml:"refreshSeconds,omitempty" json:"refreshSeconds,omitempty" description:"seconds between the fetches of the repository; never refreshed when 0"`
+}
+
+// DownwardAPIVolumeSource represents a volume of files holding fields of the pod.
+type DownwardAPIVolumeSource struct {
+	// Items are the files of the volume.
+	Items []DownwardAPIVolumeFile `json:"items" yaml:"items" description:"files of the volume"`
+}
+
+// DownwardAPIVolumeFile is a file holding a field of the pod.
+type DownwardAPIVolumeFile struct {
+	// Required: path of the file relative to the volume, without '..'.
+	Path string `json:"path" yaml:"path" description:"path of the file relative to the volume; must not contain '..'"`
+	// Required: the field of the pod held by the file.
+	FieldRef ObjectFieldSelector `json:"fieldRef" yaml:"fieldRef" description:"field of the pod held by the file"`
+}
+
+// NFS represents an NFS export mounted for the lifetime of a pod.
+type NFS struct {
+	// Required: server is the hostname or IP address of the NFS server.
+	Server string `json:"server" yaml:"server" description:"hostname or IP address of the NFS server"`
+	// Required: path is the path exported by the NFS server.
+	Path string `json:"path" yaml:"path" description:"path exported by the NFS server"`
+	// Optional: Defaults to false (read/write). ReadOnly here will force
+	// the ReadOnly setting in VolumeMounts.
+	ReadOnly bool `json:"readOnly,omitempty" yaml:"readOnly,omitempty" description:"mount the export read-only; defaults to false"`
+}
+
+// LocalQuotaVolume represents a directory of the data filesystem of the host, whose size is
+// limited by a project quota. It is kept across the restarts of the containers of the pod.
+type LocalQuotaVolume struct {
+	// Required: Disk space size in GB.
+	Disk int `json:"disk" yaml:"disk" description:"disk space size in GB"`
+}
+
+// SecretSource represents a volume holding the data of a secret, one file per key.
+type SecretSource struct {
+	// Required: name of the secret, in the namespace of the pod.
+	SecretName string `json:"secretName" yaml:"secretName" description:"name of the secret in the namespace of the pod"`
 }
 
 // Port represents a network port in a single container
@@ -187,6 +247,22 @@ type EnvVar struct {
 	Key  string `yaml:"key,omitempty" json:"key,omitempty" description:"name of the environment variable; must be a C_IDENTIFIER; deprecated - use name instead"`
 	// Optional: defaults to "".
 	Value string `yaml:"value,omitempty" json:"value,omitempty" description:"value of the environment variable; defaults to empty string"`
+	// Optional: the source of the value, in which case Value must be empty.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty" yaml:"valueFrom,omitempty" description:"source of the value of the environment variable; value must be empty if set"`
+}
+
+// EnvVarSource represents a source for the value of an EnvVar.
+type EnvVarSource struct {
+	// Required: selects a field of the pod.
+	FieldRef *ObjectFieldSelector `json:"fieldRef" yaml:"fieldRef" description:"selects a field of the pod"`
+}
+
+// ObjectFieldSelector selects a field of the pod: metadata.name, metadata.namespace,
+// metadata.labels, metadata.labels['<key>'], metadata.annotations, res.network.address,
+// res.network.gateway, res.network.macAddress, res.network.vlanID or res.cpuSet.
+type ObjectFieldSelector struct {
+	// Required: path of the field.
+	FieldPath string `json:"fieldPath" yaml:"fieldPath" description:"path of the field, e.g. metadata.name or res.network.address"`
 }
 
 // HTTPGetAction describes an action based on HTTP Get requests.
@@ -197,6 +273,28 @@ type HTTPGetAction struct {
 	Port util.IntOrString `yaml:"port,omitempty" json:"port,omitempty" description:"number or name of the port to access on the container"`
 	// Optional: Host name to connect to, defaults to the pod IP.
 	Host string `yaml:"host,omitempty" json:"host,omitempty" description:"hostname to connect to; defaults to pod IP"`
+	// Optional: Scheme to use for connecting to the host, defaults to HTTP.
+	Scheme URIScheme `json:"scheme,omitempty" yaml:"scheme,omitempty" description:"scheme to connect with, one of HTTP, HTTPS; defaults to HTTP"`
+	// Optional: Custom headers to set in the request.
+	HTTPHeaders []HTTPHeader `json:"httpHeaders,omitempty" yaml:"httpHeaders,omitempty" description:"custom headers to set in the request"`
+}
+
+// URIScheme identifies the scheme used for connection to a host for Get actions.
+type URIScheme string
+
+const (
+	// URISchemeHTTP means that the scheme used will be http://
+	URISchemeHTTP URIScheme = "HTTP"
+	// URISchemeHTTPS means that the scheme used will be https://
+	URISchemeHTTPS URIScheme = "HTTPS"
+)
+
+// HTTPHeader describes a custom header to be used in HTTP probes.
+type HTTPHeader struct {
+	// The header field name.
+	Name string `json:"name" yaml:"name" description:"header field name"`
+	// The header field value.
+	Value string `json:"value" yaml:"value" description:"header field value"`
 }
 
 // TCPSocketAction describes an action based on opening a socket
@@ -226,6 +324,16 @@ type LivenessProbe struct {
 	Exec *ExecAction `yaml:"exec,omitempty" json:"exec,omitempty" description:"parameters for exec-based liveness probe"`
 	// Length of time before health checking is activated.  In seconds.
 	InitialDelaySeconds int64 `yaml:"initialDelaySeconds,omitempty" json:"initialDelaySeconds,omitempty" description:"number of seconds after the container has started before liveness probes are initiated"`
+	// Optional: Length of time before the probe times out, defaults to 1 second.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty" description:"number of seconds after which the probe times out; defaults to 1 second"`
+	// Optional: How often to run the probe, defaults to 10 seconds.
+	PeriodSeconds int64 `json:"periodSeconds,omitempty" yaml:"periodSeconds,omitempty" description:"how often in seconds to run the probe; defaults to 10 seconds"`
+	// Optional: Minimum consecutive successes for the probe to be considered successful
+	// after having failed, defaults to 1.
+	SuccessThreshold int `json:"successThreshold,omitempty" yaml:"successThreshold,omitempty" description:"minimum consecutive successes for the probe to be considered successful after having failed; defaults to 1"`
+	// Optional: Minimum consecutive failures for the probe to be considered failed
+	// after having succeeded, defaults to 3.
+	FailureThreshold int `json:"failureThreshold,omitempty" yaml:"failureThreshold,omitempty" description:"minimum consecutive failures for the probe to be considered failed after having succeeded; defaults to 3"`
 }
 
 // PullPolicy describes a policy for if/when to pull a container image
@@ -263,7 +371,9 @@ type Container struct {
 	Disk          int            `yaml:"disk,omitempty" json:"disk,omitempty" description:"Disk space size in GB"`
 	VolumeMounts  []VolumeMount  `yaml:"volumeMounts,omitempty" json:"volumeMounts,omitempty" description:"pod volumes to mount into the container's filesystem"`
 	LivenessProbe *LivenessProbe `yaml:"livenessProbe,omitempty" json:"livenessProbe,omitempty" description:"periodic probe of container liveness; container will be restarted if the probe fails"`
-	Lifecycle     *Lifecycle     `yaml:"lifecycle,omitempty" json:"lifecycle,omitempty" description:"actions that the management system should take in response to container lifecycle events"`
+	// Optional: the container receives service traffic only while this probe succeeds.
+	ReadinessProbe *LivenessProbe `yaml:"readinessProbe,omitempty" json:"readinessProbe,omitempty" description:"periodic probe of container service readiness; the pod is removed from service endpoints while the probe fails"`
+	Lifecycle      *Lifecycle     `yaml:"lifecycle,omitempty" json:"lifecycle,omitempty" description:"actions that the management system should take in response to container lifecycle events"`
 	// Optional: Defaults to /dev/termination-log
 	TerminationMessagePath string `yaml:"terminationMessagePath,omitempty" json:"terminationMessagePath,omitempty" description:"path at which the file to which the container's termination message will be written is mounted into the container's filesystem; message written is intended to be brief final status, such as an assertion failure message; defaults to /dev/termination-log"`
 	// Optional: Default to false.
@@ -314,14 +424,15 @@ type Lifecycle struct {
 
 // TypeMeta is shared by all objects sent to, or returned from the client.
 type TypeMeta struct {
-	Kind              string    `json:"kind,omitempty" yaml:"kind,omitempty" description:"kind of object, in CamelCase"`
-	ID                string    `json:"id,omitempty" yaml:"id,omitempty" description:"name of the object; must be a DNS_SUBDOMAIN and unique among all objects of the same kind within the same namespace; used in resource URLs"`
-	UID               string    `json:"uid,omitempty" yaml:"uid,omitempty" description:"UUID assigned by the system upon creation, unique across space and time"`
-	CreationTimestamp util.Time `json:"creationTimestamp,omitempty" yaml:"creationTimestamp,omitempty" description:"RFC 3339 date and time at which the object was created; recorded by the system; null for lists"`
-	SelfLink          string    `json:"selfLink,omitempty" yaml:"selfLink,omitempty" description:"URL for the object"`
-	ResourceVersion   uint64    `json:"resourceVersion,omitempty" yaml:"resourceVersion,omitempty" description:"string that identifies the internal version of this object that can be used by clients to determine when objects have changed; value must be treated as opaque by clients and passed unmodified back to the server"`
-	APIVersion        string    `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty" description:"version of the schema the object should have"`
-	Namespace         string    `json:"namespace,omitempty" yaml:"namespace,omitempty" description:"namespace to which the object belongs; must be a DNS_SUBDOMAIN; 'default' by default"`
+	Kind              string     `json:"kind,omitempty" yaml:"kind,omitempty" description:"kind of object, in CamelCase"`
+	ID                string     `json:"id,omitempty" yaml:"id,omitempty" description:"name of the object; must be a DNS_SUBDOMAIN and unique among all objects of the same kind within the same namespace; used in resource URLs"`
+	UID               string     `json:"uid,omitempty" yaml:"uid,omitempty" description:"UUID assigned by the system upon creation, unique across space and time"`
+	CreationTimestamp util.Time  `json:"creationTimestamp,omitempty" yaml:"creationTimestamp,omitempty" description:"RFC 3339 date and time at which the object was created; recorded by the system; null for lists"`
+	DeletionTimestamp *util.Time `json:"deletionTimestamp,omitempty" yaml:"deletionTimestamp,omitempty" description:"RFC 3339 date and time after which the object will be removed; set by the system when a graceful deletion is requested; a terminating pod is stopped by its kubelet and is no longer an endpoint of its services"`
+	SelfLink          string     `json:"selfLink,omitempty" yaml:"selfLink,omitempty" description:"URL for the object"`
+	ResourceVersion   uint64     `json:"resourceVersion,omitempty" yaml:"resourceVersion,omitempty" description:"string that identifies the internal version of this object that can be used by clients to determine when objects have changed; value must be treated as opaque by clients and passed unmodified back to the server"`
+	APIVersion        string     `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty" description:"version of the schema the object should have"`
+	Namespace         string     `json:"namespace,omitempty" yaml:"namespace,omitempty" description:"namespace to which the object belongs; must be a DNS_SUBDOMAIN; 'default' by default"`
 
 	// Annotations are unstructured key value data stored with a resource that may be set by
 	// external tooling. They are not queryable and should be preserved when modifying
@@ -342,9 +453,63 @@ const (
 	PodTerminated PodStatus = "Terminated"
 )
 
+// ProbeResultType is the outcome of a single run of a probe.
+type ProbeResultType string
+
+// These are the valid results of a probe.
+const (
+	ProbeSuccess ProbeResultType = "Success"
+	ProbeFailure ProbeResultType = "Failure"
+	ProbeUnknown ProbeResultType = "Unknown"
+)
+
+// ProbeResult describes the most recent runs of a probe of a container.
+type ProbeResult struct {
+	// Result of the last run.
+	Result ProbeResultType `json:"result,omitempty" yaml:"result,omitempty" description:"result of the last run of the probe; one of Success, Failure, Unknown"`
+	// Optional: details about the last run, such as the error of a probe which could not be run.
+	Message string `json:"message,omitempty" yaml:"message,omitempty" description:"details about the last run of the probe"`
+	// Number of consecutive successful runs up to the last one.
+	ConsecutiveSuccesses int `json:"consecutiveSuccesses,omitempty" yaml:"consecutiveSuccesses,omitempty" description:"number of consecutive successful runs of the probe"`
+	// Number of consecutive failed runs up to the last one.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty" yaml:"consecutiveFailures,omitempty" description:"number of consecutive failed runs of the probe"`
+	// Time of the last run.
+	// TODO: change to util.Time
+	LastProbeTime time.Time `json:"lastProbeTime,omitempty" yaml:"lastProbeTime,omitempty" description:"time of the last run of the probe"`
+}
+
+// PodConditionKind is a valid value for PodCondition.Kind
+type PodConditionKind string
+
+// These are valid conditions of pod.
+const (
+	// PodReady means the pod is able to service requests and should be added to the
+	// load balancing pools of all matching services.
+	PodReady PodConditionKind = "Ready"
+)
+
+// ConditionStatus is the status of a condition.
+type ConditionStatus string
+
+// These are valid condition statuses.
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// PodCondition describes one aspect of the current state of a pod.
+type PodCondition struct {
+	Kind   PodConditionKind `json:"kind" yaml:"kind" description:"kind of the condition, currently only Ready"`
+	Status ConditionStatus  `json:"status" yaml:"status" description:"status of the condition, one of True, False, Unknown"`
+}
+
 type ContainerStateWaiting struct {
 	// Reason could be pulling image,
-	Reason string `json:"reason,omitempty" yaml:"reason,omitempty" description:"(brief) reason the container is not yet running, such as pulling its image"`
+	Reason  string `json:"reason,omitempty" yaml:"reason,omitempty" description:"(brief) reason the container is not yet running, such as pulling its image"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty" description:"human readable details about the reason, such as the back-off delay of a crashing container"`
+	// TODO: change to util.Time
+	NextRetryAt time.Time `json:"nextRetryAt,omitempty" yaml:"nextRetryAt,omitempty" description:"time at which a container in crash loop back-off will be restarted"`
 }
 
 type ContainerStateRunning struct {
@@ -384,6 +549,10 @@ type ContainerStatus struct {
 	PodIP string `json:"podIP,omitempty" yaml:"podIP,omitempty" description:"pod's IP address"`
 	// TODO(dchen1107): Need to decide how to reprensent this in v1beta3
 	Image string `yaml:"image" json:"image" description:"image of the container"`
+	Ready bool   `json:"ready" yaml:"ready" description:"whether the container is running and has passed its readiness probe"`
+	// Optional: results of the liveness and readiness probes of the container.
+	Liveness  *ProbeResult `json:"liveness,omitempty" yaml:"liveness,omitempty" description:"results of the liveness probe"`
+	Readiness *ProbeResult `json:"readiness,omitempty" yaml:"readiness,omitempty" description:"results of the readiness probe"`
 }
 
 // PodInfo contains one entry for every container with available info.
@@ -412,9 +581,11 @@ type PodState struct {
 	Status   PodStatus         `json:"status,omitempty" yaml:"status,omitempty" description:"current condition of the pod, Waiting, Running, or Terminated"`
 	// A human readable message indicating details about why the pod is in this state.
 	Message string `json:"message,omitempty" yaml:"message,omitempty" description:"human readable message indicating details about why the pod is in this condition"`
-	Host    string `json:"host,omitempty" yaml:"host,omitempty" description:"host to which the pod is assigned; empty if not yet scheduled"`
-	HostIP  string `json:"hostIP,omitempty" yaml:"hostIP,omitempty" description:"IP address of the host to which the pod is assigned; empty if not yet scheduled"`
-	PodIP   string `json:"podIP,omitempty" yaml:"podIP,omitempty" description:"IP address allocated to the pod; routable at least within the cluster; empty if not yet allocated"`
+	// A brief CamelCase reason why the pod is in this state, e.g. "Evicted".
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty" description:"brief CamelCase reason why the pod is in this condition, e.g. Evicted"`
+	Host   string `json:"host,omitempty" yaml:"host,omitempty" description:"host to which the pod is assigned; empty if not yet scheduled"`
+	HostIP string `json:"hostIP,omitempty" yaml:"hostIP,omitempty" description:"IP address of the host to which the pod is assigned; empty if not yet scheduled"`
+	PodIP  string `json:"podIP,omitempty" yaml:"podIP,omitempty" description:"IP address allocated to the pod; routable at least within the cluster; empty if not yet allocated"`
 
 	// The key of this map is the *name* of the container within the manifest; it has one
 	// entry per container in the manifest. The value of this map is ContainerStatus for
@@ -424,6 +595,8 @@ type PodState struct {
 	Network Network `json:"network,omitempty" yaml:"network,omitempty"`
 	// CPU set("1,3")
 	CpuSet string `json:"cpuSet,omitempty" yaml:"cpuSet,omitempty"`
+
+	Conditions []PodCondition `json:"conditions,omitempty" yaml:"conditions,omitempty" description:"current service state of the pod"`
 }
 
 // PodList is a list of Pods.
@@ -544,6 +717,30 @@ type Minion struct {
 	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize minions; labels of a minion assigned by the scheduler must match the scheduled pod's nodeSelector"`
 	//vm infomation
 	VMs []VM `json:"vms,omitempty" yaml:"vms,omitempty"`
+	// Conditions reported by the kubelet of the node
+	Conditions []NodeCondition `json:"conditions,omitempty" yaml:"conditions,omitempty" description:"conditions reported by the kubelet of the node, such as MemoryPressure"`
+}
+
+// NodeConditionKind is a valid value for NodeCondition.Kind
+type NodeConditionKind string
+
+// These are valid conditions of node.
+const (
+	// NodeMemoryPressure means the available memory of the node is below an eviction threshold
+	// of the kubelet. No best-effort pod is scheduled to the node.
+	NodeMemoryPressure NodeConditionKind = "MemoryPressure"
+	// NodeDiskPressure means the available space of the data filesystem of the node is below an
+	// eviction threshold of the kubelet. No pod is scheduled to the node.
+	NodeDiskPressure NodeConditionKind = "DiskPressure"
+)
+
+// NodeCondition describes one aspect of the current state of a node.
+type NodeCondition struct {
+	Kind               NodeConditionKind `json:"kind" yaml:"kind" description:"kind of the condition, one of MemoryPressure, DiskPressure"`
+	Status             ConditionStatus   `json:"status" yaml:"status" description:"status of the condition, one of True, False, Unknown"`
+	Reason             string            `json:"reason,omitempty" yaml:"reason,omitempty" description:"brief CamelCase reason for the last transition of the condition"`
+	Message            string            `json:"message,omitempty" yaml:"message,omitempty" description:"human readable message with details about the last transition"`
+	LastTransitionTime time.Time         `json:"lastTransitionTime,omitempty" yaml:"lastTransitionTime,omitempty" description:"time of the last transition of the status of the condition"`
 }
 
 // MinionList is a list of minions.
@@ -776,6 +973,23 @@ type EventList struct {
 	Items    []Event `yaml:"items" json:"items" description:"list of events"`
 }
 
+// Secret holds secret data, such as credentials, for the pods of its namespace. The data is
+// only written to the tmpfs volumes of the pods which mount it.
+type Secret struct {
+	TypeMeta `json:",inline" yaml:",inline"`
+
+	// Data maps keys, which name the files of the volumes, to the secret values, encoded in base64
+	// in JSON and YAML.
+	Data util.ByteMap `json:"data,omitempty" yaml:"data,omitempty" description:"base64 encoded secret data by key; the keys name the files of the secret volumes"`
+}
+
+// SecretList is a list of secrets.
+type SecretList struct {
+	TypeMeta `json:",inline" yaml:",inline"`
+
+	Items []Secret `json:"items" yaml:"items" description:"list of secrets"`
+}
+
 // Backported from v1beta3 to replace ContainerManifest
 
 // PodSpec is a description of a pod
@@ -785,7 +999,9 @@ type PodSpec struct {
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" yaml:"restartPolicy,omitempty" description:"restart policy for all containers within the pod; one of RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever"`
 	NetworkMode   string        `json:"networkMode,omitempty" yaml:"networkMode,omitempty" description:"network mode for pod"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node
-	NodeSelector map[string]string `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty" description:"selector which must match a node's labels for the pod to be scheduled on that node"`
+	NodeSelector                  map[string]string `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty" description:"selector which must match a node's labels for the pod to be scheduled on that node"`
+	TerminationGracePeriodSeconds *int64            `json:"terminationGracePeriodSeconds,omitempty" yaml:"terminationGracePeriodSeconds,omitempty" description:"optional duration in seconds the pod needs to terminate gracefully; the containers run their PreStop handlers and are sent SIGTERM, and are killed once it is over; defaults to 30 seconds, 0 kills the containers at once"`
+	InitContainers                []Container       `json:"initContainers,omitempty" yaml:"initContainers,omitempty" description:"ordered list of containers which run to completion in the network container of the pod and with its volumes before the containers are started; a failed init container is retried according to the restart policy"`
 }
 
 type BoundResource struct {
@@ -841,7 +1057,7 @@ type Network struct {
 	VlanID int `json:"vlanID,omitempty" yaml:"vlanID,omitempty"`
 }
 
-//vm
+// vm
 type VM struct {
 	//Asset ID
 	AssetID string `json:"assetID,omitempty" yaml:"assetID,omitempty"`
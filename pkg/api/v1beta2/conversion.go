@@ -75,6 +75,7 @@ func init() {
 			out.ID = in.Name
 			out.UID = in.UID
 			out.CreationTimestamp = in.CreationTimestamp
+			out.DeletionTimestamp = in.DeletionTimestamp
 			out.SelfLink = in.SelfLink
 			if len(in.ResourceVersion) > 0 {
 				v, err := strconv.ParseUint(in.ResourceVersion, 10, 64)
@@ -90,6 +91,7 @@ func init() {
 			out.Name = in.ID
 			out.UID = in.UID
 			out.CreationTimestamp = in.CreationTimestamp
+			out.DeletionTimestamp = in.DeletionTimestamp
 			out.SelfLink = in.SelfLink
 			if in.ResourceVersion != 0 {
 				out.ResourceVersion = strconv.FormatUint(in.ResourceVersion, 10)
@@ -269,10 +271,14 @@ func init() {
 			if err := s.Convert(&in.Containers, &out.Containers, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.InitContainers, &out.InitContainers, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.RestartPolicy, &out.RestartPolicy, 0); err != nil {
 				return err
 			}
 			out.NetworkMode = in.NetworkMode
+			out.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
 			out.Version = "v1beta2"
 			return nil
 		},
@@ -283,10 +289,14 @@ func init() {
 			if err := s.Convert(&in.Containers, &out.Containers, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.InitContainers, &out.InitContainers, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.RestartPolicy, &out.RestartPolicy, 0); err != nil {
 				return err
 			}
 			out.NetworkMode = in.NetworkMode
+			out.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
 			return nil
 		},
 
@@ -297,9 +307,14 @@ func init() {
 			if err := s.Convert(&in.Info, &out.Info, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Conditions, &out.Conditions, 0); err != nil {
+				return err
+			}
 			out.Host = in.Host
 			out.HostIP = in.HostIP
 			out.PodIP = in.PodIP
+			out.Reason = in.Reason
+			out.Message = in.Message
 			return nil
 		},
 		func(in *PodState, out *newer.PodStatus, s conversion.Scope) error {
@@ -309,9 +324,14 @@ func init() {
 			if err := s.Convert(&in.Info, &out.Info, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Conditions, &out.Conditions, 0); err != nil {
+				return err
+			}
 			out.Host = in.Host
 			out.HostIP = in.HostIP
 			out.PodIP = in.PodIP
+			out.Reason = in.Reason
+			out.Message = in.Message
 			return nil
 		},
 
@@ -389,6 +409,10 @@ func init() {
 			if err := s.Convert(&in.Spec.VMs, &out.VMs, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Status.Conditions, &out.Conditions, 0); err != nil {
+				return err
+			}
+
 			out.HostIP = in.Status.HostIP
 			return s.Convert(&in.Spec.Capacity, &out.NodeResources.Capacity, 0)
 		},
@@ -405,6 +429,9 @@ func init() {
 			if err := s.Convert(&in.VMs, &out.Spec.VMs, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Conditions, &out.Status.Conditions, 0); err != nil {
+				return err
+			}
 			out.Status.HostIP = in.HostIP
 			return s.Convert(&in.NodeResources.Capacity, &out.Spec.Capacity, 0)
 		},
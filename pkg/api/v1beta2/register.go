@@ -42,6 +42,8 @@ func init() {
 		&ServerOpList{},
 		&Event{},
 		&EventList{},
+		&Secret{},
+		&SecretList{},
 		&ContainerManifest{},
 		&ContainerManifestList{},
 		&BoundPod{},
@@ -65,6 +67,8 @@ func (*ServerOp) IsAnAPIObject()                  {}
 func (*ServerOpList) IsAnAPIObject()              {}
 func (*Event) IsAnAPIObject()                     {}
 func (*EventList) IsAnAPIObject()                 {}
+func (*Secret) IsAnAPIObject()                    {}
+func (*SecretList) IsAnAPIObject()                {}
 func (*ContainerManifest) IsAnAPIObject()         {}
 func (*ContainerManifestList) IsAnAPIObject()     {}
 func (*BoundPod) IsAnAPIObject()                  {}
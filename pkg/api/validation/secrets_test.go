@@ -0,0 +1,80 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func TestValidateSecret(t *testing.T) {
+	table := []struct {
+		name string
+		*api.Secret
+		valid bool
+	}{
+		{
+			"valid",
+			&api.Secret{
+				ObjectMeta: api.ObjectMeta{Name: "db-password", Namespace: "foo"},
+				Data:       map[string][]byte{"password": []byte("secret"), ".dockercfg": []byte("{}")},
+			},
+			true,
+		}, {
+			"missing name",
+			&api.Secret{
+				ObjectMeta: api.ObjectMeta{Namespace: "foo"},
+			},
+			false,
+		}, {
+			"invalid namespace",
+			&api.Secret{
+				ObjectMeta: api.ObjectMeta{Name: "db-password", Namespace: "aoeu-_-aoeu"},
+			},
+			false,
+		}, {
+			"key with a slash",
+			&api.Secret{
+				ObjectMeta: api.ObjectMeta{Name: "db-password", Namespace: "foo"},
+				Data:       map[string][]byte{"../password": []byte("secret")},
+			},
+			false,
+		}, {
+			"dot dot key",
+			&api.Secret{
+				ObjectMeta: api.ObjectMeta{Name: "db-password", Namespace: "foo"},
+				Data:       map[string][]byte{"..": []byte("secret")},
+			},
+			false,
+		}, {
+			"too large",
+			&api.Secret{
+				ObjectMeta: api.ObjectMeta{Name: "db-password", Namespace: "foo"},
+				Data:       map[string][]byte{"big": []byte(strings.Repeat("a", api.MaxSecretSize+1))},
+			},
+			false,
+		},
+	}
+
+	for _, item := range table {
+		if e, a := item.valid, len(ValidateSecret(item.Secret)) == 0; e != a {
+			t.Errorf("%v: expected %v, got %v", item.name, e, a)
+		}
+	}
+}
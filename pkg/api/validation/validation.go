@@ -18,12 +18,14 @@ package validation
 
 import (
 	"fmt"
+	"path"
 	"reflect"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	errs "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/capabilities"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fieldpath"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
@@ -82,6 +84,26 @@ func validateSource(source *api.VolumeSource) errs.ValidationErrorList {
 		numVolumes++
 		allErrs = append(allErrs, validateGCEPersistentDisk(source.GCEPersistentDisk)...)
 	}
+	if source.DownwardAPI != nil {
+		numVolumes++
+		allErrs = append(allErrs, validateDownwardAPI(source.DownwardAPI).Prefix("downwardAPI")...)
+	}
+	if source.Secret != nil {
+		numVolumes++
+		if !util.IsDNSSubdomain(source.Secret.SecretName) {
+			allErrs = append(allErrs, errs.NewFieldInvalid("secret.secretName", source.Secret.SecretName, ""))
+		}
+	}
+	if source.NFS != nil {
+		numVolumes++
+		allErrs = append(allErrs, validateNFS(source.NFS).Prefix("nfs")...)
+	}
+	if source.LocalQuotaVolume != nil {
+		numVolumes++
+		if source.LocalQuotaVolume.Disk <= 0 {
+			allErrs = append(allErrs, errs.NewFieldInvalid("localQuotaVolume.disk", source.LocalQuotaVolume.Disk, "must be greater than 0"))
+		}
+	}
 	if numVolumes != 1 {
 		allErrs = append(allErrs, errs.NewFieldInvalid("", source, "exactly 1 volume type is required"))
 	}
@@ -96,11 +118,102 @@ func validateHostDir(hostDir *api.HostDir) errs.ValidationErrorList {
 	return allErrs
 }
 
+// validateHostDirs tests the host directories of the pod spec against the host directory
+// policy of the namespace: every directory must be allowed, and the ones of read-only
+// rules may only be mounted read-only.
+func validateHostDirs(namespace string, spec *api.PodSpec) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	policy := capabilities.Get().HostDirPolicy
+	if policy == nil {
+		return allErrs
+	}
+	readOnly := util.StringSet{}
+	for i := range spec.Volumes {
+		vol := &spec.Volumes[i]
+		if vol.Source == nil || vol.Source.HostDir == nil {
+			continue
+		}
+		rule := policy.Rule(namespace, vol.Source.HostDir.Path)
+		if rule == nil {
+			allErrs = append(allErrs, errs.NewFieldForbidden(fmt.Sprintf("volumes[%d].source.hostDirectory.path", i), vol.Source.HostDir.Path))
+		} else if rule.ReadOnly {
+			readOnly.Insert(vol.Name)
+		}
+	}
+	for i := range spec.Containers {
+		for j, mount := range spec.Containers[i].VolumeMounts {
+			if readOnly.Has(mount.Name) && !mount.ReadOnly {
+				allErrs = append(allErrs, errs.NewFieldForbidden(fmt.Sprintf("containers[%d].volumeMounts[%d].readOnly", i, j), mount.ReadOnly))
+			}
+		}
+	}
+	for i := range spec.InitContainers {
+		for j, mount := range spec.InitContainers[i].VolumeMounts {
+			if readOnly.Has(mount.Name) && !mount.ReadOnly {
+				allErrs = append(allErrs, errs.NewFieldForbidden(fmt.Sprintf("initContainers[%d].volumeMounts[%d].readOnly", i, j), mount.ReadOnly))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateNFS(nfs *api.NFS) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if nfs.Server == "" {
+		allErrs = append(allErrs, errs.NewFieldRequired("server", nfs.Server))
+	}
+	if nfs.Path == "" {
+		allErrs = append(allErrs, errs.NewFieldRequired("path", nfs.Path))
+	} else if !path.IsAbs(nfs.Path) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("path", nfs.Path, "must be an absolute path"))
+	}
+	return allErrs
+}
+
+func validateDownwardAPI(downwardAPI *api.DownwardAPIVolumeSource) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allPaths := util.StringSet{}
+	for i, item := range downwardAPI.Items {
+		iErrs := errs.ValidationErrorList{}
+		if item.Path == "" {
+			iErrs = append(iErrs, errs.NewFieldRequired("path", item.Path))
+		} else if strings.HasPrefix(item.Path, "/") || strings.Contains(item.Path, "..") {
+			iErrs = append(iErrs, errs.NewFieldInvalid("path", item.Path, "must be a relative path without '..'"))
+		} else if allPaths.Has(item.Path) {
+			iErrs = append(iErrs, errs.NewFieldDuplicate("path", item.Path))
+		} else {
+			allPaths.Insert(item.Path)
+		}
+		iErrs = append(iErrs, validateObjectFieldSelector(&item.FieldRef).Prefix("fieldRef")...)
+		allErrs = append(allErrs, iErrs.PrefixIndex(i).Prefix("items")...)
+	}
+	return allErrs
+}
+
+func validateObjectFieldSelector(selector *api.ObjectFieldSelector) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if selector.FieldPath == "" {
+		allErrs = append(allErrs, errs.NewFieldRequired("fieldPath", selector.FieldPath))
+	} else if !fieldpath.IsSupported(selector.FieldPath) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("fieldPath", selector.FieldPath))
+	}
+	return allErrs
+}
+
 func validateGitRepo(gitRepo *api.GitRepo) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 	if gitRepo.Repository == "" {
 		allErrs = append(allErrs, errs.NewFieldRequired("gitRepo.Repository", gitRepo.Repository))
 	}
+	if path.IsAbs(gitRepo.Directory) || strings.Contains(gitRepo.Directory, "..") {
+		allErrs = append(allErrs, errs.NewFieldInvalid("gitRepo.directory", gitRepo.Directory, "must be a relative path without '..'"))
+	}
+	if gitRepo.Depth < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("gitRepo.depth", gitRepo.Depth, "must not be negative"))
+	}
+	if gitRepo.RefreshSeconds < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("gitRepo.refreshSeconds", gitRepo.RefreshSeconds, "must not be negative"))
+	}
 	return allErrs
 }
 
@@ -166,6 +279,16 @@ func validateEnv(vars []api.EnvVar) errs.ValidationErrorList {
 		if !util.IsCIdentifier(ev.Name) {
 			vErrs = append(vErrs, errs.NewFieldInvalid("name", ev.Name, ""))
 		}
+		if ev.ValueFrom != nil {
+			if len(ev.Value) != 0 {
+				vErrs = append(vErrs, errs.NewFieldInvalid("value", ev.Value, "may not be set with valueFrom"))
+			}
+			if ev.ValueFrom.FieldRef == nil {
+				vErrs = append(vErrs, errs.NewFieldRequired("valueFrom.fieldRef", ev.ValueFrom.FieldRef))
+			} else {
+				vErrs = append(vErrs, validateObjectFieldSelector(ev.ValueFrom.FieldRef).Prefix("valueFrom.fieldRef")...)
+			}
+		}
 		allErrs = append(allErrs, vErrs.PrefixIndex(i)...)
 	}
 	return allErrs
@@ -265,6 +388,41 @@ func validateLifecycle(lifecycle *api.Lifecycle) errs.ValidationErrorList {
 	return allErrs
 }
 
+var supportedURISchemes = util.NewStringSet("", string(api.URISchemeHTTP), string(api.URISchemeHTTPS))
+
+func validateProbe(probe *api.LivenessProbe) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if probe.InitialDelaySeconds < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("initialDelaySeconds", probe.InitialDelaySeconds, "must be non-negative"))
+	}
+	if probe.TimeoutSeconds < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("timeoutSeconds", probe.TimeoutSeconds, "must be non-negative"))
+	}
+	if probe.PeriodSeconds < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("periodSeconds", probe.PeriodSeconds, "must be non-negative"))
+	}
+	if probe.SuccessThreshold < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("successThreshold", probe.SuccessThreshold, "must be non-negative"))
+	}
+	if probe.FailureThreshold < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("failureThreshold", probe.FailureThreshold, "must be non-negative"))
+	}
+	if probe.HTTPGet != nil {
+		httpErrs := errs.ValidationErrorList{}
+		if !supportedURISchemes.Has(string(probe.HTTPGet.Scheme)) {
+			httpErrs = append(httpErrs, errs.NewFieldNotSupported("scheme", probe.HTTPGet.Scheme))
+		}
+		for i, header := range probe.HTTPGet.HTTPHeaders {
+			if len(header.Name) == 0 {
+				headerErrs := errs.ValidationErrorList{errs.NewFieldRequired("name", header.Name)}
+				httpErrs = append(httpErrs, headerErrs.PrefixIndex(i).Prefix("httpHeaders")...)
+			}
+		}
+		allErrs = append(allErrs, httpErrs.Prefix("httpGet")...)
+	}
+	return allErrs
+}
+
 func validateContainers(containers []api.Container, volumes util.StringSet) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 
@@ -290,6 +448,12 @@ func validateContainers(containers []api.Container, volumes util.StringSet) errs
 		if ctr.Lifecycle != nil {
 			cErrs = append(cErrs, validateLifecycle(ctr.Lifecycle).Prefix("lifecycle")...)
 		}
+		if ctr.LivenessProbe != nil {
+			cErrs = append(cErrs, validateProbe(ctr.LivenessProbe).Prefix("livenessProbe")...)
+		}
+		if ctr.ReadinessProbe != nil {
+			cErrs = append(cErrs, validateProbe(ctr.ReadinessProbe).Prefix("readinessProbe")...)
+		}
 		cErrs = append(cErrs, validatePorts(ctr.Ports).Prefix("ports")...)
 		cErrs = append(cErrs, validateEnv(ctr.Env).Prefix("env")...)
 		cErrs = append(cErrs, validateVolumeMounts(ctr.VolumeMounts, volumes).Prefix("volumeMounts")...)
@@ -305,6 +469,36 @@ func validateContainers(containers []api.Container, volumes util.StringSet) errs
 	return allErrs
 }
 
+// validateInitContainers tests the init containers like the containers of a pod. Their names
+// must not be used by the containers, and they can't have probes or lifecycle hooks since they
+// run to completion.
+func validateInitContainers(initContainers, containers []api.Container, volumes util.StringSet) errs.ValidationErrorList {
+	allErrs := validateContainers(initContainers, volumes)
+
+	containerNames := util.StringSet{}
+	for i := range containers {
+		containerNames.Insert(containers[i].Name)
+	}
+	for i := range initContainers {
+		cErrs := errs.ValidationErrorList{}
+		ctr := &initContainers[i]
+		if containerNames.Has(ctr.Name) {
+			cErrs = append(cErrs, errs.NewFieldDuplicate("name", ctr.Name))
+		}
+		if ctr.Lifecycle != nil {
+			cErrs = append(cErrs, errs.NewFieldForbidden("lifecycle", ctr.Lifecycle))
+		}
+		if ctr.LivenessProbe != nil {
+			cErrs = append(cErrs, errs.NewFieldForbidden("livenessProbe", ctr.LivenessProbe))
+		}
+		if ctr.ReadinessProbe != nil {
+			cErrs = append(cErrs, errs.NewFieldForbidden("readinessProbe", ctr.ReadinessProbe))
+		}
+		allErrs = append(allErrs, cErrs.PrefixIndex(i)...)
+	}
+	return allErrs
+}
+
 var supportedManifestVersions = util.NewStringSet("v1beta1", "v1beta2")
 
 // ValidateManifest tests that the specified ContainerManifest has valid data.
@@ -323,7 +517,17 @@ func ValidateManifest(manifest *api.ContainerManifest) errs.ValidationErrorList
 	allVolumes, vErrs := validateVolumes(manifest.Volumes)
 	allErrs = append(allErrs, vErrs.Prefix("volumes")...)
 	allErrs = append(allErrs, validateContainers(manifest.Containers, allVolumes).Prefix("containers")...)
+	allErrs = append(allErrs, validateInitContainers(manifest.InitContainers, manifest.Containers, allVolumes).Prefix("initContainers")...)
 	allErrs = append(allErrs, validateRestartPolicy(&manifest.RestartPolicy).Prefix("restartPolicy")...)
+	allErrs = append(allErrs, validateTerminationGracePeriod(manifest.TerminationGracePeriodSeconds)...)
+	return allErrs
+}
+
+func validateTerminationGracePeriod(seconds *int64) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if seconds != nil && *seconds < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("terminationGracePeriodSeconds", *seconds, "must be non-negative"))
+	}
 	return allErrs
 }
 
@@ -363,6 +567,7 @@ func ValidatePod(pod *api.Pod) errs.ValidationErrorList {
 		allErrs = append(allErrs, errs.NewFieldInvalid("namespace", pod.Namespace, ""))
 	}
 	allErrs = append(allErrs, ValidatePodSpec(&pod.Spec).Prefix("spec")...)
+	allErrs = append(allErrs, validateHostDirs(pod.Namespace, &pod.Spec).Prefix("spec")...)
 	allErrs = append(allErrs, validateLabels(pod.Labels)...)
 	return allErrs
 }
@@ -377,8 +582,10 @@ func ValidatePodSpec(spec *api.PodSpec) errs.ValidationErrorList {
 	allVolumes, vErrs := validateVolumes(spec.Volumes)
 	allErrs = append(allErrs, vErrs.Prefix("volumes")...)
 	allErrs = append(allErrs, validateContainers(spec.Containers, allVolumes).Prefix("containers")...)
+	allErrs = append(allErrs, validateInitContainers(spec.InitContainers, spec.Containers, allVolumes).Prefix("initContainers")...)
 	allErrs = append(allErrs, validateRestartPolicy(&spec.RestartPolicy).Prefix("restartPolicy")...)
 	allErrs = append(allErrs, validateLabels(spec.NodeSelector).Prefix("nodeSelector")...)
+	allErrs = append(allErrs, validateTerminationGracePeriod(spec.TerminationGracePeriodSeconds)...)
 	return allErrs
 }
 
@@ -476,6 +683,9 @@ func ValidateReplicationController(controller *api.ReplicationController) errs.V
 		allErrs = append(allErrs, errs.NewFieldInvalid("namespace", controller.Namespace, ""))
 	}
 	allErrs = append(allErrs, ValidateReplicationControllerSpec(&controller.Spec).Prefix("spec")...)
+	if controller.Spec.Template != nil {
+		allErrs = append(allErrs, validateHostDirs(controller.Namespace, &controller.Spec.Template.Spec).Prefix("spec.template.spec")...)
+	}
 	allErrs = append(allErrs, validateLabels(controller.Labels)...)
 	return allErrs
 }
@@ -540,12 +750,14 @@ func ValidateBoundPod(pod *api.BoundPod) (errors []error) {
 		errors = append(errors, errs.NewFieldInvalid("namespace", pod.Namespace, ""))
 	}
 	containerManifest := &api.ContainerManifest{
-		Version:       "v1beta2",
-		ID:            pod.Name,
-		UUID:          pod.UID,
-		Containers:    pod.Spec.Containers,
-		Volumes:       pod.Spec.Volumes,
-		RestartPolicy: pod.Spec.RestartPolicy,
+		Version:                       "v1beta2",
+		ID:                            pod.Name,
+		UUID:                          pod.UID,
+		Containers:                    pod.Spec.Containers,
+		InitContainers:                pod.Spec.InitContainers,
+		Volumes:                       pod.Spec.Volumes,
+		RestartPolicy:                 pod.Spec.RestartPolicy,
+		TerminationGracePeriodSeconds: pod.Spec.TerminationGracePeriodSeconds,
 	}
 	if errs := ValidateManifest(containerManifest); len(errs) != 0 {
 		errors = append(errors, errs...)
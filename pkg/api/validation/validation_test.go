@@ -42,13 +42,21 @@ func TestValidateVolumes(t *testing.T) {
 		{Name: "abc-123", Source: &api.VolumeSource{HostDir: &api.HostDir{"/mnt/path3"}}},
 		{Name: "empty", Source: &api.VolumeSource{EmptyDir: &api.EmptyDir{}}},
 		{Name: "gcepd", Source: &api.VolumeSource{GCEPersistentDisk: &api.GCEPersistentDisk{"my-PD", "ext4", 1, false}}},
-		{Name: "gitrepo", Source: &api.VolumeSource{GitRepo: &api.GitRepo{"my-repo", "hashstring"}}},
+		{Name: "gitrepo", Source: &api.VolumeSource{GitRepo: &api.GitRepo{Repository: "my-repo", Revision: "hashstring"}}},
+		{Name: "downward", Source: &api.VolumeSource{DownwardAPI: &api.DownwardAPIVolumeSource{Items: []api.DownwardAPIVolumeFile{
+			{Path: "labels", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+			{Path: "net/address", FieldRef: api.ObjectFieldSelector{FieldPath: "res.network.address"}},
+		}}}},
+		{Name: "secret", Source: &api.VolumeSource{Secret: &api.SecretSource{SecretName: "my-secret"}}},
+		{Name: "nfs", Source: &api.VolumeSource{NFS: &api.NFS{Server: "nfs.example.com", Path: "/exports/data", ReadOnly: true}}},
+		{Name: "gitsync", Source: &api.VolumeSource{GitRepo: &api.GitRepo{Repository: "my-repo", SecretName: "creds", Directory: "docs/html", Depth: 1, Submodules: true, RefreshSeconds: 60}}},
+		{Name: "quota", Source: &api.VolumeSource{LocalQuotaVolume: &api.LocalQuotaVolume{Disk: 10}}},
 	}
 	names, errs := validateVolumes(successCase)
 	if len(errs) != 0 {
 		t.Errorf("expected success: %v", errs)
 	}
-	if len(names) != 6 || !names.HasAll("abc", "123", "abc-123", "empty", "gcepd", "gitrepo") {
+	if len(names) != 11 || !names.HasAll("abc", "123", "abc-123", "empty", "gcepd", "gitrepo", "gitsync", "downward", "secret", "nfs", "quota") {
 		t.Errorf("wrong names result: %v", names)
 	}
 
@@ -61,6 +69,18 @@ func TestValidateVolumes(t *testing.T) {
 		"name > 63 characters": {[]api.Volume{{Name: strings.Repeat("a", 64)}}, errors.ValidationErrorTypeInvalid, "[0].name"},
 		"name not a DNS label": {[]api.Volume{{Name: "a.b.c"}}, errors.ValidationErrorTypeInvalid, "[0].name"},
 		"name not unique":      {[]api.Volume{{Name: "abc"}, {Name: "abc"}}, errors.ValidationErrorTypeDuplicate, "[1].name"},
+		"downward path with ..": {[]api.Volume{{Name: "abc", Source: &api.VolumeSource{DownwardAPI: &api.DownwardAPIVolumeSource{Items: []api.DownwardAPIVolumeFile{
+			{Path: "../labels", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+		}}}}}, errors.ValidationErrorTypeInvalid, "[0].source.downwardAPI.items[0].path"},
+		"downward unsupported field": {[]api.Volume{{Name: "abc", Source: &api.VolumeSource{DownwardAPI: &api.DownwardAPIVolumeSource{Items: []api.DownwardAPIVolumeFile{
+			{Path: "host", FieldRef: api.ObjectFieldSelector{FieldPath: "spec.host"}},
+		}}}}}, errors.ValidationErrorTypeNotSupported, "[0].source.downwardAPI.items[0].fieldRef.fieldPath"},
+		"secret without name":   {[]api.Volume{{Name: "abc", Source: &api.VolumeSource{Secret: &api.SecretSource{}}}}, errors.ValidationErrorTypeInvalid, "[0].source.secret.secretName"},
+		"nfs without server":    {[]api.Volume{{Name: "abc", Source: &api.VolumeSource{NFS: &api.NFS{Path: "/exports"}}}}, errors.ValidationErrorTypeRequired, "[0].source.nfs.server"},
+		"nfs relative path":     {[]api.Volume{{Name: "abc", Source: &api.VolumeSource{NFS: &api.NFS{Server: "nfs", Path: "exports"}}}}, errors.ValidationErrorTypeInvalid, "[0].source.nfs.path"},
+		"quota without size":    {[]api.Volume{{Name: "abc", Source: &api.VolumeSource{LocalQuotaVolume: &api.LocalQuotaVolume{}}}}, errors.ValidationErrorTypeInvalid, "[0].source.localQuotaVolume.disk"},
+		"git directory with ..": {[]api.Volume{{Name: "abc", Source: &api.VolumeSource{GitRepo: &api.GitRepo{Repository: "my-repo", Directory: "docs/../.."}}}}, errors.ValidationErrorTypeInvalid, "[0].source.gitRepo.directory"},
+		"git negative refresh":  {[]api.Volume{{Name: "abc", Source: &api.VolumeSource{GitRepo: &api.GitRepo{Repository: "my-repo", RefreshSeconds: -1}}}}, errors.ValidationErrorTypeInvalid, "[0].source.gitRepo.refreshSeconds"},
 	}
 	for k, v := range errorCases {
 		_, errs := validateVolumes(v.V)
@@ -141,6 +161,8 @@ func TestValidateEnv(t *testing.T) {
 		{Name: "ABC", Value: "value"},
 		{Name: "AbC_123", Value: "value"},
 		{Name: "abc", Value: ""},
+		{Name: "POD_IP", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "res.network.address"}}},
+		{Name: "APP", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "metadata.labels['app']"}}},
 	}
 	if errs := validateEnv(successCase); len(errs) != 0 {
 		t.Errorf("expected success: %v", errs)
@@ -149,6 +171,9 @@ func TestValidateEnv(t *testing.T) {
 	errorCases := map[string][]api.EnvVar{
 		"zero-length name":        {{Name: ""}},
 		"name not a C identifier": {{Name: "a.b.c"}},
+		"value and valueFrom":     {{Name: "abc", Value: "value", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "metadata.name"}}}},
+		"missing fieldRef":        {{Name: "abc", ValueFrom: &api.EnvVarSource{}}},
+		"unsupported field":       {{Name: "abc", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "spec.host"}}}},
 	}
 	for k, v := range errorCases {
 		if errs := validateEnv(v); len(errs) == 0 {
@@ -201,6 +226,23 @@ func TestValidateContainers(t *testing.T) {
 			},
 		},
 		{Name: "abc-1234", Image: "image", Privileged: true},
+		{
+			Name:  "probe-123",
+			Image: "image",
+			LivenessProbe: &api.LivenessProbe{
+				HTTPGet: &api.HTTPGetAction{
+					Scheme:      api.URISchemeHTTPS,
+					HTTPHeaders: []api.HTTPHeader{{Name: "X-Probe", Value: "liveness"}},
+				},
+				TimeoutSeconds:   2,
+				PeriodSeconds:    5,
+				FailureThreshold: 3,
+			},
+			ReadinessProbe: &api.LivenessProbe{
+				TCPSocket:        &api.TCPSocketAction{},
+				SuccessThreshold: 2,
+			},
+		},
 	}
 	if errs := validateContainers(successCase, volumes); len(errs) != 0 {
 		t.Errorf("expected success: %v", errs)
@@ -262,6 +304,18 @@ func TestValidateContainers(t *testing.T) {
 		"privilege disabled": {
 			{Name: "abc", Image: "image", Privileged: true},
 		},
+		"invalid probe, negative period.": {
+			{Name: "abc", Image: "image", LivenessProbe: &api.LivenessProbe{PeriodSeconds: -1}},
+		},
+		"invalid probe, negative failure threshold.": {
+			{Name: "abc", Image: "image", ReadinessProbe: &api.LivenessProbe{FailureThreshold: -1}},
+		},
+		"invalid probe, unsupported scheme.": {
+			{Name: "abc", Image: "image", LivenessProbe: &api.LivenessProbe{HTTPGet: &api.HTTPGetAction{Scheme: "FTP"}}},
+		},
+		"invalid probe, no header name.": {
+			{Name: "abc", Image: "image", LivenessProbe: &api.LivenessProbe{HTTPGet: &api.HTTPGetAction{HTTPHeaders: []api.HTTPHeader{{Value: "v"}}}}},
+		},
 	}
 	for k, v := range errorCases {
 		if errs := validateContainers(v, volumes); len(errs) == 0 {
@@ -270,6 +324,37 @@ func TestValidateContainers(t *testing.T) {
 	}
 }
 
+func TestValidateInitContainers(t *testing.T) {
+	volumes := util.StringSet{}
+	containers := []api.Container{{Name: "app", Image: "image"}}
+
+	successCase := []api.Container{
+		{Name: "fetch", Image: "image"},
+		{Name: "migrate", Image: "image"},
+	}
+	if errs := validateInitContainers(successCase, containers, volumes); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	errorCases := map[string][]api.Container{
+		"zero-length image":     {{Name: "fetch", Image: ""}},
+		"name not unique":       {{Name: "fetch", Image: "image"}, {Name: "fetch", Image: "image"}},
+		"name of a container":   {{Name: "app", Image: "image"}},
+		"lifecycle not allowed": {{Name: "fetch", Image: "image", Lifecycle: &api.Lifecycle{PreStop: &api.Handler{Exec: &api.ExecAction{Command: []string{"true"}}}}}},
+		"liveness probe not allowed": {
+			{Name: "fetch", Image: "image", LivenessProbe: &api.LivenessProbe{TCPSocket: &api.TCPSocketAction{}}},
+		},
+		"readiness probe not allowed": {
+			{Name: "fetch", Image: "image", ReadinessProbe: &api.LivenessProbe{TCPSocket: &api.TCPSocketAction{}}},
+		},
+	}
+	for k, v := range errorCases {
+		if errs := validateInitContainers(v, containers, volumes); len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+	}
+}
+
 func TestValidateRestartPolicy(t *testing.T) {
 	successCases := []api.RestartPolicy{
 		{},
@@ -407,6 +492,11 @@ func TestValidatePod(t *testing.T) {
 	if len(errs) != 1 {
 		t.Errorf("Unexpected error list: %#v", errs)
 	}
+	gracePeriod := int64(-1)
+	errs = ValidatePodSpec(&api.PodSpec{TerminationGracePeriodSeconds: &gracePeriod})
+	if len(errs) != 1 || errs[0].(*errors.ValidationError).Field != "terminationGracePeriodSeconds" {
+		t.Errorf("Unexpected error list: %#v", errs)
+	}
 	errs = ValidatePod(&api.Pod{
 		ObjectMeta: api.ObjectMeta{
 			Name:      "foo",
@@ -428,6 +518,63 @@ func TestValidatePod(t *testing.T) {
 	}
 }
 
+func TestValidatePodHostDirs(t *testing.T) {
+	capabilities.SetForTests(capabilities.Capabilities{
+		HostDirPolicy: capabilities.HostDirPolicy{
+			capabilities.AllNamespaces: {{Prefix: "/data"}},
+			"infra":                    {{Prefix: "/var/log", ReadOnly: true}, {Prefix: "/var/log/infra"}},
+		},
+	})
+	defer capabilities.SetForTests(capabilities.Capabilities{})
+	newPod := func(namespace, hostPath string, readOnly bool) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: namespace},
+			Spec: api.PodSpec{
+				Volumes: []api.Volume{{Name: "host", Source: &api.VolumeSource{HostDir: &api.HostDir{Path: hostPath}}}},
+				Containers: []api.Container{{
+					Name:         "ctr",
+					Image:        "image",
+					VolumeMounts: []api.VolumeMount{{Name: "host", MountPath: "/host", ReadOnly: readOnly}},
+				}},
+				RestartPolicy: api.RestartPolicy{Always: &api.RestartPolicyAlways{}},
+			},
+		}
+	}
+
+	successCases := map[string]*api.Pod{
+		"allowed to all namespaces": newPod(api.NamespaceDefault, "/data/foo", false),
+		"read-only prefix":          newPod("infra", "/var/log", true),
+		"longer read-write prefix":  newPod("infra", "/var/log/infra/foo", false),
+	}
+	for k, pod := range successCases {
+		if errs := ValidatePod(pod); len(errs) != 0 {
+			t.Errorf("expected success for %s: %v", k, errs)
+		}
+	}
+
+	errorCases := map[string]struct {
+		pod   *api.Pod
+		field string
+	}{
+		"not allowed":           {newPod(api.NamespaceDefault, "/var/run/docker.sock", false), "spec.volumes[0].source.hostDirectory.path"},
+		"sibling of the prefix": {newPod(api.NamespaceDefault, "/database", false), "spec.volumes[0].source.hostDirectory.path"},
+		"escaping the prefix":   {newPod(api.NamespaceDefault, "/data/../etc", false), "spec.volumes[0].source.hostDirectory.path"},
+		"namespace rules only":  {newPod("infra", "/data/foo", false), "spec.volumes[0].source.hostDirectory.path"},
+		"read-only mounted rw":  {newPod("infra", "/var/log/foo", false), "spec.containers[0].volumeMounts[0].readOnly"},
+	}
+	for k, v := range errorCases {
+		errs := ValidatePod(v.pod)
+		if len(errs) != 1 {
+			t.Errorf("expected one failure for %s, got %v", k, errs)
+			continue
+		}
+		err := errs[0].(*errors.ValidationError)
+		if err.Type != errors.ValidationErrorTypeForbidden || err.Field != v.field {
+			t.Errorf("%s: expected a forbidden %s, got %v", k, v.field, err)
+		}
+	}
+}
+
 func TestValidatePodUpdate(t *testing.T) {
 	tests := []struct {
 		a       api.Pod
@@ -147,6 +147,14 @@ func registerResourceHandlers(ws *restful.WebService, version string, path strin
 		Param(ws.PathParameter("name", "name of the "+kind).DataType("string")).
 		Writes(versionedObject)) // on the response
 
+	if _, ok := storage.(SubresourceLocator); ok {
+		ws.Route(ws.GET(path + "/{name}/{subresource}").To(h).
+			Doc("read a subresource of the specified " + kind).
+			Operation("read" + kind + "Subresource").
+			Param(ws.PathParameter("name", "name of the "+kind).DataType("string")).
+			Param(ws.PathParameter("subresource", "name of the subresource").DataType("string")))
+	}
+
 	ws.Route(ws.PUT(path + "/{name}").To(h).
 		Doc("update the specified " + kind).
 		Operation("update" + kind).
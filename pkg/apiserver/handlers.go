@@ -58,11 +58,24 @@ func KindFromRequest(req http.Request) string {
 	return ""
 }
 
+// mutatingSubresources contains the subresources whose GET has side effects, such as running
+// a command in a container or connecting to a port of a pod.
+var mutatingSubresources = map[string]bool{
+	"exec":        true,
+	"attach":      true,
+	"portforward": true,
+}
+
 // IsReadOnlyReq() is true for any (or at least many) request which has no observable
 // side effects on state of apiserver (though there may be internal side effects like
 // caching and logging).
 func IsReadOnlyReq(req http.Request) bool {
 	if req.Method == "GET" {
+		// A GET of /api/<version>/<resource>/<name>/<subresource>.
+		parts := splitPath(req.URL.Path)
+		if len(parts) == 5 && parts[0] == "api" && !specialVerbs[parts[2]] && mutatingSubresources[parts[4]] {
+			return false
+		}
 		// TODO: add OPTIONS and HEAD if we ever support those.
 		return true
 	}
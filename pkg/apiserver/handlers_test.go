@@ -42,6 +42,28 @@ func TestRateLimit(t *testing.T) {
 	}
 }
 
+func TestIsReadOnlyReq(t *testing.T) {
+	table := map[string]bool{
+		"/api/v1beta1/pods":                 true,
+		"/api/v1beta1/pods/foo":             true,
+		"/api/v1beta1/pods/foo/stats":       true,
+		"/api/v1beta1/pods/foo/exec":        false,
+		"/api/v1beta1/pods/foo/attach":      false,
+		"/api/v1beta1/pods/foo/portforward": false,
+		"/api/v1beta1/pods/exec":            true,
+		"/api/v1beta1/proxy/pods/exec/":     true,
+	}
+	for path, expected := range table {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatalf("Couldn't make request: %v", err)
+		}
+		if IsReadOnlyReq(*req) != expected {
+			t.Errorf("expected GET %s read only to be %v", path, expected)
+		}
+	}
+}
+
 func TestReadOnly(t *testing.T) {
 	server := httptest.NewServer(ReadOnly(http.HandlerFunc(
 		func(w http.ResponseWriter, req *http.Request) {
@@ -17,6 +17,8 @@ limitations under the License.
 package apiserver
 
 import (
+	"net/http"
+
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
@@ -79,3 +81,10 @@ type Redirector interface {
 	// ResourceLocation should return the remote location of the given resource, or an error.
 	ResourceLocation(ctx api.Context, id string) (remoteLocation string, err error)
 }
+
+// SubresourceLocator knows where the subresources of a resource, e.g. the stats of a pod, are served.
+type SubresourceLocator interface {
+	// SubresourceLocation should return the URL serving the subresource of the given resource and the
+	// transport to reach it with, or an error. A nil transport means http.DefaultTransport.
+	SubresourceLocation(ctx api.Context, id, subresource string) (remoteLocation string, transport http.RoundTripper, err error)
+}
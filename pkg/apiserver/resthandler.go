@@ -113,6 +113,7 @@ func curry(f func(runtime.Object, *http.Request) error, req *http.Request) func(
 //   POST       /foo          create
 //   PUT        /foo/bar      update 'bar'
 //   DELETE     /foo/bar      delete 'bar'
+//   GET        /foo/bar/baz  proxy to the subresource 'baz' of 'bar'
 // Returns 404 if the method/pattern doesn't match one of these entries
 // The s accepts several query parameters:
 //    sync=[false|true] Synchronous request (only applies to create, update, delete operations)
@@ -165,6 +166,8 @@ func (h *RESTHandler) handleRESTStorage(parts []string, req *http.Request, w htt
 				return
 			}
 			writeJSON(http.StatusOK, h.codec, item, w)
+		case 3:
+			h.proxySubresource(api.WithNamespaceDefaultIfNone(ctx), parts, req, w, storage)
 		default:
 			notFound(w, req)
 		}
@@ -0,0 +1,134 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/httplog"
+)
+
+// proxySubresource forwards a request for the subresource parts[2] of the resource parts[1]
+// to the location returned by storage, which must be a SubresourceLocator.
+func (h *RESTHandler) proxySubresource(ctx api.Context, parts []string, req *http.Request, w http.ResponseWriter, storage RESTStorage) {
+	locator, ok := storage.(SubresourceLocator)
+	if !ok {
+		httplog.LogOf(req, w).Addf("'%v' has no subresources", parts[0])
+		notFound(w, req)
+		return
+	}
+	location, transport, err := locator.SubresourceLocation(ctx, parts[1], parts[2])
+	if err != nil {
+		errorJSON(err, h.codec, w)
+		return
+	}
+	destURL, err := url.Parse(location)
+	if err != nil {
+		errorJSON(err, h.codec, w)
+		return
+	}
+	destURL.RawQuery = req.URL.RawQuery
+	if isUpgradeRequest(req) {
+		if err := proxyUpgrade(destURL, transport, req, w); err != nil {
+			errorJSON(err, h.codec, w)
+		}
+		return
+	}
+	newReq, err := http.NewRequest(req.Method, destURL.String(), req.Body)
+	if err != nil {
+		errorJSON(err, h.codec, w)
+		return
+	}
+	newReq.Header = req.Header
+
+	proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: destURL.Scheme, Host: destURL.Host})
+	proxy.FlushInterval = 200 * time.Millisecond
+	if transport != nil {
+		proxy.Transport = transport
+	}
+	proxy.ServeHTTP(w, newReq)
+}
+
+// isUpgradeRequest returns true if req asks to switch protocols, e.g. to websockets.
+func isUpgradeRequest(req *http.Request) bool {
+	for _, value := range req.Header["Connection"] {
+		if strings.Contains(strings.ToLower(value), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyUpgrade forwards a request which switches protocols to destURL, then copies the traffic
+// both ways until either side closes its connection. The connections to https locations use the
+// TLS configuration of transport, e.g. its client certificate. An error is returned only if nothing
+// was written to w yet.
+func proxyUpgrade(destURL *url.URL, transport http.RoundTripper, req *http.Request, w http.ResponseWriter) error {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return fmt.Errorf("unable to upgrade the connection: %v does not support hijacking", w)
+	}
+	var backend net.Conn
+	var err error
+	if destURL.Scheme == "https" {
+		tlsConfig := &tls.Config{}
+		if t, ok := transport.(*http.Transport); ok && t.TLSClientConfig != nil {
+			tlsConfig = t.TLSClientConfig
+		}
+		backend, err = tls.Dial("tcp", destURL.Host, tlsConfig)
+	} else {
+		backend, err = net.Dial("tcp", destURL.Host)
+	}
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	newReq := *req
+	newReq.URL = destURL
+	newReq.Host = destURL.Host
+	newReq.RequestURI = ""
+	if err := newReq.Write(backend); err != nil {
+		return err
+	}
+
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(backend, buffered)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(client, backend)
+		done <- struct{}{}
+	}()
+	<-done
+	return nil
+}
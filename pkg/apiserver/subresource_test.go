@@ -0,0 +1,172 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"golang.org/x/net/websocket"
+)
+
+type SubresourceRESTStorage struct {
+	SimpleRESTStorage
+	location             string
+	transport            http.RoundTripper
+	err                  error
+	requestedID          string
+	requestedSubresource string
+	requestedNamespace   string
+}
+
+func (storage *SubresourceRESTStorage) SubresourceLocation(ctx api.Context, id, subresource string) (string, http.RoundTripper, error) {
+	storage.requestedID = id
+	storage.requestedSubresource = subresource
+	storage.requestedNamespace = api.Namespace(ctx)
+	return storage.location, storage.transport, storage.err
+}
+
+func TestProxySubresource(t *testing.T) {
+	var backendPath, backendQuery string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		backendPath = req.URL.Path
+		backendQuery = req.URL.RawQuery
+		w.Write([]byte("stats"))
+	}))
+	defer backend.Close()
+
+	storage := &SubresourceRESTStorage{location: backend.URL + "/stats/pod/other/cozy"}
+	handler := Handle(map[string]RESTStorage{
+		"foo": storage,
+	}, codec, "/prefix", testVersion, selfLinker)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/prefix/version/foo/cozy/stats?namespace=other")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "stats" {
+		t.Errorf("unexpected response: %d %q", resp.StatusCode, string(body))
+	}
+	if storage.requestedID != "cozy" || storage.requestedSubresource != "stats" || storage.requestedNamespace != "other" {
+		t.Errorf("unexpected request: %#v", storage)
+	}
+	if backendPath != "/stats/pod/other/cozy" || backendQuery != "namespace=other" {
+		t.Errorf("unexpected backend request: %s?%s", backendPath, backendQuery)
+	}
+
+	storage.err = errors.New("no such subresource")
+	resp, err = http.Get(server.URL + "/prefix/version/foo/cozy/bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected %d, got %d", http.StatusInternalServerError, resp.StatusCode)
+	}
+}
+
+func TestProxySubresourceUpgrade(t *testing.T) {
+	var backendPath, backendQuery string
+	backend := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		backendPath = ws.Request().URL.Path
+		backendQuery = ws.Request().URL.RawQuery
+		var message string
+		if err := websocket.Message.Receive(ws, &message); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		websocket.Message.Send(ws, strings.ToUpper(message))
+	}))
+	defer backend.Close()
+
+	storage := &SubresourceRESTStorage{location: backend.URL + "/exec/other/cozy"}
+	handler := Handle(map[string]RESTStorage{
+		"foo": storage,
+	}, codec, "/prefix", testVersion, selfLinker)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/prefix/version/foo/cozy/exec?command=ls", "", server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ws.Close()
+	if err := websocket.Message.Send(ws, "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var reply string
+	if err := websocket.Message.Receive(ws, &reply); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reply != "HELLO" {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if storage.requestedSubresource != "exec" {
+		t.Errorf("unexpected subresource %q", storage.requestedSubresource)
+	}
+	if backendPath != "/exec/other/cozy" || backendQuery != "command=ls" {
+		t.Errorf("unexpected backend request: %s?%s", backendPath, backendQuery)
+	}
+}
+
+func TestProxySubresourceUpgradeTLS(t *testing.T) {
+	backend := httptest.NewTLSServer(websocket.Handler(func(ws *websocket.Conn) {
+		var message string
+		if err := websocket.Message.Receive(ws, &message); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		websocket.Message.Send(ws, strings.ToUpper(message))
+	}))
+	defer backend.Close()
+
+	// The backend certificate is only trusted by the transport of the storage.
+	storage := &SubresourceRESTStorage{
+		location:  backend.URL + "/exec/other/cozy",
+		transport: backend.Client().Transport,
+	}
+	handler := Handle(map[string]RESTStorage{
+		"foo": storage,
+	}, codec, "/prefix", testVersion, selfLinker)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/prefix/version/foo/cozy/exec", "", server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ws.Close()
+	if err := websocket.Message.Send(ws, "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var reply string
+	if err := websocket.Message.Receive(ws, &reply); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reply != "HELLO" {
+		t.Errorf("unexpected reply %q", reply)
+	}
+}
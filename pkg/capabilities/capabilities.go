@@ -24,6 +24,8 @@ import (
 // For now these are global.  Eventually they may be per-user
 type Capabilities struct {
 	AllowPrivileged bool
+	// The host directories the pods may mount, all of them when nil.
+	HostDirPolicy HostDirPolicy
 }
 
 var once sync.Once
@@ -0,0 +1,74 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capabilities
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// AllNamespaces holds the host directory rules of the namespaces without rules of their own.
+const AllNamespaces = "*"
+
+// HostDirRule allows the pods to mount the host directories under Prefix.
+type HostDirRule struct {
+	Prefix string `json:"prefix"`
+	// The directories may only be mounted read-only.
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// HostDirPolicy holds the host directory rules of the namespaces, e.g. the policy
+// {"*": [{"prefix": "/data"}], "infra": [{"prefix": "/var/log", "readOnly": true}]}
+// allows /data to every namespace, and only /var/log read-only to the infra namespace.
+// A nil policy allows every host directory.
+type HostDirPolicy map[string][]HostDirRule
+
+// Rule returns the rule of the host directory in the namespace, which is the one of the
+// longest matching prefix, or nil when the policy forbids the directory.
+func (p HostDirPolicy) Rule(namespace, hostPath string) *HostDirRule {
+	rules, ok := p[namespace]
+	if !ok {
+		rules = p[AllNamespaces]
+	}
+	hostPath = path.Clean(hostPath)
+	var match *HostDirRule
+	for i := range rules {
+		prefix := path.Clean(rules[i].Prefix)
+		if hostPath != prefix && prefix != "/" && !strings.HasPrefix(hostPath, prefix+"/") {
+			continue
+		}
+		if match == nil || len(prefix) > len(path.Clean(match.Prefix)) {
+			match = &rules[i]
+		}
+	}
+	return match
+}
+
+// LoadHostDirPolicy reads the host directory policy from a JSON file.
+func LoadHostDirPolicy(file string) (HostDirPolicy, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	policy := HostDirPolicy{}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// BoundPodsInterface has methods to work with the pods bound to the hosts.
+type BoundPodsInterface interface {
+	BoundPods() BoundPodInterface
+}
+
+// BoundPodInterface has methods to read the pods bound to a host.
+type BoundPodInterface interface {
+	Get(host string) (*api.BoundPods, error)
+	Watch(host, resourceVersion string) (watch.Interface, error)
+}
+
+// boundPods implements BoundPodInterface
+type boundPods struct {
+	r *Client
+}
+
+// newBoundPods returns a boundPods
+func newBoundPods(c *Client) *boundPods {
+	return &boundPods{c}
+}
+
+// Get returns the pods bound to the host.
+func (c *boundPods) Get(host string) (*api.BoundPods, error) {
+	result := &api.BoundPods{}
+	err := c.r.Get().Path("boundPods").Path(host).Do().Into(result)
+	return result, err
+}
+
+// Watch watches the pods bound to the host, after the resourceVersion.
+func (c *boundPods) Watch(host, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().
+		Path("watch").
+		Path("boundPods").
+		Param("resourceVersion", resourceVersion).
+		SelectorParam("fields", labels.Set{"host": host}.AsSelector()).
+		Watch()
+}
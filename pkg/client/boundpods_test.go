@@ -0,0 +1,53 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func TestGetBoundPods(t *testing.T) {
+	c := &testClient{
+		Request: testRequest{Method: "GET", Path: "/boundPods/machine"},
+		Response: Response{
+			StatusCode: 200,
+			Body: &api.BoundPods{
+				ObjectMeta: api.ObjectMeta{Name: "machine"},
+				Host:       "machine",
+				Items:      []api.BoundPod{{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "ns"}}},
+			},
+		},
+	}
+	boundPods, err := c.Setup().BoundPods().Get("machine")
+	c.Validate(t, boundPods, err)
+}
+
+func TestWatchBoundPods(t *testing.T) {
+	c := &testClient{
+		Request: testRequest{
+			Method: "GET",
+			Path:   "/watch/boundPods",
+			Query:  url.Values{"resourceVersion": []string{"5"}, "fields": []string{"host=machine"}},
+		},
+		Response: Response{StatusCode: 200},
+	}
+	_, err := c.Setup().BoundPods().Watch("machine", "5")
+	c.Validate(t, nil, err)
+}
@@ -34,6 +34,9 @@ type Interface interface {
 	VersionInterface
 	MinionsInterface
 	EventNamespacer
+	SecretsNamespacer
+	BoundPodsInterface
+	MirrorPodsNamespacer
 }
 
 func (c *Client) ReplicationControllers(namespace string) ReplicationControllerInterface {
@@ -48,6 +51,18 @@ func (c *Client) Events(namespace string) EventInterface {
 	return newEvents(c, namespace)
 }
 
+func (c *Client) Secrets(namespace string) SecretInterface {
+	return newSecrets(c, namespace)
+}
+
+func (c *Client) BoundPods() BoundPodInterface {
+	return newBoundPods(c)
+}
+
+func (c *Client) MirrorPods(namespace string) MirrorPodInterface {
+	return newMirrorPods(c, namespace)
+}
+
 func (c *Client) Endpoints(namespace string) EndpointsInterface {
 	return newEndpoints(c, namespace)
 }
@@ -249,6 +249,22 @@ func TestGetPod(t *testing.T) {
 	c.Validate(t, receivedPod, err)
 }
 
+func TestGetPodStats(t *testing.T) {
+	body := `{"name": "foo.default.etcd", "stats": [{"memory": {"usage": 1024}}]}`
+	c := &testClient{
+		Request: testRequest{Method: "GET", Path: "/pods/foo/stats", Query: url.Values{"num_stats": []string{"5"}}},
+		Response: Response{
+			StatusCode: 200,
+			RawBody:    &body,
+		},
+	}
+	stats, err := c.Setup().Pods(api.NamespaceDefault).Stats("foo", 5)
+	c.ValidateCommon(t, err)
+	if stats == nil || stats.Name != "foo.default.etcd" || len(stats.Stats) != 1 || stats.Stats[0].Memory.Usage != 1024 {
+		t.Errorf("unexpected stats: %#v", stats)
+	}
+}
+
 func TestDeletePod(t *testing.T) {
 	c := &testClient{
 		Request:  testRequest{Method: "DELETE", Path: "/pods/foo"},
@@ -34,15 +34,19 @@ type FakeAction struct {
 // Fake implements Interface. Meant to be embedded into a struct to get a default
 // implementation. This makes faking out just the method you want to test easier.
 type Fake struct {
-	Actions       []FakeAction
-	PodsList      api.PodList
-	Ctrl          api.ReplicationController
-	ServiceList   api.ServiceList
-	EndpointsList api.EndpointsList
-	MinionsList   api.MinionList
-	EventsList    api.EventList
-	Err           error
-	Watch         watch.Interface
+	Actions        []FakeAction
+	PodsList       api.PodList
+	Ctrl           api.ReplicationController
+	ServiceList    api.ServiceList
+	EndpointsList  api.EndpointsList
+	MinionsList    api.MinionList
+	EventsList     api.EventList
+	SecretList     api.SecretList
+	Secret         api.Secret
+	BoundPodsList  api.BoundPods
+	MirrorPodsList api.PodList
+	Err            error
+	Watch          watch.Interface
 }
 
 func (c *Fake) ReplicationControllers(namespace string) ReplicationControllerInterface {
@@ -57,6 +61,18 @@ func (c *Fake) Events(namespace string) EventInterface {
 	return &FakeEvents{Fake: c}
 }
 
+func (c *Fake) Secrets(namespace string) SecretInterface {
+	return &FakeSecrets{Fake: c, Namespace: namespace}
+}
+
+func (c *Fake) BoundPods() BoundPodInterface {
+	return &FakeBoundPods{Fake: c}
+}
+
+func (c *Fake) MirrorPods(namespace string) MirrorPodInterface {
+	return &FakeMirrorPods{Fake: c, Namespace: namespace}
+}
+
 func (c *Fake) Endpoints(namespace string) EndpointsInterface {
 	return &FakeEndpoints{Fake: c, Namespace: namespace}
 }
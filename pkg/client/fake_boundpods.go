@@ -0,0 +1,38 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// FakeBoundPods implements BoundPodInterface. Meant to be embedded into a struct to get a default
+// implementation. This makes faking out just the method you want to test easier.
+type FakeBoundPods struct {
+	Fake *Fake
+}
+
+func (c *FakeBoundPods) Get(host string) (*api.BoundPods, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "get-boundPods", Value: host})
+	return api.Scheme.CopyOrDie(&c.Fake.BoundPodsList).(*api.BoundPods), c.Fake.Err
+}
+
+func (c *FakeBoundPods) Watch(host, resourceVersion string) (watch.Interface, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "watch-boundPods", Value: resourceVersion})
+	return c.Fake.Watch, c.Fake.Err
+}
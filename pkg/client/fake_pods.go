@@ -19,6 +19,7 @@ package client
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/google/cadvisor/info"
 )
 
 // FakePods implements PodsInterface. Meant to be embedded into a struct to get a default
@@ -52,3 +53,8 @@ func (c *FakePods) Update(pod *api.Pod) (*api.Pod, error) {
 	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "update-pod", Value: pod.Name})
 	return &api.Pod{}, nil
 }
+
+func (c *FakePods) Stats(name string, numStats int) (*info.ContainerInfo, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "get-pod-stats", Value: name})
+	return &info.ContainerInfo{}, nil
+}
@@ -36,6 +36,8 @@ var ErrPodInfoNotAvailable = errors.New("no pod info available")
 type KubeletClient interface {
 	KubeletHealthChecker
 	PodInfoGetter
+	NodeConditionsGetter
+	ConnectionInfoGetter
 }
 
 // KubeletHealthchecker is an interface for healthchecking kubelets
@@ -51,6 +53,18 @@ type PodInfoGetter interface {
 	GetPodInfo(host, podNamespace, podID string) (api.PodInfo, error)
 }
 
+// NodeConditionsGetter is an interface for things that can get the conditions of a node, such
+// as its memory and disk pressure, from its kubelet.
+type NodeConditionsGetter interface {
+	GetNodeConditions(host string) ([]api.NodeCondition, error)
+}
+
+// ConnectionInfoGetter is an interface for things that know how to connect to the kubelet of a host,
+// for the requests which are proxied to it.
+type ConnectionInfoGetter interface {
+	GetConnectionInfo(host string) (scheme string, port uint, transport http.RoundTripper, err error)
+}
+
 // HTTPKubeletClient is the default implementation of PodInfoGetter and KubeletHealthchecker, accesses the kubelet over HTTP.
 type HTTPKubeletClient struct {
 	Client      *http.Client
@@ -88,6 +102,19 @@ func (c *HTTPKubeletClient) url(host string) string {
 		net.JoinHostPort(host, strconv.FormatUint(uint64(c.Port), 10)))
 }
 
+// GetConnectionInfo returns the scheme, port and transport the client reaches the kubelet of host with.
+func (c *HTTPKubeletClient) GetConnectionInfo(host string) (string, uint, http.RoundTripper, error) {
+	scheme := "http"
+	if c.EnableHttps {
+		scheme = "https"
+	}
+	transport := c.Client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return scheme, c.Port, transport, nil
+}
+
 // GetPodInfo gets information about the specified pod.
 func (c *HTTPKubeletClient) GetPodInfo(host, podNamespace, podID string) (api.PodInfo, error) {
 	request, err := http.NewRequest(
@@ -126,6 +153,27 @@ func (c *HTTPKubeletClient) HealthCheck(host string) (health.Status, error) {
 	return health.DoHTTPCheck(fmt.Sprintf("%s/healthz", c.url(host)), c.Client)
 }
 
+// GetNodeConditions gets the conditions reported by the kubelet of the specified host.
+func (c *HTTPKubeletClient) GetNodeConditions(host string) ([]api.NodeCondition, error) {
+	response, err := c.Client.Get(fmt.Sprintf("%s/conditions", c.url(host)))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status getting the conditions of %s: %d", host, response.StatusCode)
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	conditions := []api.NodeCondition{}
+	if err := json.Unmarshal(body, &conditions); err != nil {
+		return nil, err
+	}
+	return conditions, nil
+}
+
 // FakeKubeletClient is a fake implementation of KubeletClient which returns an error
 // when called.  It is useful to pass to the master in a test configuration with
 // no kubelets.
@@ -139,3 +187,11 @@ func (c FakeKubeletClient) GetPodInfo(host, podNamespace string, podID string) (
 func (c FakeKubeletClient) HealthCheck(host string) (health.Status, error) {
 	return health.Unknown, errors.New("Not Implemented")
 }
+
+func (c FakeKubeletClient) GetNodeConditions(host string) ([]api.NodeCondition, error) {
+	return nil, errors.New("Not Implemented")
+}
+
+func (c FakeKubeletClient) GetConnectionInfo(host string) (string, uint, http.RoundTripper, error) {
+	return "", 0, nil, errors.New("Not Implemented")
+}
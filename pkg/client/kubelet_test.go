@@ -109,3 +109,40 @@ func TestHTTPKubeletClientNotFound(t *testing.T) {
 		t.Errorf("Expected %#v, Got %#v", ErrPodInfoNotAvailable, err)
 	}
 }
+
+func TestHTTPKubeletClientNodeConditions(t *testing.T) {
+	expected := []api.NodeCondition{{Kind: api.NodeDiskPressure, Status: api.ConditionTrue}}
+	body, err := json.Marshal(expected)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	fakeHandler := util.FakeHandler{
+		StatusCode:   200,
+		ResponseBody: string(body),
+	}
+	testServer := httptest.NewServer(&fakeHandler)
+	defer testServer.Close()
+
+	hostURL, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	parts := strings.Split(hostURL.Host, ":")
+	port, err := strconv.Atoi(parts[1])
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	c := &HTTPKubeletClient{
+		Client: http.DefaultClient,
+		Port:   uint(port),
+	}
+	conditions, err := c.GetNodeConditions(parts[0])
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(conditions) != 1 || conditions[0].Kind != api.NodeDiskPressure || conditions[0].Status != api.ConditionTrue {
+		t.Errorf("unexpected conditions: %#v", conditions)
+	}
+	fakeHandler.ValidateRequest(t, "/conditions", "GET", nil)
+}
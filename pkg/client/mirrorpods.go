@@ -0,0 +1,68 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+)
+
+// MirrorPodsNamespacer has methods to work with the mirror pods in a namespace
+type MirrorPodsNamespacer interface {
+	MirrorPods(namespace string) MirrorPodInterface
+}
+
+// MirrorPodInterface has methods the kubelets mirror the pods they run from their own
+// sources with. The mirror pods are read like the other pods.
+type MirrorPodInterface interface {
+	List(host string) (*api.PodList, error)
+	Create(pod *api.Pod) (*api.Pod, error)
+	Delete(name string) error
+}
+
+// mirrorPods implements MirrorPodInterface
+type mirrorPods struct {
+	r  *Client
+	ns string
+}
+
+// newMirrorPods returns a mirrorPods
+func newMirrorPods(c *Client, namespace string) *mirrorPods {
+	return &mirrorPods{
+		r:  c,
+		ns: namespace,
+	}
+}
+
+// List returns the mirror pods of the host.
+func (c *mirrorPods) List(host string) (result *api.PodList, err error) {
+	result = &api.PodList{}
+	err = c.r.Get().Namespace(c.ns).Path("mirrorPods").SelectorParam("fields", labels.Set{"host": host}.AsSelector()).Do().Into(result)
+	return
+}
+
+// Create creates the mirror of a pod the host of the pod runs.
+func (c *mirrorPods) Create(pod *api.Pod) (result *api.Pod, err error) {
+	result = &api.Pod{}
+	err = c.r.Post().Namespace(c.ns).Path("mirrorPods").Body(pod).Do().Into(result)
+	return
+}
+
+// Delete deletes a mirror pod.
+func (c *mirrorPods) Delete(name string) error {
+	return c.r.Delete().Namespace(c.ns).Path("mirrorPods").Path(name).Do().Error()
+}
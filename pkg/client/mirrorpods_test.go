@@ -0,0 +1,70 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func TestListMirrorPods(t *testing.T) {
+	c := &testClient{
+		Request: testRequest{
+			Method: "GET",
+			Path:   "/mirrorPods",
+			Query:  url.Values{"fields": []string{"host=machine"}},
+		},
+		Response: Response{
+			StatusCode: 200,
+			Body: &api.PodList{
+				Items: []api.Pod{{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "ns"}}},
+			},
+		},
+	}
+	pods, err := c.Setup().MirrorPods(api.NamespaceAll).List("machine")
+	c.Validate(t, pods, err)
+}
+
+func TestCreateMirrorPod(t *testing.T) {
+	requestPod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Annotations: map[string]string{api.ConfigSourceAnnotationKey: "file"},
+		},
+		Status: api.PodStatus{Host: "machine"},
+	}
+	c := &testClient{
+		Request: testRequest{Method: "POST", Path: "/mirrorPods", Body: requestPod},
+		Response: Response{
+			StatusCode: 200,
+			Body:       requestPod,
+		},
+	}
+	receivedPod, err := c.Setup().MirrorPods(api.NamespaceDefault).Create(requestPod)
+	c.Validate(t, receivedPod, err)
+}
+
+func TestDeleteMirrorPod(t *testing.T) {
+	c := &testClient{
+		Request:  testRequest{Method: "DELETE", Path: "/mirrorPods/foo"},
+		Response: Response{StatusCode: 200},
+	}
+	err := c.Setup().MirrorPods(api.NamespaceDefault).Delete("foo")
+	c.Validate(t, nil, err)
+}
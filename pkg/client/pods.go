@@ -17,10 +17,12 @@ limitations under the License.
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/google/cadvisor/info"
 )
 
 // PodsNamespacer has methods to work with Pod resources in a namespace
@@ -35,6 +37,7 @@ type PodInterface interface {
 	Delete(name string) error
 	Create(pod *api.Pod) (*api.Pod, error)
 	Update(pod *api.Pod) (*api.Pod, error)
+	Stats(name string, numStats int) (*info.ContainerInfo, error)
 }
 
 // pods implements PodsNamespacer interface
@@ -87,3 +90,15 @@ func (c *pods) Update(pod *api.Pod) (result *api.Pod, err error) {
 	err = c.r.Put().Namespace(c.ns).Path("pods").Path(pod.Name).Body(pod).Do().Into(result)
 	return
 }
+
+// Stats takes the name of the pod and returns the usage of the pod aggregated over its containers
+// by the kubelet, with at most numStats samples of its history. All samples are returned if numStats is 0.
+func (c *pods) Stats(name string, numStats int) (result *info.ContainerInfo, err error) {
+	body, err := c.r.Get().Namespace(c.ns).Path("pods").Path(name).Path("stats").UintParam("num_stats", uint64(numStats)).Do().Raw()
+	if err != nil {
+		return
+	}
+	result = &info.ContainerInfo{}
+	err = json.Unmarshal(body, result)
+	return
+}
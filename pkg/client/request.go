@@ -263,6 +263,11 @@ func (r *Request) Poller(poller PollFunc) *Request {
 }
 
 func (r *Request) finalURL() string {
+	return r.URL().String()
+}
+
+// URL returns the URL the request is sent to.
+func (r *Request) URL() *url.URL {
 	finalURL := *r.baseURL
 	finalURL.Path = r.path
 	query := url.Values{}
@@ -279,7 +284,7 @@ func (r *Request) finalURL() string {
 		}
 	}
 	finalURL.RawQuery = query.Encode()
-	return finalURL.String()
+	return &finalURL
 }
 
 // Watch attempts to begin watching the requested location.
@@ -0,0 +1,111 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// SecretsNamespacer has methods to work with Secret resources in a namespace
+type SecretsNamespacer interface {
+	Secrets(namespace string) SecretInterface
+}
+
+// SecretInterface has methods to work with Secret resources
+type SecretInterface interface {
+	Create(secret *api.Secret) (*api.Secret, error)
+	List(label, field labels.Selector) (*api.SecretList, error)
+	Get(name string) (*api.Secret, error)
+	Update(secret *api.Secret) (*api.Secret, error)
+	Delete(name string) error
+	Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error)
+}
+
+// secrets implements SecretInterface
+type secrets struct {
+	r  *Client
+	ns string
+}
+
+// newSecrets returns a secrets
+func newSecrets(c *Client, namespace string) *secrets {
+	return &secrets{c, namespace}
+}
+
+// Create creates a new secret.
+func (c *secrets) Create(secret *api.Secret) (*api.Secret, error) {
+	result := &api.Secret{}
+	err := c.r.Post().Namespace(c.ns).Path("secrets").Body(secret).Do().Into(result)
+	return result, err
+}
+
+// List takes label and field selectors, and returns the list of secrets that match them.
+func (c *secrets) List(label, field labels.Selector) (result *api.SecretList, err error) {
+	result = &api.SecretList{}
+	err = c.r.Get().
+		Namespace(c.ns).
+		Path("secrets").
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Do().
+		Into(result)
+	return
+}
+
+// Get returns the secret with the given name.
+func (c *secrets) Get(name string) (result *api.Secret, err error) {
+	result = &api.Secret{}
+	err = c.r.Get().Namespace(c.ns).Path("secrets").Path(name).Do().Into(result)
+	return
+}
+
+// Update updates an existing secret.
+func (c *secrets) Update(secret *api.Secret) (*api.Secret, error) {
+	result := &api.Secret{}
+	if len(secret.ResourceVersion) == 0 {
+		return nil, fmt.Errorf("invalid update object, missing resource version: %v", secret)
+	}
+	err := c.r.Put().
+		Namespace(c.ns).
+		Path("secrets").
+		Path(secret.Name).
+		Body(secret).
+		Do().
+		Into(result)
+	return result, err
+}
+
+// Delete deletes an existing secret.
+func (c *secrets) Delete(name string) error {
+	return c.r.Delete().Namespace(c.ns).Path("secrets").Path(name).Do().Error()
+}
+
+// Watch returns a watch.Interface that watches the requested secrets.
+func (c *secrets) Watch(label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().
+		Namespace(c.ns).
+		Path("watch").
+		Path("secrets").
+		Param("resourceVersion", resourceVersion).
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Watch()
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func TestGetSecret(t *testing.T) {
+	c := &testClient{
+		Request: testRequest{
+			Method: "GET",
+			Path:   "/secrets/foo",
+			Query:  url.Values{"namespace": []string{"ns"}},
+		},
+		Response: Response{
+			StatusCode: 200,
+			Body: &api.Secret{
+				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "ns"},
+				Data:       map[string][]byte{"token": []byte("secret")},
+			},
+		},
+	}
+	secret, err := c.Setup().Secrets("ns").Get("foo")
+	c.Validate(t, secret, err)
+}
+
+func TestCreateSecret(t *testing.T) {
+	requestSecret := &api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "ns"},
+		Data:       map[string][]byte{"token": []byte("secret")},
+	}
+	c := &testClient{
+		Request:  testRequest{Method: "POST", Path: "/secrets", Query: url.Values{"namespace": []string{"ns"}}, Body: requestSecret},
+		Response: Response{StatusCode: 200, Body: requestSecret},
+	}
+	secret, err := c.Setup().Secrets("ns").Create(requestSecret)
+	c.Validate(t, secret, err)
+}
+
+func TestDeleteSecret(t *testing.T) {
+	c := &testClient{
+		Request:  testRequest{Method: "DELETE", Path: "/secrets/foo", Query: url.Values{"namespace": []string{"ns"}}},
+		Response: Response{StatusCode: 200},
+	}
+	err := c.Setup().Secrets("ns").Delete("foo")
+	c.Validate(t, nil, err)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
+	"golang.org/x/net/websocket"
+)
+
+// StreamPod streams the standard streams of a command run in, or of the main process of, a container
+// of a pod through the "exec" or "attach" subresource of the pod. Only the non nil streams are
+// streamed and the terminal of the process is resized on every size received on resize. The container
+// may be omitted if the pod has a single container. It returns the error of the process.
+func StreamPod(config *Config, namespace, name, subresource, container string, command []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	query := url.Values{}
+	opts := remotecommand.Options{Stdin: stdin != nil, Stdout: stdout != nil, Stderr: stderr != nil, TTY: tty}
+	opts.AddToQuery(query)
+	if container != "" {
+		query.Set(remotecommand.ContainerParam, container)
+	}
+	for _, arg := range command {
+		query.Add(remotecommand.CommandParam, arg)
+	}
+	ws, err := dialPod(config, namespace, name, subresource, query)
+	if err != nil {
+		return err
+	}
+	defer ws.Close()
+	return remotecommand.Stream(ws, stdin, stdout, stderr, resize)
+}
+
+// PortForward opens a connection to port of a pod through the "portforward" subresource of the
+// pod. The connection is closed when either the caller or the pod closes it.
+func PortForward(config *Config, namespace, name string, port uint16) (io.ReadWriteCloser, error) {
+	query := url.Values{}
+	query.Set("port", strconv.Itoa(int(port)))
+	ws, err := dialPod(config, namespace, name, "portforward", query)
+	if err != nil {
+		return nil, err
+	}
+	ws.PayloadType = websocket.BinaryFrame
+	return ws, nil
+}
+
+// dialPod opens a websocket to a subresource of a pod with the given query parameters in addition
+// to the ones of the location of the pod.
+func dialPod(config *Config, namespace, name, subresource string, query url.Values) (*websocket.Conn, error) {
+	c := *config
+	if c.Prefix == "" {
+		c.Prefix = "/api"
+	}
+	client, err := RESTClientFor(&c)
+	if err != nil {
+		return nil, err
+	}
+	location := client.Get().Namespace(namespace).Path("pods").Path(name).Path(subresource).URL()
+	locationQuery := location.Query()
+	for key, values := range query {
+		for _, value := range values {
+			locationQuery.Add(key, value)
+		}
+	}
+	location.RawQuery = locationQuery.Encode()
+
+	wsConfig, err := webSocketConfigFor(&c, location)
+	if err != nil {
+		return nil, err
+	}
+	return websocket.DialConfig(wsConfig)
+}
+
+// webSocketConfigFor returns the configuration of a websocket to location which provides the
+// authentication and transport level security defined by config. A custom transport is not used.
+func webSocketConfigFor(config *Config, location *url.URL) (*websocket.Config, error) {
+	origin := url.URL{Scheme: location.Scheme, Host: location.Host}
+	wsLocation := *location
+	wsLocation.Scheme = "ws"
+	if location.Scheme == "https" {
+		wsLocation.Scheme = "wss"
+	}
+	wsConfig, err := websocket.NewConfig(wsLocation.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if location.Scheme == "https" {
+		tlsConfig := &tls.Config{InsecureSkipVerify: config.Insecure}
+		if config.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		if config.CAFile != "" {
+			data, err := ioutil.ReadFile(config.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			certPool := x509.NewCertPool()
+			certPool.AppendCertsFromPEM(data)
+			tlsConfig.RootCAs = certPool
+		}
+		wsConfig.TlsConfig = tlsConfig
+	}
+
+	hasBasicAuth := config.Username != "" || config.Password != ""
+	if hasBasicAuth && config.BearerToken != "" {
+		return nil, fmt.Errorf("username/password or bearer token may be set, but not both")
+	}
+	switch {
+	case config.BearerToken != "":
+		wsConfig.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	case hasBasicAuth:
+		credentials := base64.StdEncoding.EncodeToString([]byte(config.Username + ":" + config.Password))
+		wsConfig.Header.Set("Authorization", "Basic "+credentials)
+	}
+	return wsConfig, nil
+}
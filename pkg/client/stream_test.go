@@ -0,0 +1,114 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
+	"golang.org/x/net/websocket"
+)
+
+func TestStreamPod(t *testing.T) {
+	var query map[string][]string
+	var user, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1beta1/pods/foo/exec" {
+			t.Errorf("unexpected path %s", req.URL.Path)
+		}
+		query = req.URL.Query()
+		user, password, _ = req.BasicAuth()
+		opts := remotecommand.OptionsFromQuery(req.URL.Query())
+		remotecommand.NewWebSocketHandler(opts, func(stdin io.Reader, stdout, stderr io.Writer, resize <-chan remotecommand.TerminalSize) error {
+			data, _ := ioutil.ReadAll(stdin)
+			fmt.Fprint(stdout, strings.ToUpper(string(data)))
+			return nil
+		}).ServeHTTP(w, req)
+	}))
+	defer server.Close()
+
+	config := &Config{Host: server.URL, Version: "v1beta1", Username: "user", Password: "pass"}
+	var stdout bytes.Buffer
+	err := StreamPod(config, "other", "foo", "exec", "web", []string{"cat", "-"}, strings.NewReader("hello"), &stdout, nil, false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "HELLO" {
+		t.Errorf("unexpected stdout %q", stdout.String())
+	}
+	expected := map[string][]string{
+		"namespace": {"other"},
+		"container": {"web"},
+		"command":   {"cat", "-"},
+		"stdin":     {"1"},
+		"stdout":    {"1"},
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+	if user != "user" || password != "pass" {
+		t.Errorf("unexpected credentials %s:%s", user, password)
+	}
+}
+
+func TestPortForward(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1beta1/pods/foo/portforward" {
+			t.Errorf("unexpected path %s", req.URL.Path)
+		}
+		query = req.URL.Query()
+		websocket.Handler(func(ws *websocket.Conn) {
+			defer ws.Close()
+			ws.PayloadType = websocket.BinaryFrame
+			io.Copy(ws, ws)
+		}).ServeHTTP(w, req)
+	}))
+	defer server.Close()
+
+	config := &Config{Host: server.URL, Version: "v1beta1"}
+	conn, err := PortForward(config, "other", "foo", 8080)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(buf) != "ping" {
+		t.Errorf("unexpected data %q", string(buf))
+	}
+	expected := map[string][]string{
+		"namespace": {"other"},
+		"port":      {"8080"},
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+}
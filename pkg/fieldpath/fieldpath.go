@@ -0,0 +1,93 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+// fieldGetters maps the supported field paths to their values.
+var fieldGetters = map[string]func(pod *api.BoundPod) string{
+	"metadata.name":          func(pod *api.BoundPod) string { return pod.Name },
+	"metadata.namespace":     func(pod *api.BoundPod) string { return pod.Namespace },
+	"metadata.labels":        func(pod *api.BoundPod) string { return FormatMap(pod.Labels) },
+	"metadata.annotations":   func(pod *api.BoundPod) string { return FormatMap(pod.Annotations) },
+	"res.network.address":    func(pod *api.BoundPod) string { return pod.Res.Network.Address },
+	"res.network.gateway":    func(pod *api.BoundPod) string { return pod.Res.Network.Gateway },
+	"res.network.macAddress": func(pod *api.BoundPod) string { return pod.Res.Network.MacAddress },
+	"res.network.vlanID": func(pod *api.BoundPod) string {
+		if pod.Res.Network.VlanID == 0 {
+			return ""
+		}
+		return strconv.Itoa(pod.Res.Network.VlanID)
+	},
+	"res.cpuSet": func(pod *api.BoundPod) string { return pod.Res.CpuSet },
+}
+
+// labelPrefix and labelSuffix enclose the key of a single label, as in metadata.labels['app'].
+const (
+	labelPrefix = "metadata.labels['"
+	labelSuffix = "']"
+)
+
+// IsSupported returns true if fieldPath selects a field of bound pods.
+func IsSupported(fieldPath string) bool {
+	if _, found := fieldGetters[fieldPath]; found {
+		return true
+	}
+	_, found := labelKey(fieldPath)
+	return found
+}
+
+func labelKey(fieldPath string) (string, bool) {
+	if !strings.HasPrefix(fieldPath, labelPrefix) || !strings.HasSuffix(fieldPath, labelSuffix) {
+		return "", false
+	}
+	key := fieldPath[len(labelPrefix) : len(fieldPath)-len(labelSuffix)]
+	return key, key != ""
+}
+
+// ExtractFieldPathAsString returns the value of the field of pod selected by fieldPath. Labels
+// and annotations are formatted by FormatMap.
+func ExtractFieldPathAsString(pod *api.BoundPod, fieldPath string) (string, error) {
+	if getter, found := fieldGetters[fieldPath]; found {
+		return getter(pod), nil
+	}
+	if key, found := labelKey(fieldPath); found {
+		return pod.Labels[key], nil
+	}
+	return "", fmt.Errorf("unsupported field path %q", fieldPath)
+}
+
+// FormatMap formats a map as lines of key="value", sorted by key.
+func FormatMap(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s=%q", key, m[key]))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func TestExtractFieldPathAsString(t *testing.T) {
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "bar",
+			Labels:      map[string]string{"app": "web", "tier": "front"},
+			Annotations: map[string]string{"owner": "team \"a\""},
+		},
+		Res: api.BoundResource{
+			Network: api.Network{
+				Address:    "10.0.0.5/24",
+				Gateway:    "10.0.0.1",
+				MacAddress: "02:42:ac:11:00:05",
+				VlanID:     100,
+			},
+			CpuSet: "0-3",
+		},
+	}
+	tests := map[string]string{
+		"metadata.name":           "foo",
+		"metadata.namespace":      "bar",
+		"metadata.labels":         "app=\"web\"\ntier=\"front\"",
+		"metadata.labels['tier']": "front",
+		"metadata.labels['none']": "",
+		"metadata.annotations":    "owner=\"team \\\"a\\\"\"",
+		"res.network.address":     "10.0.0.5/24",
+		"res.network.gateway":     "10.0.0.1",
+		"res.network.macAddress":  "02:42:ac:11:00:05",
+		"res.network.vlanID":      "100",
+		"res.cpuSet":              "0-3",
+	}
+	for fieldPath, expected := range tests {
+		if !IsSupported(fieldPath) {
+			t.Errorf("expected %s to be supported", fieldPath)
+		}
+		value, err := ExtractFieldPathAsString(pod, fieldPath)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", fieldPath, err)
+		}
+		if value != expected {
+			t.Errorf("%s: expected %q, got %q", fieldPath, expected, value)
+		}
+	}
+
+	for _, fieldPath := range []string{"", "metadata.uid", "metadata.labels['']", "res.network", "spec.host"} {
+		if IsSupported(fieldPath) {
+			t.Errorf("expected %q to be unsupported", fieldPath)
+		}
+		if _, err := ExtractFieldPathAsString(pod, fieldPath); err == nil {
+			t.Errorf("expected an error for %q", fieldPath)
+		}
+	}
+}
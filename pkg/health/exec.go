@@ -19,6 +19,7 @@ package health
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/golang/glog"
@@ -27,7 +28,9 @@ import (
 const defaultHealthyOutput = "ok"
 
 type CommandRunner interface {
-	RunInContainer(podFullName, uuid, containerName string, cmd []string) ([]byte, error)
+	// RunInContainerWithTimeout runs cmd in the container and kills it once it has run
+	// for timeout.  A zero timeout means no timeout.
+	RunInContainerWithTimeout(podFullName, uuid, containerName string, cmd []string, timeout time.Duration) ([]byte, error)
 }
 
 type ExecHealthChecker struct {
@@ -42,7 +45,7 @@ func (e *ExecHealthChecker) HealthCheck(podFullName, podUUID string, currentStat
 	if container.LivenessProbe.Exec == nil {
 		return Unknown, fmt.Errorf("missing exec parameters")
 	}
-	data, err := e.runner.RunInContainer(podFullName, podUUID, container.Name, container.LivenessProbe.Exec.Command)
+	data, err := e.runner.RunInContainerWithTimeout(podFullName, podUUID, container.Name, container.LivenessProbe.Exec.Command, probeTimeout(container.LivenessProbe))
 	glog.V(1).Infof("container %s failed health check: %s", podFullName, string(data))
 	if err != nil {
 		return Unknown, err
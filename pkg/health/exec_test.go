@@ -20,18 +20,21 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 )
 
 type FakeExec struct {
-	cmd []string
-	out []byte
-	err error
+	cmd     []string
+	timeout time.Duration
+	out     []byte
+	err     error
 }
 
-func (f *FakeExec) RunInContainer(podFullName, uuid, container string, cmd []string) ([]byte, error) {
+func (f *FakeExec) RunInContainerWithTimeout(podFullName, uuid, container string, cmd []string, timeout time.Duration) ([]byte, error) {
 	f.cmd = cmd
+	f.timeout = timeout
 	return f.out, f.err
 }
 
@@ -82,3 +85,18 @@ func TestExec(t *testing.T) {
 		}
 	}
 }
+
+func TestExecTimeout(t *testing.T) {
+	fake := FakeExec{out: []byte("ok")}
+	checker := ExecHealthChecker{&fake}
+	probe := &api.LivenessProbe{
+		Exec:           &api.ExecAction{Command: []string{"ls"}},
+		TimeoutSeconds: 3,
+	}
+	if _, err := checker.HealthCheck("test", "", api.PodState{}, api.Container{LivenessProbe: probe}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if fake.timeout != 3*time.Second {
+		t.Errorf("expected the command to be killed after 3s, got %v", fake.timeout)
+	}
+}
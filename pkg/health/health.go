@@ -18,6 +18,7 @@ package health
 
 import (
 	"sync"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/golang/glog"
@@ -78,6 +79,7 @@ func (m *muxHealthChecker) findCheckerFor(probe *api.LivenessProbe) HealthChecke
 
 // HealthCheck delegates the health-checking of the container to one of the bundled implementations.
 // If there is no health checker that can check container it returns Unknown, nil.
+// The implementations give up once the TimeoutSeconds of the probe have passed.
 func (m *muxHealthChecker) HealthCheck(podFullName, podUUID string, currentState api.PodState, container api.Container) (Status, error) {
 	checker := m.findCheckerFor(container.LivenessProbe)
 	if checker == nil {
@@ -91,6 +93,11 @@ func (m *muxHealthChecker) CanCheck(probe *api.LivenessProbe) bool {
 	return m.findCheckerFor(probe) != nil
 }
 
+// probeTimeout returns how long a check of the probe may take, zero for no limit.
+func probeTimeout(probe *api.LivenessProbe) time.Duration {
+	return time.Duration(probe.TimeoutSeconds) * time.Second
+}
+
 // findPortByName is a helper function to look up a port in a container by name.
 // Returns the HostPort if found, -1 if not found.
 func findPortByName(container api.Container, portName string) int {
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -34,13 +35,23 @@ type HTTPGetInterface interface {
 	Get(url string) (*http.Response, error)
 }
 
+// HTTPDoInterface is an abstract interface for testability. It abstracts the interface of http.Client.Do.
+type HTTPDoInterface interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
 // HTTPHealthChecker is an implementation of HealthChecker which checks container health by sending HTTP Get requests.
 type HTTPHealthChecker struct {
-	client HTTPGetInterface
+	client *http.Client
 }
 
+// NewHTTPHealthChecker creates a HTTPHealthChecker which sends its requests through client.
+// HTTPS probes are only as strict about certificates as the TLS config of the client.
 func NewHTTPHealthChecker(client *http.Client) HealthChecker {
-	return &HTTPHealthChecker{client: &http.Client{}}
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &HTTPHealthChecker{client: client}
 }
 
 // getURLParts parses the components of the target URL.  For testability.
@@ -77,9 +88,12 @@ func getURLParts(currentState api.PodState, container api.Container) (string, in
 }
 
 // formatURL formats a URL from args.  For testability.
-func formatURL(host string, port int, path string) string {
+func formatURL(scheme api.URIScheme, host string, port int, path string) string {
+	if len(scheme) == 0 {
+		scheme = api.URISchemeHTTP
+	}
 	u := url.URL{
-		Scheme: "http",
+		Scheme: strings.ToLower(string(scheme)),
 		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
 		Path:   path,
 	}
@@ -96,12 +110,25 @@ func DoHTTPCheck(url string, client HTTPGetInterface) (Status, error) {
 	if err != nil {
 		return Unknown, err
 	}
+	return checkHTTPResponse(url, res), nil
+}
+
+// doHTTPRequestCheck is like DoHTTPCheck, for a prepared request.
+func doHTTPRequestCheck(req *http.Request, client HTTPDoInterface) (Status, error) {
+	res, err := client.Do(req)
+	if err != nil {
+		return Unknown, err
+	}
+	return checkHTTPResponse(req.URL.String(), res), nil
+}
+
+func checkHTTPResponse(url string, res *http.Response) Status {
 	defer res.Body.Close()
 	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusBadRequest {
-		return Healthy, nil
+		return Healthy
 	}
 	glog.V(1).Infof("Health check failed for %s, Response: %v", url, *res)
-	return Unhealthy, nil
+	return Unhealthy
 }
 
 // HealthCheck checks if the container is healthy by trying sending HTTP Get requests to the container.
@@ -110,7 +137,18 @@ func (h *HTTPHealthChecker) HealthCheck(podFullName, podUUID string, currentStat
 	if err != nil {
 		return Unknown, err
 	}
-	return DoHTTPCheck(formatURL(host, port, path), h.client)
+	params := container.LivenessProbe.HTTPGet
+	req, err := http.NewRequest("GET", formatURL(params.Scheme, host, port, path), nil)
+	if err != nil {
+		return Unknown, err
+	}
+	for _, header := range params.HTTPHeaders {
+		req.Header.Add(header.Name, header.Value)
+	}
+	// The copy shares the transport of the client, only the timeout is the probe's.
+	client := *h.client
+	client.Timeout = probeTimeout(container.LivenessProbe)
+	return doHTTPRequestCheck(req, &client)
 }
 
 func (h *HTTPHealthChecker) CanCheck(probe *api.LivenessProbe) bool {
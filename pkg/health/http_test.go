@@ -17,6 +17,7 @@ limitations under the License.
 package health
 
 import (
+	"crypto/tls"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -71,16 +72,18 @@ func TestGetURLParts(t *testing.T) {
 
 func TestFormatURL(t *testing.T) {
 	testCases := []struct {
+		scheme api.URIScheme
 		host   string
 		port   int
 		path   string
 		result string
 	}{
-		{"localhost", 93, "", "http://localhost:93"},
-		{"localhost", 93, "/path", "http://localhost:93/path"},
+		{"", "localhost", 93, "", "http://localhost:93"},
+		{api.URISchemeHTTP, "localhost", 93, "/path", "http://localhost:93/path"},
+		{api.URISchemeHTTPS, "localhost", 93, "/path", "https://localhost:93/path"},
 	}
 	for _, test := range testCases {
-		url := formatURL(test.host, test.port, test.path)
+		url := formatURL(test.scheme, test.host, test.port, test.path)
 		if url != test.result {
 			t.Errorf("Expected %s, got %s", test.result, url)
 		}
@@ -135,3 +138,85 @@ func TestHTTPHealthChecker(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPHealthCheckerSchemeAndHeaders(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Probe") != "liveness" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hc := &HTTPHealthChecker{
+		client: &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}},
+	}
+	testCases := []struct {
+		headers []api.HTTPHeader
+		health  Status
+	}{
+		{[]api.HTTPHeader{{Name: "X-Probe", Value: "liveness"}}, Healthy},
+		{nil, Unhealthy},
+	}
+	for _, test := range testCases {
+		container := api.Container{
+			LivenessProbe: &api.LivenessProbe{
+				HTTPGet: &api.HTTPGetAction{
+					Scheme:      api.URISchemeHTTPS,
+					Port:        util.NewIntOrStringFromString(port),
+					Host:        host,
+					HTTPHeaders: test.headers,
+				},
+			},
+		}
+		health, err := hc.HealthCheck("test", "", api.PodState{}, container)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if health != test.health {
+			t.Errorf("Expected %v, got %v", test.health, health)
+		}
+	}
+}
+
+func TestHTTPHealthCheckerTimeout(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hc := &HTTPHealthChecker{client: &http.Client{}}
+	container := api.Container{
+		LivenessProbe: &api.LivenessProbe{
+			HTTPGet:        &api.HTTPGetAction{Port: util.NewIntOrStringFromString(port), Host: host},
+			TimeoutSeconds: 1,
+		},
+	}
+	health, err := hc.HealthCheck("test", "", api.PodState{}, container)
+	if err == nil {
+		t.Errorf("Expected a timeout error")
+	}
+	if health != Unknown {
+		t.Errorf("Expected %v, got %v", Unknown, health)
+	}
+	if hc.client.Timeout != 0 {
+		t.Errorf("Expected the client to be left without a timeout, got %v", hc.client.Timeout)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -63,7 +64,13 @@ func getTCPAddrParts(currentState api.PodState, container api.Container) (string
 // If the socket fails to open, it returns Unhealthy.
 // This is exported because some other packages may want to do direct TCP checks.
 func DoTCPCheck(addr string) (Status, error) {
-	conn, err := net.Dial("tcp", addr)
+	return doTCPCheckWithTimeout(addr, 0)
+}
+
+// doTCPCheckWithTimeout is like DoTCPCheck, but gives up connecting after timeout.
+// A zero timeout means no timeout.
+func doTCPCheckWithTimeout(addr string, timeout time.Duration) (Status, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
 	if err != nil {
 		return Unhealthy, nil
 	}
@@ -79,7 +86,7 @@ func (t *TCPHealthChecker) HealthCheck(podFullName, podUUID string, currentState
 	if err != nil {
 		return Unknown, err
 	}
-	return DoTCPCheck(net.JoinHostPort(host, strconv.Itoa(port)))
+	return doTCPCheckWithTimeout(net.JoinHostPort(host, strconv.Itoa(port)), probeTimeout(container.LivenessProbe))
 }
 
 func (t *TCPHealthChecker) CanCheck(probe *api.LivenessProbe) bool {
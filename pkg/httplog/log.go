@@ -17,7 +17,9 @@ limitations under the License.
 package httplog
 
 import (
+	"bufio"
 	"fmt"
+	"net"
 	"net/http"
 	"runtime"
 	"time"
@@ -171,6 +173,17 @@ func (rl *respLogger) Flush() {
 	}
 }
 
+// Hijack implements http.Hijacker if the underlying http.Writer implements it.
+// Hijack is used to switch protocols, e.g. to websockets.
+func (rl *respLogger) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rl.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("unable to convert %v into http.Hijacker", rl.w)
+	}
+	rl.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
 // WriteHeader implements http.ResponseWriter.
 func (rl *respLogger) WriteHeader(status int) {
 	rl.status = status
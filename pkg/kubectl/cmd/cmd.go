@@ -100,6 +100,8 @@ Find more information at https://github.com/GoogleCloudPlatform/kubernetes.`,
 
 	cmds.AddCommand(NewCmdNamespace(out))
 	cmds.AddCommand(NewCmdLog(out))
+	cmds.AddCommand(NewCmdExec(out))
+	cmds.AddCommand(NewCmdPortForward(out))
 
 	if err := cmds.Execute(); err != nil {
 		os.Exit(1)
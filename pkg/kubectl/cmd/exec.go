@@ -0,0 +1,85 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdExec(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec <pod> [-c <container>] [-i] [-t] -- <command> [<arg>...]",
+		Short: "Run a command in a container of a pod",
+		Long: `Run a command in a container of a pod and stream its output.
+
+The container may be omitted if the pod has a single container. With -i the
+standard input is streamed to the command, with -t the command runs in a
+terminal, e.g. for an interactive shell.
+
+Examples:
+  $ kubectl exec 1234-56-7890 -- date
+  <print the date in the container of pod 1234-56-7890>
+
+  $ kubectl exec 1234-56-7890 -c web -i -t -- /bin/sh
+  <open a shell in the web container of pod 1234-56-7890>`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 2 {
+				usageError(cmd, "<pod> and <command> are required for exec")
+			}
+			namespace := getKubeNamespace(cmd)
+			config := GetKubeConfig(cmd)
+			err := streamPod(config, namespace, args[0], "exec", GetFlagString(cmd, "container"), args[1:],
+				GetFlagBool(cmd, "stdin"), GetFlagBool(cmd, "tty"), out)
+			checkErr(err)
+		},
+	}
+	cmd.Flags().StringP("container", "c", "", "Container name. Required if the pod has more than one container")
+	cmd.Flags().BoolP("stdin", "i", false, "Pass stdin to the command")
+	cmd.Flags().BoolP("tty", "t", false, "Run the command in a terminal. Requires stdin to be a terminal")
+	return cmd
+}
+
+// streamPod streams the standard streams of a command run in, or of the main process of, a container
+// of a pod with the terminal of kubectl.
+func streamPod(config *client.Config, namespace, pod, subresource, container string, command []string, stdin, tty bool, out io.Writer) error {
+	var in io.Reader
+	if stdin {
+		in = os.Stdin
+	}
+	var errOut io.Writer = os.Stderr
+	var resize <-chan remotecommand.TerminalSize
+	if tty {
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("-t requires stdin to be a terminal")
+		}
+		restore, err := setRawTerminal(os.Stdin)
+		if err != nil {
+			return err
+		}
+		defer restore()
+		resize = watchTerminalSize(os.Stdin)
+		// The terminal merges the stderr of the process into its stdout.
+		errOut = nil
+	}
+	return client.StreamPod(config, namespace, pod, subresource, container, command, in, out, errOut, tty, resize)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdPortForward(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "port-forward <pod> [<local port>:]<remote port>...",
+		Short: "Forward local ports to a pod",
+		Long: `Forward one or more local ports to a pod.
+
+Every connection to a local port is forwarded to the matching port in the
+network namespace of the pod. The local port defaults to the remote port, an
+empty local port picks a free one.
+
+Examples:
+  $ kubectl port-forward 1234-56-7890 8080 6060:6061
+  <forward localhost:8080 to port 8080 and localhost:6060 to port 6061 of pod 1234-56-7890>
+
+  $ kubectl port-forward 1234-56-7890 :80
+  <forward a free local port to port 80 of pod 1234-56-7890>`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 2 {
+				usageError(cmd, "<pod> and at least one port are required for port-forward")
+			}
+			ports, err := parsePortMappings(args[1:])
+			checkErr(err)
+			namespace := getKubeNamespace(cmd)
+			config := GetKubeConfig(cmd)
+			err = forwardPorts(config, namespace, args[0], GetFlagString(cmd, "address"), ports, out)
+			checkErr(err)
+		},
+	}
+	cmd.Flags().String("address", "127.0.0.1", "The local address on which to listen")
+	return cmd
+}
+
+// portMapping forwards a local port to a port of a pod.
+type portMapping struct {
+	Local  uint16
+	Remote uint16
+}
+
+// parsePortMappings parses mappings in the [<local port>:]<remote port> format.
+func parsePortMappings(specs []string) ([]portMapping, error) {
+	mappings := []portMapping{}
+	for _, spec := range specs {
+		local, remote := spec, spec
+		if i := strings.Index(spec, ":"); i >= 0 {
+			local, remote = spec[:i], spec[i+1:]
+		}
+		mapping := portMapping{}
+		if local != "" {
+			port, err := strconv.ParseUint(local, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid local port in %q", spec)
+			}
+			mapping.Local = uint16(port)
+		}
+		port, err := strconv.ParseUint(remote, 10, 16)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("invalid remote port in %q", spec)
+		}
+		mapping.Remote = uint16(port)
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+// forwardPorts listens on the local ports of the mappings and forwards every connection to a
+// port of a pod until a listener fails.
+func forwardPorts(config *client.Config, namespace, pod, address string, mappings []portMapping, out io.Writer) error {
+	listeners := []net.Listener{}
+	for _, mapping := range mappings {
+		listener, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(int(mapping.Local))))
+		if err != nil {
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, listener)
+		fmt.Fprintf(out, "Forwarding from %s -> %d\n", listener.Addr(), mapping.Remote)
+	}
+
+	errs := make(chan error, len(listeners))
+	var wg sync.WaitGroup
+	for i := range listeners {
+		wg.Add(1)
+		go func(listener net.Listener, port uint16) {
+			defer wg.Done()
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					errs <- err
+					return
+				}
+				go forwardConnection(config, namespace, pod, port, conn)
+			}
+		}(listeners[i], mappings[i].Remote)
+	}
+	err := <-errs
+	for _, listener := range listeners {
+		listener.Close()
+	}
+	wg.Wait()
+	return err
+}
+
+// forwardConnection copies the data of a local connection to and from a port of a pod until
+// either side closes.
+func forwardConnection(config *client.Config, namespace, pod string, port uint16, conn net.Conn) {
+	defer conn.Close()
+	remote, err := client.PortForward(config, namespace, pod, port)
+	if err != nil {
+		glog.Errorf("Unable to forward a connection to port %d of pod %s: %v", port, pod, err)
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, remote)
+		done <- struct{}{}
+	}()
+	<-done
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
+)
+
+// isTerminal returns true if f is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// stty runs stty on the terminal in.
+func stty(in *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = in
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// setRawTerminal puts the terminal in in raw mode and returns a function restoring its previous mode.
+func setRawTerminal(in *os.File) (func(), error) {
+	state, err := stty(in, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(in, "raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() {
+		stty(in, state)
+	}, nil
+}
+
+// terminalSize returns the size of the terminal in.
+func terminalSize(in *os.File) (remotecommand.TerminalSize, error) {
+	out, err := stty(in, "size")
+	if err != nil {
+		return remotecommand.TerminalSize{}, err
+	}
+	var size remotecommand.TerminalSize
+	if _, err := fmt.Sscanf(out, "%d %d", &size.Height, &size.Width); err != nil {
+		return remotecommand.TerminalSize{}, fmt.Errorf("unexpected terminal size %q: %v", out, err)
+	}
+	return size, nil
+}
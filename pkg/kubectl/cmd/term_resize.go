@@ -0,0 +1,51 @@
+// +build !windows
+
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
+)
+
+// watchTerminalSize sends the size of the terminal in on the returned channel, then again whenever
+// the terminal is resized.
+func watchTerminalSize(in *os.File) <-chan remotecommand.TerminalSize {
+	sizes := make(chan remotecommand.TerminalSize, 1)
+	if size, err := terminalSize(in); err == nil {
+		sizes <- size
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+	go func() {
+		for _ = range signals {
+			size, err := terminalSize(in)
+			if err != nil {
+				continue
+			}
+			select {
+			case sizes <- size:
+			default:
+			}
+		}
+	}()
+	return sizes
+}
@@ -95,6 +95,9 @@ func (d *PodDescriber) Describe(namespace, name string) (string, error) {
 		fmt.Fprintf(out, "Labels:\t%s\n", formatLabels(pod.Labels))
 		fmt.Fprintf(out, "Status:\t%s\n", string(pod.Status.Phase))
 		fmt.Fprintf(out, "Replication Controllers:\t%s\n", getReplicationControllersForLabels(rc, labels.Set(pod.Labels)))
+		if len(spec.InitContainers) > 0 {
+			describeInitContainers(spec, pod.Status.Info, out)
+		}
 		if events != nil {
 			describeEvents(events, out)
 		}
@@ -102,6 +105,24 @@ func (d *PodDescriber) Describe(namespace, name string) (string, error) {
 	})
 }
 
+// describeInitContainers writes the state of the init containers of a pod in the order they run.
+func describeInitContainers(spec *api.PodSpec, info api.PodInfo, w io.Writer) {
+	fmt.Fprint(w, "Init Containers:\nName\tImage\tState\tRestarts\n")
+	for _, container := range spec.InitContainers {
+		status := info[container.Name]
+		state := "Waiting"
+		switch {
+		case status.State.Running != nil:
+			state = "Running"
+		case status.State.Termination != nil:
+			state = fmt.Sprintf("Terminated (exit code %d)", status.State.Termination.ExitCode)
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+			state = fmt.Sprintf("Waiting (%s)", status.State.Waiting.Reason)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", container.Name, container.Image, state, status.RestartCount)
+	}
+}
+
 // ReplicationControllerDescriber generates information about a replication controller
 // and the pods it has created.
 type ReplicationControllerDescriber struct {
@@ -17,9 +17,11 @@ limitations under the License.
 package kubectl
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 )
 
@@ -43,6 +45,34 @@ func TestDescribePod(t *testing.T) {
 	}
 }
 
+func TestDescribeInitContainers(t *testing.T) {
+	spec := &api.PodSpec{
+		InitContainers: []api.Container{
+			{Name: "fetch", Image: "busybox"},
+			{Name: "migrate", Image: "migrator"},
+			{Name: "warm", Image: "warmer"},
+		},
+	}
+	info := api.PodInfo{
+		"fetch": api.ContainerStatus{
+			State: api.ContainerState{Termination: &api.ContainerStateTerminated{ExitCode: 0}},
+		},
+		"migrate": api.ContainerStatus{
+			State:        api.ContainerState{Running: &api.ContainerStateRunning{}},
+			RestartCount: 2,
+		},
+	}
+	out := &bytes.Buffer{}
+	describeInitContainers(spec, info, out)
+	expected := "Init Containers:\nName\tImage\tState\tRestarts\n" +
+		"fetch\tbusybox\tTerminated (exit code 0)\t0\n" +
+		"migrate\tmigrator\tRunning\t2\n" +
+		"warm\twarmer\tWaiting\t0\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
 func TestDescribeService(t *testing.T) {
 	fake := &client.Fake{}
 	c := &describeClient{T: t, Namespace: "foo", Fake: fake}
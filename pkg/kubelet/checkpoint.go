@@ -0,0 +1,63 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+)
+
+// checkpointFile is the file under the root directory the last pods of the kubelet
+// are saved to, so that a restarted kubelet keeps running them while their sources
+// are unavailable.
+const checkpointFile = "boundpods.checkpoint"
+
+// writeCheckpoint saves the pods, with their source and bound resources, replacing
+// the previous checkpoint atomically.
+func writeCheckpoint(rootDirectory string, pods []api.BoundPod) error {
+	data, err := latest.Codec.Encode(&api.BoundPods{Items: pods})
+	if err != nil {
+		return err
+	}
+	file := path.Join(rootDirectory, checkpointFile)
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// LoadCheckpoint returns the pods saved under the root directory, or none if the
+// kubelet never saved them.
+func LoadCheckpoint(rootDirectory string) ([]api.BoundPod, error) {
+	data, err := ioutil.ReadFile(path.Join(rootDirectory, checkpointFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	boundPods := &api.BoundPods{}
+	if err := latest.Codec.DecodeInto(data, boundPods); err != nil {
+		return nil, err
+	}
+	return boundPods.Items, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func TestCheckpoint(t *testing.T) {
+	rootDirectory, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(rootDirectory)
+
+	pods, err := LoadCheckpoint(rootDirectory)
+	if err != nil || pods != nil {
+		t.Errorf("Expected no pods without a checkpoint, got %#v, %v", pods, err)
+	}
+
+	expected := []api.BoundPod{
+		{
+			ObjectMeta: api.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "new",
+				UID:         "12345678",
+				Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{{Name: "bar", Image: "test"}},
+			},
+			Res: api.BoundResource{
+				Network: api.Network{Mode: api.PodNetworkModeBridge, Bridge: "br0", Address: "10.0.0.2/24"},
+				CpuSet:  "0-3",
+			},
+		},
+	}
+	if err := writeCheckpoint(rootDirectory, expected); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pods, err = LoadCheckpoint(rootDirectory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 1 {
+		t.Fatalf("Expected 1 pod, got %#v", pods)
+	}
+	pod := pods[0]
+	if pod.Name != "foo" || pod.Namespace != "new" || pod.UID != "12345678" || GetPodFullName(&pod) != "foo.new.etcd" {
+		t.Errorf("Unexpected pod: %#v", pod.ObjectMeta)
+	}
+	if !reflect.DeepEqual(expected[0].Res, pod.Res) {
+		t.Errorf("Expected %#v, got %#v", expected[0].Res, pod.Res)
+	}
+	if len(pod.Spec.Containers) != 1 || pod.Spec.Containers[0].Image != "test" {
+		t.Errorf("Unexpected containers: %#v", pod.Spec.Containers)
+	}
+}
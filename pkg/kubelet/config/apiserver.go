@@ -0,0 +1,97 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Reads the pod configuration from the Kubernetes apiserver.
+package config
+
+import (
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+)
+
+type sourceApiserver struct {
+	host    string
+	client  client.BoundPodsInterface
+	updates chan<- interface{}
+	// The resource version the watch resumes from, "" to list the pods first.
+	resourceVersion string
+}
+
+// NewSourceApiserver creates a config source that lists and watches the pods bound to
+// the host through the apiserver.
+func NewSourceApiserver(c client.BoundPodsInterface, host string, updates chan<- interface{}) {
+	source := &sourceApiserver{
+		host:    host,
+		client:  c,
+		updates: updates,
+	}
+	glog.V(1).Infof("Watching the apiserver for the pods bound to %s", host)
+	go util.Forever(source.run, time.Second)
+}
+
+func (s *sourceApiserver) run() {
+	if s.resourceVersion == "" {
+		boundPods, err := s.client.BoundPods().Get(s.host)
+		if err != nil {
+			glog.Errorf("Failed to list the bound pods of %s: %v", s.host, err)
+			return
+		}
+		s.send(boundPods)
+	}
+	watching, err := s.client.BoundPods().Watch(s.host, s.resourceVersion)
+	if err != nil {
+		glog.Errorf("Failed to watch the bound pods of %s: %v", s.host, err)
+		return
+	}
+	defer watching.Stop()
+	for event := range watching.ResultChan() {
+		switch event.Type {
+		case watch.Error:
+			// The watch fails once its resource version is too old, and resumes after a new list.
+			glog.Infof("Watch closed (%#v). Relisting.", event.Object)
+			s.resourceVersion = ""
+			return
+		case watch.Deleted:
+			boundPods, ok := event.Object.(*api.BoundPods)
+			if !ok {
+				glog.Errorf("Unexpected object from the bound pods watch: %#v", event.Object)
+				continue
+			}
+			s.send(&api.BoundPods{ObjectMeta: api.ObjectMeta{ResourceVersion: boundPods.ResourceVersion}})
+		default:
+			boundPods, ok := event.Object.(*api.BoundPods)
+			if !ok {
+				glog.Errorf("Unexpected object from the bound pods watch: %#v", event.Object)
+				continue
+			}
+			s.send(boundPods)
+		}
+	}
+}
+
+// send sends the pods to the kubelet, and resumes the watch after their version.
+func (s *sourceApiserver) send(boundPods *api.BoundPods) {
+	s.resourceVersion = boundPods.ResourceVersion
+	pods := boundPodsToPods(boundPods)
+	glog.V(4).Infof("Received state from the apiserver: %+v", pods)
+	s.updates <- kubelet.PodUpdate{pods, kubelet.SET}
+}
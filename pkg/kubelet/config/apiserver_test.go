@@ -0,0 +1,99 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+func TestApiserverListAndWatch(t *testing.T) {
+	fakeWatch := watch.NewFake()
+	fakeClient := &client.Fake{
+		BoundPodsList: api.BoundPods{
+			ObjectMeta: api.ObjectMeta{ResourceVersion: "1"},
+			Items:      []api.BoundPod{{ObjectMeta: api.ObjectMeta{Name: "foo"}}},
+		},
+		Watch: fakeWatch,
+	}
+	ch := make(chan interface{}, 10)
+	source := &sourceApiserver{host: "machine", client: fakeClient, updates: ch}
+
+	done := make(chan struct{})
+	go func() {
+		source.run()
+		close(done)
+	}()
+
+	expectUpdate := func(pods ...api.BoundPod) {
+		if pods == nil {
+			pods = []api.BoundPod{}
+		}
+		update := (<-ch).(kubelet.PodUpdate)
+		expected := kubelet.PodUpdate{pods, kubelet.SET}
+		if !reflect.DeepEqual(expected, update) {
+			t.Errorf("Expected %#v, got %#v", expected, update)
+		}
+	}
+	expectUpdate(api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault}})
+
+	fakeWatch.Modify(&api.BoundPods{
+		ObjectMeta: api.ObjectMeta{ResourceVersion: "2"},
+		Items:      []api.BoundPod{{ObjectMeta: api.ObjectMeta{Name: "bar", Namespace: "ns"}}},
+	})
+	expectUpdate(api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "bar", Namespace: "ns"}})
+
+	fakeWatch.Delete(&api.BoundPods{ObjectMeta: api.ObjectMeta{ResourceVersion: "3"}})
+	expectUpdate()
+
+	fakeWatch.Error(&api.Status{Status: api.StatusFailure, Message: "too old resource version"})
+	<-done
+	if source.resourceVersion != "" {
+		t.Errorf("Expected a relist after a watch error, got resource version %q", source.resourceVersion)
+	}
+
+	expectedActions := []client.FakeAction{
+		{Action: "get-boundPods", Value: "machine"},
+		{Action: "watch-boundPods", Value: "1"},
+	}
+	if !reflect.DeepEqual(expectedActions, fakeClient.Actions) {
+		t.Errorf("Expected %#v, got %#v", expectedActions, fakeClient.Actions)
+	}
+}
+
+func TestApiserverResumesWatch(t *testing.T) {
+	fakeWatch := watch.NewFake()
+	fakeClient := &client.Fake{Watch: fakeWatch}
+	ch := make(chan interface{}, 10)
+	source := &sourceApiserver{host: "machine", client: fakeClient, updates: ch, resourceVersion: "5"}
+
+	fakeWatch.Stop()
+	source.run()
+
+	expectedActions := []client.FakeAction{{Action: "watch-boundPods", Value: "5"}}
+	if !reflect.DeepEqual(expectedActions, fakeClient.Actions) {
+		t.Errorf("Expected %#v, got %#v", expectedActions, fakeClient.Actions)
+	}
+	if len(ch) != 0 {
+		t.Errorf("Unexpected updates: %d", len(ch))
+	}
+}
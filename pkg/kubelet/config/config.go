@@ -53,6 +53,11 @@ type PodConfig struct {
 
 	// the channel of denormalized changes passed to listeners
 	updates chan kubelet.PodUpdate
+
+	// the names of the sources, and the pods restored for them from a checkpoint
+	sourcesLock sync.Mutex
+	sources     util.StringSet
+	checkpoint  map[string][]api.BoundPod
 }
 
 // NewPodConfig creates an object that can merge many configuration sources into a stream
@@ -64,16 +69,51 @@ func NewPodConfig(mode PodConfigNotificationMode) *PodConfig {
 		pods:    storage,
 		mux:     config.NewMux(storage),
 		updates: updates,
+		sources: util.StringSet{},
 	}
 	return podConfig
 }
 
 // Channel creates or returns a config source channel.  The channel
-// only accepts PodUpdates
+// only accepts PodUpdates. The pods restored for the source are its state
+// until it sends one.
 func (c *PodConfig) Channel(source string) chan<- interface{} {
+	c.sourcesLock.Lock()
+	defer c.sourcesLock.Unlock()
+	if !c.sources.Has(source) {
+		c.sources.Insert(source)
+		if pods, found := c.checkpoint[source]; found {
+			glog.Infof("Restored %d pods of source %s from the checkpoint", len(pods), source)
+			c.pods.restore(source, pods)
+			// The update channel is only read once the kubelet runs.
+			go c.pods.Sync()
+		}
+	}
 	return c.mux.Channel(source)
 }
 
+// Restore sets the pods of a checkpoint as the provisional state of the sources
+// they came from, so that the containers of a source which is unavailable when the
+// kubelet starts are not killed. It must be called before the sources are created;
+// the pods of the sources which are never created are dropped.
+func (c *PodConfig) Restore(pods []api.BoundPod) {
+	c.sourcesLock.Lock()
+	defer c.sourcesLock.Unlock()
+	c.checkpoint = make(map[string][]api.BoundPod)
+	for _, pod := range pods {
+		source := pod.Annotations[kubelet.ConfigSourceAnnotationKey]
+		c.checkpoint[source] = append(c.checkpoint[source], pod)
+	}
+}
+
+// SeenAllSources returns true once every source has sent its state, or had it
+// restored from the checkpoint.
+func (c *PodConfig) SeenAllSources() bool {
+	c.sourcesLock.Lock()
+	defer c.sourcesLock.Unlock()
+	return c.pods.seenSources(c.sources.List()...)
+}
+
 // Updates returns a channel of updates to the configuration, properly denormalized.
 func (c *PodConfig) Updates() <-chan kubelet.PodUpdate {
 	return c.updates
@@ -93,6 +133,8 @@ type podStorage struct {
 	// map of source name to pod name to pod reference
 	pods map[string]map[string]*api.BoundPod
 	mode PodConfigNotificationMode
+	// the sources which have set their pods at least once
+	sourcesSeen util.StringSet
 
 	// ensures that updates are delivered in strict order
 	// on the updates channel
@@ -105,9 +147,10 @@ type podStorage struct {
 // TODO: allow initialization of the current state of the store with snapshotted version.
 func newPodStorage(updates chan<- kubelet.PodUpdate, mode PodConfigNotificationMode) *podStorage {
 	return &podStorage{
-		pods:    make(map[string]map[string]*api.BoundPod),
-		mode:    mode,
-		updates: updates,
+		pods:        make(map[string]map[string]*api.BoundPod),
+		mode:        mode,
+		sourcesSeen: util.StringSet{},
+		updates:     updates,
 	}
 }
 
@@ -251,9 +294,24 @@ func (s *podStorage) merge(source string, change interface{}) (adds, updates, de
 	}
 
 	s.pods[source] = pods
+	s.sourcesSeen.Insert(source)
 	return adds, updates, deletes
 }
 
+// restore sets the pods of a source without notifying the listeners.
+func (s *podStorage) restore(source string, pods []api.BoundPod) {
+	s.updateLock.Lock()
+	defer s.updateLock.Unlock()
+	s.merge(source, kubelet.PodUpdate{pods, kubelet.SET})
+}
+
+// seenSources returns true if all the sources have set their pods.
+func (s *podStorage) seenSources(sources ...string) bool {
+	s.podLock.RLock()
+	defer s.podLock.RUnlock()
+	return s.sourcesSeen.HasAll(sources...)
+}
+
 func filterInvalidPods(pods []api.BoundPod, source string) (filtered []*api.BoundPod) {
 	names := util.StringSet{}
 	for i := range pods {
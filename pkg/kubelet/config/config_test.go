@@ -240,3 +240,33 @@ func TestNewPodAddedUpdatedSet(t *testing.T) {
 		CreatePodUpdate(kubelet.ADD, CreateValidPod("foo4", "new", "test")),
 		CreatePodUpdate(kubelet.UPDATE, pod))
 }
+
+func TestRestoreCheckpoint(t *testing.T) {
+	config := NewPodConfig(PodConfigNotificationSnapshotAndUpdates)
+	ch := config.Updates()
+	config.Restore([]api.BoundPod{CreateValidPod("foo", "new", "test"), CreateValidPod("bar", "new", "gone")})
+
+	// the pods of a source are restored when it is created
+	channel := config.Channel("test")
+	expectPodUpdate(t, ch, CreatePodUpdate(kubelet.SET, CreateValidPod("foo", "new", "test")))
+	if !config.SeenAllSources() {
+		t.Errorf("Expected the restored source to be seen")
+	}
+
+	// a source without restored pods is unseen until it sends its state
+	other := config.Channel("other")
+	if config.SeenAllSources() {
+		t.Errorf("Expected the source without a state to be unseen")
+	}
+	other <- CreatePodUpdate(kubelet.SET, CreateValidPod("baz", "other", ""))
+	expectPodUpdate(t, ch, CreatePodUpdate(kubelet.SET, CreateValidPod("foo", "new", "test"), CreateValidPod("baz", "other", "other")))
+	if !config.SeenAllSources() {
+		t.Errorf("Expected all the sources to be seen")
+	}
+
+	// the state of the source replaces the restored pods
+	channel <- CreatePodUpdate(kubelet.SET, CreateValidPod("foo", "new", ""))
+	expectNoPodUpdate(t, ch)
+	channel <- CreatePodUpdate(kubelet.SET)
+	expectPodUpdate(t, ch, CreatePodUpdate(kubelet.SET, CreateValidPod("baz", "other", "other")))
+}
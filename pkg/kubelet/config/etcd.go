@@ -87,12 +87,17 @@ func (s *sourceEtcd) run() {
 // eventToPods takes a watch.Event object, and turns it into a structured list of pods.
 // It returns a list of containers, or an error if one occurs.
 func eventToPods(ev watch.Event) ([]api.BoundPod, error) {
-	pods := []api.BoundPod{}
 	boundPods, ok := ev.Object.(*api.BoundPods)
 	if !ok {
-		return pods, errors.New("unable to parse response as BoundPods")
+		return []api.BoundPod{}, errors.New("unable to parse response as BoundPods")
 	}
+	return boundPodsToPods(boundPods), nil
+}
 
+// boundPodsToPods returns the pods of the bound pods, defaulting the fields older
+// api servers do not set.
+func boundPodsToPods(boundPods *api.BoundPods) []api.BoundPod {
+	pods := []api.BoundPod{}
 	for i, pod := range boundPods.Items {
 		if len(pod.Name) == 0 {
 			pod.Name = fmt.Sprintf("%d", i+1)
@@ -107,6 +112,5 @@ func eventToPods(ev watch.Event) ([]api.BoundPod, error) {
 		}
 		pods = append(pods, pod)
 	}
-
-	return pods, nil
+	return pods
 }
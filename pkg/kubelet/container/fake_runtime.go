@@ -0,0 +1,551 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
+)
+
+// FakeRuntime is an in-memory Runtime, which runs the containers of the pods without any
+// container runtime, so that the kubelet can be tested without docker.
+type FakeRuntime struct {
+	sync.Mutex
+	// The pods with running containers.
+	RunningPods Pods
+	// The pods with exited containers.
+	ExitedPods Pods
+	// The statuses of the containers by ID.  A container without a status is running if it
+	// is in RunningPods, and exited with code 0 otherwise.
+	Statuses map[string]*ContainerStatus
+	// The images present on the node.
+	Images []string
+	// The output of RunInContainer.
+	RunOutput []byte
+	// The logs of the containers by ID.
+	ContainerLogs map[string]string
+	// The cgroup values written by UpdateContainerResources by container ID.
+	Resources map[string][]KeyValue
+	// The settings written by UpdateContainerConfig by container ID.
+	Config map[string][]KeyValue
+	// The options of the containers run by RunContainer by ID.
+	RunOptions map[string]*RunContainerOptions
+	// Every call returns this error, after being recorded, if it is not nil.
+	Err error
+
+	// The recorded calls, e.g. "RunContainer" or "KillContainer".
+	CalledFunctions []string
+	// The names of the containers started by RunContainer and StartContainer.
+	StartedContainers []string
+	// The IDs of the containers killed by KillContainer.
+	KilledContainers []string
+	// The images pulled by PullImage.
+	PulledImages []string
+	// The images pushed by PushImage.
+	PushedImages []string
+	// The commands run by RunInContainer and ExecInContainer, the container ID first.
+	Commands [][]string
+	nextID   int
+}
+
+func (f *FakeRuntime) called(function string) {
+	f.CalledFunctions = append(f.CalledFunctions, function)
+}
+
+// AssertCalls returns an error unless the functions called are calls, in order.
+func (f *FakeRuntime) AssertCalls(calls []string) error {
+	f.Lock()
+	defer f.Unlock()
+	if !reflect.DeepEqual(calls, f.CalledFunctions) {
+		return fmt.Errorf("expected %#v, got %#v", calls, f.CalledFunctions)
+	}
+	return nil
+}
+
+// ClearCalls forgets the calls recorded so far.
+func (f *FakeRuntime) ClearCalls() {
+	f.Lock()
+	defer f.Unlock()
+	f.CalledFunctions = nil
+	f.StartedContainers = nil
+	f.KilledContainers = nil
+	f.PulledImages = nil
+	f.PushedImages = nil
+	f.Commands = nil
+}
+
+// copyPods returns a deep copy of pods, so that the callers do not see the later changes.
+func copyPods(pods Pods) Pods {
+	result := Pods{}
+	for _, pod := range pods {
+		p := *pod
+		p.Containers = []*Container{}
+		for _, c := range pod.Containers {
+			container := *c
+			p.Containers = append(p.Containers, &container)
+		}
+		result = append(result, &p)
+	}
+	return result
+}
+
+// findPod returns the pod with the full name and UID in pods, adding it if it is missing.
+func findPod(pods *Pods, podFullName, uid string) *Pod {
+	for _, pod := range *pods {
+		if pod.FullName == podFullName && pod.UID == uid {
+			return pod
+		}
+	}
+	pod := &Pod{FullName: podFullName, UID: uid}
+	*pods = append(*pods, pod)
+	return pod
+}
+
+// move moves the container with the ID from the pods in from to the same pod in to, and
+// returns it, or nil if it is not in from.
+func move(id string, from, to *Pods) *Container {
+	for _, pod := range *from {
+		for i, container := range pod.Containers {
+			if container.ID != id {
+				continue
+			}
+			pod.Containers = append(pod.Containers[:i], pod.Containers[i+1:]...)
+			dest := findPod(to, pod.FullName, pod.UID)
+			dest.Containers = append(dest.Containers, container)
+			return container
+		}
+	}
+	return nil
+}
+
+// find returns the container with the ID, and whether it is running.
+func (f *FakeRuntime) find(id string) (*Container, bool) {
+	for i, pods := range []Pods{f.RunningPods, f.ExitedPods} {
+		for _, pod := range pods {
+			for _, container := range pod.Containers {
+				if container.ID == id {
+					return container, i == 0
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// status returns the status of a container, defaulting it from where the container is.
+func (f *FakeRuntime) status(container *Container, running bool) *ContainerStatus {
+	if status, found := f.Statuses[container.ID]; found {
+		s := *status
+		s.ID = container.ID
+		return &s
+	}
+	return &ContainerStatus{
+		ID:      container.ID,
+		Created: time.Unix(container.Created, 0),
+		Running: running,
+	}
+}
+
+func (f *FakeRuntime) setStatus(status *ContainerStatus) {
+	if f.Statuses == nil {
+		f.Statuses = map[string]*ContainerStatus{}
+	}
+	f.Statuses[status.ID] = status
+}
+
+// GetPods implements Runtime.
+func (f *FakeRuntime) GetPods(all bool) (Pods, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called("GetPods")
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	pods := copyPods(f.RunningPods)
+	if all {
+		for _, exited := range copyPods(f.ExitedPods) {
+			pod := findPod(&pods, exited.FullName, exited.UID)
+			pod.Containers = append(pod.Containers, exited.Containers...)
+		}
+	}
+	return pods, nil
+}
+
+// GetPodInfo implements Runtime, it reports the newest container of each name.
+func (f *FakeRuntime) GetPodInfo(spec api.PodSpec, podFullName, uid string) (api.PodInfo, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called("GetPodInfo")
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	info := api.PodInfo{}
+	for _, status := range f.recentContainers(podFullName, uid, "") {
+		name := status.name
+		if _, found := info[name]; found {
+			continue
+		}
+		containerStatus := api.ContainerStatus{Image: status.image}
+		if status.Running {
+			containerStatus.State.Running = &api.ContainerStateRunning{StartedAt: status.StartedAt}
+		} else {
+			containerStatus.State.Termination = &api.ContainerStateTerminated{
+				ExitCode:   status.ExitCode,
+				StartedAt:  status.StartedAt,
+				FinishedAt: status.FinishedAt,
+			}
+		}
+		info[name] = containerStatus
+	}
+	if len(info) == 0 {
+		return nil, fmt.Errorf("no containers of pod %s", podFullName)
+	}
+	for _, container := range append(append([]api.Container{}, spec.InitContainers...), spec.Containers...) {
+		if _, found := info[container.Name]; !found {
+			info[container.Name] = api.ContainerStatus{
+				State: api.ContainerState{Waiting: &api.ContainerStateWaiting{}},
+			}
+		}
+	}
+	return info, nil
+}
+
+// namedStatus is a ContainerStatus with the name and image of its container.
+type namedStatus struct {
+	*ContainerStatus
+	name  string
+	image string
+}
+
+type byCreatedDescending []namedStatus
+
+func (a byCreatedDescending) Len() int           { return len(a) }
+func (a byCreatedDescending) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byCreatedDescending) Less(i, j int) bool { return a[i].Created.After(a[j].Created) }
+
+// recentContainers returns the containers with the name, or all the containers if name is
+// empty, of an instance of a pod, running ones first and newest first.
+func (f *FakeRuntime) recentContainers(podFullName, uid, name string) []namedStatus {
+	result := []namedStatus{}
+	for i, pods := range []Pods{f.RunningPods, f.ExitedPods} {
+		for _, pod := range pods {
+			if pod.FullName != podFullName || (uid != "" && pod.UID != uid) {
+				continue
+			}
+			for _, container := range pod.Containers {
+				if name == "" || container.Name == name {
+					result = append(result, namedStatus{f.status(container, i == 0), container.Name, container.Image})
+				}
+			}
+		}
+	}
+	sort.Stable(byCreatedDescending(result))
+	return result
+}
+
+// GetRecentContainers implements Runtime.
+func (f *FakeRuntime) GetRecentContainers(podFullName, uid, containerName string) ([]*ContainerStatus, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called("GetRecentContainers")
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	result := []*ContainerStatus{}
+	for _, status := range f.recentContainers(podFullName, uid, containerName) {
+		result = append(result, status.ContainerStatus)
+	}
+	return result, nil
+}
+
+// GetContainerStatus implements Runtime.
+func (f *FakeRuntime) GetContainerStatus(containerID string) (*ContainerStatus, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called("GetContainerStatus")
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	container, running := f.find(containerID)
+	if container == nil {
+		return nil, fmt.Errorf("no such container: %s", containerID)
+	}
+	return f.status(container, running), nil
+}
+
+// RunContainer implements Runtime, the container is running until it is killed.
+func (f *FakeRuntime) RunContainer(pod *api.BoundPod, container *api.Container, opts *RunContainerOptions) (string, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called("RunContainer")
+	if f.Err != nil {
+		return "", f.Err
+	}
+	f.nextID++
+	now := time.Now()
+	id := fmt.Sprintf("%s_%d", container.Name, f.nextID)
+	running := findPod(&f.RunningPods, GetPodFullName(pod), pod.UID)
+	running.Containers = append(running.Containers, &Container{
+		ID:      id,
+		Name:    container.Name,
+		Image:   container.Image,
+		Hash:    HashContainer(container),
+		Created: now.Unix(),
+	})
+	f.setStatus(&ContainerStatus{ID: id, Created: now, Running: true, StartedAt: now})
+	if f.RunOptions == nil {
+		f.RunOptions = map[string]*RunContainerOptions{}
+	}
+	f.RunOptions[id] = opts
+	f.StartedContainers = append(f.StartedContainers, container.Name)
+	return id, nil
+}
+
+// StartContainer implements Runtime.
+func (f *FakeRuntime) StartContainer(containerID, netMode string) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("StartContainer")
+	if f.Err != nil {
+		return f.Err
+	}
+	container := move(containerID, &f.ExitedPods, &f.RunningPods)
+	if container == nil {
+		return fmt.Errorf("no exited container %s", containerID)
+	}
+	status := f.status(container, true)
+	status.Running = true
+	status.StartedAt = time.Now()
+	f.setStatus(status)
+	f.StartedContainers = append(f.StartedContainers, container.Name)
+	return nil
+}
+
+// KillContainer implements Runtime, the container exits with code 0.
+func (f *FakeRuntime) KillContainer(containerID string, gracePeriod time.Duration) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("KillContainer")
+	f.KilledContainers = append(f.KilledContainers, containerID)
+	if f.Err != nil {
+		return f.Err
+	}
+	if container := move(containerID, &f.RunningPods, &f.ExitedPods); container != nil {
+		status := f.status(container, false)
+		status.Running = false
+		status.FinishedAt = time.Now()
+		f.setStatus(status)
+	}
+	return nil
+}
+
+// PullImage implements Runtime.
+func (f *FakeRuntime) PullImage(image string) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("PullImage")
+	f.PulledImages = append(f.PulledImages, image)
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Images = append(f.Images, image)
+	return nil
+}
+
+func (f *FakeRuntime) hasImage(image string) bool {
+	for _, present := range f.Images {
+		if present == image {
+			return true
+		}
+	}
+	return false
+}
+
+// IsImagePresent implements Runtime.
+func (f *FakeRuntime) IsImagePresent(image string) (bool, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called("IsImagePresent")
+	if f.Err != nil {
+		return false, f.Err
+	}
+	return f.hasImage(image), nil
+}
+
+// GetImageID implements Runtime, the images are their own IDs.
+func (f *FakeRuntime) GetImageID(image string) (string, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called("GetImageID")
+	if f.Err != nil || !f.hasImage(image) {
+		return "", f.Err
+	}
+	return image, nil
+}
+
+// CommitContainer implements Runtime.
+func (f *FakeRuntime) CommitContainer(containerID, image, author string, includes, excludes []string) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("CommitContainer")
+	if f.Err != nil {
+		return f.Err
+	}
+	if container, _ := f.find(containerID); container == nil {
+		return fmt.Errorf("no such container: %s", containerID)
+	}
+	f.Images = append(f.Images, image)
+	return nil
+}
+
+// PushImage implements Runtime.
+func (f *FakeRuntime) PushImage(image string) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("PushImage")
+	f.PushedImages = append(f.PushedImages, image)
+	return f.Err
+}
+
+// MergeImage implements Runtime, the container runs the image afterwards.
+func (f *FakeRuntime) MergeImage(containerID, image string, pull bool) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("MergeImage")
+	if f.Err != nil {
+		return f.Err
+	}
+	container, _ := f.find(containerID)
+	if container == nil {
+		return fmt.Errorf("no such container: %s", containerID)
+	}
+	if !pull && !f.hasImage(image) {
+		return fmt.Errorf("no such image: %s", image)
+	}
+	container.Image = image
+	return nil
+}
+
+// RunInContainer implements Runtime.
+func (f *FakeRuntime) RunInContainer(containerID string, cmd []string, timeout time.Duration) ([]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called("RunInContainer")
+	f.Commands = append(f.Commands, append([]string{containerID}, cmd...))
+	return f.RunOutput, f.Err
+}
+
+// ExecInContainer implements Runtime.
+func (f *FakeRuntime) ExecInContainer(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("ExecInContainer")
+	f.Commands = append(f.Commands, append([]string{containerID}, cmd...))
+	return f.Err
+}
+
+// AttachContainer implements Runtime.
+func (f *FakeRuntime) AttachContainer(containerID string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("AttachContainer")
+	return f.Err
+}
+
+// PortForward implements Runtime.
+func (f *FakeRuntime) PortForward(runningPod Pod, port uint16, stream io.ReadWriter) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("PortForward")
+	return f.Err
+}
+
+// GetContainerLogs implements Runtime, it writes the logs of the container to stdout.
+func (f *FakeRuntime) GetContainerLogs(containerID, tail string, follow bool, stdout, stderr io.Writer) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("GetContainerLogs")
+	if f.Err != nil {
+		return f.Err
+	}
+	_, err := io.WriteString(stdout, f.ContainerLogs[containerID])
+	return err
+}
+
+// UpdateContainerResources implements Runtime.
+func (f *FakeRuntime) UpdateContainerResources(containerID string, cgroups []KeyValue) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("UpdateContainerResources")
+	if f.Err != nil {
+		return f.Err
+	}
+	if f.Resources == nil {
+		f.Resources = map[string][]KeyValue{}
+	}
+	f.Resources[containerID] = cgroups
+	return nil
+}
+
+// UpdateContainerConfig implements Runtime.
+func (f *FakeRuntime) UpdateContainerConfig(containerID string, config []KeyValue) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called("UpdateContainerConfig")
+	if f.Err != nil {
+		return f.Err
+	}
+	if f.Config == nil {
+		f.Config = map[string][]KeyValue{}
+	}
+	f.Config[containerID] = config
+	return nil
+}
+
+// ContainerCgroups implements Runtime, the cgroup files read back the values written.
+func (f *FakeRuntime) ContainerCgroups(containerID string, cgroups []CgroupValue) ([]CgroupResult, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called("ContainerCgroups")
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.Resources == nil {
+		f.Resources = map[string][]KeyValue{}
+	}
+	results := []CgroupResult{}
+	for _, cgroup := range cgroups {
+		if cgroup.Value != "" {
+			f.Resources[containerID] = append(f.Resources[containerID], KeyValue{Key: cgroup.Subsystem, Value: cgroup.Value})
+		}
+		result := CgroupResult{Group: cgroup.Group, Subsystem: cgroup.Subsystem}
+		for _, resource := range f.Resources[containerID] {
+			if resource.Key == cgroup.Subsystem {
+				result.Out = resource.Value
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
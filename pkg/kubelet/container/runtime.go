@@ -0,0 +1,210 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package container defines the interface the kubelet runs the containers of its pods
+// through, independently of the container runtime.  The kubelet decides which containers
+// of a pod run; the runtime runs, kills and reports them.
+package container
+
+import (
+	"fmt"
+	"hash/adler32"
+	"io"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
+)
+
+// Runtime is the interface of a container runtime the kubelet runs pods with.
+type Runtime interface {
+	// GetPods returns the pods which have containers on the node.  Only the running
+	// containers are returned unless all is true.
+	GetPods(all bool) (Pods, error)
+	// GetPodInfo returns the status of the containers of an instance of a pod by container
+	// name.  The containers of spec which the runtime has not run yet are reported waiting.
+	GetPodInfo(spec api.PodSpec, podFullName, uid string) (api.PodInfo, error)
+	// GetRecentContainers returns the running and exited containers with the name in an
+	// instance of a pod, newest first.  An empty UID matches any instance of the pod.
+	GetRecentContainers(podFullName, uid, containerName string) ([]*ContainerStatus, error)
+	// GetContainerStatus returns the status of a container.
+	GetContainerStatus(containerID string) (*ContainerStatus, error)
+	// RunContainer creates and starts a container of the pod, and returns its ID.
+	RunContainer(pod *api.BoundPod, container *api.Container, opts *RunContainerOptions) (string, error)
+	// StartContainer starts an exited container again with the settings it was created
+	// with, in the network namespace given by netMode as in RunContainerOptions.
+	StartContainer(containerID, netMode string) error
+	// KillContainer stops a container, and kills it if it is still running after gracePeriod.
+	KillContainer(containerID string, gracePeriod time.Duration) error
+	// PullImage pulls an image from the network to the node.
+	PullImage(image string) error
+	// IsImagePresent returns whether the image is present on the node.
+	IsImagePresent(image string) (bool, error)
+	// GetImageID returns the ID of the image with exactly this name on the node, or an
+	// empty ID if there is none.
+	GetImageID(image string) (string, error)
+	// CommitContainer saves the files of a container as the image, only those under
+	// includes, or all but those under excludes, if they are not empty.
+	CommitContainer(containerID, image, author string, includes, excludes []string) error
+	// PushImage pushes an image of the node to its registry.
+	PushImage(image string) error
+	// MergeImage replaces the files of a container which come from its image by those of
+	// image.  The changed layers are pulled if pull is true, otherwise image must be present
+	// and the files are taken from its difference with the image of the container.
+	MergeImage(containerID, image string, pull bool) error
+	// RunInContainer runs a command in a container and returns its combined stdout and stderr.
+	// The command is given up on once it has run for timeout, zero meaning no timeout.
+	RunInContainer(containerID string, cmd []string, timeout time.Duration) ([]byte, error)
+	// ExecInContainer runs a command in a container with the given streams, any of which
+	// may be nil, until it exits.
+	ExecInContainer(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error
+	// AttachContainer attaches the given streams, any of which may be nil, to the main
+	// process of a container until it exits.
+	AttachContainer(containerID string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error
+	// PortForward copies the data of stream to and from a TCP connection to port in the
+	// network namespace of the running pod, until either side closes.
+	PortForward(runningPod Pod, port uint16, stream io.ReadWriter) error
+	// GetContainerLogs writes the logs of a container to stdout and stderr.  The logs
+	// are streamed if follow is true, otherwise only the last tail lines are written
+	// unless tail is empty or "all".
+	GetContainerLogs(containerID, tail string, follow bool, stdout, stderr io.Writer) error
+	// UpdateContainerResources writes the values of the cgroup files of a running
+	// container, such as "cpuset.cpus" or "memory.limit_in_bytes".
+	UpdateContainerResources(containerID string, cgroups []KeyValue) error
+	// UpdateContainerConfig changes settings of the configuration of a container, such
+	// as its environment.
+	UpdateContainerConfig(containerID string, config []KeyValue) error
+	// ContainerCgroups reads the cgroup files of a running container, and writes those
+	// which have a value first.
+	ContainerCgroups(containerID string, cgroups []CgroupValue) ([]CgroupResult, error)
+}
+
+// RunContainerOptions are the settings of a container which the kubelet decides rather
+// than the spec of the container.
+type RunContainerOptions struct {
+	// The environment of the container, "NAME=value" each.
+	Envs []string
+	// The volumes bound into the container, "host path:container path[:ro]" each.
+	Binds []string
+	// The network namespace of the container: "host", "container:" followed by the ID of
+	// another container to share its namespace, or empty for a namespace of its own.
+	NetMode string
+	// The directory the file of the termination message of the container is kept in.
+	PodContainerDir string
+}
+
+// ContainerStatus is the state of a container of the runtime.
+type ContainerStatus struct {
+	// The ID of the container in the runtime.
+	ID string
+	// The creation time of the container.
+	Created time.Time
+	// Whether the container is running.
+	Running bool
+	// The process ID of the main process of the running container.
+	Pid int
+	// The exit code of the exited container.
+	ExitCode int
+	// When the container last started and exited, zero if it did not.
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// The CPUs the container may run on, e.g. "0-3,8".
+	CpuSet string
+}
+
+// CgroupValue is a value of a cgroup file of a container, e.g. "cpu.shares" of the "cpu"
+// group.  An empty value is only read.
+type CgroupValue struct {
+	Group     string
+	Subsystem string
+	Value     string
+}
+
+// CgroupResult is the content of a cgroup file of a container after a CgroupValue was
+// applied, with a non-zero status if it failed.
+type CgroupResult struct {
+	Group     string
+	Subsystem string
+	Out       string
+	Status    int
+}
+
+// KeyValue is the value of a setting of a container.
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// Pod is a group of containers of the runtime which belong to the same instance of a pod.
+type Pod struct {
+	// The full name of the pod, see GetPodFullName.
+	FullName string
+	// The UID of the pod instance, empty if it is unknown.
+	UID string
+	// The containers of the pod, including the infrastructure containers of the runtime.
+	Containers []*Container
+}
+
+// Container is a container of the runtime.
+type Container struct {
+	// The ID of the container in the runtime.
+	ID string
+	// The name of the container in the spec of the pod.
+	Name string
+	// The image the container runs.
+	Image string
+	// The HashContainer of the spec the container was created from, 0 if it is unknown.
+	Hash uint64
+	// The creation time of the container, in seconds since the epoch.
+	Created int64
+}
+
+// Pods is a list of pods of the runtime.
+type Pods []*Pod
+
+// FindPod returns the pod with the full name and UID, or an empty pod with that full
+// name and UID if there is none.  An empty UID matches any instance of the pod.
+func (p Pods) FindPod(podFullName, uid string) Pod {
+	for _, pod := range p {
+		if pod.FullName == podFullName && (uid == "" || pod.UID == uid) {
+			return *pod
+		}
+	}
+	return Pod{FullName: podFullName, UID: uid}
+}
+
+// FindContainerByName returns the container of the pod with the name, or nil.
+func (p *Pod) FindContainerByName(name string) *Container {
+	for _, container := range p.Containers {
+		if container.Name == name {
+			return container
+		}
+	}
+	return nil
+}
+
+// HashContainer returns the hash of the spec of a container, which tells whether a running
+// container was created from the current spec.
+func HashContainer(container *api.Container) uint64 {
+	hash := adler32.New()
+	fmt.Fprintf(hash, "%#v", *container)
+	return uint64(hash.Sum32())
+}
+
+// GetPodFullName returns a name that uniquely identifies a pod across all config sources.
+func GetPodFullName(pod *api.BoundPod) string {
+	return fmt.Sprintf("%s.%s.%s", pod.Name, pod.Namespace, pod.Annotations[api.ConfigSourceAnnotationKey])
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func TestFindPod(t *testing.T) {
+	pods := Pods{
+		{FullName: "foo.new.test", UID: "1", Containers: []*Container{{ID: "a", Name: "bar"}}},
+		{FullName: "foo.new.test", UID: "2"},
+	}
+	if pod := pods.FindPod("foo.new.test", "2"); pod.UID != "2" {
+		t.Errorf("unexpected pod: %#v", pod)
+	}
+	if pod := pods.FindPod("foo.new.test", ""); pod.UID != "1" {
+		t.Errorf("expected the first instance, got %#v", pod)
+	}
+	pod := pods.FindPod("other.new.test", "3")
+	if !reflect.DeepEqual(pod, Pod{FullName: "other.new.test", UID: "3"}) {
+		t.Errorf("expected an empty pod, got %#v", pod)
+	}
+	running := pods.FindPod("foo.new.test", "1")
+	if c := running.FindContainerByName("bar"); c == nil || c.ID != "a" {
+		t.Errorf("unexpected container: %#v", c)
+	}
+	if c := running.FindContainerByName("baz"); c != nil {
+		t.Errorf("unexpected container: %#v", c)
+	}
+}
+
+func TestGetPodFullName(t *testing.T) {
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "new",
+			Annotations: map[string]string{api.ConfigSourceAnnotationKey: "file"},
+		},
+	}
+	if name := GetPodFullName(pod); name != "foo.new.file" {
+		t.Errorf("unexpected full name: %s", name)
+	}
+}
+
+func TestFakeRuntimeRunAndKillContainer(t *testing.T) {
+	runtime := &FakeRuntime{}
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "new",
+			UID:         "12345678",
+			Annotations: map[string]string{api.ConfigSourceAnnotationKey: "file"},
+		},
+	}
+	container := &api.Container{Name: "bar", Image: "image"}
+	id, err := runtime.RunContainer(pod, container, &RunContainerOptions{NetMode: "host"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pods, _ := runtime.GetPods(false)
+	runningPod := pods.FindPod("foo.new.file", "12345678")
+	running := runningPod.FindContainerByName("bar")
+	if running == nil || running.ID != id || running.Hash != HashContainer(container) {
+		t.Errorf("unexpected running container: %#v", running)
+	}
+
+	if err := runtime.KillContainer(id, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pods, _ := runtime.GetPods(false); len(pods.FindPod("foo.new.file", "12345678").Containers) != 0 {
+		t.Errorf("expected no running containers, got %#v", pods)
+	}
+	recent, err := runtime.GetRecentContainers("foo.new.file", "12345678", "bar")
+	if err != nil || len(recent) != 1 || recent[0].ID != id || recent[0].Running || recent[0].FinishedAt.IsZero() {
+		t.Errorf("unexpected recent containers: %#v %v", recent, err)
+	}
+	if err := runtime.AssertCalls([]string{"RunContainer", "GetPods", "KillContainer", "GetPods", "GetRecentContainers"}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,371 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/golang/glog"
+)
+
+// dockerRuntime is the kubecontainer.Runtime of the docker daemon.  It reaches docker through
+// the client, puller, command runner and keyring of the kubelet, so that replacing them in the
+// kubelet replaces them in the runtime too.
+type dockerRuntime struct {
+	kl *Kubelet
+}
+
+func newDockerRuntime(kl *Kubelet) kubecontainer.Runtime {
+	return &dockerRuntime{kl: kl}
+}
+
+// GetPods returns the pods of the docker containers created by the kubelet, grouped by
+// the pod full name and UID in their docker names.
+func (r *dockerRuntime) GetPods(all bool) (kubecontainer.Pods, error) {
+	dockerContainers, err := dockertools.GetKubeletDockerContainers(r.kl.dockerClient, all)
+	if err != nil {
+		return nil, err
+	}
+	pods := map[string]*kubecontainer.Pod{}
+	result := kubecontainer.Pods{}
+	for _, dockerContainer := range dockerContainers {
+		podFullName, uuid, containerName, hash := dockertools.ParseDockerName(dockerContainer.Names[0])
+		key := podFullName + "_" + uuid
+		pod, found := pods[key]
+		if !found {
+			pod = &kubecontainer.Pod{FullName: podFullName, UID: uuid}
+			pods[key] = pod
+			result = append(result, pod)
+		}
+		pod.Containers = append(pod.Containers, &kubecontainer.Container{
+			ID:      dockerContainer.ID,
+			Name:    containerName,
+			Image:   dockerContainer.Image,
+			Hash:    hash,
+			Created: dockerContainer.Created,
+		})
+	}
+	return result, nil
+}
+
+// GetPodInfo inspects the docker containers of the pod.
+func (r *dockerRuntime) GetPodInfo(spec api.PodSpec, podFullName, uid string) (api.PodInfo, error) {
+	return dockertools.GetDockerPodInfo(r.kl.dockerClient, spec, podFullName, uid)
+}
+
+// toContainerStatus returns the status of an inspected docker container.
+func toContainerStatus(container *docker.Container) *kubecontainer.ContainerStatus {
+	status := &kubecontainer.ContainerStatus{
+		ID:         container.ID,
+		Created:    container.Created,
+		Running:    container.State.Running,
+		Pid:        container.State.Pid,
+		ExitCode:   container.State.ExitCode,
+		StartedAt:  container.State.StartedAt,
+		FinishedAt: container.State.FinishedAt,
+	}
+	if container.Config != nil {
+		status.CpuSet = container.Config.CpuSet
+	}
+	return status
+}
+
+// GetRecentContainers inspects the docker containers with the name in the pod, the paused
+// ones left out.
+func (r *dockerRuntime) GetRecentContainers(podFullName, uid, containerName string) ([]*kubecontainer.ContainerStatus, error) {
+	containers, err := dockertools.GetRecentDockerContainersWithNameAndUUID(r.kl.dockerClient, podFullName, uid, containerName)
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(ByCreated(containers))
+	result := []*kubecontainer.ContainerStatus{}
+	for _, container := range containers {
+		result = append(result, toContainerStatus(container))
+	}
+	return result, nil
+}
+
+// GetContainerStatus inspects a docker container.
+func (r *dockerRuntime) GetContainerStatus(containerID string) (*kubecontainer.ContainerStatus, error) {
+	container, err := r.kl.dockerClient.InspectContainer(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return toContainerStatus(container), nil
+}
+
+// RunContainer creates a docker container named after the pod and the hash of the container,
+// and starts it.  The ID of a container which was created but failed to start is returned
+// with the error.
+func (r *dockerRuntime) RunContainer(pod *api.BoundPod, container *api.Container, opts *kubecontainer.RunContainerOptions) (string, error) {
+	exposedPorts, portBindings := makePortsAndBindings(container)
+	dockerContainer, err := r.kl.dockerClient.CreateContainer(docker.CreateContainerOptions{
+		Name: dockertools.BuildDockerName(pod.UID, GetPodFullName(pod), container),
+		Config: &docker.Config{
+			Cmd:          container.Command,
+			Env:          opts.Envs,
+			ExposedPorts: exposedPorts,
+			Hostname:     pod.Name,
+			Image:        container.Image,
+			Memory:       int64(container.Memory),
+			CpuShares:    int64(milliCPUToShares(container.CPU)),
+			CpuSet:       pod.Res.CpuSet,
+			WorkingDir:   container.WorkingDir,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	binds := append([]string{}, opts.Binds...)
+	if len(container.TerminationMessagePath) != 0 && len(opts.PodContainerDir) != 0 {
+		if err := os.MkdirAll(opts.PodContainerDir, 0750); err != nil {
+			glog.Errorf("Error on creating %s: %v", opts.PodContainerDir, err)
+		} else {
+			containerLogPath := path.Join(opts.PodContainerDir, dockerContainer.ID)
+			fs, err := os.Create(containerLogPath)
+			if err != nil {
+				glog.Errorf("Error on creating termination-log file %s: %v", containerLogPath, err)
+			}
+			defer fs.Close()
+			binds = append(binds, fmt.Sprintf("%s:%s", containerLogPath, container.TerminationMessagePath))
+		}
+	}
+	err = r.kl.dockerClient.StartContainer(dockerContainer.ID, &docker.HostConfig{
+		PortBindings: portBindings,
+		Binds:        binds,
+		NetworkMode:  opts.NetMode,
+		Privileged:   container.Privileged,
+		CapAdd:       container.CapAdd,
+		CapDrop:      container.CapDrop,
+	})
+	return dockerContainer.ID, err
+}
+
+// StartContainer starts an exited docker container again with its ports, binds and
+// capabilities.
+func (r *dockerRuntime) StartContainer(containerID, netMode string) error {
+	container, err := r.kl.dockerClient.InspectContainer(containerID)
+	if err != nil {
+		return err
+	}
+	hostConfig := &docker.HostConfig{NetworkMode: netMode}
+	if container.HostConfig != nil {
+		hostConfig.PortBindings = container.HostConfig.PortBindings
+		hostConfig.Binds = container.HostConfig.Binds
+		hostConfig.Privileged = container.HostConfig.Privileged
+		hostConfig.CapAdd = container.HostConfig.CapAdd
+		hostConfig.CapDrop = container.HostConfig.CapDrop
+	}
+	return r.kl.dockerClient.StartContainer(containerID, hostConfig)
+}
+
+// KillContainer stops a docker container, which docker kills once the grace period, rounded
+// up to seconds, is over.
+func (r *dockerRuntime) KillContainer(containerID string, gracePeriod time.Duration) error {
+	return r.kl.dockerClient.StopContainer(containerID, stopTimeout(gracePeriod))
+}
+
+// stopTimeout returns the number of seconds docker waits after SIGTERM before killing a container.
+func stopTimeout(gracePeriod time.Duration) uint {
+	return uint((gracePeriod + time.Second - 1) / time.Second)
+}
+
+func (r *dockerRuntime) puller() dockertools.DockerPuller {
+	if r.kl.dockerPuller == nil {
+		return dockertools.NewDockerPuller(r.kl.dockerClient, r.kl.pullQPS, r.kl.pullBurst)
+	}
+	return r.kl.dockerPuller
+}
+
+// PullImage pulls an image with the credentials of the docker keyring.
+func (r *dockerRuntime) PullImage(image string) error {
+	return r.puller().Pull(image)
+}
+
+// IsImagePresent returns whether the image is present in the docker daemon.
+func (r *dockerRuntime) IsImagePresent(image string) (bool, error) {
+	return r.puller().IsImagePresent(image)
+}
+
+// GetImageID inspects the docker image.
+func (r *dockerRuntime) GetImageID(image string) (string, error) {
+	inspected, err := r.kl.dockerClient.InspectImage(image)
+	if err == docker.ErrNoSuchImage {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return inspected.ID, nil
+}
+
+// CommitContainer commits a docker container to the repository and tag of the image.
+func (r *dockerRuntime) CommitContainer(containerID, image, author string, includes, excludes []string) error {
+	// e.g. hub.oa.com/library/tlinux1.2:latest is committed to the repository
+	// hub.oa.com/library/tlinux1.2 with the tag latest.
+	_, repo, tag := dockertools.ParseImageName(image)
+	var options *docker.ChangeOptions
+	if len(includes) != 0 || len(excludes) != 0 {
+		options = &docker.ChangeOptions{Includes: includes, Excludes: excludes}
+	}
+	_, err := r.kl.dockerClient.CommitContainer(docker.CommitContainerOptions{
+		Container:  containerID,
+		Repository: repo,
+		Tag:        tag,
+		Author:     author,
+		Message:    "push custom image",
+		Options:    options,
+	})
+	return err
+}
+
+// PushImage pushes an image to its registry with the credentials of the docker keyring.
+func (r *dockerRuntime) PushImage(image string) error {
+	regi, repo, tag := dockertools.ParseImageName(image)
+	creds, ok := r.kl.keyring.Lookup(repo)
+	if !ok {
+		glog.V(1).Infof("Push image: %s without credentials", repo)
+	}
+	return r.kl.dockerClient.PushImage(docker.PushImageOptions{
+		Name:     repo,
+		Tag:      tag,
+		Registry: regi,
+	}, creds)
+}
+
+// MergeImage applies the image to a docker container through the merge extension of the
+// docker daemon, pulling the image with the credentials of the docker keyring.
+func (r *dockerRuntime) MergeImage(containerID, image string, pull bool) error {
+	container, err := r.kl.dockerClient.InspectContainer(containerID)
+	if err != nil {
+		return err
+	}
+	current, err := r.kl.dockerClient.InspectImage(container.Config.Image)
+	if err != nil {
+		return fmt.Errorf("Failed to inspect image: %s", container.Config.Image)
+	}
+	opts := docker.MergeImageOptions{
+		Container:    containerID,
+		CurrentImage: current.ID,
+		Repository:   image,
+	}
+	if !pull {
+		return r.kl.dockerClient.DiffImageAndApply(opts)
+	}
+	_, repo, _ := dockertools.ParseImageName(image)
+	creds, ok := r.kl.keyring.Lookup(repo)
+	if !ok {
+		glog.V(1).Infof("Pull image: %s without credentials", repo)
+	}
+	return r.kl.dockerClient.PullImageAndApply(opts, creds)
+}
+
+// RunInContainer runs a command in a container with docker exec, or nsinit if docker is
+// too old.
+func (r *dockerRuntime) RunInContainer(containerID string, cmd []string, timeout time.Duration) ([]byte, error) {
+	if r.kl.runner == nil {
+		return nil, fmt.Errorf("no runner specified.")
+	}
+	return r.kl.runner.RunInContainer(containerID, cmd, timeout)
+}
+
+// ExecInContainer runs a command in a container with docker exec, or nsinit if docker is
+// too old.
+func (r *dockerRuntime) ExecInContainer(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	if r.kl.runner == nil {
+		return fmt.Errorf("no runner specified.")
+	}
+	return r.kl.runner.ExecInContainer(containerID, cmd, stdin, stdout, stderr, tty, resize)
+}
+
+// AttachContainer attaches the streams to the main process of a container with docker attach.
+func (r *dockerRuntime) AttachContainer(containerID string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	return dockertools.AttachContainer(r.kl.dockerClient, containerID, stdin, stdout, stderr, tty, resize)
+}
+
+// PortForward forwards a port in the network namespace of the network container of the pod.
+func (r *dockerRuntime) PortForward(runningPod kubecontainer.Pod, port uint16, stream io.ReadWriter) error {
+	if r.kl.runner == nil {
+		return fmt.Errorf("no runner specified.")
+	}
+	netContainer := runningPod.FindContainerByName(networkContainerName)
+	if netContainer == nil {
+		return fmt.Errorf("network container not found for pod %s", runningPod.FullName)
+	}
+	return r.kl.runner.PortForward(netContainer.ID, port, stream)
+}
+
+// GetContainerLogs returns the logs of a container from the docker daemon, with timestamps.
+func (r *dockerRuntime) GetContainerLogs(containerID, tail string, follow bool, stdout, stderr io.Writer) error {
+	return dockertools.GetKubeletDockerContainerLogs(r.kl.dockerClient, containerID, tail, follow, stdout, stderr)
+}
+
+// UpdateContainerResources writes the cgroup values of a running container through the
+// cgroup extension of the docker daemon.
+func (r *dockerRuntime) UpdateContainerResources(containerID string, cgroups []kubecontainer.KeyValue) error {
+	config := []docker.KeyValuePair{}
+	for _, cgroup := range cgroups {
+		config = append(config, docker.KeyValuePair{Key: cgroup.Key, Value: cgroup.Value})
+	}
+	resp, err := r.kl.dockerClient.UpdateContainerCgroup(containerID, config)
+	glog.V(3).Infof("Update container %s cgroup: %+v\n\t result:%+v", containerID, config, resp)
+	return err
+}
+
+// UpdateContainerConfig changes the configuration of a docker container through the
+// extension of the docker daemon.
+func (r *dockerRuntime) UpdateContainerConfig(containerID string, config []kubecontainer.KeyValue) error {
+	var pairs []docker.KeyValuePair
+	for _, entry := range config {
+		pairs = append(pairs, docker.KeyValuePair{Key: entry.Key, Value: entry.Value})
+	}
+	return r.kl.dockerClient.UpdateContainerConfig(containerID, pairs)
+}
+
+// ContainerCgroups reads and writes the cgroup files of a docker container directly.
+func (r *dockerRuntime) ContainerCgroups(containerID string, cgroups []kubecontainer.CgroupValue) ([]kubecontainer.CgroupResult, error) {
+	data := []docker.CgroupData{}
+	for _, cgroup := range cgroups {
+		data = append(data, docker.CgroupData{
+			Group:     cgroup.Group,
+			Subsystem: cgroup.Subsystem,
+			Value:     cgroup.Value,
+		})
+	}
+	resp, err := docker.ContainerCgroup(containerID, data)
+	results := []kubecontainer.CgroupResult{}
+	for _, v := range resp {
+		results = append(results, kubecontainer.CgroupResult{
+			Group:     v.Group,
+			Subsystem: v.Subsystem,
+			Out:       v.Out,
+			Status:    v.Status,
+		})
+	}
+	return results, err
+}
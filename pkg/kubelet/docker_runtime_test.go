@@ -0,0 +1,144 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/fsouza/go-dockerclient"
+)
+
+func TestDockerRuntimeGetPods(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1234", Names: []string{"/k8s_bar.1a2b_foo.new.test_12345678_42"}, Image: "image", Created: 10},
+		{ID: "9876", Names: []string{"/k8s_net_foo.new.test_12345678_42"}},
+		{ID: "5678", Names: []string{"/k8s_bar_foo.new.test_87654321_42"}},
+		{ID: "4567", Names: []string{"/not_managed"}},
+	}
+	pods, err := kubelet.runtime.GetPods(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 pods, got %#v", pods)
+	}
+	pod := pods.FindPod("foo.new.test", "12345678")
+	if len(pod.Containers) != 2 {
+		t.Fatalf("expected 2 containers, got %#v", pod.Containers)
+	}
+	bar := pod.FindContainerByName("bar")
+	if bar == nil || bar.ID != "1234" || bar.Hash != 0x1a2b || bar.Image != "image" || bar.Created != 10 {
+		t.Errorf("unexpected container: %#v", bar)
+	}
+	if net := pod.FindContainerByName(networkContainerName); net == nil || net.ID != "9876" {
+		t.Errorf("unexpected network container: %#v", net)
+	}
+	if other := pods.FindPod("foo.new.test", "87654321"); len(other.Containers) != 1 {
+		t.Errorf("unexpected pod: %#v", other)
+	}
+	verifyCalls(t, fakeDocker, []string{"list"})
+}
+
+func TestDockerRuntimeRunContainer(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "new", UID: "12345678"},
+	}
+	container := &api.Container{Name: "bar", Image: "image", Privileged: true}
+	opts := &kubecontainer.RunContainerOptions{
+		Envs:    []string{"A=B"},
+		Binds:   []string{"/host:/container"},
+		NetMode: "container:9876",
+	}
+	id, err := kubelet.runtime.RunContainer(pod, container, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	verifyCalls(t, fakeDocker, []string{"create", "start"})
+	if id != fakeDocker.Container.ID {
+		t.Errorf("expected the started container %q, got %q", fakeDocker.Container.ID, id)
+	}
+
+	// The docker name carries the pod, the container and its hash.
+	podFullName, uuid, containerName, hash := dockertools.ParseDockerName(fakeDocker.Created[0])
+	if podFullName != GetPodFullName(pod) || uuid != pod.UID || containerName != "bar" || hash != kubecontainer.HashContainer(container) {
+		t.Errorf("unexpected docker name: %q", fakeDocker.Created[0])
+	}
+	hostConfig := fakeDocker.Container.HostConfig
+	if hostConfig.NetworkMode != "container:9876" || !hostConfig.Privileged {
+		t.Errorf("unexpected host config: %#v", hostConfig)
+	}
+	verifyStringArrayEquals(t, hostConfig.Binds, []string{"/host:/container"})
+}
+
+func TestDockerRuntimeStartContainer(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	fakeDocker.Container = &docker.Container{
+		ID:         "1234",
+		HostConfig: &docker.HostConfig{Binds: []string{"/host:/container"}, NetworkMode: "container:5678"},
+	}
+	if err := kubelet.runtime.StartContainer("1234", "container:9876"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	verifyCalls(t, fakeDocker, []string{"inspect_container", "start"})
+	hostConfig := fakeDocker.Container.HostConfig
+	if hostConfig.NetworkMode != "container:9876" {
+		t.Errorf("expected the new network mode, got %#v", hostConfig)
+	}
+	verifyStringArrayEquals(t, hostConfig.Binds, []string{"/host:/container"})
+}
+
+func TestDockerRuntimeKillContainer(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	if err := kubelet.runtime.KillContainer("1234", 1500*time.Millisecond); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	verifyCalls(t, fakeDocker, []string{"stop"})
+	verifyStringArrayEquals(t, fakeDocker.Stopped, []string{"1234"})
+}
+
+func TestDockerRuntimeGetRecentContainers(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1234", Names: []string{"/k8s_bar_foo.new.test_12345678_42"}},
+		{ID: "5678", Names: []string{"/k8s_bar_foo.new.test_12345678_43"}},
+		{ID: "9876", Names: []string{"/k8s_baz_foo.new.test_12345678_44"}},
+	}
+	now := time.Now()
+	fakeDocker.ContainerMap = map[string]*docker.Container{
+		"1234": {ID: "1234", Created: now.Add(-time.Minute), State: docker.State{ExitCode: 1}},
+		"5678": {ID: "5678", Created: now, State: docker.State{Running: true, Pid: 42}},
+	}
+	statuses, err := kubelet.runtime.GetRecentContainers("foo.new.test", "12345678", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 containers, got %#v", statuses)
+	}
+	if statuses[0].ID != "5678" || !statuses[0].Running || statuses[0].Pid != 42 {
+		t.Errorf("expected the newest container first, got %#v", statuses[0])
+	}
+	if statuses[1].ID != "1234" || statuses[1].Running || statuses[1].ExitCode != 1 {
+		t.Errorf("unexpected container: %#v", statuses[1])
+	}
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dockertools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/golang/glog"
+)
+
+// ContainerEventHandler is called with every container event seen by a ContainerCache, after the
+// cache has been updated. container is nil when the container no longer exists.
+type ContainerEventHandler func(event *docker.APIEvents, container *docker.APIContainers)
+
+// containerEvents are the statuses of the docker events about containers, the other events are about images.
+var containerEvents = util.NewStringSet("create", "start", "restart", "die", "kill", "stop", "pause", "unpause", "oom", "destroy")
+
+type cachedContainer struct {
+	container docker.APIContainers
+	running   bool
+}
+
+// ContainerCache is a DockerInterface which serves ListContainers from an in-memory list of the
+// containers. The list is kept up to date from the docker events stream, and from the results of
+// the container calls made through the cache. All the containers are listed again from docker
+// every relistPeriod in case events were lost, and on every ListContainers while the events
+// stream is not watched. InspectContainer and the other calls are served by docker directly, the
+// cache only holds what ListContainers returns.
+type ContainerCache struct {
+	DockerInterface
+
+	relistPeriod time.Duration
+
+	lock       sync.Mutex
+	containers map[string]*cachedContainer
+	// The time of the last full list, zero when the cache must be listed again.
+	lastList time.Time
+	// Whether the docker events are watched; the cache can't be trusted otherwise.
+	watching bool
+	// Incremented by every relist and every change of a container, so that an inspection which
+	// raced with a newer change is not written over it.
+	seq uint64
+	// The seq of the last relist.
+	listed uint64
+	// The seq of the last change of each container since the last relist, removals included.
+	changed map[string]uint64
+}
+
+// NewContainerCache wraps client with a ContainerCache which lists all the containers again every relistPeriod.
+func NewContainerCache(client DockerInterface, relistPeriod time.Duration) *ContainerCache {
+	return &ContainerCache{
+		DockerInterface: client,
+		relistPeriod:    relistPeriod,
+		changed:         map[string]uint64{},
+	}
+}
+
+// Start watches the docker events in the background, updating the cache and calling handler for
+// every container event. The events stream is watched again when it fails.
+func (c *ContainerCache) Start(handler ContainerEventHandler) {
+	go util.Forever(func() { c.watchEvents(handler) }, time.Second)
+}
+
+func (c *ContainerCache) watchEvents(handler ContainerEventHandler) {
+	events := make(chan *docker.APIEvents, 100)
+	if err := c.DockerInterface.AddEventListener(events); err != nil {
+		glog.Errorf("Failed to watch docker events: %v", err)
+		return
+	}
+	defer c.DockerInterface.RemoveEventListener(events)
+	c.setWatching(true)
+	defer c.setWatching(false)
+	for event := range events {
+		if event == nil {
+			continue
+		}
+		if !containerEvents.Has(event.Status) {
+			continue
+		}
+		var container *docker.APIContainers
+		if event.Status == "destroy" {
+			c.forget(event.ID)
+		} else {
+			container = c.refresh(event.ID)
+		}
+		if handler != nil {
+			handler(event, container)
+		}
+	}
+	glog.Warningf("Docker events stream closed")
+}
+
+func (c *ContainerCache) setWatching(watching bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.watching = watching
+	// Events may have been missed while the stream was not watched.
+	c.lastList = time.Time{}
+}
+
+// Invalidate makes the next ListContainers list all the containers from docker.
+func (c *ContainerCache) Invalidate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.lastList = time.Time{}
+}
+
+// relist replaces the cache with the containers listed from docker. Must be called with the lock held.
+func (c *ContainerCache) relist() error {
+	containers, err := c.DockerInterface.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		return err
+	}
+	c.containers = make(map[string]*cachedContainer, len(containers))
+	for _, container := range containers {
+		c.containers[container.ID] = &cachedContainer{
+			container: container,
+			running:   strings.HasPrefix(container.Status, "Up"),
+		}
+	}
+	c.lastList = time.Now()
+	c.seq++
+	c.listed = c.seq
+	c.changed = map[string]uint64{}
+	return nil
+}
+
+// refresh inspects the container with the given id and updates its entry in the cache, unless
+// the container was listed again, removed or refreshed since the inspection started.
+// It returns the updated container, or nil if the container no longer exists.
+func (c *ContainerCache) refresh(id string) *docker.APIContainers {
+	c.lock.Lock()
+	started := c.seq
+	c.lock.Unlock()
+	inspected, err := c.DockerInterface.InspectContainer(id)
+	if err != nil {
+		if _, ok := err.(*docker.NoSuchContainer); ok {
+			c.forget(id)
+			return nil
+		}
+		// The container can't be trusted anymore, list everything again next time.
+		glog.Errorf("Failed to inspect container %q: %v", id, err)
+		c.Invalidate()
+		return nil
+	}
+	cached := toCachedContainer(inspected)
+	container := cached.container
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if changed, found := c.changed[id]; found && changed > started {
+		if _, found := c.containers[id]; !found {
+			// Removed while it was inspected, don't bring it back.
+			return nil
+		}
+		// Another inspection may have seen a newer state, list everything again next time.
+		c.lastList = time.Time{}
+		return &container
+	}
+	if c.listed > started {
+		// The relist may have seen a newer state, list everything again next time.
+		c.lastList = time.Time{}
+		return &container
+	}
+	if c.containers != nil {
+		c.seq++
+		c.changed[id] = c.seq
+		c.containers[id] = cached
+	}
+	return &container
+}
+
+// forget removes the container with the given id from the cache.
+func (c *ContainerCache) forget(id string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.seq++
+	c.changed[id] = c.seq
+	delete(c.containers, id)
+}
+
+// toCachedContainer converts an inspected container to the form returned by ListContainers.
+func toCachedContainer(inspected *docker.Container) *cachedContainer {
+	container := docker.APIContainers{
+		ID:      inspected.ID,
+		Image:   inspected.Image,
+		Created: inspected.Created.Unix(),
+		Command: strings.Join(append([]string{inspected.Path}, inspected.Args...), " "),
+	}
+	if inspected.Config != nil {
+		container.Image = inspected.Config.Image
+	}
+	if inspected.Name != "" {
+		container.Names = []string{inspected.Name}
+	}
+	switch {
+	case inspected.State.Paused:
+		container.Status = "Up (Paused)"
+	case inspected.State.Running:
+		container.Status = "Up"
+	default:
+		container.Status = fmt.Sprintf("Exited (%d)", inspected.State.ExitCode)
+	}
+	return &cachedContainer{
+		container: container,
+		running:   inspected.State.Running,
+	}
+}
+
+// ListContainers returns the cached containers. Options other than All are served by docker directly.
+func (c *ContainerCache) ListContainers(options docker.ListContainersOptions) ([]docker.APIContainers, error) {
+	if options.Size || options.Limit != 0 || options.Since != "" || options.Before != "" {
+		return c.DockerInterface.ListContainers(options)
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.watching || c.lastList.IsZero() || time.Since(c.lastList) > c.relistPeriod {
+		if err := c.relist(); err != nil {
+			return nil, err
+		}
+	}
+	containers := make([]docker.APIContainers, 0, len(c.containers))
+	for _, cached := range c.containers {
+		if options.All || cached.running {
+			containers = append(containers, cached.container)
+		}
+	}
+	// Docker lists the newest containers first.
+	sort.Sort(byCreatedDescending(containers))
+	return containers, nil
+}
+
+type byCreatedDescending []docker.APIContainers
+
+func (a byCreatedDescending) Len() int      { return len(a) }
+func (a byCreatedDescending) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byCreatedDescending) Less(i, j int) bool {
+	if a[i].Created != a[j].Created {
+		return a[i].Created > a[j].Created
+	}
+	return a[i].ID < a[j].ID
+}
+
+func (c *ContainerCache) CreateContainer(opts docker.CreateContainerOptions) (*docker.Container, error) {
+	container, err := c.DockerInterface.CreateContainer(opts)
+	if err == nil && container != nil {
+		c.refresh(container.ID)
+	}
+	return container, err
+}
+
+func (c *ContainerCache) StartContainer(id string, hostConfig *docker.HostConfig) error {
+	err := c.DockerInterface.StartContainer(id, hostConfig)
+	c.refresh(id)
+	return err
+}
+
+func (c *ContainerCache) StopContainer(id string, timeout uint) error {
+	err := c.DockerInterface.StopContainer(id, timeout)
+	c.refresh(id)
+	return err
+}
+
+func (c *ContainerCache) UpdateContainerConfig(id string, conf []docker.KeyValuePair) error {
+	err := c.DockerInterface.UpdateContainerConfig(id, conf)
+	c.refresh(id)
+	return err
+}
+
+func (c *ContainerCache) PullImageAndApply(opts docker.MergeImageOptions, auth docker.AuthConfiguration) error {
+	err := c.DockerInterface.PullImageAndApply(opts, auth)
+	c.refresh(opts.Container)
+	return err
+}
+
+func (c *ContainerCache) DiffImageAndApply(opts docker.MergeImageOptions) error {
+	err := c.DockerInterface.DiffImageAndApply(opts)
+	c.refresh(opts.Container)
+	return err
+}
+
+func (c *ContainerCache) RemoveContainer(opts docker.RemoveContainerOptions) error {
+	err := c.DockerInterface.RemoveContainer(opts)
+	if err == nil {
+		c.forget(opts.ID)
+	}
+	return err
+}
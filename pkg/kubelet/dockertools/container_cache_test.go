@@ -0,0 +1,279 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dockertools
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+)
+
+func containerIDs(containers []docker.APIContainers) []string {
+	ids := []string{}
+	for _, container := range containers {
+		ids = append(ids, container.ID)
+	}
+	return ids
+}
+
+func TestContainerCacheListContainers(t *testing.T) {
+	fakeDocker := &FakeDockerClient{
+		ContainerList: []docker.APIContainers{
+			{ID: "1", Names: []string{"/k8s_a"}, Status: "Up 2 hours", Created: 2},
+			{ID: "2", Names: []string{"/k8s_b"}, Status: "Exited (0) 1 hours ago", Created: 1},
+		},
+		ContainerMap: map[string]*docker.Container{
+			"3": {
+				ID:      "3",
+				Name:    "/k8s_c",
+				Created: time.Unix(3, 0),
+				Config:  &docker.Config{Image: "image"},
+				State:   docker.State{Running: true},
+			},
+		},
+	}
+	cache := NewContainerCache(fakeDocker, time.Hour)
+	cache.watching = true
+
+	containers, err := cache.ListContainers(docker.ListContainersOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := containerIDs(containers); !reflect.DeepEqual(ids, []string{"1"}) {
+		t.Errorf("expected the running containers, got %v", ids)
+	}
+	containers, err = cache.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := containerIDs(containers); !reflect.DeepEqual(ids, []string{"1", "2"}) {
+		t.Errorf("expected all the containers, got %v", ids)
+	}
+	verifyCalls(t, fakeDocker, []string{"list"})
+
+	// The containers started and removed through the cache are seen without listing again.
+	if err := cache.StartContainer("3", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cache.RemoveContainer(docker.RemoveContainerOptions{ID: "2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	containers, err = cache.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := containerIDs(containers); !reflect.DeepEqual(ids, []string{"3", "1"}) {
+		t.Errorf("expected the newest containers first, got %v", ids)
+	}
+	if containers[0].Image != "image" || containers[0].Names[0] != "/k8s_c" || containers[0].Status != "Up" {
+		t.Errorf("unexpected container: %#v", containers[0])
+	}
+	verifyCalls(t, fakeDocker, []string{"list", "start", "inspect_container", "remove"})
+
+	cache.Invalidate()
+	containers, err = cache.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := containerIDs(containers); !reflect.DeepEqual(ids, []string{"1", "2"}) {
+		t.Errorf("expected the containers listed again, got %v", ids)
+	}
+	verifyCalls(t, fakeDocker, []string{"list", "start", "inspect_container", "remove", "list"})
+}
+
+func TestContainerCacheRelistPeriod(t *testing.T) {
+	fakeDocker := &FakeDockerClient{}
+	cache := NewContainerCache(fakeDocker, time.Nanosecond)
+	cache.watching = true
+	for i := 0; i < 2; i++ {
+		if _, err := cache.ListContainers(docker.ListContainersOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	verifyCalls(t, fakeDocker, []string{"list", "list"})
+}
+
+func TestContainerCacheNotWatching(t *testing.T) {
+	fakeDocker := &FakeDockerClient{}
+	cache := NewContainerCache(fakeDocker, time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, err := cache.ListContainers(docker.ListContainersOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	verifyCalls(t, fakeDocker, []string{"list", "list"})
+}
+
+type containerEvent struct {
+	event     *docker.APIEvents
+	container *docker.APIContainers
+}
+
+func TestContainerCacheEvents(t *testing.T) {
+	fakeDocker := &FakeDockerClient{
+		ContainerMap: map[string]*docker.Container{
+			"1": {ID: "1", Name: "/k8s_a", State: docker.State{Running: true}},
+		},
+	}
+	cache := NewContainerCache(fakeDocker, time.Hour)
+	events := make(chan containerEvent, 10)
+	cache.Start(func(event *docker.APIEvents, container *docker.APIContainers) {
+		events <- containerEvent{event, container}
+	})
+	for i := 0; ; i++ {
+		cache.lock.Lock()
+		watching := cache.watching
+		cache.lock.Unlock()
+		if watching {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("the cache never watched the docker events")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := cache.ListContainers(docker.ListContainersOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The started container is seen from its event alone.
+	fakeDocker.EmitEvent(&docker.APIEvents{Status: "start", ID: "1"})
+	got := <-events
+	if got.event.Status != "start" || got.container == nil || got.container.Names[0] != "/k8s_a" {
+		t.Errorf("unexpected event: %#v %#v", got.event, got.container)
+	}
+	containers, err := cache.ListContainers(docker.ListContainersOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := containerIDs(containers); !reflect.DeepEqual(ids, []string{"1"}) {
+		t.Errorf("expected the started container, got %v", ids)
+	}
+
+	// Image events are not about containers.
+	fakeDocker.EmitEvent(&docker.APIEvents{Status: "untag", ID: "image"})
+	fakeDocker.EmitEvent(&docker.APIEvents{Status: "destroy", ID: "1"})
+	got = <-events
+	if got.event.Status != "destroy" || got.container != nil {
+		t.Errorf("unexpected event: %#v %#v", got.event, got.container)
+	}
+	containers, err = cache.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(containers) != 0 {
+		t.Errorf("expected the destroyed container to be forgotten, got %v", containerIDs(containers))
+	}
+}
+
+// racingDockerClient runs during before every inspection returns.
+type racingDockerClient struct {
+	*FakeDockerClient
+	during func()
+}
+
+func (r *racingDockerClient) InspectContainer(id string) (*docker.Container, error) {
+	container, err := r.FakeDockerClient.InspectContainer(id)
+	if r.during != nil {
+		r.during()
+	}
+	return container, err
+}
+
+func TestContainerCacheRefreshRaces(t *testing.T) {
+	fakeDocker := &FakeDockerClient{
+		ContainerList: []docker.APIContainers{
+			{ID: "1", Names: []string{"/k8s_a"}, Status: "Up 2 hours"},
+		},
+		ContainerMap: map[string]*docker.Container{
+			"1": {ID: "1", Name: "/k8s_a", State: docker.State{Running: true}},
+		},
+	}
+	client := &racingDockerClient{FakeDockerClient: fakeDocker}
+	cache := NewContainerCache(client, time.Hour)
+	cache.watching = true
+	if _, err := cache.ListContainers(docker.ListContainersOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A container removed while it is inspected is not brought back.
+	client.during = func() { cache.forget("1") }
+	if container := cache.refresh("1"); container != nil {
+		t.Errorf("expected no container, got %#v", container)
+	}
+	client.during = nil
+	containers, err := cache.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(containers) != 0 {
+		t.Errorf("expected the removed container to stay forgotten, got %v", containerIDs(containers))
+	}
+
+	// A container listed again while it is inspected is left to the list, which is made again.
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1", Names: []string{"/k8s_a"}, Status: "Exited (0) 1 seconds ago"},
+	}
+	client.during = func() {
+		cache.lock.Lock()
+		defer cache.lock.Unlock()
+		if err := cache.relist(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if container := cache.refresh("1"); container == nil || container.Status != "Up" {
+		t.Errorf("expected the inspected container, got %#v", container)
+	}
+	client.during = nil
+	cache.lock.Lock()
+	status, lastList := cache.containers["1"].container.Status, cache.lastList
+	cache.lock.Unlock()
+	if status != "Exited (0) 1 seconds ago" {
+		t.Errorf("expected the listed container to be kept, got %q", status)
+	}
+	if !lastList.IsZero() {
+		t.Errorf("expected the cache to be listed again")
+	}
+}
+
+func TestContainerCacheMergeImage(t *testing.T) {
+	fakeDocker := &FakeDockerClient{
+		ContainerList: []docker.APIContainers{
+			{ID: "1", Names: []string{"/k8s_a"}, Image: "old", Status: "Up 2 hours"},
+		},
+		ContainerMap: map[string]*docker.Container{
+			"1": {ID: "1", Name: "/k8s_a", Config: &docker.Config{Image: "new"}, State: docker.State{Running: true}},
+		},
+	}
+	cache := NewContainerCache(fakeDocker, time.Hour)
+	cache.watching = true
+	if _, err := cache.ListContainers(docker.ListContainersOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cache.PullImageAndApply(docker.MergeImageOptions{Container: "1", Repository: "new"}, docker.AuthConfiguration{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	containers, err := cache.ListContainers(docker.ListContainersOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(containers) != 1 || containers[0].Image != "new" {
+		t.Errorf("expected the container with the merged image, got %#v", containers)
+	}
+}
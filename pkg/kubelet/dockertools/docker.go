@@ -21,16 +21,19 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"hash/adler32"
 	"io"
 	"io/ioutil"
 	"math/rand"
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/credentialprovider"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/golang/glog"
@@ -46,16 +49,23 @@ type DockerInterface interface {
 	RemoveContainer(opts docker.RemoveContainerOptions) error
 	CommitContainer(opts docker.CommitContainerOptions) (*docker.Image, error)
 	InspectImage(image string) (*docker.Image, error)
+	ListImages(all bool) ([]docker.APIImages, error)
+	RemoveImage(image string) error
 	PullImage(opts docker.PullImageOptions, auth docker.AuthConfiguration) error
 	PushImage(opts docker.PushImageOptions, auth docker.AuthConfiguration) error
 	Logs(opts docker.LogsOptions) error
 	Version() (*docker.Env, error)
 	CreateExec(docker.CreateExecOptions) (*docker.Exec, error)
 	StartExec(string, docker.StartExecOptions) error
+	ResizeExecTTY(id string, height, width int) error
+	AttachToContainer(opts docker.AttachToContainerOptions) error
+	ResizeContainerTTY(id string, height, width int) error
 	UpdateContainerCgroup(id string, conf []docker.KeyValuePair) ([]docker.CgroupResponse, error)
 	UpdateContainerConfig(id string, conf []docker.KeyValuePair) error
 	PullImageAndApply(opts docker.MergeImageOptions, auth docker.AuthConfiguration) error
 	DiffImageAndApply(opts docker.MergeImageOptions) error
+	AddEventListener(listener chan<- *docker.APIEvents) error
+	RemoveEventListener(listener chan *docker.APIEvents) error
 }
 
 // DockerID is an ID of docker container. It is a type to make it clear when we're working with docker container Ids
@@ -147,23 +157,46 @@ func (d *dockerContainerCommandRunner) getRunInContainerCommand(containerID stri
 	return command, nil
 }
 
-func (d *dockerContainerCommandRunner) runInContainerUsingNsinit(containerID string, cmd []string) ([]byte, error) {
+func (d *dockerContainerCommandRunner) runInContainerUsingNsinit(containerID string, cmd []string, timeout time.Duration) ([]byte, error) {
 	c, err := d.getRunInContainerCommand(containerID, cmd)
 	if err != nil {
 		return nil, err
 	}
-	return c.CombinedOutput()
+	if timeout <= 0 {
+		return c.CombinedOutput()
+	}
+	var buf bytes.Buffer
+	c.Stdout = &buf
+	c.Stderr = &buf
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Wait()
+	}()
+	select {
+	case err = <-done:
+		return buf.Bytes(), err
+	case <-time.After(timeout):
+		c.Process.Kill()
+		<-done
+		return buf.Bytes(), fmt.Errorf("%v killed after %v", cmd, timeout)
+	}
 }
 
-// RunInContainer uses nsinit to run the command inside the container identified by containerID
-func (d *dockerContainerCommandRunner) RunInContainer(containerID string, cmd []string) ([]byte, error) {
+// RunInContainer uses nsinit to run the command inside the container identified by containerID.
+// A command run with nsinit is killed once it has run for timeout, zero meaning no timeout.  The
+// exec API of docker has no way to stop an exec instance, so a command run with docker exec is
+// only given up on after timeout and left to finish in the container.
+func (d *dockerContainerCommandRunner) RunInContainer(containerID string, cmd []string, timeout time.Duration) ([]byte, error) {
 	// If native exec support does not exist in the local docker daemon use nsinit.
 	useNativeExec, err := d.nativeExecSupportExists()
 	if err != nil {
 		return nil, err
 	}
 	if !useNativeExec {
-		return d.runInContainerUsingNsinit(containerID, cmd)
+		return d.runInContainerUsingNsinit(containerID, cmd, timeout)
 	}
 	createOpts := docker.CreateExecOptions{
 		Container:    containerID,
@@ -190,8 +223,144 @@ func (d *dockerContainerCommandRunner) RunInContainer(containerID string, cmd []
 	go func() {
 		errChan <- d.client.StartExec(execObj.Id, startOpts)
 	}()
+	var expired <-chan time.Time
+	if timeout > 0 {
+		expired = time.After(timeout)
+	}
+	select {
+	case err = <-errChan:
+	case <-expired:
+		return nil, fmt.Errorf("%v timed out after %v", cmd, timeout)
+	}
 	wrBuf.Flush()
-	return buf.Bytes(), <-errChan
+	return buf.Bytes(), err
+}
+
+func (d *dockerContainerCommandRunner) execInContainerUsingNsinit(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	if tty {
+		return fmt.Errorf("a tty requires a docker daemon with native exec support")
+	}
+	c, err := d.getRunInContainerCommand(containerID, cmd)
+	if err != nil {
+		return err
+	}
+	c.Stdin = stdin
+	c.Stdout = stdout
+	c.Stderr = stderr
+	return c.Run()
+}
+
+// ExecInContainer runs the command inside the container identified by containerID with the given
+// streams, any of which may be nil, until it exits. It uses docker exec if the docker daemon supports
+// it and nsinit otherwise. The terminal of the command is resized on every size received on resize.
+func (d *dockerContainerCommandRunner) ExecInContainer(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	useNativeExec, err := d.nativeExecSupportExists()
+	if err != nil {
+		return err
+	}
+	if !useNativeExec {
+		return d.execInContainerUsingNsinit(containerID, cmd, stdin, stdout, stderr, tty)
+	}
+	createOpts := docker.CreateExecOptions{
+		Container:    containerID,
+		Cmd:          cmd,
+		AttachStdin:  stdin != nil,
+		AttachStdout: stdout != nil,
+		AttachStderr: stderr != nil,
+		Tty:          tty,
+	}
+	execObj, err := d.client.CreateExec(createOpts)
+	if err != nil {
+		return fmt.Errorf("failed to exec in container - Exec setup failed - %v", err)
+	}
+	if resize != nil {
+		go func() {
+			for size := range resize {
+				if err := d.client.ResizeExecTTY(execObj.Id, int(size.Height), int(size.Width)); err != nil {
+					glog.V(4).Infof("Failed to resize the tty of exec %s: %v", execObj.Id, err)
+				}
+			}
+		}()
+	}
+	startOpts := docker.StartExecOptions{
+		Detach:       false,
+		Tty:          tty,
+		InputStream:  stdin,
+		OutputStream: stdout,
+		ErrorStream:  stderr,
+		RawTerminal:  tty,
+	}
+	return d.client.StartExec(execObj.Id, startOpts)
+}
+
+// AttachContainer attaches the given streams, any of which may be nil, to the main process of the
+// container identified by containerID until it exits. The terminal of the container is resized on
+// every size received on resize.
+func AttachContainer(client DockerInterface, containerID string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	if resize != nil {
+		go func() {
+			for size := range resize {
+				if err := client.ResizeContainerTTY(containerID, int(size.Height), int(size.Width)); err != nil {
+					glog.V(4).Infof("Failed to resize the tty of container %s: %v", containerID, err)
+				}
+			}
+		}()
+	}
+	opts := docker.AttachToContainerOptions{
+		Container:    containerID,
+		InputStream:  stdin,
+		OutputStream: stdout,
+		ErrorStream:  stderr,
+		Stream:       true,
+		Stdin:        stdin != nil,
+		Stdout:       stdout != nil,
+		Stderr:       stderr != nil,
+		RawTerminal:  tty,
+	}
+	return client.AttachToContainer(opts)
+}
+
+// PortForward copies the data of stream to and from a TCP connection to port on localhost in the
+// network namespace of the container identified by containerID until either side closes. It enters
+// the namespace with nsenter and connects with socat, which must both be installed on the host.
+func (d *dockerContainerCommandRunner) PortForward(containerID string, port uint16, stream io.ReadWriter) error {
+	container, err := d.client.InspectContainer(containerID)
+	if err != nil {
+		return err
+	}
+	if !container.State.Running {
+		return fmt.Errorf("container not running (%s)", containerID)
+	}
+	nsenterPath, err := exec.LookPath("nsenter")
+	if err != nil {
+		return fmt.Errorf("unable to do port forwarding: nsenter not found")
+	}
+	socatPath, err := exec.LookPath("socat")
+	if err != nil {
+		return fmt.Errorf("unable to do port forwarding: socat not found")
+	}
+	args := []string{"-t", strconv.Itoa(container.State.Pid), "-n", socatPath, "-", fmt.Sprintf("TCP4:localhost:%d", port)}
+	command := exec.Command(nsenterPath, args...)
+	// The data from the client is copied through a pipe, as exec would otherwise wait for the
+	// client to send data or close after socat exited.
+	stdin, err := command.StdinPipe()
+	if err != nil {
+		return err
+	}
+	command.Stdout = stream
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+	if err := command.Start(); err != nil {
+		return err
+	}
+	go func() {
+		io.Copy(stdin, stream)
+		stdin.Close()
+	}()
+	if err := command.Wait(); err != nil {
+		return fmt.Errorf("failed to forward port %d of container %s: %v: %s", port, containerID, err, stderr.String())
+	}
+	return nil
 }
 
 // NewDockerContainerCommandRunner creates a ContainerCommandRunner which uses nsinit to run a command
@@ -201,6 +370,10 @@ func NewDockerContainerCommandRunner(client DockerInterface) ContainerCommandRun
 }
 
 func (p dockerPuller) Pull(image string) error {
+	start := time.Now()
+	defer func() {
+		metrics.ImagePullLatency.Observe(metrics.SinceInSeconds(start))
+	}()
 	image, tag := parseImageName(image)
 
 	// If no tag was specified, use the default "latest".
@@ -437,8 +610,10 @@ func inspectContainer(client DockerInterface, dockerID, containerName, tPath str
 // GetDockerPodInfo returns docker info for all containers in the pod/manifest.
 func GetDockerPodInfo(client DockerInterface, manifest api.PodSpec, podFullName, uuid string) (api.PodInfo, error) {
 	info := api.PodInfo{}
+	// The init containers report their status like the containers of the pod.
+	specContainers := append(append([]api.Container{}, manifest.InitContainers...), manifest.Containers...)
 	expectedContainers := make(map[string]api.Container)
-	for _, container := range manifest.Containers {
+	for _, container := range specContainers {
 		expectedContainers[container.Name] = container
 	}
 	expectedContainers["net"] = api.Container{}
@@ -487,11 +662,11 @@ func GetDockerPodInfo(client DockerInterface, manifest api.PodSpec, podFullName,
 		return nil, ErrNoNetworkContainerInPod
 	}
 
-	if len(info) < (len(manifest.Containers) + 1) {
+	if len(info) < (len(specContainers) + 1) {
 		var containerStatus api.ContainerStatus
 		// Not all containers expected are created, verify if there are
 		// image related issues
-		for _, container := range manifest.Containers {
+		for _, container := range specContainers {
 			if _, found := info[container.Name]; found {
 				continue
 			}
@@ -523,10 +698,9 @@ func GetDockerPodInfo(client DockerInterface, manifest api.PodSpec, podFullName,
 
 const containerNamePrefix = "k8s"
 
+// HashContainer returns the kubecontainer.HashContainer of a container, which its docker name carries.
 func HashContainer(container *api.Container) uint64 {
-	hash := adler32.New()
-	fmt.Fprintf(hash, "%#v", *container)
-	return uint64(hash.Sum32())
+	return kubecontainer.HashContainer(container)
 }
 
 // Creates a name which can be reversed to identify both full pod name and container name.
@@ -618,5 +792,7 @@ func ParseImageName(image string) (string, string, string) {
 }
 
 type ContainerCommandRunner interface {
-	RunInContainer(containerID string, cmd []string) ([]byte, error)
+	RunInContainer(containerID string, cmd []string, timeout time.Duration) ([]byte, error)
+	ExecInContainer(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error
+	PortForward(containerID string, port uint16, stream io.ReadWriter) error
 }
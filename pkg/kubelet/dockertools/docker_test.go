@@ -17,13 +17,16 @@ limitations under the License.
 package dockertools
 
 import (
+	"bytes"
 	"fmt"
 	"hash/adler32"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/credentialprovider"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
 	docker "github.com/fsouza/go-dockerclient"
 )
 
@@ -264,3 +267,23 @@ func TestDockerKeyringLookup(t *testing.T) {
 		}
 	}
 }
+
+func TestInstrumentedDockerInterface(t *testing.T) {
+	fakeDocker := &FakeDockerClient{Err: fmt.Errorf("test error")}
+	client := NewInstrumentedDockerInterface(fakeDocker)
+	if _, err := client.ListContainers(docker.ListContainersOptions{}); err == nil {
+		t.Errorf("expected the error of the wrapped client")
+	}
+	verifyCalls(t, fakeDocker, []string{"list"})
+
+	var buf bytes.Buffer
+	metrics.WriteText(&buf, metrics.DockerOperationsErrors.Collect())
+	if !strings.Contains(buf.String(), `kubelet_docker_operations_errors_total{operation="list_containers"} 1`) {
+		t.Errorf("expected a recorded error, got %s", buf.String())
+	}
+	buf.Reset()
+	metrics.WriteText(&buf, metrics.DockerOperationsLatency.Collect())
+	if !strings.Contains(buf.String(), `kubelet_docker_operations_latency_seconds_count{operation="list_containers"} 1`) {
+		t.Errorf("expected a recorded latency, got %s", buf.String())
+	}
+}
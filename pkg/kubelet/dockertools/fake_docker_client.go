@@ -31,15 +31,19 @@ type FakeDockerClient struct {
 	Container     *docker.Container
 	ContainerMap  map[string]*docker.Container
 	Image         *docker.Image
+	MissingImages []string
+	Images        []docker.APIImages
 	Err           error
 	called        []string
 	Stopped       []string
 	pulled        []string
 	Created       []string
 	Removed       []string
+	RemovedImages []string
 	Commit        []string
 	Push          []string
 	VersionInfo   docker.Env
+	listeners     []chan<- *docker.APIEvents
 }
 
 func (f *FakeDockerClient) clearCalls() {
@@ -83,14 +87,51 @@ func (f *FakeDockerClient) InspectContainer(id string) (*docker.Container, error
 }
 
 // InspectImage is a test-spy implementation of DockerInterface.InspectImage.
-// It adds an entry "inspect" to the internal method call record.
+// It adds an entry "inspect" to the internal method call record, and returns
+// docker.ErrNoSuchImage for the MissingImages.  The other images are Image, or
+// an image whose ID is the name if Image is nil.
 func (f *FakeDockerClient) InspectImage(name string) (*docker.Image, error) {
 	f.Lock()
 	defer f.Unlock()
 	f.called = append(f.called, "inspect_image")
+	for _, missing := range f.MissingImages {
+		if name == missing {
+			return nil, docker.ErrNoSuchImage
+		}
+	}
+	if f.Image == nil && f.Err == nil {
+		return &docker.Image{ID: name}, nil
+	}
 	return f.Image, f.Err
 }
 
+// ListImages is a test-spy implementation of DockerInterface.ListImages.
+// It adds an entry "list_images" to the internal method call record.
+func (f *FakeDockerClient) ListImages(all bool) ([]docker.APIImages, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.called = append(f.called, "list_images")
+	return f.Images, f.Err
+}
+
+// RemoveImage is a test-spy implementation of DockerInterface.RemoveImage.
+// It adds an entry "remove_image" to the internal method call record.
+func (f *FakeDockerClient) RemoveImage(image string) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called = append(f.called, "remove_image")
+	if f.Err == nil {
+		f.RemovedImages = append(f.RemovedImages, image)
+		for i := range f.Images {
+			if f.Images[i].ID == image {
+				f.Images = append(f.Images[:i], f.Images[i+1:]...)
+				break
+			}
+		}
+	}
+	return f.Err
+}
+
 // CreateContainer is a test-spy implementation of DockerInterface.CreateContainer.
 // It adds an entry "create" to the internal method call record.
 func (f *FakeDockerClient) CreateContainer(c docker.CreateContainerOptions) (*docker.Container, error) {
@@ -190,6 +231,29 @@ func (f *FakeDockerClient) StartExec(_ string, _ docker.StartExecOptions) error
 	return nil
 }
 
+func (f *FakeDockerClient) ResizeExecTTY(id string, height, width int) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called = append(f.called, "resize_exec")
+	return f.Err
+}
+
+// AttachToContainer is a test-spy implementation of DockerInterface.AttachToContainer.
+// It adds an entry "attach" to the internal method call record.
+func (f *FakeDockerClient) AttachToContainer(opts docker.AttachToContainerOptions) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called = append(f.called, "attach")
+	return f.Err
+}
+
+func (f *FakeDockerClient) ResizeContainerTTY(id string, height, width int) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called = append(f.called, "resize")
+	return f.Err
+}
+
 // FakeDockerPuller is a stub implementation of DockerPuller.
 type FakeDockerPuller struct {
 	sync.Mutex
@@ -258,3 +322,41 @@ func (f *FakeDockerClient) DiffImageAndApply(opts docker.MergeImageOptions) erro
 	f.called = append(f.called, "merge")
 	return nil
 }
+
+// AddEventListener is a test-spy implementation of DockerInterface.AddEventListener.
+// It adds an entry "add_event_listener" to the internal method call record.
+func (f *FakeDockerClient) AddEventListener(listener chan<- *docker.APIEvents) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called = append(f.called, "add_event_listener")
+	if f.Err != nil {
+		return f.Err
+	}
+	f.listeners = append(f.listeners, listener)
+	return nil
+}
+
+// RemoveEventListener is a test-spy implementation of DockerInterface.RemoveEventListener.
+// It adds an entry "remove_event_listener" to the internal method call record.
+func (f *FakeDockerClient) RemoveEventListener(listener chan *docker.APIEvents) error {
+	f.Lock()
+	defer f.Unlock()
+	f.called = append(f.called, "remove_event_listener")
+	for i := range f.listeners {
+		if f.listeners[i] == listener {
+			f.listeners = append(f.listeners[:i], f.listeners[i+1:]...)
+			break
+		}
+	}
+	return f.Err
+}
+
+// EmitEvent sends event to every listener added with AddEventListener.
+func (f *FakeDockerClient) EmitEvent(event *docker.APIEvents) {
+	f.Lock()
+	listeners := append([]chan<- *docker.APIEvents{}, f.listeners...)
+	f.Unlock()
+	for _, listener := range listeners {
+		listener <- event
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dockertools
+
+import (
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
+	"github.com/fsouza/go-dockerclient"
+)
+
+// instrumentedDockerInterface records the latency and the errors of the calls to a DockerInterface.
+type instrumentedDockerInterface struct {
+	client DockerInterface
+}
+
+// NewInstrumentedDockerInterface wraps client to record the latency and the errors of its calls in the kubelet metrics.
+func NewInstrumentedDockerInterface(client DockerInterface) DockerInterface {
+	return instrumentedDockerInterface{client}
+}
+
+func recordOperation(operation string, start time.Time, err error) {
+	metrics.DockerOperationsLatency.Observe(metrics.SinceInSeconds(start), operation)
+	if err != nil {
+		metrics.DockerOperationsErrors.Inc(operation)
+	}
+}
+
+func (in instrumentedDockerInterface) ListContainers(options docker.ListContainersOptions) ([]docker.APIContainers, error) {
+	start := time.Now()
+	containers, err := in.client.ListContainers(options)
+	recordOperation("list_containers", start, err)
+	return containers, err
+}
+
+func (in instrumentedDockerInterface) InspectContainer(id string) (*docker.Container, error) {
+	start := time.Now()
+	container, err := in.client.InspectContainer(id)
+	recordOperation("inspect_container", start, err)
+	return container, err
+}
+
+func (in instrumentedDockerInterface) CreateContainer(opts docker.CreateContainerOptions) (*docker.Container, error) {
+	start := time.Now()
+	container, err := in.client.CreateContainer(opts)
+	recordOperation("create_container", start, err)
+	return container, err
+}
+
+func (in instrumentedDockerInterface) StartContainer(id string, hostConfig *docker.HostConfig) error {
+	start := time.Now()
+	err := in.client.StartContainer(id, hostConfig)
+	recordOperation("start_container", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) StopContainer(id string, timeout uint) error {
+	start := time.Now()
+	err := in.client.StopContainer(id, timeout)
+	recordOperation("stop_container", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) RemoveContainer(opts docker.RemoveContainerOptions) error {
+	start := time.Now()
+	err := in.client.RemoveContainer(opts)
+	recordOperation("remove_container", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) CommitContainer(opts docker.CommitContainerOptions) (*docker.Image, error) {
+	start := time.Now()
+	image, err := in.client.CommitContainer(opts)
+	recordOperation("commit_container", start, err)
+	return image, err
+}
+
+func (in instrumentedDockerInterface) InspectImage(image string) (*docker.Image, error) {
+	start := time.Now()
+	img, err := in.client.InspectImage(image)
+	recordOperation("inspect_image", start, err)
+	return img, err
+}
+
+func (in instrumentedDockerInterface) ListImages(all bool) ([]docker.APIImages, error) {
+	start := time.Now()
+	images, err := in.client.ListImages(all)
+	recordOperation("list_images", start, err)
+	return images, err
+}
+
+func (in instrumentedDockerInterface) RemoveImage(image string) error {
+	start := time.Now()
+	err := in.client.RemoveImage(image)
+	recordOperation("remove_image", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) PullImage(opts docker.PullImageOptions, auth docker.AuthConfiguration) error {
+	start := time.Now()
+	err := in.client.PullImage(opts, auth)
+	recordOperation("pull_image", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) PushImage(opts docker.PushImageOptions, auth docker.AuthConfiguration) error {
+	start := time.Now()
+	err := in.client.PushImage(opts, auth)
+	recordOperation("push_image", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) Logs(opts docker.LogsOptions) error {
+	start := time.Now()
+	err := in.client.Logs(opts)
+	recordOperation("logs", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) Version() (*docker.Env, error) {
+	start := time.Now()
+	env, err := in.client.Version()
+	recordOperation("version", start, err)
+	return env, err
+}
+
+func (in instrumentedDockerInterface) CreateExec(opts docker.CreateExecOptions) (*docker.Exec, error) {
+	start := time.Now()
+	exec, err := in.client.CreateExec(opts)
+	recordOperation("create_exec", start, err)
+	return exec, err
+}
+
+func (in instrumentedDockerInterface) StartExec(id string, opts docker.StartExecOptions) error {
+	start := time.Now()
+	err := in.client.StartExec(id, opts)
+	recordOperation("start_exec", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) ResizeExecTTY(id string, height, width int) error {
+	start := time.Now()
+	err := in.client.ResizeExecTTY(id, height, width)
+	recordOperation("resize_exec", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) AttachToContainer(opts docker.AttachToContainerOptions) error {
+	start := time.Now()
+	err := in.client.AttachToContainer(opts)
+	recordOperation("attach", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) ResizeContainerTTY(id string, height, width int) error {
+	start := time.Now()
+	err := in.client.ResizeContainerTTY(id, height, width)
+	recordOperation("resize_container", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) UpdateContainerCgroup(id string, conf []docker.KeyValuePair) ([]docker.CgroupResponse, error) {
+	start := time.Now()
+	resp, err := in.client.UpdateContainerCgroup(id, conf)
+	recordOperation("update_container_cgroup", start, err)
+	return resp, err
+}
+
+func (in instrumentedDockerInterface) UpdateContainerConfig(id string, conf []docker.KeyValuePair) error {
+	start := time.Now()
+	err := in.client.UpdateContainerConfig(id, conf)
+	recordOperation("update_container_config", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) PullImageAndApply(opts docker.MergeImageOptions, auth docker.AuthConfiguration) error {
+	start := time.Now()
+	err := in.client.PullImageAndApply(opts, auth)
+	recordOperation("pull_image_and_apply", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) DiffImageAndApply(opts docker.MergeImageOptions) error {
+	start := time.Now()
+	err := in.client.DiffImageAndApply(opts)
+	recordOperation("diff_image_and_apply", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) AddEventListener(listener chan<- *docker.APIEvents) error {
+	start := time.Now()
+	err := in.client.AddEventListener(listener)
+	recordOperation("add_event_listener", start, err)
+	return err
+}
+
+func (in instrumentedDockerInterface) RemoveEventListener(listener chan *docker.APIEvents) error {
+	start := time.Now()
+	err := in.client.RemoveEventListener(listener)
+	recordOperation("remove_event_listener", start, err)
+	return err
+}
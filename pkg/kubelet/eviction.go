@@ -0,0 +1,541 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+)
+
+// Signals of the resources of the node which trigger evictions.
+const (
+	signalMemoryAvailable = "memory.available"
+	signalDataAvailable   = "data.available"
+)
+
+// Mount point of the data filesystem, which holds the xfs project quotas of the containers.
+const dataDirectory = "/data"
+
+// How often the eviction thresholds are checked.
+const evictionMonitoringPeriod = 10 * time.Second
+
+// Reason of the termination of the containers of evicted pods.
+const evictedReason = "Evicted"
+
+// signalConditions maps the signals to the node conditions they report.
+var signalConditions = map[string]api.NodeConditionKind{
+	signalMemoryAvailable: api.NodeMemoryPressure,
+	signalDataAvailable:   api.NodeDiskPressure,
+}
+
+// signalResources names the resources of the signals in the messages.
+var signalResources = map[string]string{
+	signalMemoryAvailable: "memory",
+	signalDataAvailable:   "disk space of " + dataDirectory,
+}
+
+// EvictionThreshold is a minimum of the available amount of a resource of the node below which
+// pods are evicted.
+type EvictionThreshold struct {
+	// Signal is one of memory.available and data.available.
+	Signal string
+	// Minimum available bytes, used if Percentage is zero.
+	Quantity uint64
+	// Minimum available percent of the capacity.
+	Percentage float64
+	// A soft threshold must stay crossed this long before pods are evicted. Zero for hard thresholds.
+	GracePeriod time.Duration
+}
+
+// min returns the minimum available bytes of the threshold given the capacity.
+func (t *EvictionThreshold) min(capacity uint64) uint64 {
+	if t.Percentage > 0 {
+		return uint64(float64(capacity) * t.Percentage / 100)
+	}
+	return t.Quantity
+}
+
+// ParseEvictionThresholds parses the hard and soft thresholds, given as comma separated lists
+// like "memory.available<100Mi,data.available<10%", and the grace periods of the soft thresholds,
+// given like "memory.available=1m30s". Every soft threshold requires a grace period.
+func ParseEvictionThresholds(hard, soft, softGracePeriods string) ([]EvictionThreshold, error) {
+	thresholds, err := parseThresholdList(hard)
+	if err != nil {
+		return nil, err
+	}
+	softThresholds, err := parseThresholdList(soft)
+	if err != nil {
+		return nil, err
+	}
+	gracePeriods := map[string]time.Duration{}
+	for _, spec := range splitList(softGracePeriods) {
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 || signalConditions[parts[0]] == "" {
+			return nil, fmt.Errorf("invalid eviction grace period %q", spec)
+		}
+		period, err := time.ParseDuration(parts[1])
+		if err != nil || period <= 0 {
+			return nil, fmt.Errorf("invalid eviction grace period %q", spec)
+		}
+		gracePeriods[parts[0]] = period
+	}
+	for _, threshold := range softThresholds {
+		period, found := gracePeriods[threshold.Signal]
+		if !found {
+			return nil, fmt.Errorf("no grace period for the soft eviction threshold of %s", threshold.Signal)
+		}
+		threshold.GracePeriod = period
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
+
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseThresholdList(list string) ([]EvictionThreshold, error) {
+	thresholds := []EvictionThreshold{}
+	for _, spec := range splitList(list) {
+		parts := strings.SplitN(spec, "<", 2)
+		if len(parts) != 2 || signalConditions[parts[0]] == "" {
+			return nil, fmt.Errorf("invalid eviction threshold %q", spec)
+		}
+		threshold := EvictionThreshold{Signal: parts[0]}
+		if strings.HasSuffix(parts[1], "%") {
+			percentage, err := strconv.ParseFloat(strings.TrimSuffix(parts[1], "%"), 64)
+			if err != nil || percentage <= 0 || percentage >= 100 {
+				return nil, fmt.Errorf("invalid eviction threshold %q", spec)
+			}
+			threshold.Percentage = percentage
+		} else {
+			quantity, err := parseBytes(parts[1])
+			if err != nil || quantity == 0 {
+				return nil, fmt.Errorf("invalid eviction threshold %q", spec)
+			}
+			threshold.Quantity = quantity
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
+
+// byteSuffixes are the multipliers of the suffixes of the quantities.
+var byteSuffixes = []struct {
+	suffix     string
+	multiplier uint64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40},
+	{"K", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12},
+}
+
+// parseBytes parses a quantity of bytes with an optional suffix, e.g. "100Mi".
+func parseBytes(value string) (uint64, error) {
+	multiplier := uint64(1)
+	for _, s := range byteSuffixes {
+		if strings.HasSuffix(value, s.suffix) {
+			value = strings.TrimSuffix(value, s.suffix)
+			multiplier = s.multiplier
+			break
+		}
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * multiplier, nil
+}
+
+// signalObservation is the available amount and the capacity of the resource of a signal in bytes.
+type signalObservation struct {
+	available uint64
+	capacity  uint64
+}
+
+// observeSignals returns the observations of the signals which could be observed.
+func observeSignals() map[string]signalObservation {
+	observations := map[string]signalObservation{}
+	if f, err := os.Open("/proc/meminfo"); err != nil {
+		glog.V(4).Infof("Failed to observe the available memory: %v", err)
+	} else {
+		available, capacity, err := parseMemInfo(f)
+		f.Close()
+		if err != nil {
+			glog.V(4).Infof("Failed to observe the available memory: %v", err)
+		} else {
+			observations[signalMemoryAvailable] = signalObservation{available, capacity}
+		}
+	}
+	if capacity, available, err := statfsUsage(dataDirectory); err != nil {
+		glog.V(4).Infof("Failed to observe the available space of %s: %v", dataDirectory, err)
+	} else {
+		observations[signalDataAvailable] = signalObservation{available, capacity}
+	}
+	return observations
+}
+
+// parseMemInfo returns the available and the total memory in bytes from the content of
+// /proc/meminfo. The available memory is estimated from the free and the cached memory on
+// kernels which do not report MemAvailable.
+func parseMemInfo(r io.Reader) (uint64, uint64, error) {
+	values := map[string]uint64{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = value * 1024
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	total, found := values["MemTotal"]
+	if !found {
+		return 0, 0, fmt.Errorf("no MemTotal in meminfo")
+	}
+	if available, found := values["MemAvailable"]; found {
+		return available, total, nil
+	}
+	return values["MemFree"] + values["Buffers"] + values["Cached"], total, nil
+}
+
+// evictionManager checks the eviction thresholds, reports the pressure conditions of the node
+// and keeps track of the evicted pods.
+type evictionManager struct {
+	thresholds []EvictionThreshold
+	// A pressure condition stays true this long after its thresholds were last crossed, so that
+	// it does not flap around a threshold.
+	pressureTransitionPeriod time.Duration
+	// Returns the observations of the signals, defaults to observeSignals.
+	observe func() map[string]signalObservation
+
+	lock sync.Mutex
+	// When the soft thresholds, by index, were first seen crossed since they were last met.
+	firstCrossed map[int]time.Time
+	// When a threshold of each signal was last seen crossed.
+	lastCrossed map[string]time.Time
+	conditions  map[api.NodeConditionKind]*api.NodeCondition
+	// Messages of the evictions of the evicted pods by UID.
+	evicted map[string]string
+}
+
+func newEvictionManager(thresholds []EvictionThreshold, pressureTransitionPeriod time.Duration) *evictionManager {
+	return &evictionManager{
+		thresholds:               thresholds,
+		pressureTransitionPeriod: pressureTransitionPeriod,
+		observe:                  observeSignals,
+		firstCrossed:             map[int]time.Time{},
+		lastCrossed:              map[string]time.Time{},
+		conditions:               map[api.NodeConditionKind]*api.NodeCondition{},
+		evicted:                  map[string]string{},
+	}
+}
+
+// check observes the signals and updates the pressure conditions. It returns the signal of the
+// first crossed hard threshold, or else of the first soft threshold crossed for longer than its
+// grace period, and the message of the evictions, if pods must be evicted.
+func (m *evictionManager) check(now time.Time) (string, string, bool) {
+	observations := m.observe()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	// Signals of the thresholds triggering evictions.
+	hard, soft := []string{}, []string{}
+	messages := map[string]string{}
+	for i := range m.thresholds {
+		threshold := &m.thresholds[i]
+		observation, found := observations[threshold.Signal]
+		if !found {
+			continue
+		}
+		min := threshold.min(observation.capacity)
+		if observation.available >= min {
+			delete(m.firstCrossed, i)
+			continue
+		}
+		m.lastCrossed[threshold.Signal] = now
+		msg := fmt.Sprintf("The node was low on %s: %d bytes available, below the threshold of %d bytes.",
+			signalResources[threshold.Signal], observation.available, min)
+		messages[threshold.Signal] = msg
+		if threshold.GracePeriod > 0 {
+			first, found := m.firstCrossed[i]
+			if !found {
+				m.firstCrossed[i] = now
+				first = now
+			}
+			if now.Sub(first) < threshold.GracePeriod {
+				continue
+			}
+		}
+		if threshold.GracePeriod == 0 {
+			hard = append(hard, threshold.Signal)
+		} else {
+			soft = append(soft, threshold.Signal)
+		}
+	}
+
+	for _, threshold := range m.thresholds {
+		kind := signalConditions[threshold.Signal]
+		last, crossed := m.lastCrossed[threshold.Signal]
+		status := api.ConditionFalse
+		if crossed && now.Sub(last) <= m.pressureTransitionPeriod {
+			status = api.ConditionTrue
+		}
+		condition, found := m.conditions[kind]
+		if found && condition.Status == status {
+			continue
+		}
+		condition = &api.NodeCondition{Kind: kind, Status: status, LastTransitionTime: now}
+		if status == api.ConditionTrue {
+			condition.Reason = "EvictionThresholdCrossed"
+			condition.Message = messages[threshold.Signal]
+		}
+		m.conditions[kind] = condition
+	}
+	if triggered := append(hard, soft...); len(triggered) > 0 {
+		return triggered[0], messages[triggered[0]], true
+	}
+	return "", "", false
+}
+
+// Conditions returns the pressure conditions of the node.
+func (m *evictionManager) Conditions() []api.NodeCondition {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	conditions := []api.NodeCondition{}
+	for _, condition := range m.conditions {
+		conditions = append(conditions, *condition)
+	}
+	sort.Sort(nodeConditionsByKind(conditions))
+	return conditions
+}
+
+type nodeConditionsByKind []api.NodeCondition
+
+func (a nodeConditionsByKind) Len() int           { return len(a) }
+func (a nodeConditionsByKind) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a nodeConditionsByKind) Less(i, j int) bool { return a[i].Kind < a[j].Kind }
+
+func (m *evictionManager) markEvicted(uid, message string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.evicted[uid] = message
+}
+
+// evictionMessage returns the message of the eviction of a pod, if it was evicted.
+func (m *evictionManager) evictionMessage(uid string) (string, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	message, found := m.evicted[uid]
+	return message, found
+}
+
+// retainEvicted forgets the evicted pods which are not in uids.
+func (m *evictionManager) retainEvicted(uids util.StringSet) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for uid := range m.evicted {
+		if !uids.Has(uid) {
+			delete(m.evicted, uid)
+		}
+	}
+}
+
+// evictionCandidate is a pod and its usage and request of the resource of a signal.
+type evictionCandidate struct {
+	pod        *api.BoundPod
+	bestEffort bool
+	usage      uint64
+	request    uint64
+}
+
+// byEvictionOrder sorts the best-effort pods first, then the pods by decreasing usage over
+// request. Pods without a request come first among the others, by decreasing usage.
+type byEvictionOrder []evictionCandidate
+
+func (a byEvictionOrder) Len() int      { return len(a) }
+func (a byEvictionOrder) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byEvictionOrder) Less(i, j int) bool {
+	if a[i].bestEffort != a[j].bestEffort {
+		return a[i].bestEffort
+	}
+	ri, rj := a[i].ratio(), a[j].ratio()
+	if ri != rj {
+		return ri > rj
+	}
+	return a[i].usage > a[j].usage
+}
+
+func (c *evictionCandidate) ratio() float64 {
+	if c.request == 0 {
+		return math.Inf(1)
+	}
+	return float64(c.usage) / float64(c.request)
+}
+
+// podRequest returns the request of a pod for the resource of a signal in bytes.
+func podRequest(pod *api.BoundPod, signal string) uint64 {
+	var request uint64
+	for _, container := range pod.Spec.Containers {
+		switch signal {
+		case signalMemoryAvailable:
+			request += uint64(container.Memory)
+		case signalDataAvailable:
+			request += uint64(container.Disk) << 30
+		}
+	}
+	return request
+}
+
+func (kl *Kubelet) isEvicted(uid string) bool {
+	if kl.evictionManager == nil {
+		return false
+	}
+	_, evicted := kl.evictionManager.evictionMessage(uid)
+	return evicted
+}
+
+// synchronizeEviction evicts the first pod in the eviction order if an eviction threshold is
+// crossed. A single pod is evicted at a time so that the usage is observed again before another.
+func (kl *Kubelet) synchronizeEviction() {
+	signal, message, evict := kl.evictionManager.check(time.Now())
+	if !evict {
+		return
+	}
+	runningPods, err := kl.runtime.GetPods(false)
+	if err != nil {
+		glog.Errorf("Error listing containers: %v", err)
+		return
+	}
+	candidates := []evictionCandidate{}
+	pods := kl.getPods()
+	for i := range pods {
+		pod := &pods[i]
+		if kl.isEvicted(pod.UID) {
+			continue
+		}
+		stats, err := kl.samplePodStats(pod, runningPods.FindPod(GetPodFullName(pod), pod.UID))
+		if err != nil {
+			// Evicting a pod without running containers frees nothing.
+			glog.V(4).Infof("Failed to sample the stats of pod %s: %v", GetPodFullName(pod), err)
+			continue
+		}
+		var usage uint64
+		switch signal {
+		case signalMemoryAvailable:
+			if stats.Memory != nil {
+				usage = stats.Memory.WorkingSet
+				if usage == 0 {
+					usage = stats.Memory.Usage
+				}
+			}
+		case signalDataAvailable:
+			for _, fs := range stats.Filesystem {
+				usage += fs.Usage
+			}
+		}
+		candidates = append(candidates, evictionCandidate{
+			pod:        pod,
+			bestEffort: api.IsPodBestEffort(&pod.Spec),
+			usage:      usage,
+			request:    podRequest(pod, signal),
+		})
+	}
+	if len(candidates) == 0 {
+		glog.Warningf("%s No pod to evict.", message)
+		return
+	}
+	sort.Sort(byEvictionOrder(candidates))
+	pod := candidates[0].pod
+	kl.evictPod(pod, runningPods.FindPod(GetPodFullName(pod), pod.UID), message)
+}
+
+// evictPod kills the containers of a pod, which are not restarted until the pod is removed
+// from the node. The containers report the eviction as the reason of their termination.
+func (kl *Kubelet) evictPod(pod *api.BoundPod, runningPod kubecontainer.Pod, message string) {
+	podFullName := GetPodFullName(pod)
+	glog.Infof("Evicting pod %s: %s", podFullName, message)
+	kl.evictionManager.markEvicted(pod.UID, message)
+	if ref, err := api.GetReference(pod); err != nil {
+		glog.V(4).Infof("Couldn't make a ref to pod %v: %v", pod.Name, err)
+	} else {
+		record.Eventf(ref, "failed", "evicted", "%s", message)
+	}
+	if _, err := kl.killContainersInPod(pod, runningPod); err != nil {
+		glog.Errorf("Error killing the containers of evicted pod %s: %v", podFullName, err)
+	}
+	if netContainer := runningPod.FindContainerByName(networkContainerName); netContainer != nil {
+		if err := kl.killContainer(runningPod, netContainer); err != nil {
+			glog.Errorf("Error killing the network container of evicted pod %s: %v", podFullName, err)
+		}
+	}
+}
+
+// setEvictionStatus reports the eviction of an evicted pod as the reason of the termination of
+// its containers.
+func (kl *Kubelet) setEvictionStatus(uid string, info api.PodInfo) {
+	if kl.evictionManager == nil {
+		return
+	}
+	message, evicted := kl.evictionManager.evictionMessage(uid)
+	if !evicted {
+		return
+	}
+	for name, status := range info {
+		if status.State.Termination == nil {
+			continue
+		}
+		termination := *status.State.Termination
+		termination.Reason = evictedReason
+		termination.Message = message
+		status.State.Termination = &termination
+		info[name] = status
+	}
+}
+
+// GetNodeConditions returns the pressure conditions of the node.
+func (kl *Kubelet) GetNodeConditions() []api.NodeCondition {
+	if kl.evictionManager == nil {
+		return []api.NodeCondition{}
+	}
+	return kl.evictionManager.Conditions()
+}
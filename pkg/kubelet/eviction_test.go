@@ -0,0 +1,266 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/fsouza/go-dockerclient"
+)
+
+func TestParseEvictionThresholds(t *testing.T) {
+	thresholds, err := ParseEvictionThresholds("memory.available<100Mi, data.available<10%", "memory.available<1G", "memory.available=1m30s")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []EvictionThreshold{
+		{Signal: signalMemoryAvailable, Quantity: 100 << 20},
+		{Signal: signalDataAvailable, Percentage: 10},
+		{Signal: signalMemoryAvailable, Quantity: 1e9, GracePeriod: 90 * time.Second},
+	}
+	if !reflect.DeepEqual(thresholds, expected) {
+		t.Errorf("expected %+v, got %+v", expected, thresholds)
+	}
+
+	for _, args := range [][3]string{
+		{"memory.free<100Mi", "", ""},
+		{"memory.available>100Mi", "", ""},
+		{"memory.available<100Xi", "", ""},
+		{"data.available<100%", "", ""},
+		{"data.available<0", "", ""},
+		{"", "memory.available<100Mi", ""},
+		{"", "memory.available<100Mi", "memory.available=soon"},
+		{"", "memory.available<100Mi", "data.available=1m"},
+	} {
+		if _, err := ParseEvictionThresholds(args[0], args[1], args[2]); err == nil {
+			t.Errorf("expected an error for %q", args)
+		}
+	}
+}
+
+func TestParseMemInfo(t *testing.T) {
+	available, total, err := parseMemInfo(strings.NewReader("MemTotal:        2048 kB\nMemFree:          512 kB\nMemAvailable:    1024 kB\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if available != 1024*1024 || total != 2048*1024 {
+		t.Errorf("unexpected available %d and total %d", available, total)
+	}
+
+	available, _, err = parseMemInfo(strings.NewReader("MemTotal: 2048 kB\nMemFree: 512 kB\nBuffers: 128 kB\nCached: 256 kB\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if available != 896*1024 {
+		t.Errorf("unexpected available %d", available)
+	}
+
+	if _, _, err := parseMemInfo(strings.NewReader("MemFree: 512 kB\n")); err == nil {
+		t.Errorf("expected an error without MemTotal")
+	}
+}
+
+func newTestEvictionManager(thresholds []EvictionThreshold, observations map[string]signalObservation) *evictionManager {
+	m := newEvictionManager(thresholds, 5*time.Minute)
+	m.observe = func() map[string]signalObservation {
+		return observations
+	}
+	return m
+}
+
+func TestEvictionHardThreshold(t *testing.T) {
+	observations := map[string]signalObservation{
+		signalMemoryAvailable: {available: 200, capacity: 1000},
+		signalDataAvailable:   {available: 50, capacity: 1000},
+	}
+	m := newTestEvictionManager([]EvictionThreshold{
+		{Signal: signalMemoryAvailable, Quantity: 100},
+		{Signal: signalDataAvailable, Percentage: 10},
+	}, observations)
+
+	now := time.Now()
+	signal, message, evict := m.check(now)
+	if !evict || signal != signalDataAvailable || message == "" {
+		t.Errorf("expected an eviction for %s, got %q %q %v", signalDataAvailable, signal, message, evict)
+	}
+	conditions := m.Conditions()
+	if len(conditions) != 2 {
+		t.Fatalf("unexpected conditions: %+v", conditions)
+	}
+	if conditions[0].Kind != api.NodeDiskPressure || conditions[0].Status != api.ConditionTrue || conditions[0].Reason != "EvictionThresholdCrossed" {
+		t.Errorf("unexpected disk condition: %+v", conditions[0])
+	}
+	if conditions[1].Kind != api.NodeMemoryPressure || conditions[1].Status != api.ConditionFalse {
+		t.Errorf("unexpected memory condition: %+v", conditions[1])
+	}
+
+	// The pressure is reported until the transition period passed since the threshold was last crossed.
+	observations[signalDataAvailable] = signalObservation{available: 500, capacity: 1000}
+	if _, _, evict := m.check(now.Add(time.Minute)); evict {
+		t.Errorf("unexpected eviction")
+	}
+	if conditions := m.Conditions(); conditions[0].Status != api.ConditionTrue || !conditions[0].LastTransitionTime.Equal(now) {
+		t.Errorf("unexpected disk condition: %+v", conditions[0])
+	}
+	m.check(now.Add(6 * time.Minute))
+	if conditions := m.Conditions(); conditions[0].Status != api.ConditionFalse || !conditions[0].LastTransitionTime.Equal(now.Add(6*time.Minute)) {
+		t.Errorf("unexpected disk condition: %+v", conditions[0])
+	}
+}
+
+func TestEvictionSoftThreshold(t *testing.T) {
+	observations := map[string]signalObservation{
+		signalMemoryAvailable: {available: 50, capacity: 1000},
+	}
+	m := newTestEvictionManager([]EvictionThreshold{
+		{Signal: signalMemoryAvailable, Quantity: 100, GracePeriod: time.Minute},
+	}, observations)
+
+	now := time.Now()
+	if _, _, evict := m.check(now); evict {
+		t.Errorf("unexpected eviction before the grace period")
+	}
+	if conditions := m.Conditions(); conditions[0].Status != api.ConditionTrue {
+		t.Errorf("expected memory pressure, got %+v", conditions[0])
+	}
+	if signal, _, evict := m.check(now.Add(time.Minute)); !evict || signal != signalMemoryAvailable {
+		t.Errorf("expected an eviction after the grace period, got %q %v", signal, evict)
+	}
+
+	// The grace period starts over once the threshold is met.
+	observations[signalMemoryAvailable] = signalObservation{available: 500, capacity: 1000}
+	m.check(now.Add(2 * time.Minute))
+	observations[signalMemoryAvailable] = signalObservation{available: 50, capacity: 1000}
+	if _, _, evict := m.check(now.Add(3 * time.Minute)); evict {
+		t.Errorf("unexpected eviction before the grace period")
+	}
+}
+
+func TestEvictionHardPrecedesSoft(t *testing.T) {
+	m := newTestEvictionManager([]EvictionThreshold{
+		{Signal: signalMemoryAvailable, Quantity: 100, GracePeriod: time.Minute},
+		{Signal: signalDataAvailable, Quantity: 100},
+	}, map[string]signalObservation{
+		signalMemoryAvailable: {available: 50, capacity: 1000},
+		signalDataAvailable:   {available: 50, capacity: 1000},
+	})
+	now := time.Now()
+	m.check(now)
+	if signal, _, _ := m.check(now.Add(time.Minute)); signal != signalDataAvailable {
+		t.Errorf("expected an eviction for %s, got %q", signalDataAvailable, signal)
+	}
+}
+
+func TestEvictionOrder(t *testing.T) {
+	pod := func(name string) *api.BoundPod {
+		return &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: name}}
+	}
+	candidates := []evictionCandidate{
+		{pod: pod("under-request"), usage: 50, request: 100},
+		{pod: pod("over-request"), usage: 300, request: 100},
+		{pod: pod("best-effort-small"), bestEffort: true, usage: 10},
+		{pod: pod("best-effort-large"), bestEffort: true, usage: 20},
+		{pod: pod("no-request"), usage: 10},
+	}
+	sort.Sort(byEvictionOrder(candidates))
+	names := []string{}
+	for _, c := range candidates {
+		names = append(names, c.pod.Name)
+	}
+	expected := []string{"best-effort-large", "best-effort-small", "no-request", "over-request", "under-request"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestPodRequest(t *testing.T) {
+	pod := &api.BoundPod{
+		Spec: api.PodSpec{
+			Containers: []api.Container{
+				{Name: "a", Memory: 100, Disk: 1},
+				{Name: "b", Memory: 200, Disk: 2},
+			},
+		},
+	}
+	if request := podRequest(pod, signalMemoryAvailable); request != 300 {
+		t.Errorf("unexpected memory request %d", request)
+	}
+	if request := podRequest(pod, signalDataAvailable); request != 3<<30 {
+		t.Errorf("unexpected disk request %d", request)
+	}
+}
+
+func TestEvictPod(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	kubelet.evictionManager = newEvictionManager(nil, time.Minute)
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			UID:         "12345",
+			Name:        "foo",
+			Namespace:   "new",
+			Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+		},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Name: "bar"}},
+		},
+	}
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{
+			Names: []string{"/k8s_bar_foo.new.test_12345_42"},
+			ID:    "1234",
+		},
+		{
+			Names: []string{"/k8s_net_foo.new.test_12345_42"},
+			ID:    "9876",
+		},
+	}
+	runningPods, err := kubelet.runtime.GetPods(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kubelet.evictPod(pod, runningPods.FindPod("foo.new.test", "12345"), "low on memory")
+
+	sort.Strings(fakeDocker.Stopped)
+	if !reflect.DeepEqual(fakeDocker.Stopped, []string{"1234", "9876"}) {
+		t.Errorf("unexpected stopped containers: %v", fakeDocker.Stopped)
+	}
+	if !kubelet.isEvicted("12345") {
+		t.Errorf("expected the pod to be evicted")
+	}
+
+	info := api.PodInfo{
+		"bar": api.ContainerStatus{
+			State: api.ContainerState{Termination: &api.ContainerStateTerminated{ExitCode: 137}},
+		},
+	}
+	kubelet.setEvictionStatus("12345", info)
+	termination := info["bar"].State.Termination
+	if termination.Reason != evictedReason || termination.Message != "low on memory" || termination.ExitCode != 137 {
+		t.Errorf("unexpected termination: %+v", termination)
+	}
+
+	kubelet.evictionManager.retainEvicted(util.NewStringSet())
+	if kubelet.isEvicted("12345") {
+		t.Errorf("expected the eviction of a removed pod to be forgotten")
+	}
+}
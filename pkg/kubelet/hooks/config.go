@@ -0,0 +1,87 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// Config describes one hook of a hooks config file, which holds a JSON list of them.
+// Exactly one of Builtin and Exec must be set.
+type Config struct {
+	Name   string  `json:"name"`
+	Phases []Phase `json:"phases"`
+	// Builtin is the name of a hook compiled into the kubelet.
+	Builtin string `json:"builtin,omitempty"`
+	// Exec is the path of an executable hook, run with Args.
+	Exec           string        `json:"exec,omitempty"`
+	Args           []string      `json:"args,omitempty"`
+	TimeoutSeconds int64         `json:"timeoutSeconds,omitempty"`
+	FailurePolicy  FailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// NewManagerFromConfig creates a Manager running the hooks of configs in order.
+// Builtin hooks are looked up in builtins.
+func NewManagerFromConfig(configs []Config, builtins map[string]Hook) (*Manager, error) {
+	m := NewManager()
+	for _, config := range configs {
+		r := Registration{
+			Name:          config.Name,
+			Phases:        config.Phases,
+			Timeout:       time.Duration(config.TimeoutSeconds) * time.Second,
+			FailurePolicy: config.FailurePolicy,
+		}
+		// Default the timeout before it is handed to the exec hook, which kills its process after it.
+		if r.Timeout <= 0 {
+			r.Timeout = DefaultTimeout
+		}
+		switch {
+		case len(config.Builtin) != 0 && len(config.Exec) != 0:
+			return nil, fmt.Errorf("hook %s sets both builtin and exec", config.Name)
+		case len(config.Builtin) != 0:
+			hook, ok := builtins[config.Builtin]
+			if !ok {
+				return nil, fmt.Errorf("hook %s refers to an unknown builtin %q", config.Name, config.Builtin)
+			}
+			r.Hook = hook
+		case len(config.Exec) != 0:
+			r.Hook = &ExecHook{Path: config.Exec, Args: config.Args, Timeout: r.Timeout}
+		default:
+			return nil, fmt.Errorf("hook %s sets neither builtin nor exec", config.Name)
+		}
+		if err := m.Register(r); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// NewManagerFromFile creates a Manager from the hooks config file at path.
+func NewManagerFromFile(path string, builtins map[string]Hook) (*Manager, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	configs := []Config{}
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("invalid hooks config %s: %v", path, err)
+	}
+	return NewManagerFromConfig(configs, builtins)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewManagerFromFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "hooks")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`[
+		{"name": "lxcfs", "phases": ["PreCreate"], "builtin": "lxcfs"},
+		{"name": "irq", "phases": ["PostStart", "PostStop"], "exec": "/usr/local/bin/irq", "timeoutSeconds": 5, "failurePolicy": "Warn"},
+		{"name": "quota", "phases": ["PostStop"], "exec": "/usr/local/bin/quota"}
+	]`)
+	file.Close()
+
+	builtins := map[string]Hook{"lxcfs": HookFunc(func(req *Request) (string, error) { return "", nil })}
+	m, err := NewManagerFromFile(file.Name(), builtins)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := m.Names(PreCreate); len(names) != 1 || names[0] != "lxcfs" {
+		t.Errorf("unexpected PreCreate hooks: %v", names)
+	}
+	irq := m.hooks[PostStop][0]
+	exec, ok := irq.Hook.(*ExecHook)
+	if !ok || exec.Path != "/usr/local/bin/irq" || exec.Timeout != 5*time.Second ||
+		irq.Timeout != 5*time.Second || irq.FailurePolicy != FailurePolicyWarn {
+		t.Errorf("unexpected registration: %#v", irq)
+	}
+	quota := m.hooks[PostStop][1]
+	if exec, ok := quota.Hook.(*ExecHook); !ok || exec.Timeout != DefaultTimeout || quota.Timeout != DefaultTimeout {
+		t.Errorf("expected the default timeout, got %#v", quota)
+	}
+
+	errorCases := map[string]Config{
+		"unknown builtin": {Name: "foo", Phases: []Phase{PreCreate}, Builtin: "foo"},
+		"both":            {Name: "foo", Phases: []Phase{PreCreate}, Builtin: "lxcfs", Exec: "/bin/true"},
+		"neither":         {Name: "foo", Phases: []Phase{PreCreate}},
+		"no phase":        {Name: "foo", Exec: "/bin/true"},
+	}
+	for k, config := range errorCases {
+		if _, err := NewManagerFromConfig([]Config{config}, builtins); err == nil {
+			t.Errorf("%s: expected an error", k)
+		}
+	}
+}
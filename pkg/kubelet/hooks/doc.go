@@ -0,0 +1,26 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hooks runs node-level plugins around the lifecycle of the containers
+// started by the kubelet, so that a site can integrate its hosts (quotas, cgroups,
+// devices, sidecar daemons) without patching the kubelet.
+//
+// A hook is either registered in-process or is an external executable. An
+// executable receives the JSON encoded Request on stdin and the phase as its
+// last argument. It succeeds by exiting with status 0 and may write a JSON
+// encoded Response to stdout; on any other exit status its output is reported
+// as the failure message.
+package hooks
@@ -0,0 +1,83 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ExecHook runs an external executable with the JSON contract described in the package documentation.
+type ExecHook struct {
+	Path string
+	Args []string
+	// Optional, defaults to DefaultTimeout. The process is killed once it has run this long.
+	Timeout time.Duration
+}
+
+// Run implements Hook.
+func (h *ExecHook) Run(req *Request) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(h.Path, append(append([]string{}, h.Args...), string(req.Phase))...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		<-done
+		return "", fmt.Errorf("%s killed after %v", h.Path, timeout)
+	}
+	if err != nil {
+		output := strings.TrimSpace(stderr.String())
+		if len(output) == 0 {
+			output = strings.TrimSpace(stdout.String())
+		}
+		return "", fmt.Errorf("%v: %s", err, output)
+	}
+
+	out := bytes.TrimSpace(stdout.Bytes())
+	if len(out) == 0 {
+		return "", nil
+	}
+	var response Response
+	if err := json.Unmarshal(out, &response); err != nil {
+		return "", fmt.Errorf("invalid response %q: %v", string(out), err)
+	}
+	return response.Message, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func writeScript(t *testing.T, dir, name, script string) string {
+	file := path.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return file
+}
+
+func TestExecHook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hooks")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	req := &Request{
+		Phase:       PostStart,
+		Pod:         &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "foo"}},
+		Container:   &api.Container{Name: "bar"},
+		ContainerID: "1234",
+	}
+	testCases := []struct {
+		name    string
+		script  string
+		args    []string
+		timeout time.Duration
+		message string
+		err     string
+	}{
+		{name: "silent", script: "exit 0\n"},
+		{name: "args", script: `echo "{\"message\": \"$1 $2\"}"` + "\n", args: []string{"quota"}, message: "quota PostStart"},
+		{name: "stdin", script: `grep -q '"containerID":"1234"' && echo '{"message": "got request"}'` + "\n", message: "got request"},
+		{name: "stderr", script: "echo no space left >&2\nexit 3\n", err: "no space left"},
+		{name: "stdout", script: "echo busy\nexit 1\n", err: "busy"},
+		{name: "garbage", script: "echo done\n", err: "invalid response"},
+		{name: "slow", script: "exec sleep 10\n", timeout: 10 * time.Millisecond, err: "killed"},
+	}
+	for _, test := range testCases {
+		hook := &ExecHook{Path: writeScript(t, dir, test.name, test.script), Args: test.args, Timeout: test.timeout}
+		message, err := hook.Run(req)
+		if len(test.err) != 0 {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected error %q, got %v", test.name, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if message != test.message {
+			t.Errorf("%s: expected message %q, got %q", test.name, test.message, message)
+		}
+	}
+}
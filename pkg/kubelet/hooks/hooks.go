@@ -0,0 +1,217 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/golang/glog"
+)
+
+// Phase is the point of the container lifecycle at which a hook runs.
+type Phase string
+
+// These are the valid phases.
+const (
+	// PreCreate runs before a container is created, or before a stopped one is started again.
+	PreCreate Phase = "PreCreate"
+	// PostStart runs after a container has been started.
+	PostStart Phase = "PostStart"
+	// PostStop runs after a container has been stopped, and once more with no
+	// container after all the containers of a removed pod are gone.
+	PostStop Phase = "PostStop"
+)
+
+// FailurePolicy tells what a failing hook does to the container being handled.
+type FailurePolicy string
+
+// These are the valid failure policies.
+const (
+	// FailurePolicyFail fails the lifecycle step; the remaining hooks of the phase are skipped.
+	FailurePolicyFail FailurePolicy = "Fail"
+	// FailurePolicyWarn reports the failure and carries on with the next hook.
+	FailurePolicyWarn FailurePolicy = "Warn"
+)
+
+// DefaultTimeout bounds a hook registered without a timeout.
+const DefaultTimeout = 30 * time.Second
+
+// Request is handed to every hook of a phase.
+type Request struct {
+	Phase Phase `json:"phase"`
+	// Pod is the pod being handled. It is nil for the PostStop of a container
+	// whose pod is not known to the kubelet anymore.
+	Pod *api.BoundPod `json:"pod,omitempty"`
+	// Container is the container being handled, nil for the pod-wide PostStop.
+	Container *api.Container `json:"container,omitempty"`
+	// ContainerID is the docker id of Container, empty before it is created.
+	ContainerID string `json:"containerID,omitempty"`
+}
+
+// Response may be written by an executable hook to report what it did.
+type Response struct {
+	Message string `json:"message,omitempty"`
+}
+
+// Hook is a node-level plugin. Run returns a message describing what was done.
+type Hook interface {
+	Run(req *Request) (string, error)
+}
+
+// HookFunc adapts a function to the Hook interface.
+type HookFunc func(req *Request) (string, error)
+
+// Run calls f(req).
+func (f HookFunc) Run(req *Request) (string, error) {
+	return f(req)
+}
+
+// Registration describes when and how a hook is run.
+type Registration struct {
+	Name   string
+	Hook   Hook
+	Phases []Phase
+	// Optional, defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Optional, defaults to FailurePolicyFail.
+	FailurePolicy FailurePolicy
+}
+
+// Result is the outcome of running one hook.
+type Result struct {
+	Name          string
+	Phase         Phase
+	FailurePolicy FailurePolicy
+	Message       string
+	Err           error
+	Duration      time.Duration
+}
+
+// Manager runs the registered hooks of a phase in registration order.
+type Manager struct {
+	lock  sync.RWMutex
+	hooks map[Phase][]*Registration
+}
+
+// NewManager creates a Manager without any hook.
+func NewManager() *Manager {
+	return &Manager{hooks: map[Phase][]*Registration{}}
+}
+
+func validPhase(phase Phase) bool {
+	return phase == PreCreate || phase == PostStart || phase == PostStop
+}
+
+// Register appends a hook to each of the phases of r.
+func (m *Manager) Register(r Registration) error {
+	if len(r.Name) == 0 {
+		return fmt.Errorf("hook has no name")
+	}
+	if r.Hook == nil {
+		return fmt.Errorf("hook %s has no implementation", r.Name)
+	}
+	if len(r.Phases) == 0 {
+		return fmt.Errorf("hook %s has no phase", r.Name)
+	}
+	for _, phase := range r.Phases {
+		if !validPhase(phase) {
+			return fmt.Errorf("hook %s has an unsupported phase %q", r.Name, phase)
+		}
+	}
+	switch r.FailurePolicy {
+	case "":
+		r.FailurePolicy = FailurePolicyFail
+	case FailurePolicyFail, FailurePolicyWarn:
+	default:
+		return fmt.Errorf("hook %s has an unsupported failure policy %q", r.Name, r.FailurePolicy)
+	}
+	if r.Timeout <= 0 {
+		r.Timeout = DefaultTimeout
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, phase := range r.Phases {
+		m.hooks[phase] = append(m.hooks[phase], &r)
+	}
+	return nil
+}
+
+// Names returns the names of the hooks of phase, in the order they run.
+func (m *Manager) Names(phase Phase) []string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	names := []string{}
+	for _, r := range m.hooks[phase] {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// Run runs the hooks of req.Phase in order and returns their results. It stops
+// at the first failing hook whose policy is FailurePolicyFail and returns its error.
+func (m *Manager) Run(req *Request) ([]Result, error) {
+	m.lock.RLock()
+	registrations := m.hooks[req.Phase]
+	m.lock.RUnlock()
+
+	results := []Result{}
+	for _, r := range registrations {
+		start := time.Now()
+		message, err := runWithTimeout(r.Hook, req, r.Timeout)
+		result := Result{
+			Name:          r.Name,
+			Phase:         req.Phase,
+			FailurePolicy: r.FailurePolicy,
+			Message:       message,
+			Err:           err,
+			Duration:      time.Since(start),
+		}
+		results = append(results, result)
+		if err == nil {
+			glog.V(3).Infof("%s hook %s succeeded in %v: %s", req.Phase, r.Name, result.Duration, message)
+			continue
+		}
+		if r.FailurePolicy == FailurePolicyFail {
+			return results, fmt.Errorf("%s hook %s failed: %v", req.Phase, r.Name, err)
+		}
+		glog.Warningf("%s hook %s failed, ignoring: %v", req.Phase, r.Name, err)
+	}
+	return results, nil
+}
+
+type hookResult struct {
+	message string
+	err     error
+}
+
+func runWithTimeout(hook Hook, req *Request, timeout time.Duration) (string, error) {
+	done := make(chan hookResult, 1)
+	go func() {
+		message, err := hook.Run(req)
+		done <- hookResult{message, err}
+	}()
+	select {
+	case result := <-done:
+		return result.message, result.err
+	case <-time.After(timeout):
+		return "", fmt.Errorf("timed out after %v", timeout)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+type fakeHook struct {
+	name  string
+	err   error
+	delay time.Duration
+	calls *[]string
+}
+
+func (f *fakeHook) Run(req *Request) (string, error) {
+	time.Sleep(f.delay)
+	*f.calls = append(*f.calls, f.name)
+	return "ran " + f.name, f.err
+}
+
+func TestRegisterValidation(t *testing.T) {
+	hook := HookFunc(func(req *Request) (string, error) { return "", nil })
+	errorCases := map[string]Registration{
+		"no name":         {Hook: hook, Phases: []Phase{PreCreate}},
+		"no hook":         {Name: "foo", Phases: []Phase{PreCreate}},
+		"no phase":        {Name: "foo", Hook: hook},
+		"bad phase":       {Name: "foo", Hook: hook, Phases: []Phase{"PreStop"}},
+		"bad policy":      {Name: "foo", Hook: hook, Phases: []Phase{PreCreate}, FailurePolicy: "Ignore"},
+		"one bad phase":   {Name: "foo", Hook: hook, Phases: []Phase{PostStart, ""}},
+		"lowercase phase": {Name: "foo", Hook: hook, Phases: []Phase{"postStart"}},
+	}
+	for k, r := range errorCases {
+		m := NewManager()
+		if err := m.Register(r); err == nil {
+			t.Errorf("%s: expected an error", k)
+		}
+		if len(m.Names(PreCreate)) != 0 || len(m.Names(PostStart)) != 0 {
+			t.Errorf("%s: expected nothing to be registered", k)
+		}
+	}
+}
+
+func TestRunInOrder(t *testing.T) {
+	calls := []string{}
+	m := NewManager()
+	for _, r := range []Registration{
+		{Name: "a", Hook: &fakeHook{name: "a", calls: &calls}, Phases: []Phase{PreCreate, PostStop}},
+		{Name: "b", Hook: &fakeHook{name: "b", calls: &calls}, Phases: []Phase{PostStart}},
+		{Name: "c", Hook: &fakeHook{name: "c", calls: &calls}, Phases: []Phase{PreCreate}},
+	} {
+		if err := m.Register(r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	results, err := m.Run(&Request{Phase: PreCreate, Pod: &api.BoundPod{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"a", "c"}) {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	if len(results) != 2 || results[1].Name != "c" || results[1].Message != "ran c" ||
+		results[1].Phase != PreCreate || results[1].FailurePolicy != FailurePolicyFail {
+		t.Errorf("unexpected results: %#v", results)
+	}
+	if names := m.Names(PostStop); !reflect.DeepEqual(names, []string{"a"}) {
+		t.Errorf("unexpected PostStop hooks: %v", names)
+	}
+}
+
+func TestRunFailurePolicy(t *testing.T) {
+	calls := []string{}
+	m := NewManager()
+	for _, r := range []Registration{
+		{Name: "warn", Hook: &fakeHook{name: "warn", err: errors.New("boom"), calls: &calls}, Phases: []Phase{PostStart}, FailurePolicy: FailurePolicyWarn},
+		{Name: "fail", Hook: &fakeHook{name: "fail", err: errors.New("bang"), calls: &calls}, Phases: []Phase{PostStart}},
+		{Name: "skipped", Hook: &fakeHook{name: "skipped", calls: &calls}, Phases: []Phase{PostStart}},
+	} {
+		if err := m.Register(r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	results, err := m.Run(&Request{Phase: PostStart})
+	if err == nil || !strings.Contains(err.Error(), "bang") {
+		t.Errorf("expected the error of the failing hook, got %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"warn", "fail"}) {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	if len(results) != 2 || results[0].Err == nil || results[0].FailurePolicy != FailurePolicyWarn {
+		t.Errorf("unexpected results: %#v", results)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	calls := []string{}
+	m := NewManager()
+	err := m.Register(Registration{
+		Name:    "slow",
+		Hook:    &fakeHook{name: "slow", delay: time.Second, calls: &calls},
+		Phases:  []Phase{PreCreate},
+		Timeout: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, err := m.Run(&Request{Phase: PreCreate})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+	if len(results) != 1 || results[0].Duration >= time.Second {
+		t.Errorf("unexpected results: %#v", results)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/hooks"
+	"github.com/golang/glog"
+)
+
+// Names of the hooks compiled into the kubelet, usable as "builtin" in a hooks config file.
+const (
+	lxcfsHook     = "lxcfs"
+	diskQuotaHook = "diskquota"
+	sriovHook     = "sriov"
+	blkioHook     = "blkio"
+)
+
+// BuiltinHooks returns the host integrations compiled into the kubelet, keyed by name.
+func (kl *Kubelet) BuiltinHooks() map[string]hooks.Hook {
+	return map[string]hooks.Hook{
+		lxcfsHook:     hooks.HookFunc(kl.lxcfsHook),
+		diskQuotaHook: hooks.HookFunc(kl.diskQuotaHook),
+		sriovHook:     hooks.HookFunc(kl.sriovHook),
+		blkioHook:     hooks.HookFunc(kl.blkioHook),
+	}
+}
+
+// defaultHooks returns a manager running the builtin hooks the way the kubelet
+// has always run them, for nodes without a hooks config file.
+func (kl *Kubelet) defaultHooks() *hooks.Manager {
+	builtins := kl.BuiltinHooks()
+	m := hooks.NewManager()
+	for _, r := range []hooks.Registration{
+		{Name: lxcfsHook, Hook: builtins[lxcfsHook], Phases: []hooks.Phase{hooks.PreCreate}},
+		{Name: diskQuotaHook, Hook: builtins[diskQuotaHook], Phases: []hooks.Phase{hooks.PostStart, hooks.PostStop}},
+		{Name: sriovHook, Hook: builtins[sriovHook], Phases: []hooks.Phase{hooks.PostStart}},
+		{Name: blkioHook, Hook: builtins[blkioHook], Phases: []hooks.Phase{hooks.PostStart}},
+		{Name: lxcfsHook, Hook: builtins[lxcfsHook], Phases: []hooks.Phase{hooks.PostStop}, FailurePolicy: hooks.FailurePolicyWarn},
+		{Name: sriovHook, Hook: builtins[sriovHook], Phases: []hooks.Phase{hooks.PostStop}, FailurePolicy: hooks.FailurePolicyWarn},
+	} {
+		if err := m.Register(r); err != nil {
+			glog.Errorf("Failed to register hook %s: %v", r.Name, err)
+		}
+	}
+	return m
+}
+
+// SetHooks replaces the node hooks. It must be called before the kubelet runs.
+func (kl *Kubelet) SetHooks(m *hooks.Manager) {
+	kl.hooks = m
+}
+
+// runHooks runs the node hooks of phase for a container, or for the whole pod if container
+// is nil, and records their results as events.
+func (kl *Kubelet) runHooks(phase hooks.Phase, pod *api.BoundPod, container *api.Container, containerID string) error {
+	if kl.hooks == nil {
+		return nil
+	}
+	results, err := kl.hooks.Run(&hooks.Request{
+		Phase:       phase,
+		Pod:         pod,
+		Container:   container,
+		ContainerID: containerID,
+	})
+	ref := hookRef(pod, container)
+	if ref == nil {
+		return err
+	}
+	for _, result := range results {
+		switch {
+		case result.Err == nil:
+			record.Eventf(ref, "", "hookSucceeded", "%s hook %s succeeded: %s", phase, result.Name, result.Message)
+		case result.FailurePolicy == hooks.FailurePolicyWarn:
+			record.Eventf(ref, "", "hookWarning", "%s hook %s failed: %v", phase, result.Name, result.Err)
+		default:
+			record.Eventf(ref, "failed", "hookFailed", "%s hook %s failed: %v", phase, result.Name, result.Err)
+		}
+	}
+	return err
+}
+
+// hookRef returns the reference the results of hooks are reported against, or nil if there is none.
+func hookRef(pod *api.BoundPod, container *api.Container) *api.ObjectReference {
+	if pod == nil {
+		return nil
+	}
+	if container == nil {
+		ref, err := api.GetReference(pod)
+		if err != nil {
+			glog.V(4).Infof("Couldn't make a ref to pod %v: %v", pod.Name, err)
+			return nil
+		}
+		return ref
+	}
+	ref, err := containerRef(pod, container)
+	if err != nil {
+		glog.V(4).Infof("Couldn't make a ref to pod %v, container %v: %v", pod.Name, container.Name, err)
+		return nil
+	}
+	return ref
+}
+
+// lxcfsHook starts lxcfs for a container before it is created and stops it once its pod is removed.
+func (kl *Kubelet) lxcfsHook(req *hooks.Request) (string, error) {
+	switch {
+	case req.Phase == hooks.PreCreate && req.Container != nil:
+		return "", kl.OpLxcfs(req.Container.Name, "start")
+	case req.Phase == hooks.PostStop && req.Container == nil && req.Pod != nil:
+		return "", kl.OpLxcfs(req.Pod.Name, "stop")
+	}
+	return "", nil
+}
+
+// diskQuotaHook sets the xfs project quota of a started container and clears it once it is stopped.
+func (kl *Kubelet) diskQuotaHook(req *hooks.Request) (string, error) {
+	if req.Container == nil || len(req.ContainerID) == 0 {
+		return "", nil
+	}
+	switch req.Phase {
+	case hooks.PostStart:
+		return "", kl.addDiskQuota(req.ContainerID, req.Container.Name, req.Container.Disk)
+	case hooks.PostStop:
+		return "", kl.removeDiskQuota(req.ContainerID, req.Container.Name)
+	}
+	return "", nil
+}
+
+// sriovHook pins the IRQs of the virtual function of a started container and resets
+// its MAC address once the pod is removed.
+func (kl *Kubelet) sriovHook(req *hooks.Request) (string, error) {
+	if req.Pod == nil || req.Pod.Res.Network.Mode != sriovMode {
+		return "", nil
+	}
+	switch {
+	case req.Phase == hooks.PostStart && req.Container != nil:
+		return "", kl.setupSriov(req.ContainerID, req.Pod)
+	case req.Phase == hooks.PostStop && req.Container == nil:
+		return "", kl.setupVFMacAddress(&req.Pod.Res.Network)
+	}
+	return "", nil
+}
+
+// blkioHook throttles the block IO of a started container.
+func (kl *Kubelet) blkioHook(req *hooks.Request) (string, error) {
+	if req.Phase != hooks.PostStart || req.Container == nil || req.Container.Blkio == nil {
+		return "", nil
+	}
+	blkio := &BlkioGroup{}
+	return "", blkio.SetUp(req.ContainerID, req.Container.Blkio)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/hooks"
+)
+
+func TestDefaultHooks(t *testing.T) {
+	kubelet, _, _ := newTestKubelet(t)
+	m := kubelet.defaultHooks()
+	expected := map[hooks.Phase][]string{
+		hooks.PreCreate: {lxcfsHook},
+		hooks.PostStart: {diskQuotaHook, sriovHook, blkioHook},
+		hooks.PostStop:  {diskQuotaHook, lxcfsHook, sriovHook},
+	}
+	for phase, names := range expected {
+		if got := m.Names(phase); !reflect.DeepEqual(got, names) {
+			t.Errorf("%s: expected hooks %v, got %v", phase, names, got)
+		}
+	}
+}
+
+func TestKillContainerRunsPostStopHooks(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{Name: "foo", UID: "12345678"},
+		Spec:       api.PodSpec{Containers: []api.Container{{Name: "bar", Disk: 10}}},
+	}
+	kubelet.podDestroyed = map[string]*api.BoundPod{"12345678": pod}
+
+	requests := []hooks.Request{}
+	kubelet.hooks = hooks.NewManager()
+	kubelet.hooks.Register(hooks.Registration{
+		Name:   "test",
+		Phases: []hooks.Phase{hooks.PostStop},
+		Hook: hooks.HookFunc(func(req *hooks.Request) (string, error) {
+			requests = append(requests, *req)
+			return "", errors.New("busy")
+		}),
+	})
+
+	// The container is stopped, so the failing hook does not fail the kill.
+	runningPod := kubecontainer.Pod{FullName: GetPodFullName(pod), UID: pod.UID}
+	if err := kubelet.killContainer(runningPod, &kubecontainer.Container{ID: "1234", Name: "bar"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	verifyCalls(t, fakeDocker, []string{"stop"})
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 hook call, got %d", len(requests))
+	}
+	req := requests[0]
+	if req.Pod != pod || req.Container != &pod.Spec.Containers[0] || req.ContainerID != "1234" {
+		t.Errorf("unexpected request: %#v", req)
+	}
+}
+
+func TestKillNetContainerSkipsHooks(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	kubelet.hooks = hooks.NewManager()
+	kubelet.hooks.Register(hooks.Registration{
+		Name:   "test",
+		Phases: []hooks.Phase{hooks.PostStop},
+		Hook: hooks.HookFunc(func(req *hooks.Request) (string, error) {
+			t.Errorf("unexpected hook call: %#v", req)
+			return "", nil
+		}),
+	})
+
+	runningPod := kubecontainer.Pod{FullName: "foo.test", UID: "12345678"}
+	if err := kubelet.killContainer(runningPod, &kubecontainer.Container{ID: "1234", Name: networkContainerName}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	verifyCalls(t, fakeDocker, []string{"stop"})
+}
+
+func TestBuiltinHooksIgnoreUnrelatedRequests(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	pod := &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
+	container := &api.Container{Name: "bar"}
+	requests := []*hooks.Request{
+		{Phase: hooks.PostStart, Pod: pod, Container: container, ContainerID: "1234"},
+		{Phase: hooks.PostStop, Pod: pod},
+		{Phase: hooks.PreCreate, Pod: pod, Container: container},
+	}
+	builtins := kubelet.BuiltinHooks()
+	for _, name := range []string{sriovHook, blkioHook} {
+		for _, req := range requests {
+			if _, err := builtins[name].Run(req); err != nil {
+				t.Errorf("%s %s: unexpected error: %v", name, req.Phase, err)
+			}
+		}
+	}
+	verifyCalls(t, fakeDocker, nil)
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/golang/glog"
+)
+
+// How often the images and the containers using them are listed to track when the images were last used.
+const imageDetectionPeriod = 5 * time.Minute
+
+// ImageGCPolicy is the policy of the garbage collection of the images.
+type ImageGCPolicy struct {
+	// Usage of the filesystem of the docker root directory, in percent, at or above which unused
+	// images are removed. 100 only removes images once the filesystem is full.
+	HighThresholdPercent int
+	// Usage, in percent, down to which the unused images are removed. Must be below HighThresholdPercent.
+	LowThresholdPercent int
+	// Images used by a container, or first detected, less than this ago are never removed.
+	MinAge time.Duration
+}
+
+// imageRecord tracks the use of an image.
+type imageRecord struct {
+	// When the image was first listed.
+	firstDetected time.Time
+	// When a container last used the image, now for the images of running containers.
+	lastUsed time.Time
+	// Size of the image and its parents.
+	size int64
+}
+
+// imageManager removes the least recently used images when the filesystem of the docker root
+// directory is over the high threshold of its policy.
+type imageManager struct {
+	dockerClient dockertools.DockerInterface
+	dockerRoot   string
+	policy       ImageGCPolicy
+	// Reference of the node for the events.
+	nodeRef *api.ObjectReference
+	// Returns the capacity and the available bytes of the filesystem of a path, defaults to statfs.
+	fsUsage func(path string) (capacity, available uint64, err error)
+
+	lock   sync.Mutex
+	images map[string]*imageRecord
+}
+
+func newImageManager(dockerClient dockertools.DockerInterface, dockerRoot string, policy ImageGCPolicy, nodeRef *api.ObjectReference) (*imageManager, error) {
+	if policy.HighThresholdPercent <= 0 || policy.HighThresholdPercent > 100 {
+		return nil, fmt.Errorf("invalid high threshold %d, must be in (0, 100]", policy.HighThresholdPercent)
+	}
+	if policy.LowThresholdPercent < 0 || policy.LowThresholdPercent >= policy.HighThresholdPercent {
+		return nil, fmt.Errorf("invalid low threshold %d, must be in [0, %d)", policy.LowThresholdPercent, policy.HighThresholdPercent)
+	}
+	return &imageManager{
+		dockerClient: dockerClient,
+		dockerRoot:   dockerRoot,
+		policy:       policy,
+		nodeRef:      nodeRef,
+		fsUsage:      statfsUsage,
+		images:       map[string]*imageRecord{},
+	}, nil
+}
+
+func statfsUsage(path string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return stat.Blocks * uint64(stat.Bsize), stat.Bavail * uint64(stat.Bsize), nil
+}
+
+// Start tracks the use of the images periodically.
+func (im *imageManager) Start() {
+	go util.Forever(func() {
+		if _, err := im.detectImages(time.Now()); err != nil {
+			glog.Errorf("Failed to detect the images: %v", err)
+		}
+	}, imageDetectionPeriod)
+}
+
+// detectImages updates the records of the images and returns the IDs of the images used by running
+// containers or by containers which exited less than the minimum age ago.
+func (im *imageManager) detectImages(now time.Time) (util.StringSet, error) {
+	images, err := im.dockerClient.ListImages(false)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := im.dockerClient.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+	inUse := util.NewStringSet()
+	lastUsed := map[string]time.Time{}
+	for _, container := range containers {
+		data, err := im.dockerClient.InspectContainer(container.ID)
+		if err != nil {
+			// The container may have been removed since the list.
+			glog.V(4).Infof("Failed to inspect container %s: %v", container.ID, err)
+			continue
+		}
+		used := data.State.FinishedAt
+		if data.State.Running {
+			used = now
+		}
+		if now.Sub(used) < im.policy.MinAge {
+			inUse.Insert(data.Image)
+		}
+		if used.After(lastUsed[data.Image]) {
+			lastUsed[data.Image] = used
+		}
+	}
+
+	im.lock.Lock()
+	defer im.lock.Unlock()
+	current := util.NewStringSet()
+	for _, image := range images {
+		current.Insert(image.ID)
+		rec, found := im.images[image.ID]
+		if !found {
+			rec = &imageRecord{firstDetected: now}
+			im.images[image.ID] = rec
+		}
+		if used := lastUsed[image.ID]; used.After(rec.lastUsed) {
+			rec.lastUsed = used
+		}
+		rec.size = image.VirtualSize
+	}
+	for id := range im.images {
+		if !current.Has(id) {
+			delete(im.images, id)
+		}
+	}
+	return inUse, nil
+}
+
+// GarbageCollect removes unused images, least recently used first, until the usage of the filesystem
+// of the docker root directory is below the low threshold, if it is at or above the high threshold.
+func (im *imageManager) GarbageCollect() error {
+	capacity, available, err := im.fsUsage(im.dockerRoot)
+	if err != nil {
+		return err
+	}
+	if capacity == 0 {
+		return fmt.Errorf("invalid capacity 0 of the filesystem of %s", im.dockerRoot)
+	}
+	used := capacity - available
+	usagePercent := int(used * 100 / capacity)
+	metrics.ImageFilesystemUsage.Set(float64(usagePercent))
+	if usagePercent < im.policy.HighThresholdPercent {
+		return nil
+	}
+
+	target := capacity * uint64(im.policy.LowThresholdPercent) / 100
+	toFree := int64(used - target)
+	glog.Infof("Filesystem of %s is %d%% used, over the high threshold of %d%%, removing images to free %d bytes",
+		im.dockerRoot, usagePercent, im.policy.HighThresholdPercent, toFree)
+	freed, err := im.freeSpace(toFree, time.Now())
+	if err != nil {
+		return err
+	}
+	if freed < toFree {
+		record.Eventf(im.nodeRef, "", "freeDiskSpaceFailed",
+			"Failed to garbage collect the required amount of images: wanted to free %d bytes, but freed %d bytes", toFree, freed)
+		return fmt.Errorf("failed to garbage collect the required amount of images: wanted to free %d bytes, but freed %d bytes", toFree, freed)
+	}
+	return nil
+}
+
+// imageByLastUsed sorts the images by the time they were last used, then by the time they were
+// first detected.
+type imageByLastUsed struct {
+	ids     []string
+	records []*imageRecord
+}
+
+func (a imageByLastUsed) Len() int { return len(a.ids) }
+func (a imageByLastUsed) Swap(i, j int) {
+	a.ids[i], a.ids[j] = a.ids[j], a.ids[i]
+	a.records[i], a.records[j] = a.records[j], a.records[i]
+}
+func (a imageByLastUsed) Less(i, j int) bool {
+	if !a.records[i].lastUsed.Equal(a.records[j].lastUsed) {
+		return a.records[i].lastUsed.Before(a.records[j].lastUsed)
+	}
+	return a.records[i].firstDetected.Before(a.records[j].firstDetected)
+}
+
+// freeSpace removes unused images, least recently used first, until at least bytes were freed.
+// It returns the size of the removed images.
+func (im *imageManager) freeSpace(bytes int64, now time.Time) (int64, error) {
+	inUse, err := im.detectImages(now)
+	if err != nil {
+		return 0, err
+	}
+
+	im.lock.Lock()
+	candidates := imageByLastUsed{}
+	for id, rec := range im.images {
+		if inUse.Has(id) || now.Sub(rec.lastUsed) < im.policy.MinAge || now.Sub(rec.firstDetected) < im.policy.MinAge {
+			continue
+		}
+		candidates.ids = append(candidates.ids, id)
+		candidates.records = append(candidates.records, rec)
+	}
+	im.lock.Unlock()
+	sort.Sort(candidates)
+
+	var freed int64
+	for i, id := range candidates.ids {
+		if freed >= bytes {
+			break
+		}
+		// An image may still be needed by a stopped container or as the parent of another image.
+		if err := im.dockerClient.RemoveImage(id); err != nil {
+			glog.V(2).Infof("Failed to remove image %s: %v", id, err)
+			continue
+		}
+		size := candidates.records[i].size
+		glog.Infof("Removed image %s of %d bytes", id, size)
+		im.lock.Lock()
+		delete(im.images, id)
+		im.lock.Unlock()
+		freed += size
+		metrics.ImageGCRemovedImages.Inc()
+		metrics.ImageGCFreedBytes.Add(float64(size))
+	}
+	if freed > 0 {
+		record.Eventf(im.nodeRef, "", "imageGC", "Removed unused images of %d bytes", freed)
+	}
+	return freed, nil
+}
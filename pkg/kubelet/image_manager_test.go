@@ -0,0 +1,174 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/fsouza/go-dockerclient"
+)
+
+func newTestImageManager(t *testing.T, fakeDocker *dockertools.FakeDockerClient, capacity, available uint64) *imageManager {
+	im, err := newImageManager(fakeDocker, "/var/lib/docker", ImageGCPolicy{HighThresholdPercent: 90, LowThresholdPercent: 80, MinAge: time.Minute}, &api.ObjectReference{Kind: "Minion", Name: "testnode"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	im.fsUsage = func(path string) (uint64, uint64, error) {
+		return capacity, available, nil
+	}
+	return im
+}
+
+func TestNewImageManagerValidatesThresholds(t *testing.T) {
+	for _, policy := range []ImageGCPolicy{
+		{HighThresholdPercent: 0, LowThresholdPercent: 0},
+		{HighThresholdPercent: 101, LowThresholdPercent: 80},
+		{HighThresholdPercent: 80, LowThresholdPercent: 80},
+		{HighThresholdPercent: 80, LowThresholdPercent: -1},
+	} {
+		if _, err := newImageManager(&dockertools.FakeDockerClient{}, "/", policy, nil); err == nil {
+			t.Errorf("expected an error for %+v", policy)
+		}
+	}
+}
+
+func TestDetectImages(t *testing.T) {
+	now := time.Now()
+	fakeDocker := &dockertools.FakeDockerClient{
+		Images: []docker.APIImages{
+			{ID: "running", VirtualSize: 1},
+			{ID: "recent", VirtualSize: 2},
+			{ID: "old", VirtualSize: 3},
+			{ID: "unused", VirtualSize: 4},
+		},
+		ContainerList: []docker.APIContainers{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+		ContainerMap: map[string]*docker.Container{
+			"1": {Image: "running", State: docker.State{Running: true}},
+			"2": {Image: "recent", State: docker.State{FinishedAt: now.Add(-30 * time.Second)}},
+			"3": {Image: "old", State: docker.State{FinishedAt: now.Add(-time.Hour)}},
+		},
+	}
+	im := newTestImageManager(t, fakeDocker, 100, 100)
+
+	inUse, err := im.detectImages(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := []string{"recent", "running"}, inUse.List(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected images in use %v, got %v", e, a)
+	}
+	if len(im.images) != 4 {
+		t.Fatalf("expected 4 image records, got %d", len(im.images))
+	}
+	if !im.images["running"].lastUsed.Equal(now) || !im.images["old"].lastUsed.Equal(now.Add(-time.Hour)) || !im.images["unused"].lastUsed.IsZero() {
+		t.Errorf("unexpected last use times %+v %+v %+v", im.images["running"], im.images["old"], im.images["unused"])
+	}
+	if !im.images["unused"].firstDetected.Equal(now) || im.images["unused"].size != 4 {
+		t.Errorf("unexpected record %+v", im.images["unused"])
+	}
+
+	fakeDocker.Images = fakeDocker.Images[:1]
+	if _, err := im.detectImages(now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(im.images) != 1 || !im.images["running"].firstDetected.Equal(now) {
+		t.Errorf("expected only the first record of the remaining image, got %+v", im.images)
+	}
+}
+
+func TestImageGarbageCollectBelowHighThreshold(t *testing.T) {
+	fakeDocker := &dockertools.FakeDockerClient{
+		Images: []docker.APIImages{{ID: "unused", VirtualSize: 10}},
+	}
+	im := newTestImageManager(t, fakeDocker, 100, 11)
+
+	if err := im.GarbageCollect(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(fakeDocker.RemovedImages) != 0 {
+		t.Errorf("unexpected removed images %v", fakeDocker.RemovedImages)
+	}
+}
+
+func TestImageGarbageCollectRemovesLeastRecentlyUsed(t *testing.T) {
+	now := time.Now()
+	fakeDocker := &dockertools.FakeDockerClient{
+		Images: []docker.APIImages{
+			{ID: "running", VirtualSize: 50},
+			{ID: "recent", VirtualSize: 50},
+			{ID: "old", VirtualSize: 6},
+			{ID: "older", VirtualSize: 6},
+			{ID: "oldest", VirtualSize: 6},
+		},
+		ContainerList: []docker.APIContainers{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}},
+		ContainerMap: map[string]*docker.Container{
+			"1": {Image: "running", State: docker.State{Running: true}},
+			"2": {Image: "recent", State: docker.State{FinishedAt: now.Add(-30 * time.Second)}},
+			"3": {Image: "old", State: docker.State{FinishedAt: now.Add(-time.Hour)}},
+			"4": {Image: "older", State: docker.State{FinishedAt: now.Add(-2 * time.Hour)}},
+			"5": {Image: "oldest", State: docker.State{FinishedAt: now.Add(-3 * time.Hour)}},
+		},
+	}
+	// 95% used, 15 bytes must be freed to get down to 80%.
+	im := newTestImageManager(t, fakeDocker, 100, 5)
+	// The images were detected long enough ago to be removed.
+	if _, err := im.detectImages(now.Add(-time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := im.GarbageCollect(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if e, a := []string{"oldest", "older", "old"}, fakeDocker.RemovedImages; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected removed images %v, got %v", e, a)
+	}
+	if _, found := im.images["oldest"]; found {
+		t.Errorf("expected the record of a removed image to be removed")
+	}
+}
+
+func TestImageGarbageCollectFailsToFreeEnough(t *testing.T) {
+	now := time.Now()
+	fakeDocker := &dockertools.FakeDockerClient{
+		Images: []docker.APIImages{
+			{ID: "running", VirtualSize: 50},
+			{ID: "new", VirtualSize: 50},
+			{ID: "unused", VirtualSize: 5},
+		},
+		ContainerList: []docker.APIContainers{{ID: "1"}},
+		ContainerMap: map[string]*docker.Container{
+			"1": {Image: "running", State: docker.State{Running: true}},
+		},
+	}
+	im := newTestImageManager(t, fakeDocker, 100, 5)
+	if _, err := im.detectImages(now.Add(-time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Pulled too recently to be removed.
+	im.images["new"].firstDetected = now
+
+	if err := im.GarbageCollect(); err == nil {
+		t.Errorf("expected an error when not enough space could be freed")
+	}
+	if e, a := []string{"unused"}, fakeDocker.RemovedImages; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected removed images %v, got %v", e, a)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -35,16 +36,21 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/capabilities"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/credentialprovider"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fieldpath"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/health"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/hooks"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
 	"github.com/fsouza/go-dockerclient"
 	"github.com/golang/glog"
-	"github.com/google/cadvisor/info"
 )
 
 const defaultChanSize = 1024
@@ -58,6 +64,13 @@ const defaultDevice = "eth1"
 const sriovMode = "sriov"
 const bridgeMode = "bridge"
 
+// Restart delays of crashing containers.
+const (
+	initialContainerBackOff    = 10 * time.Second
+	defaultMaxContainerBackOff = 5 * time.Minute
+	crashLoopBackOffReason     = "CrashLoopBackOff"
+)
+
 // SyncHandler is an interface implemented by Kubelet, for testability
 type SyncHandler interface {
 	SyncPods([]api.BoundPod) error
@@ -76,8 +89,23 @@ func NewMainKubelet(
 	pullQPS float32,
 	pullBurst int,
 	minimumGCAge time.Duration,
-	maxContainerCount int) *Kubelet {
-	return &Kubelet{
+	maxContainerCount int,
+	maxContainerBackOff time.Duration,
+	containerBackOffReset time.Duration,
+	statsHistory time.Duration,
+	dockerRoot string,
+	imageGCPolicy ImageGCPolicy,
+	evictionThresholds []EvictionThreshold,
+	evictionPressureTransitionPeriod time.Duration,
+	containerRelistPeriod time.Duration,
+	volumePlugins []volume.VolumePlugin) (*Kubelet, error) {
+	dc = dockertools.NewInstrumentedDockerInterface(dc)
+	var containerCache *dockertools.ContainerCache
+	if containerRelistPeriod > 0 {
+		containerCache = dockertools.NewContainerCache(dc, containerRelistPeriod)
+		dc = containerCache
+	}
+	kl := &Kubelet{
 		hostname:              hn,
 		dockerClient:          dc,
 		etcdClient:            ec,
@@ -94,13 +122,35 @@ func NewMainKubelet(
 		maxContainerCount:     maxContainerCount,
 		keyring:               credentialprovider.NewDockerKeyring(),
 		podDestroyed:          map[string]*api.BoundPod{},
+		backOff:               util.NewBackOff(initialContainerBackOff, maxContainerBackOff),
+		backOffReset:          containerBackOffReset,
+		containerCache:        containerCache,
+		readiness:             newReadinessStates(),
+	}
+	kl.runtime = newDockerRuntime(kl)
+	kl.hooks = kl.defaultHooks()
+	if statsHistory > 0 {
+		kl.podStats = newPodStatsHistory(statsHistory)
+	}
+	imageManager, err := newImageManager(dc, dockerRoot, imageGCPolicy, kl.nodeRef())
+	if err != nil {
+		return nil, fmt.Errorf("invalid image garbage collection policy: %v", err)
+	}
+	kl.imageManager = imageManager
+	if len(evictionThresholds) > 0 {
+		kl.evictionManager = newEvictionManager(evictionThresholds, evictionPressureTransitionPeriod)
 	}
+	if err := kl.volumePluginMgr.InitPlugins(volumePlugins, kl); err != nil {
+		return nil, err
+	}
+	metrics.Register(newPodMetricsCollector(kl))
+	return kl, nil
 }
 
 // NewIntegrationTestKubelet creates a new Kubelet for use in integration tests.
 // TODO: add more integration tests, and expand parameter list as needed.
-func NewIntegrationTestKubelet(hn string, rd string, dc dockertools.DockerInterface) *Kubelet {
-	return &Kubelet{
+func NewIntegrationTestKubelet(hn string, rd string, dc dockertools.DockerInterface, volumePlugins []volume.VolumePlugin) *Kubelet {
+	kl := &Kubelet{
 		hostname:              hn,
 		dockerClient:          dc,
 		rootDirectory:         rd,
@@ -109,7 +159,13 @@ func NewIntegrationTestKubelet(hn string, rd string, dc dockertools.DockerInterf
 		resyncInterval:        3 * time.Second,
 		podWorkers:            newPodWorkers(),
 		dockerIDToRef:         map[dockertools.DockerID]*api.ObjectReference{},
+		readiness:             newReadinessStates(),
 	}
+	kl.runtime = newDockerRuntime(kl)
+	if err := kl.volumePluginMgr.InitPlugins(volumePlugins, kl); err != nil {
+		glog.Errorf("Failed to initialize the volume plugins: %v", err)
+	}
+	return kl
 }
 
 type httpGetter interface {
@@ -120,11 +176,14 @@ type httpGetter interface {
 type Kubelet struct {
 	hostname              string
 	dockerClient          dockertools.DockerInterface
+	runtime               kubecontainer.Runtime
 	rootDirectory         string
 	networkContainerImage string
 	podWorkers            *podWorkers
 	resyncInterval        time.Duration
-	pods                  []api.BoundPod
+	// pods are set by the sync loop and read by the other goroutines through getPods.
+	pods    []api.BoundPod
+	podLock sync.RWMutex
 
 	// Needed to report events for containers belonging to deleted/modified pods.
 	// Tracks references for reporting events
@@ -158,7 +217,51 @@ type Kubelet struct {
 	keyring           credentialprovider.DockerKeyring
 
 	podDestroyed map[string]*api.BoundPod
-}
+
+	// The UIDs of the pods whose init containers have completed, which are not run again.
+	initializedPods     util.StringSet
+	initializedPodsLock sync.Mutex
+
+	// Optional, restart back-off of crashing containers, keyed by containerBackOffKey.
+	backOff *util.Backoff
+	// Optional, a container which ran at least this long has its back-off reset. If zero, never reset.
+	backOffReset time.Duration
+	// Optional, results of the readiness probes, no container with a readiness probe is ready without it.
+	readiness *readinessStates
+	// Optional, runs the liveness and readiness probes and records the readiness results in readiness.
+	prober *prober
+	// Optional, node hooks run around the lifecycle of the containers; no host integration is done without it.
+	hooks *hooks.Manager
+	// Optional, history of the aggregated usage of the pods; only the current usage is served without it.
+	podStats *podStatsHistory
+	// Optional, no image is garbage collected without it.
+	imageManager *imageManager
+	// Optional, no pod is evicted and no pressure is reported without it.
+	evictionManager *evictionManager
+
+	// Optional, no secret volume can be set up and no pod is mirrored without it.
+	kubeClient client.Interface
+	kubeLock   sync.RWMutex
+	// The mirror pods of the host in the apiserver by namespace and name, nil until listed.
+	mirrorPods map[string]*api.Pod
+
+	// The plugins of the volumes of the pods; no volume is supported without them.
+	volumePluginMgr volume.PluginMgr
+
+	// Optional, reports whether every pod source has sent its pods; the containers of
+	// the pods which are not desired are always cleaned up without it.
+	sourcesReady SourcesReadyFn
+
+	// Optional, the containers are listed from docker on every sync and the pods are only
+	// synced periodically without it.
+	containerCache *dockertools.ContainerCache
+	// The full names of the pods whose containers started or died, synced by the syncLoop
+	// without waiting for the next periodic sync.
+	podSyncs chan string
+}
+
+// SourcesReadyFn returns true once the state of every pod source is known.
+type SourcesReadyFn func() bool
 
 type ByCreated []*docker.Container
 
@@ -223,6 +326,15 @@ func (kl *Kubelet) GarbageCollectContainers() error {
 	return nil
 }
 
+// GarbageCollectImages removes the least recently used images which are not in use if the
+// filesystem of the docker root directory is over the high threshold of the image GC policy.
+func (kl *Kubelet) GarbageCollectImages() error {
+	if kl.imageManager == nil {
+		return nil
+	}
+	return kl.imageManager.GarbageCollect()
+}
+
 // SetCadvisorClient sets the cadvisor client in a thread-safe way.
 func (kl *Kubelet) SetCadvisorClient(c cadvisorInterface) {
 	kl.cadvisorLock.Lock()
@@ -237,6 +349,35 @@ func (kl *Kubelet) GetCadvisorClient() cadvisorInterface {
 	return kl.cadvisorClient
 }
 
+// SetKubeClient sets the apiserver client in a thread-safe way.
+func (kl *Kubelet) SetKubeClient(c client.Interface) {
+	kl.kubeLock.Lock()
+	defer kl.kubeLock.Unlock()
+	kl.kubeClient = c
+}
+
+// SetSourcesReady sets the function the kubelet checks before cleaning up the
+// containers of the pods which are not desired.
+func (kl *Kubelet) SetSourcesReady(sourcesReady SourcesReadyFn) {
+	kl.sourcesReady = sourcesReady
+}
+
+// GetRootDir returns the directory the volumes of the pods are set up under.
+func (kl *Kubelet) GetRootDir() string {
+	return kl.rootDirectory
+}
+
+// GetSecret fetches a secret from the apiserver, for the secret volumes.
+func (kl *Kubelet) GetSecret(namespace, name string) (*api.Secret, error) {
+	kl.kubeLock.RLock()
+	c := kl.kubeClient
+	kl.kubeLock.RUnlock()
+	if c == nil {
+		return nil, fmt.Errorf("no apiserver client to fetch the secret %s/%s", namespace, name)
+	}
+	return c.Secrets(namespace).Get(name)
+}
+
 // Run starts the kubelet reacting to config updates
 func (kl *Kubelet) Run(updates <-chan PodUpdate) {
 	if kl.logServer == nil {
@@ -245,9 +386,36 @@ func (kl *Kubelet) Run(updates <-chan PodUpdate) {
 	if kl.dockerPuller == nil {
 		kl.dockerPuller = dockertools.NewDockerPuller(kl.dockerClient, kl.pullQPS, kl.pullBurst)
 	}
+	if kl.runtime == nil {
+		kl.runtime = newDockerRuntime(kl)
+	}
 	if kl.healthChecker == nil {
 		kl.healthChecker = health.NewHealthChecker()
 	}
+	if kl.backOff == nil {
+		kl.backOff = util.NewBackOff(initialContainerBackOff, defaultMaxContainerBackOff)
+	}
+	if kl.readiness == nil {
+		kl.readiness = newReadinessStates()
+	}
+	if kl.prober == nil {
+		kl.prober = newProber(kl.healthChecker, kl.readiness)
+	}
+	if kl.podStats != nil {
+		go util.Forever(kl.collectPodStats, podStatsInterval)
+	}
+	if kl.imageManager != nil {
+		kl.imageManager.Start()
+	}
+	if kl.evictionManager != nil {
+		go util.Forever(kl.synchronizeEviction, evictionMonitoringPeriod)
+	}
+	if kl.containerCache != nil {
+		if kl.podSyncs == nil {
+			kl.podSyncs = make(chan string, podSyncsBufferSize)
+		}
+		kl.containerCache.Start(kl.handleContainerEvent)
+	}
 	kl.syncLoop(updates, kl)
 }
 
@@ -276,6 +444,7 @@ func (self *podWorkers) Run(podFullName string, action func()) {
 		return
 	}
 	self.workers.Insert(podFullName)
+	metrics.PodWorkers.Set(float64(len(self.workers)))
 
 	// Run worker async.
 	go func() {
@@ -285,15 +454,55 @@ func (self *podWorkers) Run(podFullName string, action func()) {
 		self.lock.Lock()
 		defer self.lock.Unlock()
 		self.workers.Delete(podFullName)
+		metrics.PodWorkers.Set(float64(len(self.workers)))
 	}()
 }
 
-func makeEnvironmentVariables(container *api.Container) []string {
+// makeEnvironmentVariables returns the environment of a container. The values of the variables
+// with a valueFrom are the fields of the pod they select.
+func makeEnvironmentVariables(pod *api.BoundPod, container *api.Container) ([]string, error) {
 	var result []string
 	for _, value := range container.Env {
-		result = append(result, fmt.Sprintf("%s=%s", value.Name, value.Value))
+		v := value.Value
+		if value.ValueFrom != nil && value.ValueFrom.FieldRef != nil {
+			var err error
+			v, err = fieldpath.ExtractFieldPathAsString(pod, value.ValueFrom.FieldRef.FieldPath)
+			if err != nil {
+				return nil, fmt.Errorf("environment variable %s: %v", value.Name, err)
+			}
+		}
+		result = append(result, fmt.Sprintf("%s=%s", value.Name, v))
+	}
+	return result, nil
+}
+
+// checkHostDirs enforces the host directory policy on the mounts of the container, in case
+// the apiserver let the pod through. The directories are checked once their symlinks are
+// resolved, since a symlink under an allowed prefix may point anywhere on the host.
+func checkHostDirs(pod *api.BoundPod, container *api.Container) error {
+	policy := capabilities.Get().HostDirPolicy
+	if policy == nil {
+		return nil
 	}
-	return result
+	for _, mount := range container.VolumeMounts {
+		for _, vol := range pod.Spec.Volumes {
+			if vol.Name != mount.Name || vol.Source == nil || vol.Source.HostDir == nil {
+				continue
+			}
+			hostPath := vol.Source.HostDir.Path
+			if resolved, err := filepath.EvalSymlinks(hostPath); err == nil {
+				hostPath = resolved
+			}
+			rule := policy.Rule(pod.Namespace, hostPath)
+			if rule == nil {
+				return fmt.Errorf("host directory %s is not allowed in namespace %s", hostPath, pod.Namespace)
+			}
+			if rule.ReadOnly && !mount.ReadOnly {
+				return fmt.Errorf("host directory %s may only be mounted read-only in namespace %s", hostPath, pod.Namespace)
+			}
+		}
+	}
+	return nil
 }
 
 func makeBinds(pod *api.BoundPod, container *api.Container, podVolumes volumeMap) []string {
@@ -361,7 +570,7 @@ func milliCPUToShares(milliCPU int) int {
 func (kl *Kubelet) mountExternalVolumes(pod *api.BoundPod) (volumeMap, error) {
 	podVolumes := make(volumeMap)
 	for _, vol := range pod.Spec.Volumes {
-		extVolume, err := volume.CreateVolumeBuilder(&vol, pod.Name, kl.rootDirectory)
+		extVolume, err := kl.volumePluginMgr.NewBuilder(&vol, pod)
 		if err != nil {
 			return nil, err
 		}
@@ -413,6 +622,12 @@ func fieldPath(pod *api.BoundPod, container *api.Container) (string, error) {
 			return fmt.Sprintf("spec.containers[%d]", i), nil
 		}
 	}
+	for i := range pod.Spec.InitContainers {
+		here := &pod.Spec.InitContainers[i]
+		if here == container {
+			return fmt.Sprintf("spec.initContainers[%d]", i), nil
+		}
+	}
 	return "", fmt.Errorf("container %#v not found in pod %#v", container, pod)
 }
 
@@ -470,168 +685,222 @@ func (kl *Kubelet) clearDestroyedPod(uuid string) {
 	delete(kl.podDestroyed, uuid)
 }
 
-// Run a single container from a pod. Returns the docker container ID
-func (kl *Kubelet) runContainer(pod *api.BoundPod, container *api.Container, podVolumes volumeMap, netMode string) (id dockertools.DockerID, err error) {
+// getDestroyedPodContainer returns the last known spec of a pod and of one of its containers.
+// The pod is nil if it is unknown, the container spec then only carries the name.
+func (kl *Kubelet) getDestroyedPodContainer(uuid, containerName string) (*api.BoundPod, *api.Container) {
+	kl.refLock.RLock()
+	defer kl.refLock.RUnlock()
+	pod, ok := kl.podDestroyed[uuid]
+	if !ok {
+		return nil, &api.Container{Name: containerName}
+	}
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == containerName {
+			return pod, &pod.Spec.Containers[i]
+		}
+	}
+	for i := range pod.Spec.InitContainers {
+		if pod.Spec.InitContainers[i].Name == containerName {
+			return pod, &pod.Spec.InitContainers[i]
+		}
+	}
+	return pod, &api.Container{Name: containerName}
+}
+
+// Run a single container from a pod. Returns the ID of the container in the runtime.
+func (kl *Kubelet) runContainer(pod *api.BoundPod, container *api.Container, podVolumes volumeMap, netMode string) (id string, err error) {
 	ref, err := containerRef(pod, container)
 	if err != nil {
 		glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
 	}
 
-	// start lxcf befor create container
-	// hbo
 	if container.Name != networkContainerName {
-		if err = kl.OpLxcfs(container.Name, "start"); err != nil {
-			glog.Errorf("Failed to start lxcfs for %s: %v", container.Name, err)
+		if err = kl.runHooks(hooks.PreCreate, pod, container, ""); err != nil {
+			glog.Errorf("Failed to prepare pod %s container %s: %v", pod.Name, container.Name, err)
 			return "", err
 		}
 	}
 
-	envVariables := makeEnvironmentVariables(container)
-	binds := makeBinds(pod, container, podVolumes)
-	exposedPorts, portBindings := makePortsAndBindings(container)
-
-	opts := docker.CreateContainerOptions{
-		Name: dockertools.BuildDockerName(pod.UID, GetPodFullName(pod), container),
-		Config: &docker.Config{
-			Cmd:          container.Command,
-			Env:          envVariables,
-			ExposedPorts: exposedPorts,
-			Hostname:     pod.Name,
-			Image:        container.Image,
-			Memory:       int64(container.Memory),
-			CpuShares:    int64(milliCPUToShares(container.CPU)),
-			CpuSet:       pod.Res.CpuSet,
-			WorkingDir:   container.WorkingDir,
-		},
-	}
-	dockerContainer, err := kl.dockerClient.CreateContainer(opts)
+	envVariables, err := makeEnvironmentVariables(pod, container)
 	if err != nil {
+		return "", err
+	}
+	if err := checkHostDirs(pod, container); err != nil {
 		if ref != nil {
-			record.Eventf(ref, "failed", "failed",
-				"Failed to create docker container with error: %v", err)
+			record.Eventf(ref, "failed", "forbidden", "%v", err)
 		}
 		return "", err
 	}
-	// Remember this reference so we can report events about this container
-	if ref != nil {
-		kl.setRef(dockertools.DockerID(dockerContainer.ID), ref)
-		record.Eventf(ref, "waiting", "created", "Created with docker id %v", dockerContainer.ID)
+	if container.Privileged && !capabilities.Get().AllowPrivileged {
+		return "", fmt.Errorf("container requested privileged mode, but it is disallowed globally.")
+	}
+	opts := &kubecontainer.RunContainerOptions{
+		Envs:    envVariables,
+		Binds:   makeBinds(pod, container, podVolumes),
+		NetMode: netMode,
 	}
-
 	if len(container.TerminationMessagePath) != 0 {
-		p := path.Join(kl.rootDirectory, pod.Name, container.Name)
-		if err := os.MkdirAll(p, 0750); err != nil {
-			glog.Errorf("Error on creating %s: %v", p, err)
-		} else {
-			containerLogPath := path.Join(p, dockerContainer.ID)
-			fs, err := os.Create(containerLogPath)
-			if err != nil {
-				glog.Errorf("Error on creating termination-log file %s: %v", containerLogPath, err)
-			}
-			defer fs.Close()
-			b := fmt.Sprintf("%s:%s", containerLogPath, container.TerminationMessagePath)
-			binds = append(binds, b)
-		}
+		opts.PodContainerDir = path.Join(kl.rootDirectory, pod.Name, container.Name)
 	}
-	privileged := false
-	if capabilities.Get().AllowPrivileged {
-		privileged = container.Privileged
-	} else if container.Privileged {
-		return "", fmt.Errorf("container requested privileged mode, but it is disallowed globally.")
+	id, err = kl.runtime.RunContainer(pod, container, opts)
+	if id != "" && ref != nil {
+		// Remember this reference so we can report events about this container
+		kl.setRef(dockertools.DockerID(id), ref)
+		record.Eventf(ref, "waiting", "created", "Created with id %v", id)
 	}
-	err = kl.dockerClient.StartContainer(dockerContainer.ID, &docker.HostConfig{
-		PortBindings: portBindings,
-		Binds:        binds,
-		NetworkMode:  netMode,
-		Privileged:   privileged,
-		CapAdd:       container.CapAdd,
-		CapDrop:      container.CapDrop,
-	})
 	if err != nil {
 		if ref != nil {
-			record.Eventf(ref, "failed", "failed",
-				"Failed to start with docker id %v with error: %v", dockerContainer.ID, err)
+			if id == "" {
+				record.Eventf(ref, "failed", "failed", "Failed to create container with error: %v", err)
+			} else {
+				record.Eventf(ref, "failed", "failed", "Failed to start with id %v with error: %v", id, err)
+			}
 		}
 		return "", err
 	}
 	if ref != nil {
-		record.Eventf(ref, "running", "started", "Started with docker id %v", dockerContainer.ID)
+		record.Eventf(ref, "running", "started", "Started with id %v", id)
 	}
 
 	if container.Lifecycle != nil && container.Lifecycle.PostStart != nil {
 		handlerErr := kl.runHandler(GetPodFullName(pod), pod.UID, container, container.Lifecycle.PostStart)
 		if handlerErr != nil {
-			kl.killContainerByID(dockerContainer.ID, "")
-			return dockertools.DockerID(""), fmt.Errorf("failed to call event handler: %v", handlerErr)
+			kl.runtime.KillContainer(id, terminationGracePeriod(nil))
+			return "", fmt.Errorf("failed to call event handler: %v", handlerErr)
 		}
 	}
 
 	if container.Name != networkContainerName {
-		// set disk quota
-		if err := kl.addDiskQuota(dockerContainer.ID, container.Name, container.Disk); err != nil {
-			glog.Errorf("Failed to set up disk quota %v", err)
+		if err := kl.runHooks(hooks.PostStart, pod, container, id); err != nil {
+			glog.Errorf("Failed to set up pod %s container %s: %v", pod.Name, container.Name, err)
+			runningPod := kubecontainer.Pod{FullName: GetPodFullName(pod), UID: pod.UID}
+			kl.killContainer(runningPod, &kubecontainer.Container{ID: id, Name: container.Name})
 			return "", err
 		}
-
-		// Set sriov if network mode eq "sriov"
-		if pod.Res.Network.Mode == sriovMode {
-			if err := kl.setupSriov(dockerContainer.ID, pod); err != nil {
-				glog.Errorf("Failed to Set up sriov: %v", err)
-				return "", err
-			}
-		}
-		if container.Blkio != nil {
-			blkio := &BlkioGroup{}
-			if err := blkio.SetUp(dockerContainer.ID, container.Blkio); err != nil {
-				glog.Errorf("Failed to set up blkio %v", err)
-				return "", err
-			}
-		}
 	}
 
-	return dockertools.DockerID(dockerContainer.ID), err
+	return id, nil
 }
 
-// Kill a docker container
-func (kl *Kubelet) killContainer(dockerContainer *docker.APIContainers) error {
-	return kl.killContainerByID(dockerContainer.ID, dockerContainer.Names[0])
-}
-
-func (kl *Kubelet) killContainerByID(ID, name string) error {
-	glog.V(2).Infof("Killing: %s", ID)
+// killContainer kills a container of runningPod. The container runs its PreStop handler, is
+// sent SIGTERM and is killed once the termination grace period of its pod is over.
+func (kl *Kubelet) killContainer(runningPod kubecontainer.Pod, container *kubecontainer.Container) error {
+	glog.V(2).Infof("Killing: %s", container.ID)
 
-	// delete disk quota
-	_, _, containerName, _ := dockertools.ParseDockerName(name)
-	if containerName != networkContainerName {
-		if err := kl.removeDiskQuota(ID, containerName); err != nil {
-			glog.Errorf("Failed to clean up disk quota %v", err)
-			return err
+	pod, spec := kl.getDestroyedPodContainer(runningPod.UID, container.Name)
+	gracePeriod := terminationGracePeriod(pod)
+	if pod != nil && gracePeriod > 0 && spec.Lifecycle != nil && spec.Lifecycle.PreStop != nil {
+		gracePeriod = kl.runPreStop(pod, spec, gracePeriod)
+	}
+	err := kl.runtime.KillContainer(container.ID, gracePeriod)
+	// The container is stopped, so a failing PostStop hook is logged rather than failing the kill.
+	if err == nil && container.Name != networkContainerName {
+		if hookErr := kl.runHooks(hooks.PostStop, pod, spec, container.ID); hookErr != nil {
+			glog.Errorf("Failed to clean up container %s of pod %s: %v", container.Name, runningPod.FullName, hookErr)
 		}
 	}
-
-	err := kl.dockerClient.StopContainer(ID, 10)
-	if len(name) == 0 {
-		return err
+	if kl.prober != nil {
+		kl.prober.stop(runningPod.FullName, runningPod.UID, container.Name)
 	}
 
-	ref, ok := kl.getRef(dockertools.DockerID(ID))
+	ref, ok := kl.getRef(dockertools.DockerID(container.ID))
 	if !ok {
-		glog.Warningf("No ref for pod '%v' - '%v'", ID, name)
+		glog.Warningf("No ref for pod '%v' - '%v'", container.ID, container.Name)
 	} else {
 		// TODO: pass reason down here, and state, or move this call up the stack.
-		record.Eventf(ref, "terminated", "killing", "Killing %v - %v", ID, name)
+		record.Eventf(ref, "terminated", "killing", "Killing %v - %v", container.ID, container.Name)
 	}
 
 	return err
 }
 
+// killPod kills the containers of runningPod in parallel, then its network container.
+func (kl *Kubelet) killPod(runningPod kubecontainer.Pod) error {
+	var netContainer *kubecontainer.Container
+	var lock sync.Mutex
+	errList := []error{}
+	var wg sync.WaitGroup
+	for _, container := range runningPod.Containers {
+		if container.Name == networkContainerName {
+			netContainer = container
+			continue
+		}
+		wg.Add(1)
+		go func(container *kubecontainer.Container) {
+			defer wg.Done()
+			if err := kl.killContainer(runningPod, container); err != nil {
+				glog.Errorf("Failed to kill container %s of pod %s: %v", container.ID, runningPod.FullName, err)
+				lock.Lock()
+				errList = append(errList, err)
+				lock.Unlock()
+			}
+		}(container)
+	}
+	wg.Wait()
+	if netContainer != nil {
+		if err := kl.killContainer(runningPod, netContainer); err != nil {
+			glog.Errorf("Failed to kill network container %s of pod %s: %v", netContainer.ID, runningPod.FullName, err)
+			errList = append(errList, err)
+		}
+	}
+	if len(errList) > 0 {
+		return fmt.Errorf("failed to kill containers (%v)", errList)
+	}
+	return nil
+}
+
+// minimumGracePeriod is how long a container which has a termination grace period is left to
+// stop after SIGTERM, even if its PreStop handler used up the grace period.
+const minimumGracePeriod = 2 * time.Second
+
+// terminationGracePeriod returns how long the containers of pod have to stop gracefully, at most
+// until the deletion timestamp of a terminating pod. Containers of unknown pods have the default.
+func terminationGracePeriod(pod *api.BoundPod) time.Duration {
+	if pod == nil {
+		return api.TerminationGracePeriod(&api.PodSpec{})
+	}
+	gracePeriod := api.TerminationGracePeriod(&pod.Spec)
+	if pod.DeletionTimestamp != nil {
+		if remaining := pod.DeletionTimestamp.Sub(time.Now()); remaining < gracePeriod {
+			gracePeriod = remaining
+		}
+	}
+	if gracePeriod < 0 {
+		return 0
+	}
+	return gracePeriod
+}
+
+// runPreStop runs the PreStop handler of container for at most gracePeriod, and returns how long
+// the container is left to stop after SIGTERM.
+func (kl *Kubelet) runPreStop(pod *api.BoundPod, container *api.Container, gracePeriod time.Duration) time.Duration {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- kl.runHandler(GetPodFullName(pod), pod.UID, container, container.Lifecycle.PreStop)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			glog.Errorf("PreStop handler of pod %s container %s failed: %v", GetPodFullName(pod), container.Name, err)
+		}
+	case <-time.After(gracePeriod):
+		glog.Warningf("PreStop handler of pod %s container %s did not complete within %v", GetPodFullName(pod), container.Name, gracePeriod)
+	}
+	remaining := gracePeriod - time.Since(start)
+	if remaining < minimumGracePeriod {
+		remaining = minimumGracePeriod
+	}
+	return remaining
+}
+
 const (
 	networkContainerName  = "net"
 	NetworkContainerImage = "kubernetes/pause:latest"
 )
 
-// createNetworkContainer starts the network container for a pod. Returns the docker container ID of the newly created container.
-func (kl *Kubelet) createNetworkContainer(pod *api.BoundPod) (dockertools.DockerID, error) {
+// createNetworkContainer starts the network container for a pod. Returns the ID of the newly created container.
+func (kl *Kubelet) createNetworkContainer(pod *api.BoundPod) (string, error) {
 	var ports []api.Port
 	// Docker only exports ports from the network container.  Let's
 	// collect all of the relevant ports and export them.
@@ -648,7 +917,7 @@ func (kl *Kubelet) createNetworkContainer(pod *api.BoundPod) (dockertools.Docker
 		glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
 	}
 	// TODO: make this a TTL based pull (if image older than X policy, pull)
-	ok, err := kl.dockerPuller.IsImagePresent(container.Image)
+	ok, err := kl.runtime.IsImagePresent(container.Image)
 	if err != nil {
 		if ref != nil {
 			record.Eventf(ref, "failed", "failed", "Failed to inspect image %s", container.Image)
@@ -656,7 +925,7 @@ func (kl *Kubelet) createNetworkContainer(pod *api.BoundPod) (dockertools.Docker
 		return "", err
 	}
 	if !ok {
-		if err := kl.dockerPuller.Pull(container.Image); err != nil {
+		if err := kl.runtime.PullImage(container.Image); err != nil {
 			if ref != nil {
 				record.Eventf(ref, "failed", "failed", "Failed to pull image %s", container.Image)
 			}
@@ -670,19 +939,20 @@ func (kl *Kubelet) createNetworkContainer(pod *api.BoundPod) (dockertools.Docker
 }
 
 // Kill all containers in a pod.  Returns the number of containers deleted and an error if one occurs.
-func (kl *Kubelet) killContainersInPod(pod *api.BoundPod, dockerContainers dockertools.DockerContainers) (int, error) {
+func (kl *Kubelet) killContainersInPod(pod *api.BoundPod, runningPod kubecontainer.Pod) (int, error) {
 	podFullName := GetPodFullName(pod)
 
 	count := 0
-	errs := make(chan error, len(pod.Spec.Containers))
+	containers := podContainers(&pod.Spec)
+	errs := make(chan error, len(containers))
 	wg := sync.WaitGroup{}
-	for _, container := range pod.Spec.Containers {
+	for _, container := range containers {
 		// TODO: Consider being more aggressive: kill all containers with this pod UID, period.
-		if dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, pod.UID, container.Name); found {
+		if runningContainer := runningPod.FindContainerByName(container.Name); runningContainer != nil {
 			count++
 			wg.Add(1)
 			go func() {
-				err := kl.killContainer(dockerContainer)
+				err := kl.killContainer(runningPod, runningContainer)
 				if err != nil {
 					glog.Errorf("Failed to delete container: %v; Skipping pod %s", err, podFullName)
 					errs <- err
@@ -703,34 +973,41 @@ func (kl *Kubelet) killContainersInPod(pod *api.BoundPod, dockerContainers docke
 	return count, nil
 }
 
+// podContainers returns the init containers of a pod followed by its containers.
+func podContainers(spec *api.PodSpec) []api.Container {
+	containers := make([]api.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	containers = append(containers, spec.InitContainers...)
+	return append(containers, spec.Containers...)
+}
+
 type empty struct{}
 
-func (kl *Kubelet) syncPod(pod *api.BoundPod, dockerContainers dockertools.DockerContainers) error {
+func (kl *Kubelet) syncPod(pod *api.BoundPod, runningPod kubecontainer.Pod) error {
 	if pod.Res.Network.Mode == api.PodNetworkModeHost {
-		return kl.syncPodHostNetwork(pod, dockerContainers)
+		return kl.syncPodHostNetwork(pod, runningPod)
 	}
 
 	// bridge and nat will go here
 	podFullName := GetPodFullName(pod)
 	uuid := pod.UID
-	containersToKeep := make(map[dockertools.DockerID]empty)
-	killedContainers := make(map[dockertools.DockerID]empty)
+	containersToKeep := make(map[string]empty)
+	killedContainers := make(map[string]empty)
 
 	// Make sure we have a network container
-	var netID dockertools.DockerID
-	if netDockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uuid, networkContainerName); found {
-		netID = dockertools.DockerID(netDockerContainer.ID)
+	var netID string
+	if netContainer := runningPod.FindContainerByName(networkContainerName); netContainer != nil {
+		netID = netContainer.ID
 	} else {
 		// check the network container whether has been created
 		// TODO(hbo)
-		netContainers, err := dockertools.GetRecentDockerContainersWithNameAndUUID(kl.dockerClient, podFullName, uuid, networkContainerName)
+		netContainers, err := kl.runtime.GetRecentContainers(podFullName, uuid, networkContainerName)
 		if err != nil {
 			glog.Errorf("Error listing net containers with name and uuid:%s--%s--%s", podFullName, uuid, networkContainerName)
 			return err
 		}
 		if len(netContainers) <= 0 {
 			glog.V(3).Infof("Network container doesn't exist for pod %q, re-creating the pod", podFullName)
-			count, err := kl.killContainersInPod(pod, dockerContainers)
+			count, err := kl.killContainersInPod(pod, runningPod)
 			if err != nil {
 				return err
 			}
@@ -741,17 +1018,19 @@ func (kl *Kubelet) syncPod(pod *api.BoundPod, dockerContainers dockertools.Docke
 			}
 			if count > 0 {
 				// Re-list everything, otherwise we'll think we're ok.
-				dockerContainers, err = dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+				runningPods, err := kl.runtime.GetPods(false)
 				if err != nil {
-					glog.Errorf("Error listing containers %#v", dockerContainers)
+					glog.Errorf("Error listing containers: %v", err)
 					return err
 				}
+				runningPod = runningPods.FindPod(podFullName, uuid)
 			}
 
 			if pod.Res.Network.Address != "" {
 				var errMsg string
 				errMsg, err = kl.setupNetwork(netID, pod)
 				if err != nil {
+					metrics.NetworkSetupFailures.Inc(pod.Res.Network.Mode)
 					glog.Errorf("Failed to setup network for network container: %v; error msg: %s; Skipping pod %s", err, errMsg, podFullName)
 					return err
 				}
@@ -779,23 +1058,26 @@ func (kl *Kubelet) syncPod(pod *api.BoundPod, dockerContainers dockertools.Docke
 		podState.PodIP = netInfo.PodIP
 	}
 
-	for _, container := range pod.Spec.Containers {
+	// The containers are started once all the init containers have completed.
+	containers := pod.Spec.Containers
+	if !kl.syncInitContainers(pod, runningPod, podVolumes, "container:"+netID, containersToKeep) {
+		containers = nil
+	}
+	for _, container := range containers {
 		containerChanged := false
-		if dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uuid, container.Name); found {
-			containerID := dockertools.DockerID(dockerContainer.ID)
-			healthy, err := kl.healthy(podFullName, uuid, podState, container, dockerContainer)
-			if err != nil {
-				glog.Errorf("health check errored: %v", err)
-			}
-			if healthy == health.Healthy {
-				glog.V(1).Infof("Container %s(%s) is healthy", container.Name, containerID)
+		if runningContainer := runningPod.FindContainerByName(container.Name); runningContainer != nil {
+			if kl.prober != nil {
+				kl.prober.start(podFullName, uuid, podState, container, runningContainer)
+				if !kl.prober.isLive(podFullName, uuid, container.Name) {
+					glog.V(1).Infof("Container %s(%s) fails its liveness probe", container.Name, runningContainer.ID)
+				}
 			}
-			containersToKeep[containerID] = empty{}
+			containersToKeep[runningContainer.ID] = empty{}
 			continue
 		}
 
 		// Check RestartPolicy for container
-		recentContainers, err := dockertools.GetRecentDockerContainersWithNameAndUUID(kl.dockerClient, podFullName, uuid, container.Name)
+		recentContainers, err := kl.runtime.GetRecentContainers(podFullName, uuid, container.Name)
 		if err != nil {
 			glog.Errorf("Error listing recent containers with name and uuid:%s--%s--%s", podFullName, uuid, container.Name)
 			// TODO(dawnchen): error handling here?
@@ -809,7 +1091,7 @@ func (kl *Kubelet) syncPod(pod *api.BoundPod, dockerContainers dockertools.Docke
 			}
 			if pod.Spec.RestartPolicy.OnFailure != nil {
 				// Check the exit code of last run
-				if recentContainers[0].State.ExitCode == 0 {
+				if recentContainers[0].ExitCode == 0 {
 					glog.V(3).Infof("Already successfully ran container with name %s--%s--%s, do nothing",
 						podFullName, uuid, container.Name)
 					continue
@@ -817,38 +1099,19 @@ func (kl *Kubelet) syncPod(pod *api.BoundPod, dockerContainers dockertools.Docke
 			}
 		}
 
+		if kl.containerInBackOff(pod, &container, recentContainers) {
+			continue
+		}
+
 		glog.V(3).Infof("Container with name %s--%s--%s doesn't exist, creating %#v", podFullName, uuid, container.Name, container)
 		ref, err := containerRef(pod, &container)
 		if err != nil {
 			glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
 		}
-		if !api.IsPullNever(container.ImagePullPolicy) && !kl.checkLocalImage(container.Image) {
-			present, err := kl.dockerPuller.IsImagePresent(container.Image)
-			latest := dockertools.RequireLatestImage(container.Image)
-			if err != nil {
-				if ref != nil {
-					record.Eventf(ref, "failed", "failed", "Failed to inspect image %s", container.Image)
-				}
-				glog.Errorf("Failed to inspect image %s: %v; skipping pod %s container %s", container.Image, err, podFullName, container.Name)
-				continue
-			}
-			if api.IsPullAlways(container.ImagePullPolicy) ||
-				(api.IsPullIfNotPresent(container.ImagePullPolicy) && (!present || latest)) {
-				if err := kl.dockerPuller.Pull(container.Image); err != nil {
-					if ref != nil {
-
-						record.Eventf(ref, "failed", "failed", "Failed to pull image %s", container.Image)
-					}
-					glog.Errorf("Failed to pull image %s: %v; skipping pod %s container %s.", container.Image, err, podFullName, container.Name)
-					continue
-				}
-				if ref != nil {
-					record.Eventf(ref, "waiting", "pulled", "Successfully pulled image %s", container.Image)
-				}
-			}
+		if err := kl.pullImage(podFullName, &container, ref); err != nil {
+			continue
 		}
-		// TODO(dawnchen): Check RestartPolicy.DelaySeconds before restart a container
-		containerID, err := kl.runContainer(pod, &container, podVolumes, "container:"+string(netID))
+		containerID, err := kl.runContainer(pod, &container, podVolumes, "container:"+netID)
 		if err != nil {
 			// TODO(bburns) : Perhaps blacklist a container after N failures?
 			glog.Errorf("Error running pod %s container %s: %v", podFullName, container.Name, err)
@@ -858,18 +1121,15 @@ func (kl *Kubelet) syncPod(pod *api.BoundPod, dockerContainers dockertools.Docke
 	}
 
 	// Kill any containers in this pod which were not identified above (guards against duplicates).
-	for id, container := range dockerContainers {
-		curPodFullName, curUUID, _, _ := dockertools.ParseDockerName(container.Names[0])
-		if curPodFullName == podFullName && curUUID == uuid {
-			// Don't kill containers we want to keep or those we already killed.
-			_, keep := containersToKeep[id]
-			_, killed := killedContainers[id]
-			if !keep && !killed {
-				glog.V(1).Infof("Killing unwanted container in pod %q: %+v", curUUID, container)
-				err = kl.killContainer(container)
-				if err != nil {
-					glog.Errorf("Error killing container: %v", err)
-				}
+	for _, container := range runningPod.Containers {
+		// Don't kill containers we want to keep or those we already killed.
+		_, keep := containersToKeep[container.ID]
+		_, killed := killedContainers[container.ID]
+		if !keep && !killed {
+			glog.V(1).Infof("Killing unwanted container in pod %q: %+v", uuid, container)
+			err = kl.killContainer(runningPod, container)
+			if err != nil {
+				glog.Errorf("Error killing container: %v", err)
 			}
 		}
 	}
@@ -877,6 +1137,161 @@ func (kl *Kubelet) syncPod(pod *api.BoundPod, dockerContainers dockertools.Docke
 	return nil
 }
 
+// pullImage pulls the image of a container when its pull policy asks for it, and records
+// the outcome as events of the container.
+func (kl *Kubelet) pullImage(podFullName string, container *api.Container, ref *api.ObjectReference) error {
+	if api.IsPullNever(container.ImagePullPolicy) || kl.checkLocalImage(container.Image) {
+		return nil
+	}
+	present, err := kl.runtime.IsImagePresent(container.Image)
+	latest := dockertools.RequireLatestImage(container.Image)
+	if err != nil {
+		if ref != nil {
+			record.Eventf(ref, "failed", "failed", "Failed to inspect image %s", container.Image)
+		}
+		glog.Errorf("Failed to inspect image %s: %v; skipping pod %s container %s", container.Image, err, podFullName, container.Name)
+		return err
+	}
+	if api.IsPullAlways(container.ImagePullPolicy) ||
+		(api.IsPullIfNotPresent(container.ImagePullPolicy) && (!present || latest)) {
+		if err := kl.runtime.PullImage(container.Image); err != nil {
+			if ref != nil {
+				record.Eventf(ref, "failed", "failed", "Failed to pull image %s", container.Image)
+			}
+			glog.Errorf("Failed to pull image %s: %v; skipping pod %s container %s.", container.Image, err, podFullName, container.Name)
+			return err
+		}
+		if ref != nil {
+			record.Eventf(ref, "waiting", "pulled", "Successfully pulled image %s", container.Image)
+		}
+	}
+	return nil
+}
+
+// syncInitContainers runs the init containers of a pod one after another in the given network
+// mode, and returns true once all of them have completed successfully. A failed init container
+// is restarted, subject to the restart back-off, unless the restart policy of the pod is Never.
+// Once the init containers have completed, or any container of the pod was started, they are
+// not looked at again, even if their exited instances are removed.
+func (kl *Kubelet) syncInitContainers(pod *api.BoundPod, runningPod kubecontainer.Pod, podVolumes volumeMap, netMode string, containersToKeep map[string]empty) bool {
+	podFullName := GetPodFullName(pod)
+	uuid := pod.UID
+	if len(pod.Spec.InitContainers) == 0 || kl.isPodInitialized(uuid) {
+		return true
+	}
+	for i := range pod.Spec.Containers {
+		if runningPod.FindContainerByName(pod.Spec.Containers[i].Name) != nil {
+			kl.setPodInitialized(uuid)
+			return true
+		}
+	}
+	for i := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[i]
+		if runningContainer := runningPod.FindContainerByName(container.Name); runningContainer != nil {
+			// Wait for the running init container to complete.
+			containersToKeep[runningContainer.ID] = empty{}
+			return false
+		}
+
+		recentContainers, err := kl.runtime.GetRecentContainers(podFullName, uuid, container.Name)
+		if err != nil {
+			glog.Errorf("Error listing recent containers with name and uuid:%s--%s--%s", podFullName, uuid, container.Name)
+			return false
+		}
+		if len(recentContainers) > 0 {
+			if recentContainers[0].ExitCode == 0 {
+				continue
+			}
+			if pod.Spec.RestartPolicy.Never != nil {
+				glog.V(3).Infof("Init container with name %s--%s--%s failed, do nothing", podFullName, uuid, container.Name)
+				return false
+			}
+		}
+
+		if kl.containerInBackOff(pod, container, recentContainers) {
+			return false
+		}
+
+		glog.V(3).Infof("Init container with name %s--%s--%s hasn't completed, creating %#v", podFullName, uuid, container.Name, container)
+		ref, err := containerRef(pod, container)
+		if err != nil {
+			glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
+		}
+		if err := kl.pullImage(podFullName, container, ref); err != nil {
+			return false
+		}
+		containerID, err := kl.runContainer(pod, container, podVolumes, netMode)
+		if err != nil {
+			glog.Errorf("Error running pod %s init container %s: %v", podFullName, container.Name, err)
+			return false
+		}
+		containersToKeep[containerID] = empty{}
+		return false
+	}
+	kl.setPodInitialized(uuid)
+	return true
+}
+
+func (kl *Kubelet) isPodInitialized(uuid string) bool {
+	kl.initializedPodsLock.Lock()
+	defer kl.initializedPodsLock.Unlock()
+	return kl.initializedPods.Has(uuid)
+}
+
+func (kl *Kubelet) setPodInitialized(uuid string) {
+	kl.initializedPodsLock.Lock()
+	defer kl.initializedPodsLock.Unlock()
+	if kl.initializedPods == nil {
+		kl.initializedPods = util.NewStringSet()
+	}
+	kl.initializedPods.Insert(uuid)
+}
+
+// retainInitializedPods forgets the initialized pods which are not in desiredPods.
+func (kl *Kubelet) retainInitializedPods(desiredPods map[string]empty) {
+	kl.initializedPodsLock.Lock()
+	defer kl.initializedPodsLock.Unlock()
+	for uuid := range kl.initializedPods {
+		if _, found := desiredPods[uuid]; !found {
+			kl.initializedPods.Delete(uuid)
+		}
+	}
+}
+
+// containerBackOffKey identifies a container of a pod instance in the restart back-off.
+func containerBackOffKey(podFullName, uuid, containerName string) string {
+	return podFullName + "_" + uuid + "_" + containerName
+}
+
+// containerInBackOff returns true if restarting the container has to wait because its
+// last instance crashed within the current back-off delay. Otherwise the back-off is
+// moved one step up for the restart which is about to happen. recentContainers are the
+// instances of the container, newest first.
+func (kl *Kubelet) containerInBackOff(pod *api.BoundPod, container *api.Container, recentContainers []*kubecontainer.ContainerStatus) bool {
+	if kl.backOff == nil || len(recentContainers) == 0 {
+		return false
+	}
+	last := recentContainers[0]
+	if last.Running || last.FinishedAt.IsZero() {
+		return false
+	}
+	podFullName := GetPodFullName(pod)
+	key := containerBackOffKey(podFullName, pod.UID, container.Name)
+	if kl.backOffReset > 0 && last.FinishedAt.Sub(last.StartedAt) >= kl.backOffReset {
+		kl.backOff.Reset(key)
+	}
+	if kl.backOff.IsInBackOffSince(key, last.FinishedAt) {
+		delay := kl.backOff.Get(key)
+		glog.V(3).Infof("Back-off %v restarting failed container %s in pod %s", delay, container.Name, podFullName)
+		if ref, err := containerRef(pod, container); err == nil {
+			record.Eventf(ref, "waiting", "backOff", "Back-off %v restarting failed container", delay)
+		}
+		return true
+	}
+	kl.backOff.Next(key, last.FinishedAt)
+	return false
+}
+
 type podContainer struct {
 	podFullName   string
 	uuid          string
@@ -900,7 +1315,7 @@ func getDesiredVolumes(pods []api.BoundPod) map[string]api.Volume {
 // If an active volume does not have a respective desired volume, clean it up.
 func (kl *Kubelet) reconcileVolumes(pods []api.BoundPod) error {
 	desiredVolumes := getDesiredVolumes(pods)
-	currentVolumes := volume.GetCurrentVolumes(kl.rootDirectory)
+	currentVolumes := kl.volumePluginMgr.GetCurrentVolumes(kl.rootDirectory)
 	for name, vol := range currentVolumes {
 		if _, ok := desiredVolumes[name]; !ok {
 			//TODO (jonesdl) We should somehow differentiate between volumes that are supposed
@@ -927,15 +1342,8 @@ func (kl *Kubelet) cleanPodRelatedInfo(pods []api.BoundPod) error {
 
 	for uuid, pod := range kl.podDestroyed {
 		if _, ok := desiredPods[uuid]; !ok {
-			// Stop lxcfs process
-			if err := kl.OpLxcfs(pod.Name, "stop"); err != nil {
-				glog.Errorf("Failed to stop lxcfs for %s: %v", pod.Name, err)
-			}
-			// Reset virtual function device MAC address
-			if pod.Res.Network.Mode == sriovMode {
-				if err := kl.setupVFMacAddress(&pod.Res.Network); err != nil {
-					glog.Errorf("Failed to setup vf mac address: %v", err)
-				}
+			if err := kl.runHooks(hooks.PostStop, pod, nil, ""); err != nil {
+				glog.Errorf("Failed to clean up pod %s: %v", pod.Name, err)
 			}
 			kl.clearDestroyedPod(uuid)
 		}
@@ -944,6 +1352,35 @@ func (kl *Kubelet) cleanPodRelatedInfo(pods []api.BoundPod) error {
 	return nil
 }
 
+// killUndesiredContainers kills the running containers which are not in desiredContainers,
+// leaving the pods in desiredPods to syncPod.  It returns the last error of a kill.
+func (kl *Kubelet) killUndesiredContainers(runningPods kubecontainer.Pods, desiredPods map[string]empty, desiredContainers map[podContainer]empty) error {
+	var err error
+	for _, runningPod := range runningPods {
+		// Don't kill containers that are in the desired pods.
+		if _, found := desiredPods[runningPod.UID]; found {
+			// syncPod() will handle this one.
+			continue
+		}
+		unwanted := kubecontainer.Pod{FullName: runningPod.FullName, UID: runningPod.UID}
+		for _, container := range runningPod.Containers {
+			pc := podContainer{runningPod.FullName, runningPod.UID, container.Name}
+			if _, ok := desiredContainers[pc]; !ok {
+				glog.V(1).Infof("Killing unwanted container %+v", pc)
+				unwanted.Containers = append(unwanted.Containers, container)
+			}
+		}
+		if len(unwanted.Containers) == 0 {
+			continue
+		}
+		err = kl.killPod(unwanted)
+		if err != nil {
+			glog.Errorf("Error killing the containers of pod %s: %v", runningPod.FullName, err)
+		}
+	}
+	return err
+}
+
 // SyncPods synchronizes the configured list of pods (desired state) with the host current state.
 func (kl *Kubelet) SyncPods(pods []api.BoundPod) error {
 	glog.V(4).Infof("Desired: %#v", pods)
@@ -952,9 +1389,9 @@ func (kl *Kubelet) SyncPods(pods []api.BoundPod) error {
 	desiredContainers := make(map[podContainer]empty)
 	desiredPods := make(map[string]empty)
 
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	runningPods, err := kl.runtime.GetPods(false)
 	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
+		glog.Errorf("Error listing containers: %v", err)
 		return err
 	}
 
@@ -968,44 +1405,56 @@ func (kl *Kubelet) SyncPods(pods []api.BoundPod) error {
 
 		// Add all containers (including net) to the map.
 		desiredContainers[podContainer{podFullName, uuid, networkContainerName}] = empty{}
-		for _, cont := range pod.Spec.Containers {
+		for _, cont := range podContainers(&pod.Spec) {
 			desiredContainers[podContainer{podFullName, uuid, cont.Name}] = empty{}
 		}
 
-		// Run the sync in an async manifest worker.
-		kl.podWorkers.Run(podFullName, func() {
-			err := kl.syncPod(pod, dockerContainers)
-			if err != nil {
-				glog.Errorf("Error syncing pod, skipping: %v", err)
-			}
-		})
-	}
-
-	// Kill any containers we don't need.
-	for _, container := range dockerContainers {
-		// Don't kill containers that are in the desired pods.
-		podFullName, uuid, containerName, _ := dockertools.ParseDockerName(container.Names[0])
-		if _, found := desiredPods[uuid]; found {
-			// syncPod() will handle this one.
+		// The containers of evicted pods stay stopped until the pod is removed.
+		if kl.isEvicted(uuid) {
 			continue
 		}
-		pc := podContainer{podFullName, uuid, containerName}
-		if _, ok := desiredContainers[pc]; !ok {
-			glog.V(1).Infof("Killing unwanted container %+v", pc)
-			err = kl.killContainer(container)
-			if err != nil {
-				glog.Errorf("Error killing container %+v: %v", pc, err)
-			}
+
+		kl.syncPodInWorker(pod, runningPods.FindPod(podFullName, uuid))
+	}
+
+	// Mirror the pods from the sources of the kubelet in the apiserver.
+	kl.syncMirrorPods(pods)
+
+	// A source which has not sent its pods yet may be unavailable, the containers of its
+	// pods are unknown rather than undesired.
+	if kl.sourcesReady == nil || kl.sourcesReady() {
+		err = kl.killUndesiredContainers(runningPods, desiredPods, desiredContainers)
+	} else {
+		glog.V(1).Infof("Not all the pod sources are ready, skipping the kill of the undesired containers")
+	}
+
+	if kl.evictionManager != nil {
+		uids := util.NewStringSet()
+		for uuid := range desiredPods {
+			uids.Insert(uuid)
 		}
+		kl.evictionManager.retainEvicted(uids)
 	}
 
 	// Remove any orphaned volumes.
 	kl.reconcileVolumes(pods)
 
 	// Remove orphaned pod related information
-	// e.g : stop lxcfs process and reset vf MAC address
+	// e.g : run the pod-wide PostStop hooks
 	kl.cleanPodRelatedInfo(pods)
 
+	kl.retainInitializedPods(desiredPods)
+
+	// Stop probing the containers of removed pods.
+	if kl.prober != nil {
+		kl.prober.retain(desiredContainers)
+	}
+
+	// Forget the back-off of containers which have not crashed for a while.
+	if kl.backOff != nil {
+		kl.backOff.GC()
+	}
+
 	return err
 }
 
@@ -1057,19 +1506,29 @@ func (kl *Kubelet) syncLoop(updates <-chan PodUpdate, handler SyncHandler) {
 	for {
 		select {
 		case u := <-updates:
+			var pods []api.BoundPod
 			switch u.Op {
 			case SET:
 				glog.V(3).Infof("SET: Containers changed")
-				kl.pods = u.Pods
-				kl.pods = filterHostPortConflicts(kl.pods)
+				pods = filterHostPortConflicts(u.Pods)
 			case UPDATE:
 				glog.V(3).Infof("Update: Containers changed")
-				kl.pods = updateBoundPods(u.Pods, kl.pods)
-				kl.pods = filterHostPortConflicts(kl.pods)
+				pods = updateBoundPods(u.Pods, kl.pods)
+				pods = filterHostPortConflicts(pods)
 
 			default:
 				panic("syncLoop does not support incremental changes")
 			}
+			kl.podLock.Lock()
+			kl.pods = pods
+			kl.podLock.Unlock()
+			if err := writeCheckpoint(kl.rootDirectory, kl.pods); err != nil {
+				glog.Errorf("Failed to checkpoint the pods: %v", err)
+			}
+		case podFullName := <-kl.podSyncs:
+			glog.V(4).Infof("Containers of pod %q changed", podFullName)
+			kl.syncPodByFullName(podFullName)
+			continue
 		case <-time.After(kl.resyncInterval):
 			glog.V(4).Infof("Periodic sync")
 			if kl.pods == nil {
@@ -1077,60 +1536,187 @@ func (kl *Kubelet) syncLoop(updates <-chan PodUpdate, handler SyncHandler) {
 			}
 		}
 
+		start := time.Now()
 		err := handler.SyncPods(kl.pods)
+		metrics.SyncPodsLatency.Observe(metrics.SinceInSeconds(start))
 		if err != nil {
 			glog.Errorf("Couldn't sync containers: %v", err)
 		}
 	}
 }
 
+// podSyncsBufferSize is the number of pods waiting for a sync after their containers changed;
+// the changes seen while it is full are left to the periodic sync.
+const podSyncsBufferSize = 100
+
+// handleContainerEvent queues the sync of the pod of a container which started or died.
+func (kl *Kubelet) handleContainerEvent(event *docker.APIEvents, container *docker.APIContainers) {
+	if event.Status != "start" && event.Status != "die" {
+		return
+	}
+	if container == nil || len(container.Names) == 0 {
+		return
+	}
+	podFullName, _, _, _ := dockertools.ParseDockerName(container.Names[0])
+	if podFullName == "" {
+		return
+	}
+	select {
+	case kl.podSyncs <- podFullName:
+	default:
+		glog.V(3).Infof("Too many pods waiting for a sync, leaving pod %q to the periodic sync", podFullName)
+	}
+}
+
+// syncPodByFullName syncs the desired pod with the given full name alone. The containers of
+// the pods which are not desired are left to the periodic sync.
+func (kl *Kubelet) syncPodByFullName(podFullName string) {
+	var pod *api.BoundPod
+	for ix := range kl.pods {
+		if GetPodFullName(&kl.pods[ix]) == podFullName {
+			pod = &kl.pods[ix]
+			break
+		}
+	}
+	if pod == nil || kl.isEvicted(pod.UID) {
+		return
+	}
+	runningPods, err := kl.runtime.GetPods(false)
+	if err != nil {
+		glog.Errorf("Error listing containers: %v", err)
+		return
+	}
+	kl.syncPodInWorker(pod, runningPods.FindPod(podFullName, pod.UID))
+}
+
+// syncPodInWorker runs the sync of pod in an async manifest worker. The containers of a
+// terminating pod are stopped instead, which takes up to its termination grace period.
+func (kl *Kubelet) syncPodInWorker(pod *api.BoundPod, runningPod kubecontainer.Pod) {
+	podFullName := GetPodFullName(pod)
+	if pod.DeletionTimestamp != nil {
+		if len(runningPod.Containers) == 0 {
+			return
+		}
+		kl.podWorkers.Run(podFullName, func() {
+			glog.V(1).Infof("Stopping the containers of terminating pod %s", podFullName)
+			if err := kl.killPod(runningPod); err != nil {
+				glog.Errorf("Error stopping the containers of terminating pod %s: %v", podFullName, err)
+			}
+		})
+		return
+	}
+	kl.podWorkers.Run(podFullName, func() {
+		err := kl.syncPod(pod, runningPod)
+		if err != nil {
+			glog.Errorf("Error syncing pod, skipping: %v", err)
+		}
+	})
+}
+
 // GetKubeletContainerLogs returns logs from the container
 // The second parameter of GetPodInfo and FindPodContainer methods represents pod UUID, which is allowed to be blank
 func (kl *Kubelet) GetKubeletContainerLogs(podFullName, containerName, tail string, follow bool, stdout, stderr io.Writer) error {
-	_, err := kl.GetPodInfo(podFullName, "")
-	if err == dockertools.ErrNoContainersInPod {
-		return fmt.Errorf("pod not found (%s)\n", podFullName)
-	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, true)
+	pods, err := kl.runtime.GetPods(true)
 	if err != nil {
 		return err
 	}
-	dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, "", containerName)
-	if !found {
+	runningPod := pods.FindPod(podFullName, "")
+	if len(runningPod.Containers) == 0 {
+		return fmt.Errorf("pod not found (%s)\n", podFullName)
+	}
+	container := runningPod.FindContainerByName(containerName)
+	if container == nil {
 		return fmt.Errorf("container not found (%s)\n", containerName)
 	}
-	return dockertools.GetKubeletDockerContainerLogs(kl.dockerClient, dockerContainer.ID, tail, follow, stdout, stderr)
+	return kl.runtime.GetContainerLogs(container.ID, tail, follow, stdout, stderr)
+}
+
+// getPods returns a copy of the pods of the kubelet, which may be read outside of the sync loop.
+func (kl *Kubelet) getPods() []api.BoundPod {
+	kl.podLock.RLock()
+	defer kl.podLock.RUnlock()
+	if kl.pods == nil {
+		return nil
+	}
+	pods := make([]api.BoundPod, len(kl.pods))
+	copy(pods, kl.pods)
+	return pods
 }
 
 // GetBoundPods returns all pods bound to the kubelet and their spec
 func (kl *Kubelet) GetBoundPods() ([]api.BoundPod, error) {
-	return kl.pods, nil
+	return kl.getPods(), nil
 }
 
-// GetPodInfo returns information from Docker about the containers in a pod
+// GetPodInfo returns information from the runtime about the containers in a pod
 func (kl *Kubelet) GetPodInfo(podFullName, uuid string) (api.PodInfo, error) {
 	var manifest api.PodSpec
+	podUUID := uuid
 	for _, pod := range kl.pods {
 		if GetPodFullName(&pod) == podFullName {
 			manifest = pod.Spec
+			if podUUID == "" {
+				podUUID = pod.UID
+			}
 			break
 		}
 	}
-	return dockertools.GetDockerPodInfo(kl.dockerClient, manifest, podFullName, uuid)
+	info, err := kl.runtime.GetPodInfo(manifest, podFullName, uuid)
+	if err != nil {
+		return info, err
+	}
+	kl.setBackOffStatus(podFullName, podUUID, manifest, info)
+	kl.setProbeStatus(podFullName, podUUID, manifest, info)
+	kl.setEvictionStatus(podUUID, info)
+	return info, nil
 }
 
-func (kl *Kubelet) healthy(podFullName, podUUID string, currentState api.PodState, container api.Container, dockerContainer *docker.APIContainers) (health.Status, error) {
-	// Give the container 60 seconds to start up.
-	if container.LivenessProbe == nil {
-		return health.Healthy, nil
+// setProbeStatus reports the latest probe results of the running containers, and marks
+// those which have no readiness probe, or whose readiness probe passes, as ready.
+func (kl *Kubelet) setProbeStatus(podFullName, uuid string, manifest api.PodSpec, info api.PodInfo) {
+	for _, container := range manifest.Containers {
+		status, found := info[container.Name]
+		if !found || status.State.Running == nil {
+			continue
+		}
+		if container.ReadinessProbe == nil {
+			status.Ready = true
+		} else if kl.readiness != nil {
+			status.Ready = kl.readiness.IsReady(podFullName, uuid, container.Name)
+		}
+		if kl.prober != nil {
+			status.Liveness, status.Readiness = kl.prober.results(podFullName, uuid, container.Name)
+		}
+		info[container.Name] = status
 	}
-	if time.Now().Unix()-dockerContainer.Created < container.LivenessProbe.InitialDelaySeconds {
-		return health.Healthy, nil
+}
+
+// setBackOffStatus reports the terminated containers which wait in the restart back-off
+// as waiting with reason CrashLoopBackOff.
+func (kl *Kubelet) setBackOffStatus(podFullName, uuid string, manifest api.PodSpec, info api.PodInfo) {
+	if kl.backOff == nil {
+		return
 	}
-	if kl.healthChecker == nil {
-		return health.Healthy, nil
+	for _, container := range podContainers(&manifest) {
+		status, found := info[container.Name]
+		if !found || status.State.Termination == nil {
+			continue
+		}
+		key := containerBackOffKey(podFullName, uuid, container.Name)
+		finishedAt := status.State.Termination.FinishedAt
+		if !kl.backOff.IsInBackOffSince(key, finishedAt) {
+			continue
+		}
+		delay := kl.backOff.Get(key)
+		status.State = api.ContainerState{
+			Waiting: &api.ContainerStateWaiting{
+				Reason:      crashLoopBackOffReason,
+				Message:     fmt.Sprintf("Back-off %v restarting failed container, last exit code %d", delay, status.State.Termination.ExitCode),
+				NextRetryAt: finishedAt.Add(delay),
+			},
+		}
+		info[container.Name] = status
 	}
-	return kl.healthChecker.HealthCheck(podFullName, podUUID, currentState, container)
 }
 
 // Returns logs of current machine.
@@ -1141,22 +1727,96 @@ func (kl *Kubelet) ServeLogs(w http.ResponseWriter, req *http.Request) {
 
 // Run a command in a container, returns the combined stdout, stderr as an array of bytes
 func (kl *Kubelet) RunInContainer(podFullName, uuid, container string, cmd []string) ([]byte, error) {
-	if kl.runner == nil {
-		return nil, fmt.Errorf("no runner specified.")
+	return kl.RunInContainerWithTimeout(podFullName, uuid, container, cmd, 0)
+}
+
+// RunInContainerWithTimeout is like RunInContainer, but gives up on the command once it has
+// run for timeout.  A zero timeout means no timeout.
+func (kl *Kubelet) RunInContainerWithTimeout(podFullName, uuid, container string, cmd []string, timeout time.Duration) ([]byte, error) {
+	pods, err := kl.runtime.GetPods(false)
+	if err != nil {
+		return nil, err
+	}
+	runningPod := pods.FindPod(podFullName, uuid)
+	runningContainer := runningPod.FindContainerByName(container)
+	if runningContainer == nil {
+		return nil, fmt.Errorf("container not found (%s)", container)
+	}
+	return kl.runtime.RunInContainer(runningContainer.ID, cmd, timeout)
+}
+
+// findRunningContainer returns the running container of a container of a pod. The container
+// may be omitted if the pod has a single container.
+func (kl *Kubelet) findRunningContainer(podFullName, uuid, container string) (*kubecontainer.Container, error) {
+	if container == "" {
+		pods := kl.getPods()
+		for i := range pods {
+			pod := &pods[i]
+			if GetPodFullName(pod) != podFullName || (uuid != "" && pod.UID != uuid) {
+				continue
+			}
+			if len(pod.Spec.Containers) != 1 {
+				return nil, fmt.Errorf("a container name is required for pod %s, choose one of %v", podFullName, containerNames(pod))
+			}
+			container = pod.Spec.Containers[0].Name
+			break
+		}
+		if container == "" {
+			return nil, fmt.Errorf("pod not found (%s)", podFullName)
+		}
 	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.runtime.GetPods(false)
 	if err != nil {
 		return nil, err
 	}
-	dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uuid, container)
-	if !found {
+	runningPod := pods.FindPod(podFullName, uuid)
+	runningContainer := runningPod.FindContainerByName(container)
+	if runningContainer == nil {
 		return nil, fmt.Errorf("container not found (%s)", container)
 	}
-	return kl.runner.RunInContainer(dockerContainer.ID, cmd)
+	return runningContainer, nil
+}
+
+func containerNames(pod *api.BoundPod) []string {
+	names := []string{}
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	return names
+}
+
+// ExecInContainer runs a command in a container with the given streams, any of which may be nil,
+// until it exits.
+func (kl *Kubelet) ExecInContainer(podFullName, uuid, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	runningContainer, err := kl.findRunningContainer(podFullName, uuid, container)
+	if err != nil {
+		return err
+	}
+	return kl.runtime.ExecInContainer(runningContainer.ID, cmd, stdin, stdout, stderr, tty, resize)
+}
+
+// AttachContainer attaches the given streams, any of which may be nil, to the main process of a
+// container until it exits.
+func (kl *Kubelet) AttachContainer(podFullName, uuid, container string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	runningContainer, err := kl.findRunningContainer(podFullName, uuid, container)
+	if err != nil {
+		return err
+	}
+	return kl.runtime.AttachContainer(runningContainer.ID, stdin, stdout, stderr, tty, resize)
+}
+
+// PortForward copies the data of stream to and from a TCP connection to port in the network
+// namespace of a pod, which is the one of its network container, until either side closes.
+func (kl *Kubelet) PortForward(podFullName, uuid string, port uint16, stream io.ReadWriter) error {
+	pods, err := kl.runtime.GetPods(false)
+	if err != nil {
+		return err
+	}
+	return kl.runtime.PortForward(pods.FindPod(podFullName, uuid), port, stream)
 }
 
 //setup network for net container
-func (kl *Kubelet) setupNetwork(id dockertools.DockerID, pod *api.BoundPod) (string, error) {
+func (kl *Kubelet) setupNetwork(id string, pod *api.BoundPod) (string, error) {
 	var out bytes.Buffer
 
 	network := pod.Res.Network
@@ -1184,10 +1844,10 @@ func (kl *Kubelet) setupNetwork(id dockertools.DockerID, pod *api.BoundPod) (str
 				break
 			}
 		}
-		execCmd = append([]string{defaultDevice, "--vf", network.VfID, string(id), ipAndGw, fmt.Sprintf("%s@%d", network.MacAddress, vlanID)})
+		execCmd = append([]string{defaultDevice, "--vf", network.VfID, id, ipAndGw, fmt.Sprintf("%s@%d", network.MacAddress, vlanID)})
 		break
 	case bridgeMode:
-		execCmd = append([]string{network.Bridge, string(id), ipAndGw, network.MacAddress})
+		execCmd = append([]string{network.Bridge, id, ipAndGw, network.MacAddress})
 		break
 	default:
 		return "", fmt.Errorf("Network mode does not support %s", network.Mode)
@@ -1210,13 +1870,13 @@ func (kl *Kubelet) setupSriov(containerID string, pod *api.BoundPod) error {
 	var out bytes.Buffer
 	network := pod.Res.Network
 
-	data, err := kl.dockerClient.InspectContainer(containerID)
+	status, err := kl.runtime.GetContainerStatus(containerID)
 	if err != nil {
 		return err
 	}
 
 	// Get CpuSet from Inspect Info
-	parts := strings.Split(data.Config.CpuSet, ",")
+	parts := strings.Split(status.CpuSet, ",")
 	var irqArray []string
 	for _, core := range parts {
 		irqCpu, err := util.HexCpuSet(core)
@@ -1225,7 +1885,7 @@ func (kl *Kubelet) setupSriov(containerID string, pod *api.BoundPod) error {
 		}
 		irqArray = append(irqArray, irqCpu)
 	}
-	rpsCpus, err := util.HexCpuSet(data.Config.CpuSet)
+	rpsCpus, err := util.HexCpuSet(status.CpuSet)
 	if err != nil {
 		return err
 	}
@@ -1278,22 +1938,26 @@ func (kl *Kubelet) setupVFMacAddress(network *api.Network) error {
 // BirthCry sends an event that the kubelet has started up.
 func (kl *Kubelet) BirthCry() {
 	// Make an event that kubelet restarted.
+	record.Eventf(kl.nodeRef(), "", "starting", "Starting kubelet.")
+}
+
+// nodeRef returns the reference of the node of the kubelet for its events.
+func (kl *Kubelet) nodeRef() *api.ObjectReference {
 	// TODO: get the real minion object of ourself,
 	// and use the real minion name and UID.
-	ref := &api.ObjectReference{
+	return &api.ObjectReference{
 		Kind:      "Minion",
 		Name:      kl.hostname,
 		UID:       kl.hostname,
 		Namespace: api.NamespaceDefault,
 	}
-	record.Eventf(ref, "", "starting", "Starting kubelet.")
 }
 
-func (kl *Kubelet) syncPodHostNetwork(pod *api.BoundPod, dockerContainers dockertools.DockerContainers) error {
+func (kl *Kubelet) syncPodHostNetwork(pod *api.BoundPod, runningPod kubecontainer.Pod) error {
 	podFullName := GetPodFullName(pod)
 	uuid := pod.UID
-	containersToKeep := make(map[dockertools.DockerID]empty)
-	killedContainers := make(map[dockertools.DockerID]empty)
+	containersToKeep := make(map[string]empty)
+	killedContainers := make(map[string]empty)
 
 	podVolumes, err := kl.mountExternalVolumes(pod)
 	if err != nil {
@@ -1302,40 +1966,44 @@ func (kl *Kubelet) syncPodHostNetwork(pod *api.BoundPod, dockerContainers docker
 	}
 
 	podState := api.PodState{}
-	for _, container := range pod.Spec.Containers {
-		expectedHash := dockertools.HashContainer(&container)
-		if dockerContainer, found, hash := dockerContainers.FindPodContainer(podFullName, uuid, container.Name); found {
-			containerID := dockertools.DockerID(dockerContainer.ID)
+	// The containers are started once all the init containers have completed.
+	containers := pod.Spec.Containers
+	if !kl.syncInitContainers(pod, runningPod, podVolumes, "host", containersToKeep) {
+		containers = nil
+	}
+	for _, container := range containers {
+		expectedHash := kubecontainer.HashContainer(&container)
+		if runningContainer := runningPod.FindContainerByName(container.Name); runningContainer != nil {
+			containerID := runningContainer.ID
+			hash := runningContainer.Hash
 			glog.V(3).Infof("pod %s container %s exists as %v", podFullName, container.Name, containerID)
 
 			// look for changes in the container.
 			if hash == 0 || hash == expectedHash {
-				// TODO: This should probably be separated out into a separate goroutine.
-				healthy, err := kl.healthy(podFullName, uuid, podState, container, dockerContainer)
-				if err != nil {
-					glog.V(1).Infof("health check errored: %v", err)
+				if kl.prober == nil {
 					containersToKeep[containerID] = empty{}
 					continue
 				}
-				if healthy == health.Healthy {
+				kl.prober.start(podFullName, uuid, podState, container, runningContainer)
+				if kl.prober.isLive(podFullName, uuid, container.Name) {
 					containersToKeep[containerID] = empty{}
 					continue
 				}
-				glog.V(1).Infof("pod %s container %s is unhealthy.", podFullName, container.Name, healthy)
+				glog.V(1).Infof("pod %s container %s is unhealthy.", podFullName, container.Name)
 			} else {
 				glog.V(3).Infof("container hash changed %d vs %d.", hash, expectedHash)
 			}
 
 			// unhealthy or changed, kill it
-			if err := kl.killContainer(dockerContainer); err != nil {
-				glog.V(1).Infof("Failed to kill container %s: %v", dockerContainer.ID, err)
+			if err := kl.killContainer(runningPod, runningContainer); err != nil {
+				glog.V(1).Infof("Failed to kill container %s: %v", containerID, err)
 				continue
 			}
 			killedContainers[containerID] = empty{}
 		}
 
 		// Check RestartPolicy for container
-		recentContainers, err := dockertools.GetRecentDockerContainersWithNameAndUUID(kl.dockerClient, podFullName, uuid, container.Name)
+		recentContainers, err := kl.runtime.GetRecentContainers(podFullName, uuid, container.Name)
 		if err != nil {
 			glog.Errorf("Error listing recent containers with name and uuid:%s--%s--%s", podFullName, uuid, container.Name)
 			// TODO(dawnchen): error handling here?
@@ -1349,7 +2017,7 @@ func (kl *Kubelet) syncPodHostNetwork(pod *api.BoundPod, dockerContainers docker
 			}
 			if pod.Spec.RestartPolicy.OnFailure != nil {
 				// Check the exit code of last run
-				if recentContainers[0].State.ExitCode == 0 {
+				if recentContainers[0].ExitCode == 0 {
 					glog.V(3).Infof("Already successfully ran container with name %s--%s--%s, do nothing",
 						podFullName, uuid, container.Name)
 					continue
@@ -1357,37 +2025,18 @@ func (kl *Kubelet) syncPodHostNetwork(pod *api.BoundPod, dockerContainers docker
 			}
 		}
 
+		if kl.containerInBackOff(pod, &container, recentContainers) {
+			continue
+		}
+
 		glog.V(3).Infof("Container with name %s--%s--%s doesn't exist, creating %#v", podFullName, uuid, container.Name, container)
 		ref, err := containerRef(pod, &container)
 		if err != nil {
 			glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
 		}
-		if !api.IsPullNever(container.ImagePullPolicy) && !kl.checkLocalImage(container.Image) {
-			present, err := kl.dockerPuller.IsImagePresent(container.Image)
-			latest := dockertools.RequireLatestImage(container.Image)
-			if err != nil {
-				if ref != nil {
-					record.Eventf(ref, "failed", "failed", "Failed to inspect image %s", container.Image)
-				}
-				glog.Errorf("Failed to inspect image %s: %v; skipping pod %s container %s", container.Image, err, podFullName, container.Name)
-				continue
-			}
-			if api.IsPullAlways(container.ImagePullPolicy) ||
-				(api.IsPullIfNotPresent(container.ImagePullPolicy) && (!present || latest)) {
-				if err := kl.dockerPuller.Pull(container.Image); err != nil {
-					if ref != nil {
-
-						record.Eventf(ref, "failed", "failed", "Failed to pull image %s", container.Image)
-					}
-					glog.Errorf("Failed to pull image %s: %v; skipping pod %s container %s.", container.Image, err, podFullName, container.Name)
-					continue
-				}
-				if ref != nil {
-					record.Eventf(ref, "waiting", "pulled", "Successfully pulled image %s", container.Image)
-				}
-			}
+		if err := kl.pullImage(podFullName, &container, ref); err != nil {
+			continue
 		}
-		// TODO(dawnchen): Check RestartPolicy.DelaySeconds before restart a container
 		containerID, err := kl.runContainer(pod, &container, podVolumes, "host")
 		if err != nil {
 			// TODO(bburns) : Perhaps blacklist a container after N failures?
@@ -1398,18 +2047,15 @@ func (kl *Kubelet) syncPodHostNetwork(pod *api.BoundPod, dockerContainers docker
 	}
 
 	// Kill any containers in this pod which were not identified above (guards against duplicates).
-	for id, container := range dockerContainers {
-		curPodFullName, curUUID, _, _ := dockertools.ParseDockerName(container.Names[0])
-		if curPodFullName == podFullName && curUUID == uuid {
-			// Don't kill containers we want to keep or those we already killed.
-			_, keep := containersToKeep[id]
-			_, killed := killedContainers[id]
-			if !keep && !killed {
-				glog.V(1).Infof("Killing unwanted container in pod %q: %+v", curUUID, container)
-				err = kl.killContainer(container)
-				if err != nil {
-					glog.Errorf("Error killing container: %v", err)
-				}
+	for _, container := range runningPod.Containers {
+		// Don't kill containers we want to keep or those we already killed.
+		_, keep := containersToKeep[container.ID]
+		_, killed := killedContainers[container.ID]
+		if !keep && !killed {
+			glog.V(1).Infof("Killing unwanted container in pod %q: %+v", uuid, container)
+			err = kl.killContainer(runningPod, container)
+			if err != nil {
+				glog.Errorf("Error killing container: %v", err)
 			}
 		}
 	}
@@ -1419,18 +2065,19 @@ func (kl *Kubelet) syncPodHostNetwork(pod *api.BoundPod, dockerContainers docker
 
 func (kl *Kubelet) opPodStartContainer(pod *api.BoundPod) error {
 	// Get running container list
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.runtime.GetPods(false)
 	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
+		glog.Errorf("Error listing containers: %v", err)
 		return err
 	}
 
 	podFullName := GetPodFullName(pod)
 	uuid := pod.UID
-	var netID dockertools.DockerID
+	runningPod := pods.FindPod(podFullName, uuid)
+	var netID string
 
-	if netDockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uuid, networkContainerName); found {
-		netID = dockertools.DockerID(netDockerContainer.ID)
+	if netContainer := runningPod.FindContainerByName(networkContainerName); netContainer != nil {
+		netID = netContainer.ID
 	} else {
 		netID, err = kl.createNetworkContainer(pod)
 		if err != nil {
@@ -1441,6 +2088,7 @@ func (kl *Kubelet) opPodStartContainer(pod *api.BoundPod) error {
 			var errMsg string
 			errMsg, err = kl.setupNetwork(netID, pod)
 			if err != nil {
+				metrics.NetworkSetupFailures.Inc(pod.Res.Network.Mode)
 				glog.Errorf("Failed to setup network for network container: %v; error msg: %s; Skipping pod %s", err, errMsg, podFullName)
 				return err
 			}
@@ -1449,82 +2097,54 @@ func (kl *Kubelet) opPodStartContainer(pod *api.BoundPod) error {
 		}
 	}
 
-	glog.V(3).Infof("Network container ID is: %s", string(netID))
+	glog.V(3).Infof("Network container ID is: %s", netID)
 
 	for _, container := range pod.Spec.Containers {
-		if _, found, _ := dockerContainers.FindPodContainer(podFullName, uuid, container.Name); found {
+		if runningPod.FindContainerByName(container.Name) != nil {
 			glog.V(3).Infof("Container %s.%s is running, skiped.", podFullName, container.Name)
 			continue
 		}
 
-		deadContainers, err := dockertools.GetRecentDockerContainersWithNameAndUUID(kl.dockerClient, podFullName, uuid, container.Name)
-		sort.Sort(ByCreated(deadContainers))
+		deadContainers, err := kl.runtime.GetRecentContainers(podFullName, uuid, container.Name)
+		if err != nil {
+			glog.Errorf("Error listing recent containers with name and uuid:%s--%s--%s", podFullName, uuid, container.Name)
+			return err
+		}
+		if len(deadContainers) == 0 {
+			return fmt.Errorf("no container %s to start in pod %s", container.Name, podFullName)
+		}
 		latestContainer := deadContainers[0]
 
-		// start lxcf befor create container
-		if err = kl.OpLxcfs(container.Name, "start"); err != nil {
-			glog.Errorf("Failed to start lxcfs for %s: %v", container.Name, err)
+		if err = kl.runHooks(hooks.PreCreate, pod, &container, ""); err != nil {
+			glog.Errorf("Failed to prepare pod %s container %s: %v", podFullName, container.Name, err)
 			return err
 		}
 
-		err = kl.dockerClient.StartContainer(latestContainer.ID, &docker.HostConfig{
-			PortBindings: latestContainer.HostConfig.PortBindings,
-			Binds:        latestContainer.HostConfig.Binds,
-			NetworkMode:  "container:" + string(netID),
-			Privileged:   latestContainer.HostConfig.Privileged,
-			CapAdd:       latestContainer.HostConfig.CapAdd,
-			CapDrop:      latestContainer.HostConfig.CapDrop,
-		})
+		err = kl.runtime.StartContainer(latestContainer.ID, "container:"+netID)
 		if err != nil {
 			glog.Errorf("Start container %s.%s  %s error: %v", podFullName, container.Name, latestContainer.ID, err)
 			return err
 		}
-		// set disk quota
-		if err := kl.addDiskQuota(latestContainer.ID, container.Name, container.Disk); err != nil {
-			glog.Errorf("Failed to set up disk quota %v", err)
+		if err := kl.runHooks(hooks.PostStart, pod, &container, latestContainer.ID); err != nil {
+			glog.Errorf("Failed to set up pod %s container %s: %v", podFullName, container.Name, err)
 			return err
 		}
-		// Set sriov if network mode eq "sriov"
-		if pod.Res.Network.Mode == sriovMode {
-			if err := kl.setupSriov(latestContainer.ID, pod); err != nil {
-				glog.Errorf("Failed to Set up sriov: %v", err)
-				return err
-			}
-		}
-		// set blkio
-		if container.Blkio != nil {
-			blkio := &BlkioGroup{}
-			if err := blkio.SetUp(latestContainer.ID, container.Blkio); err != nil {
-				glog.Errorf("Failed to set up blkio %v", err)
-				return err
-			}
-		}
 	}
 	return nil
 }
 
 func (kl *Kubelet) opPodStopContainer(pod *api.BoundPod) error {
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.runtime.GetPods(false)
 	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
+		glog.Errorf("Error listing containers: %v", err)
 		return err
 	}
-	podFullName := GetPodFullName(pod)
-	uuid := pod.UID
-	count, err := kl.killContainersInPod(pod, dockerContainers)
-	if err != nil {
+	runningPod := pods.FindPod(GetPodFullName(pod), pod.UID)
+	if err := kl.killPod(runningPod); err != nil {
 		glog.Errorf("Error stop containers in pod: %s", pod.Name)
 		return err
 	}
-	glog.V(3).Infof("Stop %d containers in pod: %s", count, pod.Name)
-
-	if netContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uuid, networkContainerName); found {
-		if err := kl.killContainer(netContainer); err != nil {
-			glog.V(1).Infof("Failed to stop network container %s: %v", podFullName, err)
-			return err
-		}
-	}
-
+	glog.V(3).Infof("Stop %d containers in pod: %s", len(runningPod.Containers), pod.Name)
 	return nil
 }
 
@@ -1565,62 +2185,55 @@ func (kl *Kubelet) OpPod(podFullName, op string) error {
 // PushImage push image to local hub
 func (kl *Kubelet) PushImage(params *PushImageParams) error {
 	var (
-		pod         *api.BoundPod
-		containerID string
-		options     *docker.ChangeOptions
-		err         error
+		pod                *api.BoundPod
+		containerID        string
+		includes, excludes []string
 	)
 	// check image if exists
-	_, err = kl.dockerClient.InspectImage(params.Image)
-	if err == nil {
-		return fmt.Errorf("Image: %s already exists, can't push again", params.Image)
-	}
-	if err.Error() != "no such image" {
+	id, err := kl.runtime.GetImageID(params.Image)
+	if err != nil {
 		return fmt.Errorf("Failed to inspect image: %s", params.Image)
 	}
+	if id != "" {
+		return fmt.Errorf("Image: %s already exists, can't push again", params.Image)
+	}
 
 	// get change options
 	if params.PathType != "" {
 		switch params.PathType {
 		case "exclude":
-			options = &docker.ChangeOptions{
-				Excludes: params.PathContent,
-			}
+			excludes = params.PathContent
 		case "include":
-			options = &docker.ChangeOptions{
-				Includes: params.PathContent,
-			}
+			includes = params.PathContent
 		default:
 			return fmt.Errorf("pathType must be 'exclude' or 'include'")
 		}
 	}
 
 	// get container id
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.runtime.GetPods(false)
 	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
+		glog.Errorf("Error listing containers: %v", err)
 		return err
 	}
-	podFullName := GetPodFullName(&api.BoundPod{
-		ObjectMeta: api.ObjectMeta{
-			Name:        params.PodID,
-			Namespace:   params.PodNamespace,
-			Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-		},
-	})
 	for i, size := 0, len(kl.pods); i < size; i++ {
 		p := &kl.pods[i]
-		if GetPodFullName(p) == podFullName {
+		if p.Name == params.PodID && p.Namespace == params.PodNamespace {
 			pod = p
 			break
 		}
 	}
+	if pod == nil {
+		return fmt.Errorf("pod %s/%s not found", params.PodNamespace, params.PodID)
+	}
+	podFullName := GetPodFullName(pod)
+	runningPod := pods.FindPod(podFullName, pod.UID)
 	for _, container := range pod.Spec.Containers {
-		if dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, pod.UID, container.Name); found {
-			containerID = dockerContainer.ID
+		if runningContainer := runningPod.FindContainerByName(container.Name); runningContainer != nil {
+			containerID = runningContainer.ID
 			break
 		}
-		commitContainers, err := dockertools.GetRecentDockerContainersWithNameAndUUID(kl.dockerClient, podFullName, pod.UID, container.Name)
+		commitContainers, err := kl.runtime.GetRecentContainers(podFullName, pod.UID, container.Name)
 		if err != nil {
 			glog.Errorf("Error listing recent containers with name and uuid:%s--%s--%s", podFullName, pod.UID, container.Name)
 			return err
@@ -1635,43 +2248,17 @@ func (kl *Kubelet) PushImage(params *PushImageParams) error {
 		}
 	}
 
-	glog.V(3).Infof("Commit containerID: %s; change options: %v", containerID, options)
-	// data.image e.g. hub.oa.com/library/tlinux1.2:latest
-	// after parse,result is:
-	// regi:	hub.oa.com
-	// repo:	hub.oa.com/library/tlinux1.2
-	// tag:		latest
-	regi, repo, tag := dockertools.ParseImageName(params.Image)
-
-	// commit container
-	containerOpts := docker.CommitContainerOptions{
-		Container:  containerID,
-		Repository: repo,
-		Tag:        tag,
-		Author:     params.Author,
-		Message:    "push custom image",
-		Options:    options,
-	}
-	_, err = kl.dockerClient.CommitContainer(containerOpts)
+	glog.V(3).Infof("Commit containerID: %s; includes: %v; excludes: %v", containerID, includes, excludes)
+	err = kl.runtime.CommitContainer(containerID, params.Image, params.Author, includes, excludes)
 	if err != nil {
 		glog.Errorf("Failed to commit container: %s, error: %v", containerID, err)
 		return err
 	}
 	glog.V(3).Info("Commit successfully")
 
-	// push image
-	imageOpts := docker.PushImageOptions{
-		Name:     repo,
-		Tag:      tag,
-		Registry: regi,
-	}
-	creds, ok := kl.keyring.Lookup(repo)
-	if !ok {
-		glog.V(1).Infof("Push image: %s without credentials", repo)
-	}
-	err = kl.dockerClient.PushImage(imageOpts, creds)
+	err = kl.runtime.PushImage(params.Image)
 	if err != nil {
-		glog.Errorf("Failed to push image: %s, error: %v", repo, err)
+		glog.Errorf("Failed to push image: %s, error: %v", params.Image, err)
 		return err
 	}
 	glog.V(3).Info("Push successfully")
@@ -1686,13 +2273,13 @@ func (kl *Kubelet) addDiskQuota(ID, name string, disk int) error {
 		return nil
 	}
 
-	data, err := kl.dockerClient.InspectContainer(ID)
+	status, err := kl.runtime.GetContainerStatus(ID)
 	if err != nil {
 		return err
 	}
-	pid := data.State.Pid % 0xFFFF
+	pid := status.Pid % 0xFFFF
 
-	glog.V(3).Infof("Handle for addDiskQuota:Pid=>%d(c32:%d) ID=>%s Name=>%s Disk=>%d", data.State.Pid, pid, ID, name, disk)
+	glog.V(3).Infof("Handle for addDiskQuota:Pid=>%d(c32:%d) ID=>%s Name=>%s Disk=>%d", status.Pid, pid, ID, name, disk)
 
 	// set /etc/projects file
 	err = kl.refreshProjfile("/etc/projects", fmt.Sprintf("%d:%s%s", pid, "/data/docker-volumes/", name), name)
@@ -1723,13 +2310,13 @@ func (kl *Kubelet) addDiskQuota(ID, name string, disk int) error {
 
 // removeDiskQuota clean up disk queta on pod
 func (kl *Kubelet) removeDiskQuota(ID, name string) error {
-	data, err := kl.dockerClient.InspectContainer(ID)
+	status, err := kl.runtime.GetContainerStatus(ID)
 	if err != nil {
 		return err
 	}
-	pid := data.State.Pid % 0xFFFF
+	pid := status.Pid % 0xFFFF
 
-	glog.V(3).Infof("Handle for removeDiskQuota:Pid=>%d(c32:%d) ID=>%s Name=>%s", data.State.Pid, pid, ID, name)
+	glog.V(3).Infof("Handle for removeDiskQuota:Pid=>%d(c32:%d) ID=>%s Name=>%s", status.Pid, pid, ID, name)
 
 	cmd := exec.Command("xfs_quota", "-x", "-c", fmt.Sprintf("project -C %s", name), "/data")
 	stderr := bytes.NewBuffer(nil)
@@ -1821,7 +2408,7 @@ func (kl *Kubelet) UpdatePodCgroup(podFullName string, podConfig *PodConfig) err
 	var (
 		err            error
 		pod            *api.BoundPod
-		writeSubsystem []docker.KeyValuePair
+		writeSubsystem []kubecontainer.KeyValue
 	)
 
 	for i, size := 0, len(kl.pods); i < size; i++ {
@@ -1835,33 +2422,30 @@ func (kl *Kubelet) UpdatePodCgroup(podFullName string, podConfig *PodConfig) err
 		glog.Errorf("Can't find pod: %s", podFullName)
 		return dockertools.ErrNoContainersInPod
 	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.runtime.GetPods(false)
 	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
+		glog.Errorf("Error listing containers: %v", err)
 		return err
 	}
+	runningPod := pods.FindPod(podFullName, pod.UID)
 
 	isUpdateCpu := false
 	for _, entry := range podConfig.WriteSubsystem {
-		writeSubsystem = append(writeSubsystem, docker.KeyValuePair{Key: entry.Key, Value: entry.Value})
+		writeSubsystem = append(writeSubsystem, kubecontainer.KeyValue{Key: entry.Key, Value: entry.Value})
 		if strings.Contains(entry.Key, "cpuset") {
 			isUpdateCpu = true
 		}
 	}
 
 	for _, container := range pod.Spec.Containers {
-		if dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, pod.UID, container.Name); found {
-			resp, err := kl.dockerClient.UpdateContainerCgroup(dockerContainer.ID, writeSubsystem)
-
-			glog.V(3).Infof("Update container(%s - %s) cgroup: %+v\n\t result:%+v", container.Name, dockerContainer.ID, writeSubsystem, resp)
-
-			if err != nil {
-				glog.Errorf("Update cgroup on container %s.%s  %s error: %v", podFullName, container.Name, dockerContainer.ID, err)
+		if runningContainer := runningPod.FindContainerByName(container.Name); runningContainer != nil {
+			if err := kl.runtime.UpdateContainerResources(runningContainer.ID, writeSubsystem); err != nil {
+				glog.Errorf("Update cgroup on container %s.%s  %s error: %v", podFullName, container.Name, runningContainer.ID, err)
 				return err
 			}
 			// When change pod cpu and network mode eq "sriov",Should be setup pod sriov
 			if isUpdateCpu && pod.Res.Network.Mode == sriovMode {
-				if err := kl.setupSriov(dockerContainer.ID, pod); err != nil {
+				if err := kl.setupSriov(runningContainer.ID, pod); err != nil {
 					glog.Errorf("Failed to Set up sriov: %v", err)
 					return err
 				}
@@ -1891,9 +2475,8 @@ func (kl *Kubelet) UpdatePodDisk(podFullName string, podConfig *PodConfig) error
 		glog.Errorf("Can't find pod: %s", podFullName)
 		return dockertools.ErrNoContainersInPod
 	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
-	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
+	if _, err = kl.runtime.GetPods(false); err != nil {
+		glog.Errorf("Error listing containers: %v", err)
 		return err
 	}
 
@@ -1927,7 +2510,7 @@ func (kl *Kubelet) UpdatePodConfig(podFullName string, attribute []KVPair) error
 	var (
 		err    error
 		pod    *api.BoundPod
-		config []docker.KeyValuePair
+		config []kubecontainer.KeyValue
 	)
 
 	for i, size := 0, len(kl.pods); i < size; i++ {
@@ -1941,20 +2524,21 @@ func (kl *Kubelet) UpdatePodConfig(podFullName string, attribute []KVPair) error
 		glog.Errorf("Can't find pod: %s", podFullName)
 		return dockertools.ErrNoContainersInPod
 	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.runtime.GetPods(false)
 	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
+		glog.Errorf("Error listing containers: %v", err)
 		return err
 	}
+	runningPod := pods.FindPod(podFullName, pod.UID)
 
 	for _, attr := range attribute {
-		config = append(config, docker.KeyValuePair{Key: attr.Key, Value: attr.Value})
+		config = append(config, kubecontainer.KeyValue{Key: attr.Key, Value: attr.Value})
 	}
 	glog.V(3).Infof("Update container config: %v ", config)
 
 	for _, container := range pod.Spec.Containers {
-		if dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, pod.UID, container.Name); found {
-			if err = kl.dockerClient.UpdateContainerConfig(dockerContainer.ID, config); err != nil {
+		if runningContainer := runningPod.FindContainerByName(container.Name); runningContainer != nil {
+			if err = kl.runtime.UpdateContainerConfig(runningContainer.ID, config); err != nil {
 				return err
 			}
 		}
@@ -1963,46 +2547,6 @@ func (kl *Kubelet) UpdatePodConfig(podFullName string, attribute []KVPair) error
 	return nil
 }
 
-// Get pod stats and spec info
-// TODO(hbo)
-func (kl *Kubelet) GetPodStats(podFullName string) (*info.ContainerInfo, error) {
-	var (
-		err   error
-		pod   *api.BoundPod
-		cinfo *info.ContainerInfo
-	)
-
-	for i, size := 0, len(kl.pods); i < size; i++ {
-		p := &kl.pods[i]
-		if GetPodFullName(p) == podFullName {
-			pod = p
-			break
-		}
-	}
-	if pod == nil {
-		glog.Errorf("Can't find pod: %s", podFullName)
-		return nil, dockertools.ErrNoContainersInPod
-	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
-	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
-		return nil, err
-	}
-
-	for _, container := range pod.Spec.Containers {
-		if dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, pod.UID, container.Name); found {
-			cinfo, err = docker.GetContainerInfo(dockerContainer.ID)
-			if err != nil {
-				glog.Errorf("Get container %s stats error: %v", dockerContainer.ID, err)
-				return nil, err
-			}
-		}
-	}
-	cinfo.ContainerReference.Name = podFullName
-
-	return cinfo, nil
-}
-
 func (kl *Kubelet) MergeContainer(podFullName, image, op string) error {
 	var (
 		err error
@@ -2020,41 +2564,29 @@ func (kl *Kubelet) MergeContainer(podFullName, image, op string) error {
 		glog.Errorf("Can't find pod: %s", podFullName)
 		return dockertools.ErrNoContainersInPod
 	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.runtime.GetPods(false)
 	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
+		glog.Errorf("Error listing containers: %v", err)
 		return err
 	}
+	runningPod := pods.FindPod(podFullName, pod.UID)
 
-	_, repo, tag := dockertools.ParseImageName(image)
+	_, _, tag := dockertools.ParseImageName(image)
 	if tag == "" {
 		return fmt.Errorf("Missing tag: %s", image)
 	}
 
 	for _, container := range pod.Spec.Containers {
-		if dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, pod.UID, container.Name); found {
-			img, err := kl.dockerClient.InspectImage(dockerContainer.Image)
-			if err != nil {
-				return fmt.Errorf("Failed to inspect image: %s", dockerContainer.Image)
-			}
-			imageOpts := docker.MergeImageOptions{
-				Container:    dockerContainer.ID,
-				CurrentImage: img.ID,
-				Repository:   image,
-			}
+		if runningContainer := runningPod.FindContainerByName(container.Name); runningContainer != nil {
 			if op == "pull" {
-				creds, ok := kl.keyring.Lookup(repo)
-				if !ok {
-					glog.V(1).Infof("Pull image: %s without credentials", repo)
-				}
-				if err = kl.dockerClient.PullImageAndApply(imageOpts, creds); err != nil {
+				if err = kl.runtime.MergeImage(runningContainer.ID, image, true); err != nil {
 					return err
 				}
 			} else if op == "diff" {
-				if err = kl.dockerPuller.Pull(image); err != nil {
+				if err = kl.runtime.PullImage(image); err != nil {
 					return err
 				}
-				if err = kl.dockerClient.DiffImageAndApply(imageOpts); err != nil {
+				if err = kl.runtime.MergeImage(runningContainer.ID, image, false); err != nil {
 					return err
 				}
 			} else {
@@ -2069,7 +2601,7 @@ func (kl *Kubelet) DockerPodCgroup(podFullName string, cgroups []CgroupData) ([]
 	var (
 		err    error
 		pod    *api.BoundPod
-		data   []docker.CgroupData
+		data   []kubecontainer.CgroupValue
 		result []CgroupResponse
 	)
 
@@ -2084,14 +2616,15 @@ func (kl *Kubelet) DockerPodCgroup(podFullName string, cgroups []CgroupData) ([]
 		glog.Errorf("Can't find pod: %s", podFullName)
 		return nil, dockertools.ErrNoContainersInPod
 	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.runtime.GetPods(false)
 	if err != nil {
-		glog.Errorf("Error listing containers: %#v", dockerContainers)
+		glog.Errorf("Error listing containers: %v", err)
 		return nil, err
 	}
+	runningPod := pods.FindPod(podFullName, pod.UID)
 
 	for _, v := range cgroups {
-		data = append(data, docker.CgroupData{
+		data = append(data, kubecontainer.CgroupValue{
 			Group:     v.Group,
 			Subsystem: v.Subsystem,
 			Value:     v.Value,
@@ -2099,10 +2632,10 @@ func (kl *Kubelet) DockerPodCgroup(podFullName string, cgroups []CgroupData) ([]
 	}
 
 	for _, container := range pod.Spec.Containers {
-		if dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, pod.UID, container.Name); found {
-			resp, err := docker.ContainerCgroup(dockerContainer.ID, data)
+		if runningContainer := runningPod.FindContainerByName(container.Name); runningContainer != nil {
+			resp, err := kl.runtime.ContainerCgroups(runningContainer.ID, data)
 			if err != nil {
-				glog.Errorf("Set or Get container %s cgroup error: %v", dockerContainer.ID, err)
+				glog.Errorf("Set or Get container %s cgroup error: %v", runningContainer.ID, err)
 			}
 			for _, v := range resp {
 				result = append(result, CgroupResponse{
@@ -2122,8 +2655,8 @@ func (kl *Kubelet) checkLocalImage(image string) bool {
 	var retries = 5
 	for i := 1; i <= retries; i++ {
 		glog.V(3).Infof("Inspect image [retries: %d]", i)
-		_, err := kl.dockerClient.InspectImage(image)
-		if err != nil && (err.Error() == "no such image" || i == retries) {
+		id, err := kl.runtime.GetImageID(image)
+		if (err == nil && id == "") || (err != nil && i == retries) {
 			glog.Warningf("Can't find image: %s. error: %v", image, err)
 			return false
 		} else if err != nil {
@@ -2137,9 +2670,9 @@ func (kl *Kubelet) checkLocalImage(image string) bool {
 }
 
 // The comparison of container, to determine whether to auto restart container
-func (kl *Kubelet) compare(container api.Container, dockerContainer *docker.APIContainers) int {
-	if container.Image != dockerContainer.Image {
-		glog.V(3).Infof("Image hash changed %s vs %s.", container.Image, dockerContainer.Image)
+func (kl *Kubelet) compare(container api.Container, runningContainer *kubecontainer.Container) int {
+	if container.Image != runningContainer.Image {
+		glog.V(3).Infof("Image hash changed %s vs %s.", container.Image, runningContainer.Image)
 		return 2
 	}
 	// TODO(hbo)
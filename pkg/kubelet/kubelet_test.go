@@ -17,8 +17,13 @@ limitations under the License.
 package kubelet
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -28,8 +33,11 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/capabilities"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/health"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
@@ -48,9 +56,24 @@ func newTestKubelet(t *testing.T) (*Kubelet, *tools.FakeEtcdClient, *dockertools
 	kubelet.etcdClient = fakeEtcdClient
 	kubelet.rootDirectory = "/tmp/kubelet"
 	kubelet.podWorkers = newPodWorkers()
+	kubelet.dockerIDToRef = map[dockertools.DockerID]*api.ObjectReference{}
+	kubelet.podDestroyed = map[string]*api.BoundPod{}
+	kubelet.readiness = newReadinessStates()
+	kubelet.runtime = newDockerRuntime(kubelet)
 	return kubelet, fakeEtcdClient, fakeDocker
 }
 
+func newTestKubeletWithFakeRuntime() (*Kubelet, *kubecontainer.FakeRuntime) {
+	fakeRuntime := &kubecontainer.FakeRuntime{}
+	kubelet := &Kubelet{}
+	kubelet.runtime = fakeRuntime
+	kubelet.rootDirectory = "/tmp/kubelet"
+	kubelet.podWorkers = newPodWorkers()
+	kubelet.dockerIDToRef = map[dockertools.DockerID]*api.ObjectReference{}
+	kubelet.podDestroyed = map[string]*api.BoundPod{}
+	return kubelet, fakeRuntime
+}
+
 func verifyCalls(t *testing.T, fakeDocker *dockertools.FakeDockerClient, calls []string) {
 	err := fakeDocker.AssertCalls(calls)
 	if err != nil {
@@ -94,7 +117,7 @@ func TestKillContainerWithError(t *testing.T) {
 	}
 	kubelet, _, _ := newTestKubelet(t)
 	kubelet.dockerClient = fakeDocker
-	err := kubelet.killContainer(&fakeDocker.ContainerList[0])
+	err := kubelet.killContainer(kubecontainer.Pod{FullName: "qux"}, &kubecontainer.Container{ID: "1234", Name: "foo"})
 	if err == nil {
 		t.Errorf("expected error, found nil")
 	}
@@ -117,7 +140,7 @@ func TestKillContainer(t *testing.T) {
 		Name: "foobar",
 	}
 
-	err := kubelet.killContainer(&fakeDocker.ContainerList[0])
+	err := kubelet.killContainer(kubecontainer.Pod{FullName: "qux"}, &kubecontainer.Container{ID: "1234", Name: "foo"})
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -212,7 +235,7 @@ func TestSyncPodsWithTerminationLog(t *testing.T) {
 	}
 	kubelet.drainWorkers()
 	verifyCalls(t, fakeDocker, []string{
-		"list", "create", "start", "list", "inspect_container", "list", "create", "start"})
+		"list", "list", "create", "start", "list", "inspect_container", "list", "inspect_image", "create", "start"})
 
 	fakeDocker.Lock()
 	parts := strings.Split(fakeDocker.Container.HostConfig.Binds[0], ":")
@@ -269,7 +292,7 @@ func TestSyncPodsCreatesNetAndContainer(t *testing.T) {
 	kubelet.drainWorkers()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "create", "start", "list", "inspect_container", "list", "create", "start"})
+		"list", "list", "create", "start", "list", "inspect_container", "list", "inspect_image", "create", "start"})
 
 	fakeDocker.Lock()
 
@@ -297,6 +320,7 @@ func TestSyncPodsCreatesNetAndContainerPullsImage(t *testing.T) {
 	puller.HasImages = []string{}
 	kubelet.networkContainerImage = "custom_image_name"
 	fakeDocker.ContainerList = []docker.APIContainers{}
+	fakeDocker.MissingImages = []string{""}
 	err := kubelet.SyncPods([]api.BoundPod{
 		{
 			ObjectMeta: api.ObjectMeta{
@@ -317,7 +341,7 @@ func TestSyncPodsCreatesNetAndContainerPullsImage(t *testing.T) {
 	kubelet.drainWorkers()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "create", "start", "list", "inspect_container", "list", "create", "start"})
+		"list", "list", "create", "start", "list", "inspect_container", "list", "inspect_image", "create", "start"})
 
 	fakeDocker.Lock()
 
@@ -362,7 +386,7 @@ func TestSyncPodsWithNetCreatesContainer(t *testing.T) {
 	kubelet.drainWorkers()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "inspect_container", "list", "create", "start"})
+		"list", "list", "inspect_container", "list", "inspect_image", "create", "start"})
 
 	fakeDocker.Lock()
 	if len(fakeDocker.Created) != 1 ||
@@ -414,7 +438,7 @@ func TestSyncPodsWithNetCreatesContainerCallsHandler(t *testing.T) {
 	kubelet.drainWorkers()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "inspect_container", "list", "create", "start"})
+		"list", "list", "inspect_container", "list", "inspect_image", "create", "start"})
 
 	fakeDocker.Lock()
 	if len(fakeDocker.Created) != 1 ||
@@ -456,7 +480,7 @@ func TestSyncPodsDeletesWithNoNetContainer(t *testing.T) {
 	kubelet.drainWorkers()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "stop", "create", "start", "list", "list", "inspect_container", "list", "create", "start"})
+		"list", "list", "stop", "create", "start", "list", "list", "inspect_container", "list", "inspect_image", "create", "start"})
 
 	// A map iteration is used to delete containers, so must not depend on
 	// order here.
@@ -508,28 +532,43 @@ func TestSyncPodsDeletes(t *testing.T) {
 	}
 }
 
-func TestSyncPodDeletesDuplicate(t *testing.T) {
+func TestSyncPodsKeepsUndesiredUntilSourcesReady(t *testing.T) {
 	kubelet, _, fakeDocker := newTestKubelet(t)
-	dockerContainers := dockertools.DockerContainers{
-		"1234": &docker.APIContainers{
-			// the k8s prefix is required for the kubelet to manage the container
-			Names: []string{"/k8s_foo_bar.new.test_1"},
+	ready := false
+	kubelet.SetSourcesReady(func() bool { return ready })
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{
+			Names: []string{"/k8s_foo_bar.new.etcd"},
 			ID:    "1234",
 		},
-		"9876": &docker.APIContainers{
-			// network container
-			Names: []string{"/k8s_net_bar.new.test_"},
-			ID:    "9876",
-		},
-		"4567": &docker.APIContainers{
+	}
+	kubelet.prober = newProber(&FalseHealthChecker{}, kubelet.readiness)
+	kubelet.prober.containers[podContainer{"bar.new.etcd", "", "foo"}] = &containerProbes{id: "1234", stop: make(chan struct{})}
+	if err := kubelet.SyncPods([]api.BoundPod{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	verifyCalls(t, fakeDocker, []string{"list"})
+	// Only the kill of the undesired containers waits for the sources.
+	if len(kubelet.prober.containers) != 0 {
+		t.Errorf("expected the probes of the undesired containers to be stopped, got %v", kubelet.prober.containers)
+	}
+
+	ready = true
+	if err := kubelet.SyncPods([]api.BoundPod{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	verifyCalls(t, fakeDocker, []string{"list", "list", "stop"})
+}
+
+func TestSyncPodDeletesDuplicate(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	runningPod := kubecontainer.Pod{
+		FullName: "bar.new.test",
+		Containers: []*kubecontainer.Container{
+			{ID: "1234", Name: "foo"},
+			{ID: "9876", Name: networkContainerName},
 			// Duplicate for the same container.
-			Names: []string{"/k8s_foo_bar.new.test_2"},
-			ID:    "4567",
-		},
-		"2304": &docker.APIContainers{
-			// Container for another pod, untouched.
-			Names: []string{"/k8s_baz_fiz.new.test_6"},
-			ID:    "2304",
+			{ID: "4567", Name: "foo"},
 		},
 	}
 	err := kubelet.syncPod(&api.BoundPod{
@@ -543,7 +582,7 @@ func TestSyncPodDeletesDuplicate(t *testing.T) {
 				{Name: "foo"},
 			},
 		},
-	}, dockerContainers)
+	}, runningPod)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -568,19 +607,11 @@ func (f *FalseHealthChecker) CanCheck(probe *api.LivenessProbe) bool {
 
 func TestSyncPodBadHash(t *testing.T) {
 	kubelet, _, fakeDocker := newTestKubelet(t)
-	kubelet.healthChecker = &FalseHealthChecker{}
-	dockerContainers := dockertools.DockerContainers{
-		"1234": &docker.APIContainers{
-			// the k8s prefix is required for the kubelet to manage the container
-			Names: []string{"/k8s_bar.1234_foo.new.test"},
-			ID:    "1234",
-		},
-		"9876": &docker.APIContainers{
-			// network container
-			Names: []string{"/k8s_net_foo.new.test_"},
-			ID:    "9876",
-		},
+	runningPod := kubecontainer.Pod{
+		FullName:   "foo.new.test",
+		Containers: []*kubecontainer.Container{{ID: "1234", Name: "bar", Hash: 0x1234}},
 	}
+	// Only the containers of the pods on the host network are replaced when their spec changes.
 	err := kubelet.syncPod(&api.BoundPod{
 		ObjectMeta: api.ObjectMeta{
 			Name:        "foo",
@@ -592,41 +623,32 @@ func TestSyncPodBadHash(t *testing.T) {
 				{Name: "bar"},
 			},
 		},
-	}, dockerContainers)
+		Res: api.BoundResource{Network: api.Network{Mode: api.PodNetworkModeHost}},
+	}, runningPod)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	verifyCalls(t, fakeDocker, []string{"list", "stop", "stop", "list", "create", "start"})
+	verifyCalls(t, fakeDocker, []string{"stop", "list", "inspect_image", "create", "start"})
 
-	// A map interation is used to delete containers, so must not depend on
-	// order here.
-	expectedToStop := map[string]bool{
-		"1234": true,
-		"9876": true,
-	}
-	if len(fakeDocker.Stopped) != 2 ||
-		(!expectedToStop[fakeDocker.Stopped[0]] &&
-			!expectedToStop[fakeDocker.Stopped[1]]) {
+	if !reflect.DeepEqual(fakeDocker.Stopped, []string{"1234"}) {
 		t.Errorf("Wrong containers were stopped: %v", fakeDocker.Stopped)
 	}
 }
 
 func TestSyncPodUnhealthy(t *testing.T) {
 	kubelet, _, fakeDocker := newTestKubelet(t)
-	kubelet.healthChecker = &FalseHealthChecker{}
-	dockerContainers := dockertools.DockerContainers{
-		"1234": &docker.APIContainers{
-			// the k8s prefix is required for the kubelet to manage the container
-			Names: []string{"/k8s_bar_foo.new.test"},
-			ID:    "1234",
-		},
-		"9876": &docker.APIContainers{
-			// network container
-			Names: []string{"/k8s_net_foo.new.test_"},
-			ID:    "9876",
-		},
+	kubelet.prober = newProber(&FalseHealthChecker{}, kubelet.readiness)
+	// The liveness probe of the running container has failed.
+	kubelet.prober.containers[podContainer{"foo.new.test", "", "bar"}] = &containerProbes{
+		id:   "1234",
+		stop: make(chan struct{}),
 	}
+	runningPod := kubecontainer.Pod{
+		FullName:   "foo.new.test",
+		Containers: []*kubecontainer.Container{{ID: "1234", Name: "bar"}},
+	}
+	// Only the containers of the pods on the host network are restarted when they are unhealthy.
 	err := kubelet.syncPod(&api.BoundPod{
 		ObjectMeta: api.ObjectMeta{
 			Name:        "foo",
@@ -637,27 +659,20 @@ func TestSyncPodUnhealthy(t *testing.T) {
 			Containers: []api.Container{
 				{Name: "bar",
 					LivenessProbe: &api.LivenessProbe{
-					// Always returns healthy == false
+						// Always returns healthy == false
 					},
 				},
 			},
 		},
-	}, dockerContainers)
+		Res: api.BoundResource{Network: api.Network{Mode: api.PodNetworkModeHost}},
+	}, runningPod)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	verifyCalls(t, fakeDocker, []string{"list", "stop", "stop", "list", "create", "start"})
+	verifyCalls(t, fakeDocker, []string{"stop", "list", "inspect_image", "create", "start"})
 
-	// A map interation is used to delete containers, so must not depend on
-	// order here.
-	expectedToStop := map[string]bool{
-		"1234": true,
-		"9876": true,
-	}
-	if len(fakeDocker.Stopped) != 2 ||
-		(!expectedToStop[fakeDocker.Stopped[0]] &&
-			expectedToStop[fakeDocker.Stopped[0]]) {
+	if !reflect.DeepEqual(fakeDocker.Stopped, []string{"1234"}) {
 		t.Errorf("Wrong containers were stopped: %v", fakeDocker.Stopped)
 	}
 }
@@ -675,7 +690,10 @@ func TestMakeEnvVariables(t *testing.T) {
 			},
 		},
 	}
-	vars := makeEnvironmentVariables(&container)
+	vars, err := makeEnvironmentVariables(&api.BoundPod{}, &container)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	if len(vars) != len(container.Env) {
 		t.Errorf("Vars don't match.  Expected: %#v Found: %#v", container.Env, vars)
 	}
@@ -687,8 +705,52 @@ func TestMakeEnvVariables(t *testing.T) {
 	}
 }
 
+func TestMakeEnvVariablesFromFields(t *testing.T) {
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:      "foo",
+			Namespace: "bar",
+			Labels:    map[string]string{"app": "web"},
+		},
+		Res: api.BoundResource{
+			Network: api.Network{Address: "10.0.0.5/24", Gateway: "10.0.0.1", VlanID: 100},
+			CpuSet:  "0-3",
+		},
+	}
+	fieldRef := func(fieldPath string) *api.EnvVarSource {
+		return &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: fieldPath}}
+	}
+	container := api.Container{
+		Env: []api.EnvVar{
+			{Name: "POD_NAME", ValueFrom: fieldRef("metadata.name")},
+			{Name: "POD_NAMESPACE", ValueFrom: fieldRef("metadata.namespace")},
+			{Name: "APP", ValueFrom: fieldRef("metadata.labels['app']")},
+			{Name: "POD_IP", ValueFrom: fieldRef("res.network.address")},
+			{Name: "GATEWAY", ValueFrom: fieldRef("res.network.gateway")},
+			{Name: "VLAN", ValueFrom: fieldRef("res.network.vlanID")},
+			{Name: "CPUSET", ValueFrom: fieldRef("res.cpuSet")},
+		},
+	}
+	vars, err := makeEnvironmentVariables(pod, &container)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"POD_NAME=foo", "POD_NAMESPACE=bar", "APP=web", "POD_IP=10.0.0.5/24", "GATEWAY=10.0.0.1", "VLAN=100", "CPUSET=0-3"}
+	if !reflect.DeepEqual(vars, expected) {
+		t.Errorf("expected %v, got %v", expected, vars)
+	}
+
+	container.Env = []api.EnvVar{{Name: "HOST", ValueFrom: fieldRef("spec.host")}}
+	if _, err := makeEnvironmentVariables(pod, &container); err == nil {
+		t.Errorf("expected an error for an unsupported field")
+	}
+}
+
 func TestMountExternalVolumes(t *testing.T) {
 	kubelet, _, _ := newTestKubelet(t)
+	if err := kubelet.volumePluginMgr.InitPlugins(volume.ProbeVolumePlugins(), kubelet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	pod := api.BoundPod{
 		ObjectMeta: api.ObjectMeta{
 			Name:      "foo",
@@ -705,7 +767,10 @@ func TestMountExternalVolumes(t *testing.T) {
 			},
 		},
 	}
-	podVolumes, _ := kubelet.mountExternalVolumes(&pod)
+	podVolumes, err := kubelet.mountExternalVolumes(&pod)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
 	expectedPodVolumes := make(volumeMap)
 	expectedPodVolumes["host-dir"] = &volume.HostDir{"/dir/path"}
 	if len(expectedPodVolumes) != len(podVolumes) {
@@ -772,6 +837,49 @@ func TestMakeVolumesAndBinds(t *testing.T) {
 	verifyStringArrayEquals(t, binds, expectedBinds)
 }
 
+func TestCheckHostDirs(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "data")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dataDir)
+	os.Mkdir(path.Join(dataDir, "logs"), 0750)
+	os.Symlink("/", path.Join(dataDir, "root"))
+	capabilities.SetForTests(capabilities.Capabilities{
+		HostDirPolicy: capabilities.HostDirPolicy{
+			capabilities.AllNamespaces: {{Prefix: dataDir}, {Prefix: path.Join(dataDir, "logs"), ReadOnly: true}},
+		},
+	})
+	defer capabilities.SetForTests(capabilities.Capabilities{})
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{Name: "pod", Namespace: "test"},
+		Spec: api.PodSpec{
+			Volumes: []api.Volume{
+				{Name: "data", Source: &api.VolumeSource{HostDir: &api.HostDir{Path: dataDir}}},
+				{Name: "logs", Source: &api.VolumeSource{HostDir: &api.HostDir{Path: path.Join(dataDir, "logs")}}},
+				{Name: "root", Source: &api.VolumeSource{HostDir: &api.HostDir{Path: path.Join(dataDir, "root")}}},
+				{Name: "scratch", Source: &api.VolumeSource{EmptyDir: &api.EmptyDir{}}},
+			},
+		},
+	}
+	tests := []struct {
+		mount   api.VolumeMount
+		allowed bool
+	}{
+		{api.VolumeMount{Name: "data"}, true},
+		{api.VolumeMount{Name: "scratch"}, true},
+		{api.VolumeMount{Name: "logs", ReadOnly: true}, true},
+		{api.VolumeMount{Name: "logs"}, false},
+		{api.VolumeMount{Name: "root", ReadOnly: true}, false},
+	}
+	for _, test := range tests {
+		container := &api.Container{Name: "foo", VolumeMounts: []api.VolumeMount{test.mount}}
+		if err := checkHostDirs(pod, container); (err == nil) != test.allowed {
+			t.Errorf("unexpected result for %#v: %v", test.mount, err)
+		}
+	}
+}
+
 func TestMakePortsAndBindings(t *testing.T) {
 	container := api.Container{
 		Ports: []api.Port{
@@ -1009,17 +1117,79 @@ func TestGetContainerInfoOnNonExistContainer(t *testing.T) {
 }
 
 type fakeContainerCommandRunner struct {
-	Cmd []string
-	ID  string
-	E   error
+	Cmd  []string
+	ID   string
+	Port uint16
+	E    error
 }
 
-func (f *fakeContainerCommandRunner) RunInContainer(id string, cmd []string) ([]byte, error) {
+func (f *fakeContainerCommandRunner) RunInContainer(id string, cmd []string, timeout time.Duration) ([]byte, error) {
 	f.Cmd = cmd
 	f.ID = id
 	return []byte{}, f.E
 }
 
+func (f *fakeContainerCommandRunner) ExecInContainer(id string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	f.Cmd = cmd
+	f.ID = id
+	return f.E
+}
+
+func (f *fakeContainerCommandRunner) PortForward(id string, port uint16, stream io.ReadWriter) error {
+	f.ID = id
+	f.Port = port
+	return f.E
+}
+
+func TestExecInContainerDefaultsToSingleContainer(t *testing.T) {
+	fakeCommandRunner := fakeContainerCommandRunner{}
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	kubelet.runner = &fakeCommandRunner
+	kubelet.pods = []api.BoundPod{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "bar", UID: "12345", Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"}},
+			Spec:       api.PodSpec{Containers: []api.Container{{Name: "web"}}},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "two", Namespace: "bar", UID: "67890", Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"}},
+			Spec:       api.PodSpec{Containers: []api.Container{{Name: "web"}, {Name: "log"}}},
+		},
+	}
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1234", Names: []string{"/k8s_web.1_foo.bar.etcd_12345_1"}},
+	}
+
+	if err := kubelet.ExecInContainer("foo.bar.etcd", "", "", []string{"sh"}, nil, nil, nil, false, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if fakeCommandRunner.ID != "1234" || !reflect.DeepEqual(fakeCommandRunner.Cmd, []string{"sh"}) {
+		t.Errorf("unexpected exec of %v in %s", fakeCommandRunner.Cmd, fakeCommandRunner.ID)
+	}
+	if err := kubelet.ExecInContainer("two.bar.etcd", "", "", []string{"sh"}, nil, nil, nil, false, nil); err == nil {
+		t.Errorf("expected an error without a container name for a pod with two containers")
+	}
+}
+
+func TestPortForwardUsesNetworkContainer(t *testing.T) {
+	fakeCommandRunner := fakeContainerCommandRunner{}
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	kubelet.runner = &fakeCommandRunner
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1234", Names: []string{"/k8s_web.1_foo.bar.etcd_12345_1"}},
+		{ID: "5678", Names: []string{"/k8s_net.1_foo.bar.etcd_12345_1"}},
+	}
+
+	if err := kubelet.PortForward("foo.bar.etcd", "", 8080, &bytes.Buffer{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if fakeCommandRunner.ID != "5678" || fakeCommandRunner.Port != 8080 {
+		t.Errorf("unexpected port forward of %d in %s", fakeCommandRunner.Port, fakeCommandRunner.ID)
+	}
+	if err := kubelet.PortForward("qux.bar.etcd", "", 8080, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for a pod without a network container")
+	}
+}
+
 func TestRunInContainerNoSuchPod(t *testing.T) {
 	fakeCommandRunner := fakeContainerCommandRunner{}
 	kubelet, _, fakeDocker := newTestKubelet(t)
@@ -1196,12 +1366,9 @@ func TestSyncPodEventHandlerFails(t *testing.T) {
 	kubelet.httpClient = &fakeHTTP{
 		err: fmt.Errorf("test error"),
 	}
-	dockerContainers := dockertools.DockerContainers{
-		"9876": &docker.APIContainers{
-			// network container
-			Names: []string{"/k8s_net_foo.new.test_"},
-			ID:    "9876",
-		},
+	runningPod := kubecontainer.Pod{
+		FullName:   "foo.new.test",
+		Containers: []*kubecontainer.Container{{ID: "9876", Name: networkContainerName}},
 	}
 	err := kubelet.syncPod(&api.BoundPod{
 		ObjectMeta: api.ObjectMeta{
@@ -1224,12 +1391,12 @@ func TestSyncPodEventHandlerFails(t *testing.T) {
 				},
 			},
 		},
-	}, dockerContainers)
+	}, runningPod)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	verifyCalls(t, fakeDocker, []string{"list", "list", "create", "start", "stop"})
+	verifyCalls(t, fakeDocker, []string{"list", "list", "inspect_image", "create", "start", "stop"})
 
 	if len(fakeDocker.Stopped) != 1 {
 		t.Errorf("Wrong containers were stopped: %v", fakeDocker.Stopped)
@@ -1536,6 +1703,8 @@ func TestSyncPodsWithPullPolicy(t *testing.T) {
 	puller.HasImages = []string{"existing_one", "want:latest"}
 	kubelet.networkContainerImage = "custom_image_name"
 	fakeDocker.ContainerList = []docker.APIContainers{}
+	// The images found in docker are not pulled.
+	fakeDocker.MissingImages = []string{"pull_always_image", "pull_if_not_present_image", "want:latest"}
 	err := kubelet.SyncPods([]api.BoundPod{
 		{
 			ObjectMeta: api.ObjectMeta{
@@ -1570,3 +1739,689 @@ func TestSyncPodsWithPullPolicy(t *testing.T) {
 	}
 	fakeDocker.Unlock()
 }
+
+func TestContainerInBackOff(t *testing.T) {
+	kubelet, _, _ := newTestKubelet(t)
+	now := time.Now()
+	clock := &util.FakeClock{Time: now}
+	kubelet.backOff = util.NewBackOff(10*time.Second, time.Minute)
+	kubelet.backOff.Clock = clock
+	kubelet.backOffReset = 10 * time.Minute
+
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "new",
+			UID:         "12345678",
+			Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+		},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Name: "bar"}},
+		},
+	}
+	dead := func(started, finished time.Time) []*kubecontainer.ContainerStatus {
+		return []*kubecontainer.ContainerStatus{
+			{
+				ID:         "9876",
+				Created:    started,
+				StartedAt:  started,
+				FinishedAt: finished,
+				ExitCode:   1,
+			},
+		}
+	}
+	container := &pod.Spec.Containers[0]
+
+	// The first crash is restarted right away.
+	if kubelet.containerInBackOff(pod, container, dead(now.Add(-2*time.Second), now.Add(-time.Second))) {
+		t.Errorf("unexpected back-off on the first crash")
+	}
+	// The second crash has to wait for the initial delay.
+	clock.Time = now.Add(5 * time.Second)
+	if !kubelet.containerInBackOff(pod, container, dead(now.Add(3*time.Second), now.Add(4*time.Second))) {
+		t.Errorf("expected back-off on the second crash")
+	}
+	clock.Time = now.Add(15 * time.Second)
+	if kubelet.containerInBackOff(pod, container, dead(now.Add(3*time.Second), now.Add(4*time.Second))) {
+		t.Errorf("unexpected back-off after the delay elapsed")
+	}
+	key := containerBackOffKey(GetPodFullName(pod), pod.UID, container.Name)
+	if delay := kubelet.backOff.Get(key); delay != 20*time.Second {
+		t.Errorf("expected delay of 20s, got %v", delay)
+	}
+
+	// A container which ran long enough resets the back-off.
+	clock.Time = now.Add(time.Hour)
+	if kubelet.containerInBackOff(pod, container, dead(now.Add(time.Minute), now.Add(time.Hour))) {
+		t.Errorf("unexpected back-off after a stable run")
+	}
+	if delay := kubelet.backOff.Get(key); delay != 10*time.Second {
+		t.Errorf("expected delay to be reset to 10s, got %v", delay)
+	}
+}
+
+func TestSetBackOffStatus(t *testing.T) {
+	kubelet, _, _ := newTestKubelet(t)
+	now := time.Now()
+	kubelet.backOff = util.NewBackOff(10*time.Second, time.Minute)
+	kubelet.backOff.Clock = &util.FakeClock{Time: now}
+
+	finishedAt := now.Add(-time.Second)
+	kubelet.backOff.Next(containerBackOffKey("foo.new.test", "12345678", "bar"), finishedAt)
+	manifest := api.PodSpec{
+		Containers: []api.Container{{Name: "bar"}, {Name: "baz"}},
+	}
+	info := api.PodInfo{
+		"bar": {State: api.ContainerState{Termination: &api.ContainerStateTerminated{ExitCode: 2, FinishedAt: finishedAt}}},
+		"baz": {State: api.ContainerState{Termination: &api.ContainerStateTerminated{ExitCode: 0, FinishedAt: finishedAt}}},
+	}
+	kubelet.setBackOffStatus("foo.new.test", "12345678", manifest, info)
+
+	waiting := info["bar"].State.Waiting
+	if waiting == nil || waiting.Reason != crashLoopBackOffReason {
+		t.Fatalf("expected bar to wait in back-off, got %#v", info["bar"].State)
+	}
+	if !waiting.NextRetryAt.Equal(finishedAt.Add(10 * time.Second)) {
+		t.Errorf("unexpected next retry time: %v", waiting.NextRetryAt)
+	}
+	if info["baz"].State.Termination == nil {
+		t.Errorf("expected baz to stay terminated, got %#v", info["baz"].State)
+	}
+}
+
+func TestSetProbeStatus(t *testing.T) {
+	kubelet, _, _ := newTestKubelet(t)
+	kubelet.prober = newProber(&FalseHealthChecker{}, kubelet.readiness)
+	probe := &api.LivenessProbe{Exec: &api.ExecAction{Command: []string{"ls"}}}
+	manifest := api.PodSpec{
+		Containers: []api.Container{
+			{Name: "noprobe"},
+			{Name: "ready", ReadinessProbe: probe},
+			{Name: "unprobed", ReadinessProbe: probe},
+			{Name: "stopped"},
+		},
+	}
+	running := api.ContainerState{Running: &api.ContainerStateRunning{}}
+	info := api.PodInfo{
+		"noprobe":  {State: running},
+		"ready":    {State: running},
+		"unprobed": {State: running},
+		"stopped":  {State: api.ContainerState{Termination: &api.ContainerStateTerminated{}}},
+	}
+	key := podContainer{"foo.new.test", "12345678", "ready"}
+	c := &containerProbes{id: "1234", stop: make(chan struct{}), live: true, readiness: &api.ProbeResult{}}
+	kubelet.prober.containers[key] = c
+	kubelet.prober.record(key, c, readinessProbe, withProbeDefaults(probe), health.Healthy, nil)
+	kubelet.setProbeStatus("foo.new.test", "12345678", manifest, info)
+
+	expected := map[string]bool{"noprobe": true, "ready": true, "unprobed": false, "stopped": false}
+	for name, ready := range expected {
+		if info[name].Ready != ready {
+			t.Errorf("container %s: expected ready %v, got %v", name, ready, info[name].Ready)
+		}
+	}
+	if result := info["ready"].Readiness; result == nil || result.Result != api.ProbeSuccess {
+		t.Errorf("expected the readiness probe result of ready, got %#v", result)
+	}
+}
+
+func TestSyncPodsWithFakeRuntime(t *testing.T) {
+	kubelet, fakeRuntime := newTestKubeletWithFakeRuntime()
+	fakeRuntime.RunningPods = kubecontainer.Pods{
+		{
+			FullName: "foo.new.test",
+			UID:      "12345678",
+			Containers: []*kubecontainer.Container{
+				{ID: "4321", Name: networkContainerName},
+				{ID: "1234", Name: "bar"},
+				{ID: "5678", Name: "old"},
+			},
+		},
+		{
+			FullName:   "gone.new.test",
+			UID:        "87654321",
+			Containers: []*kubecontainer.Container{{ID: "9876", Name: "baz"}},
+		},
+	}
+	pod := api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "new",
+			UID:         "12345678",
+			Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+		},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Name: "bar"}, {Name: "new", Image: "image"}},
+		},
+	}
+	if err := kubelet.SyncPods([]api.BoundPod{pod}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	kubelet.drainWorkers()
+
+	// The missing container is pulled and run, the undesired ones of the pod and of the
+	// removed pod are killed.
+	if !reflect.DeepEqual(fakeRuntime.StartedContainers, []string{"new"}) {
+		t.Errorf("unexpected started containers: %v", fakeRuntime.StartedContainers)
+	}
+	if !reflect.DeepEqual(fakeRuntime.PulledImages, []string{"image"}) {
+		t.Errorf("unexpected pulled images: %v", fakeRuntime.PulledImages)
+	}
+	killed := util.NewStringSet(fakeRuntime.KilledContainers...)
+	if !killed.HasAll("5678", "9876") || len(killed) != 2 {
+		t.Errorf("unexpected killed containers: %v", fakeRuntime.KilledContainers)
+	}
+	pods, _ := fakeRuntime.GetPods(false)
+	running := pods.FindPod("foo.new.test", "12345678")
+	if len(running.Containers) != 3 || running.FindContainerByName("bar").ID != "1234" || running.FindContainerByName("new") == nil {
+		t.Errorf("unexpected running pod: %#v", running)
+	}
+	if opts := fakeRuntime.RunOptions[running.FindContainerByName("new").ID]; opts == nil || opts.NetMode != "container:4321" {
+		t.Errorf("expected the container in the network of the pod, got %#v", opts)
+	}
+}
+
+func TestSyncPodWithFakeRuntime(t *testing.T) {
+	exited := func(id, name string, exitCode int) (*kubecontainer.Container, *kubecontainer.ContainerStatus) {
+		now := time.Now()
+		return &kubecontainer.Container{ID: id, Name: name},
+			&kubecontainer.ContainerStatus{ID: id, Created: now.Add(-time.Minute), StartedAt: now.Add(-time.Minute), FinishedAt: now, ExitCode: exitCode}
+	}
+	bar := api.Container{Name: "bar", Image: "image"}
+	tests := []struct {
+		name          string
+		restartPolicy api.RestartPolicy
+		hostNetwork   bool
+		running       []*kubecontainer.Container
+		exitCode      *int
+		backOff       bool
+		started       []string
+		killed        []string
+	}{
+		{name: "missing container started", started: []string{"bar"}},
+		{name: "running container kept", running: []*kubecontainer.Container{{ID: "1234", Name: "bar"}}},
+		{
+			name:    "duplicate killed",
+			running: []*kubecontainer.Container{{ID: "1234", Name: "bar"}, {ID: "5678", Name: "bar"}},
+			killed:  []string{"5678"},
+		},
+		{
+			name:    "undesired container killed",
+			running: []*kubecontainer.Container{{ID: "1234", Name: "bar"}, {ID: "5678", Name: "old"}},
+			killed:  []string{"5678"},
+		},
+		{name: "exited container restarted", exitCode: new(int), started: []string{"bar"}},
+		{
+			name:          "succeeded container not restarted on failure",
+			restartPolicy: api.RestartPolicy{OnFailure: &api.RestartPolicyOnFailure{}},
+			exitCode:      new(int),
+		},
+		{
+			name:          "failed container restarted on failure",
+			restartPolicy: api.RestartPolicy{OnFailure: &api.RestartPolicyOnFailure{}},
+			exitCode:      intPtr(1),
+			started:       []string{"bar"},
+		},
+		{
+			name:          "failed container never restarted",
+			restartPolicy: api.RestartPolicy{Never: &api.RestartPolicyNever{}},
+			exitCode:      intPtr(1),
+		},
+		{name: "crashed container in back-off", exitCode: intPtr(1), backOff: true},
+		{
+			name:        "changed container replaced on the host network",
+			hostNetwork: true,
+			running:     []*kubecontainer.Container{{ID: "1234", Name: "bar", Hash: 1}},
+			started:     []string{"bar"},
+			killed:      []string{"1234"},
+		},
+		{
+			name:        "unchanged container kept on the host network",
+			hostNetwork: true,
+			running:     []*kubecontainer.Container{{ID: "1234", Name: "bar", Hash: kubecontainer.HashContainer(&bar)}},
+		},
+	}
+	for _, test := range tests {
+		kubelet, fakeRuntime := newTestKubeletWithFakeRuntime()
+		fakeRuntime.Images = []string{"image"}
+		pod := &api.BoundPod{
+			ObjectMeta: api.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "new",
+				UID:         "12345678",
+				Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+			},
+			Spec: api.PodSpec{
+				Containers:    []api.Container{bar},
+				RestartPolicy: test.restartPolicy,
+			},
+		}
+		runningPod := &kubecontainer.Pod{FullName: "foo.new.test", UID: "12345678"}
+		if test.hostNetwork {
+			pod.Res.Network.Mode = api.PodNetworkModeHost
+		} else {
+			runningPod.Containers = append(runningPod.Containers, &kubecontainer.Container{ID: "9876", Name: networkContainerName})
+		}
+		runningPod.Containers = append(runningPod.Containers, test.running...)
+		fakeRuntime.RunningPods = kubecontainer.Pods{runningPod}
+		if test.exitCode != nil {
+			container, status := exited("4321", "bar", *test.exitCode)
+			fakeRuntime.ExitedPods = kubecontainer.Pods{{FullName: "foo.new.test", UID: "12345678", Containers: []*kubecontainer.Container{container}}}
+			fakeRuntime.Statuses = map[string]*kubecontainer.ContainerStatus{"4321": status}
+		}
+		if test.backOff {
+			kubelet.backOff = util.NewBackOff(time.Minute, time.Hour)
+			kubelet.backOff.Next(containerBackOffKey("foo.new.test", "12345678", "bar"), time.Now())
+		}
+
+		pods, _ := fakeRuntime.GetPods(false)
+		if err := kubelet.syncPod(pod, pods.FindPod("foo.new.test", "12345678")); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if len(fakeRuntime.StartedContainers) != 0 || len(test.started) != 0 {
+			if !reflect.DeepEqual(fakeRuntime.StartedContainers, test.started) {
+				t.Errorf("%s: expected started %v, got %v", test.name, test.started, fakeRuntime.StartedContainers)
+			}
+		}
+		if len(fakeRuntime.KilledContainers) != 0 || len(test.killed) != 0 {
+			if !reflect.DeepEqual(fakeRuntime.KilledContainers, test.killed) {
+				t.Errorf("%s: expected killed %v, got %v", test.name, test.killed, fakeRuntime.KilledContainers)
+			}
+		}
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestSyncPodsRuntimeError(t *testing.T) {
+	kubelet, fakeRuntime := newTestKubeletWithFakeRuntime()
+	fakeRuntime.Err = fmt.Errorf("runtime is down")
+	if err := kubelet.SyncPods([]api.BoundPod{}); err == nil {
+		t.Errorf("expected error")
+	}
+	if err := fakeRuntime.AssertCalls([]string{"GetPods"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRuntimeContainerOperations(t *testing.T) {
+	kubelet, fakeRuntime := newTestKubeletWithFakeRuntime()
+	pod := api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "new",
+			UID:         "12345678",
+			Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+		},
+		Spec: api.PodSpec{Containers: []api.Container{{Name: "bar"}}},
+	}
+	kubelet.pods = []api.BoundPod{pod}
+	fakeRuntime.RunningPods = kubecontainer.Pods{
+		{
+			FullName:   "foo.new.test",
+			UID:        "12345678",
+			Containers: []*kubecontainer.Container{{ID: "1234", Name: "bar"}},
+		},
+	}
+	fakeRuntime.ContainerLogs = map[string]string{"1234": "hello"}
+	fakeRuntime.RunOutput = []byte("output")
+
+	var stdout bytes.Buffer
+	if err := kubelet.GetKubeletContainerLogs("foo.new.test", "bar", "", false, &stdout, &stdout); err != nil || stdout.String() != "hello" {
+		t.Errorf("unexpected logs: %q %v", stdout.String(), err)
+	}
+	if err := kubelet.GetKubeletContainerLogs("other.new.test", "bar", "", false, &stdout, &stdout); err == nil {
+		t.Errorf("expected error for a missing pod")
+	}
+	if out, err := kubelet.RunInContainer("foo.new.test", "", "bar", []string{"ls"}); err != nil || string(out) != "output" {
+		t.Errorf("unexpected output: %q %v", out, err)
+	}
+	if err := kubelet.ExecInContainer("foo.new.test", "", "", []string{"sh"}, nil, nil, nil, false, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := kubelet.RunInContainer("foo.new.test", "", "missing", []string{"ls"}); err == nil {
+		t.Errorf("expected error for a missing container")
+	}
+	expectedCommands := [][]string{{"1234", "ls"}, {"1234", "sh"}}
+	if !reflect.DeepEqual(fakeRuntime.Commands, expectedCommands) {
+		t.Errorf("expected %v, got %v", expectedCommands, fakeRuntime.Commands)
+	}
+
+	err := kubelet.UpdatePodCgroup("foo.new.test", &PodConfig{
+		WriteSubsystem: []KVPair{{Key: "memory.limit_in_bytes", Value: "1048576"}},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expectedResources := map[string][]kubecontainer.KeyValue{
+		"1234": {{Key: "memory.limit_in_bytes", Value: "1048576"}},
+	}
+	if !reflect.DeepEqual(fakeRuntime.Resources, expectedResources) {
+		t.Errorf("expected %v, got %v", expectedResources, fakeRuntime.Resources)
+	}
+
+	if err := kubelet.OpPod("foo.new.test", "stop"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(fakeRuntime.KilledContainers, []string{"1234"}) {
+		t.Errorf("unexpected killed containers: %v", fakeRuntime.KilledContainers)
+	}
+}
+
+func TestSyncPodOnContainerEvent(t *testing.T) {
+	kubelet, fakeRuntime := newTestKubeletWithFakeRuntime()
+	kubelet.podSyncs = make(chan string, 1)
+	kubelet.pods = []api.BoundPod{
+		{
+			ObjectMeta: api.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "new",
+				UID:         "12345678",
+				Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{{Name: "bar"}},
+			},
+		},
+	}
+	fakeRuntime.RunningPods = kubecontainer.Pods{
+		{
+			FullName:   "foo.new.test",
+			UID:        "12345678",
+			Containers: []*kubecontainer.Container{{ID: "9876", Name: networkContainerName}},
+		},
+	}
+	fakeRuntime.Images = []string{"image"}
+	kubelet.pods[0].Spec.Containers[0].Image = "image"
+	container := &docker.APIContainers{Names: []string{"/k8s_bar.1234_foo.new.test_12345678_42"}}
+
+	// Only the containers of the kubelet which started or died queue a sync.
+	kubelet.handleContainerEvent(&docker.APIEvents{Status: "create"}, container)
+	kubelet.handleContainerEvent(&docker.APIEvents{Status: "die"}, &docker.APIContainers{Names: []string{"/other"}})
+	kubelet.handleContainerEvent(&docker.APIEvents{Status: "destroy"}, nil)
+	select {
+	case podFullName := <-kubelet.podSyncs:
+		t.Fatalf("unexpected sync of pod %q", podFullName)
+	default:
+	}
+	kubelet.handleContainerEvent(&docker.APIEvents{Status: "die"}, container)
+	// The events seen while the queue is full are left to the periodic sync.
+	kubelet.handleContainerEvent(&docker.APIEvents{Status: "start"}, container)
+	podFullName := <-kubelet.podSyncs
+	if podFullName != "foo.new.test" {
+		t.Errorf("unexpected pod: %q", podFullName)
+	}
+
+	kubelet.syncPodByFullName(podFullName)
+	kubelet.drainWorkers()
+	if err := fakeRuntime.AssertCalls([]string{"GetPods", "GetPodInfo", "GetRecentContainers", "GetImageID", "RunContainer"}); err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(fakeRuntime.StartedContainers, []string{"bar"}) {
+		t.Errorf("unexpected started containers: %v", fakeRuntime.StartedContainers)
+	}
+
+	// The pods which are not desired are left to the periodic sync.
+	fakeRuntime.ClearCalls()
+	kubelet.syncPodByFullName("gone.new.test")
+	kubelet.drainWorkers()
+	if err := fakeRuntime.AssertCalls(nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTerminationGracePeriod(t *testing.T) {
+	seconds := int64(10)
+	soon := util.Time{Time: time.Now().Add(5 * time.Second)}
+	past := util.Time{Time: time.Now().Add(-time.Minute)}
+	tests := []struct {
+		pod      *api.BoundPod
+		expected time.Duration
+		slack    time.Duration
+	}{
+		{nil, 30 * time.Second, 0},
+		{&api.BoundPod{}, 30 * time.Second, 0},
+		{&api.BoundPod{Spec: api.PodSpec{TerminationGracePeriodSeconds: &seconds}}, 10 * time.Second, 0},
+		{&api.BoundPod{ObjectMeta: api.ObjectMeta{DeletionTimestamp: &soon}, Spec: api.PodSpec{TerminationGracePeriodSeconds: &seconds}}, 5 * time.Second, time.Second},
+		{&api.BoundPod{ObjectMeta: api.ObjectMeta{DeletionTimestamp: &past}}, 0, 0},
+	}
+	for i, test := range tests {
+		gracePeriod := terminationGracePeriod(test.pod)
+		if gracePeriod > test.expected || gracePeriod < test.expected-test.slack {
+			t.Errorf("%d: expected %v, got %v", i, test.expected, gracePeriod)
+		}
+	}
+	if timeout := stopTimeout(1500 * time.Millisecond); timeout != 2 {
+		t.Errorf("expected the stop timeout rounded up, got %d", timeout)
+	}
+}
+
+func TestKillContainerRunsPreStop(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	fakeCommandRunner := &fakeContainerCommandRunner{}
+	kubelet.runner = fakeCommandRunner
+	seconds := int64(10)
+	kubelet.podDestroyed = map[string]*api.BoundPod{
+		"12345678": {
+			ObjectMeta: api.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "new",
+				UID:         "12345678",
+				Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{
+					{
+						Name: "bar",
+						Lifecycle: &api.Lifecycle{
+							PreStop: &api.Handler{Exec: &api.ExecAction{Command: []string{"drain"}}},
+						},
+					},
+				},
+				TerminationGracePeriodSeconds: &seconds,
+			},
+		},
+	}
+	name := "/k8s_bar.1234_foo.new.test_12345678_42"
+	fakeDocker.ContainerList = []docker.APIContainers{{ID: "1234", Names: []string{name}}}
+
+	runningPod := kubecontainer.Pod{FullName: "foo.new.test", UID: "12345678"}
+	if err := kubelet.killContainer(runningPod, &kubecontainer.Container{ID: "1234", Name: "bar"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	verifyCalls(t, fakeDocker, []string{"list", "stop"})
+	if !reflect.DeepEqual(fakeCommandRunner.Cmd, []string{"drain"}) || fakeCommandRunner.ID != "1234" {
+		t.Errorf("expected the PreStop handler to run in the container, got %v in %q", fakeCommandRunner.Cmd, fakeCommandRunner.ID)
+	}
+	if !reflect.DeepEqual(fakeDocker.Stopped, []string{"1234"}) {
+		t.Errorf("unexpected stopped containers: %v", fakeDocker.Stopped)
+	}
+}
+
+func TestSyncPodsStopsTerminatingPods(t *testing.T) {
+	kubelet, fakeRuntime := newTestKubeletWithFakeRuntime()
+	fakeRuntime.RunningPods = kubecontainer.Pods{
+		{
+			FullName:   "foo.new.test",
+			UID:        "12345678",
+			Containers: []*kubecontainer.Container{{ID: "1234", Name: "bar"}},
+		},
+	}
+	deletionTimestamp := util.Time{Time: time.Now().Add(30 * time.Second)}
+	pods := []api.BoundPod{
+		{
+			ObjectMeta: api.ObjectMeta{
+				Name:              "foo",
+				Namespace:         "new",
+				UID:               "12345678",
+				Annotations:       map[string]string{ConfigSourceAnnotationKey: "test"},
+				DeletionTimestamp: &deletionTimestamp,
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{{Name: "bar"}},
+			},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{
+				Name:              "gone",
+				Namespace:         "new",
+				UID:               "87654321",
+				Annotations:       map[string]string{ConfigSourceAnnotationKey: "test"},
+				DeletionTimestamp: &deletionTimestamp,
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{{Name: "baz"}},
+			},
+		},
+	}
+	if err := kubelet.SyncPods(pods); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	kubelet.drainWorkers()
+
+	// The containers of the terminating pods are stopped rather than restarted.
+	if err := fakeRuntime.AssertCalls([]string{"GetPods", "KillContainer"}); err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(fakeRuntime.KilledContainers, []string{"1234"}) {
+		t.Errorf("unexpected killed containers: %v", fakeRuntime.KilledContainers)
+	}
+
+	// A container which dies during the termination is not restarted either.
+	fakeRuntime.ClearCalls()
+	kubelet.pods = pods
+	kubelet.syncPodByFullName("foo.new.test")
+	kubelet.drainWorkers()
+	if err := fakeRuntime.AssertCalls([]string{"GetPods"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSyncInitContainers(t *testing.T) {
+	pod := api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "new",
+			UID:         "12345678",
+			Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+		},
+		Spec: api.PodSpec{
+			InitContainers: []api.Container{{Name: "fetch"}, {Name: "migrate"}},
+			Containers:     []api.Container{{Name: "bar"}},
+		},
+	}
+	tests := []struct {
+		name          string
+		restartPolicy api.RestartPolicy
+		running       string
+		started       string
+		exited        map[string]int
+		initialized   bool
+		created       string
+	}{
+		{name: "first init container", created: "fetch"},
+		{name: "containers started without init containers", started: "bar", initialized: true},
+		{name: "init container running", running: "fetch"},
+		{name: "next init container", exited: map[string]int{"fetch": 0}, created: "migrate"},
+		{name: "all init containers completed", exited: map[string]int{"fetch": 0, "migrate": 0}, initialized: true},
+		{
+			name:          "failed init container restarted",
+			restartPolicy: api.RestartPolicy{OnFailure: &api.RestartPolicyOnFailure{}},
+			exited:        map[string]int{"fetch": 1},
+			created:       "fetch",
+		},
+		{
+			name:          "failed init container not restarted",
+			restartPolicy: api.RestartPolicy{Never: &api.RestartPolicyNever{}},
+			exited:        map[string]int{"fetch": 1},
+		},
+	}
+	for _, test := range tests {
+		kubelet, fakeRuntime := newTestKubeletWithFakeRuntime()
+		fakeRuntime.Statuses = map[string]*kubecontainer.ContainerStatus{}
+		exited := &kubecontainer.Pod{FullName: "foo.new.test", UID: "12345678"}
+		for name, exitCode := range test.exited {
+			id := "exited-" + name
+			exited.Containers = append(exited.Containers, &kubecontainer.Container{ID: id, Name: name})
+			fakeRuntime.Statuses[id] = &kubecontainer.ContainerStatus{ExitCode: exitCode, Created: time.Now()}
+		}
+		fakeRuntime.ExitedPods = kubecontainer.Pods{exited}
+		runningPod := kubecontainer.Pod{FullName: "foo.new.test", UID: "12345678"}
+		if test.running != "" {
+			runningPod.Containers = append(runningPod.Containers, &kubecontainer.Container{ID: "running", Name: test.running})
+		}
+		if test.started != "" {
+			runningPod.Containers = append(runningPod.Containers, &kubecontainer.Container{ID: "started", Name: test.started})
+		}
+		pod.Spec.RestartPolicy = test.restartPolicy
+		containersToKeep := map[string]empty{}
+
+		initialized := kubelet.syncInitContainers(&pod, runningPod, volumeMap{}, "container:net", containersToKeep)
+		if initialized != test.initialized {
+			t.Errorf("%s: expected initialized %v, got %v", test.name, test.initialized, initialized)
+		}
+		if test.created == "" {
+			if len(fakeRuntime.StartedContainers) != 0 {
+				t.Errorf("%s: unexpected containers started %v", test.name, fakeRuntime.StartedContainers)
+			}
+		} else if !reflect.DeepEqual(fakeRuntime.StartedContainers, []string{test.created}) {
+			t.Errorf("%s: unexpected containers started %v", test.name, fakeRuntime.StartedContainers)
+		} else if id := containerIDOf(fakeRuntime, test.created); fakeRuntime.RunOptions[id].NetMode != "container:net" {
+			t.Errorf("%s: unexpected network mode %#v", test.name, fakeRuntime.RunOptions[id])
+		}
+		if _, kept := containersToKeep["running"]; kept != (test.running != "") {
+			t.Errorf("%s: unexpected containers to keep %v", test.name, containersToKeep)
+		}
+	}
+}
+
+// containerIDOf returns the ID of the running container with the name in the fake runtime.
+func containerIDOf(fakeRuntime *kubecontainer.FakeRuntime, name string) string {
+	for _, pod := range fakeRuntime.RunningPods {
+		if container := pod.FindContainerByName(name); container != nil {
+			return container.ID
+		}
+	}
+	return ""
+}
+
+func TestSyncInitContainersCompletedOnce(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	pod := api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "new",
+			UID:         "12345678",
+			Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+		},
+		Spec: api.PodSpec{
+			InitContainers: []api.Container{{Name: "fetch"}},
+			Containers:     []api.Container{{Name: "bar"}},
+		},
+	}
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "exited-fetch", Names: []string{"/k8s_fetch.1234_foo.new.test_12345678_42"}},
+	}
+	fakeDocker.ContainerMap = map[string]*docker.Container{
+		"exited-fetch": {ID: "exited-fetch", Created: time.Now()},
+	}
+	if !kubelet.syncInitContainers(&pod, kubecontainer.Pod{}, volumeMap{}, "container:net", map[string]empty{}) {
+		t.Fatalf("expected the init containers completed")
+	}
+
+	// The exited init container is removed, e.g. by the garbage collection.
+	fakeDocker.ContainerList = nil
+	if !kubelet.syncInitContainers(&pod, kubecontainer.Pod{}, volumeMap{}, "container:net", map[string]empty{}) {
+		t.Errorf("expected the init containers still completed")
+	}
+	// Only the first sync looked for the exited init container.
+	verifyCalls(t, fakeDocker, []string{"list", "inspect_container"})
+
+	// The init containers of a new pod are run again.
+	kubelet.retainInitializedPods(map[string]empty{})
+	if kubelet.syncInitContainers(&pod, kubecontainer.Pod{}, volumeMap{}, "container:net", map[string]empty{}) {
+		t.Errorf("expected the init containers run again")
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+)
+
+// Metrics of the kubelet internals.
+var (
+	SyncPodsLatency = NewHistogram(
+		"kubelet_sync_pods_latency_seconds",
+		"Latency of a sync of all the pods bound to the node.",
+		DefBuckets)
+	PodWorkers = NewGauge(
+		"kubelet_pod_workers",
+		"Number of pods with a sync in progress.")
+	DockerOperationsLatency = NewHistogram(
+		"kubelet_docker_operations_latency_seconds",
+		"Latency of the calls to the docker API by operation.",
+		DefBuckets,
+		"operation")
+	DockerOperationsErrors = NewCounter(
+		"kubelet_docker_operations_errors_total",
+		"Number of failed calls to the docker API by operation.",
+		"operation")
+	ImagePullLatency = NewHistogram(
+		"kubelet_image_pull_latency_seconds",
+		"Latency of the image pulls.",
+		[]float64{1, 5, 10, 30, 60, 120, 300, 600})
+	NetworkSetupFailures = NewCounter(
+		"kubelet_network_setup_failures_total",
+		"Number of failed setups of the network of a pod by network mode.",
+		"mode")
+	ImageFilesystemUsage = NewGauge(
+		"kubelet_image_filesystem_usage_percent",
+		"Usage of the filesystem of the docker root directory in percent, as of the last image garbage collection.")
+	ImageGCRemovedImages = NewCounter(
+		"kubelet_image_gc_removed_images_total",
+		"Number of images removed by the image garbage collection.")
+	ImageGCFreedBytes = NewCounter(
+		"kubelet_image_gc_freed_bytes_total",
+		"Size of the images removed by the image garbage collection.")
+)
+
+// DefaultRegistry serves the metrics of the kubelet.
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	DefaultRegistry.Register(SyncPodsLatency)
+	DefaultRegistry.Register(PodWorkers)
+	DefaultRegistry.Register(DockerOperationsLatency)
+	DefaultRegistry.Register(DockerOperationsErrors)
+	DefaultRegistry.Register(ImagePullLatency)
+	DefaultRegistry.Register(NetworkSetupFailures)
+	DefaultRegistry.Register(ImageFilesystemUsage)
+	DefaultRegistry.Register(ImageGCRemovedImages)
+	DefaultRegistry.Register(ImageGCFreedBytes)
+}
+
+// Register adds c to the collectors of DefaultRegistry.
+func Register(c Collector) {
+	DefaultRegistry.Register(c)
+}
+
+// SinceInSeconds returns the time elapsed since start in seconds.
+func SinceInSeconds(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
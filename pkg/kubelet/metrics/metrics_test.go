@@ -0,0 +1,114 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	counter := NewCounter("test_errors_total", "Number of errors.", "operation")
+	gauge := NewGauge("test_workers", "Number of\nworkers.")
+	r.Register(counter)
+	r.Register(gauge)
+	r.Register(CollectorFunc(func() []Family {
+		return []Family{{
+			Name: "test_usage_bytes",
+			Type: GaugeType,
+			Samples: []Sample{
+				{Name: "test_usage_bytes", Labels: []Label{{Name: "pod", Value: `a"b\c`}}, Value: math.Inf(1)},
+			},
+		}}
+	}))
+	counter.Inc("list")
+	counter.Add(2, "list")
+	counter.Inc("create")
+	gauge.Set(3)
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `# HELP test_errors_total Number of errors.
+# TYPE test_errors_total counter
+test_errors_total{operation="create"} 1
+test_errors_total{operation="list"} 3
+# TYPE test_usage_bytes gauge
+test_usage_bytes{pod="a\"b\\c"} +Inf
+# HELP test_workers Number of\nworkers.
+# TYPE test_workers gauge
+test_workers 3
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram("test_latency_seconds", "Latency.", []float64{1, 5}, "operation")
+	h.Observe(0.5, "pull")
+	h.Observe(2, "pull")
+	h.Observe(10, "pull")
+
+	var buf bytes.Buffer
+	WriteText(&buf, h.Collect())
+	expected := `# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{operation="pull",le="1"} 1
+test_latency_seconds_bucket{operation="pull",le="5"} 2
+test_latency_seconds_bucket{operation="pull",le="+Inf"} 3
+test_latency_seconds_sum{operation="pull"} 12.5
+test_latency_seconds_count{operation="pull"} 3
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+func TestLabelValuesMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic on a wrong number of label values")
+		}
+	}()
+	NewCounter("test_total", "", "operation").Inc()
+}
+
+func TestInstallHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	InstallHandler(mux)
+	SyncPodsLatency.Observe(0.1)
+
+	req, err := http.NewRequest("GET", "/metrics", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "# TYPE kubelet_sync_pods_latency_seconds histogram\n") {
+		t.Errorf("expected the kubelet metrics, got %s", w.Body.String())
+	}
+}
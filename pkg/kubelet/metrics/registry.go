@@ -0,0 +1,195 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Type is the type of a metric family.
+type Type string
+
+const (
+	CounterType   Type = "counter"
+	GaugeType     Type = "gauge"
+	HistogramType Type = "histogram"
+)
+
+// Label is a label of a sample.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a metric family. Name is the name of the family, or of
+// one of its series such as <name>_bucket for histograms.
+type Sample struct {
+	Name   string
+	Labels []Label
+	Value  float64
+}
+
+// Family is a set of samples of the same metric.
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Samples []Sample
+}
+
+// Collector returns the current families of a set of metrics.
+type Collector interface {
+	Collect() []Family
+}
+
+// CollectorFunc is a function which implements Collector.
+type CollectorFunc func() []Family
+
+// Collect calls f().
+func (f CollectorFunc) Collect() []Family {
+	return f()
+}
+
+// Registry serves the metrics of the registered collectors.
+type Registry struct {
+	lock       sync.RWMutex
+	collectors []Collector
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds c to the collectors of r.
+func (r *Registry) Register(c Collector) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// Gather returns the families of all the collectors sorted by name. The samples of the
+// families with the same name are merged.
+func (r *Registry) Gather() []Family {
+	r.lock.RLock()
+	collectors := append([]Collector{}, r.collectors...)
+	r.lock.RUnlock()
+
+	byName := map[string]*Family{}
+	names := []string{}
+	for _, c := range collectors {
+		for _, f := range c.Collect() {
+			if existing, found := byName[f.Name]; found {
+				existing.Samples = append(existing.Samples, f.Samples...)
+				continue
+			}
+			family := f
+			byName[f.Name] = &family
+			names = append(names, f.Name)
+		}
+	}
+	sort.Strings(names)
+	families := make([]Family, 0, len(names))
+	for _, name := range names {
+		families = append(families, *byName[name])
+	}
+	return families
+}
+
+// WriteText writes the metrics of r to w in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	return WriteText(w, r.Gather())
+}
+
+// ServeHTTP serves the metrics of r.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	r.WriteText(w)
+}
+
+// WriteText writes families to w in the Prometheus text exposition format.
+func WriteText(w io.Writer, families []Family) error {
+	out := bufio.NewWriter(w)
+	for _, f := range families {
+		if len(f.Samples) == 0 {
+			continue
+		}
+		if f.Help != "" {
+			fmt.Fprintf(out, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+		}
+		fmt.Fprintf(out, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, s := range f.Samples {
+			out.WriteString(s.Name)
+			if len(s.Labels) != 0 {
+				out.WriteByte('{')
+				for i, l := range s.Labels {
+					if i > 0 {
+						out.WriteByte(',')
+					}
+					fmt.Fprintf(out, "%s=\"%s\"", l.Name, escapeLabelValue(l.Value))
+				}
+				out.WriteByte('}')
+			}
+			out.WriteByte(' ')
+			out.WriteString(formatValue(s.Value))
+			out.WriteByte('\n')
+		}
+	}
+	return out.Flush()
+}
+
+var helpEscaper = strings.NewReplacer("\\", `\\`, "\n", `\n`)
+var labelValueEscaper = strings.NewReplacer("\\", `\\`, "\n", `\n`, "\"", `\"`)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// mux is an interface describing the methods InstallHandler requires.
+type mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// InstallHandler registers a handler serving the metrics of DefaultRegistry on the path "/metrics" to mux.
+func InstallHandler(mux mux) {
+	mux.Handle("/metrics", DefaultRegistry)
+}
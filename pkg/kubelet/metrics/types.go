@@ -0,0 +1,202 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are the default upper bounds, in seconds, of the buckets of a histogram of latencies.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// vec keeps the series of a metric by the values of its labels.
+type vec struct {
+	name       string
+	help       string
+	labelNames []string
+}
+
+func (v *vec) key(labelValues []string) string {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metric %s has labels %v, got values %v", v.name, v.labelNames, labelValues))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+func (v *vec) labels(key string, extra ...Label) []Label {
+	labels := []Label{}
+	if len(v.labelNames) != 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			labels = append(labels, Label{v.labelNames[i], value})
+		}
+	}
+	return append(labels, extra...)
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Counter is a metric which only goes up, with one series per set of label values.
+type Counter struct {
+	vec
+	lock   sync.Mutex
+	values map[string]float64
+}
+
+// NewCounter creates a Counter with the given label names.
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{vec: vec{name, help, labelNames}, values: map[string]float64{}}
+}
+
+// Inc adds 1 to the series of labelValues.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds delta, which must not be negative, to the series of labelValues.
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("counter %s cannot decrease", c.name))
+	}
+	key := c.key(labelValues)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.values[key] += delta
+}
+
+// Collect implements Collector.
+func (c *Counter) Collect() []Family {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	f := Family{Name: c.name, Help: c.help, Type: CounterType}
+	for _, key := range sortedKeys(c.values) {
+		f.Samples = append(f.Samples, Sample{c.name, c.labels(key), c.values[key]})
+	}
+	return []Family{f}
+}
+
+// Gauge is a metric which goes up and down, with one series per set of label values.
+type Gauge struct {
+	vec
+	lock   sync.Mutex
+	values map[string]float64
+}
+
+// NewGauge creates a Gauge with the given label names.
+func NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{vec: vec{name, help, labelNames}, values: map[string]float64{}}
+}
+
+// Set sets the series of labelValues to value.
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	key := g.key(labelValues)
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.values[key] = value
+}
+
+// Collect implements Collector.
+func (g *Gauge) Collect() []Family {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	f := Family{Name: g.name, Help: g.help, Type: GaugeType}
+	for _, key := range sortedKeys(g.values) {
+		f.Samples = append(f.Samples, Sample{g.name, g.labels(key), g.values[key]})
+	}
+	return []Family{f}
+}
+
+type histogramSeries struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// Histogram counts observations in buckets, with one series per set of label values.
+type Histogram struct {
+	vec
+	upperBounds []float64
+	lock        sync.Mutex
+	series      map[string]*histogramSeries
+}
+
+// NewHistogram creates a Histogram whose buckets have the given increasing upper bounds.
+func NewHistogram(name, help string, upperBounds []float64, labelNames ...string) *Histogram {
+	for i := 1; i < len(upperBounds); i++ {
+		if upperBounds[i] <= upperBounds[i-1] {
+			panic(fmt.Sprintf("histogram %s has unsorted buckets %v", name, upperBounds))
+		}
+	}
+	return &Histogram{
+		vec:         vec{name, help, labelNames},
+		upperBounds: upperBounds,
+		series:      map[string]*histogramSeries{},
+	}
+}
+
+// Observe adds value to the series of labelValues.
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	s, found := h.series[key]
+	if !found {
+		s = &histogramSeries{buckets: make([]uint64, len(h.upperBounds))}
+		h.series[key] = s
+	}
+	for i, bound := range h.upperBounds {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+// Collect implements Collector.
+func (h *Histogram) Collect() []Family {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	f := Family{Name: h.name, Help: h.help, Type: HistogramType}
+	for _, key := range keys {
+		s := h.series[key]
+		for i, bound := range h.upperBounds {
+			le := Label{"le", strconv.FormatFloat(bound, 'g', -1, 64)}
+			f.Samples = append(f.Samples, Sample{h.name + "_bucket", h.labels(key, le), float64(s.buckets[i])})
+		}
+		f.Samples = append(f.Samples,
+			Sample{h.name + "_bucket", h.labels(key, Label{"le", "+Inf"}), float64(s.count)},
+			Sample{h.name + "_sum", h.labels(key), s.sum},
+			Sample{h.name + "_count", h.labels(key), float64(s.count)})
+	}
+	return []Family{f}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"hash/adler32"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+)
+
+// ConfigHashAnnotationKey is the annotation of a mirror pod with the hash of the pod it mirrors.
+const ConfigHashAnnotationKey = "kubernetes/config.hash"
+
+// mirrorSources are the sources of the pods the kubelet runs on its own, whose pods are
+// mirrored in the apiserver to be seen there and counted by the scheduler.
+var mirrorSources = util.NewStringSet("file", "http")
+
+func mirrorKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// makeMirrorPod returns the mirror of a pod the host runs from its own source.
+func makeMirrorPod(pod *api.BoundPod, host string) *api.Pod {
+	hash := adler32.New()
+	fmt.Fprintf(hash, "%#v %#v", pod.Labels, pod.Spec)
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Labels:    pod.Labels,
+			Annotations: map[string]string{
+				ConfigSourceAnnotationKey: pod.Annotations[ConfigSourceAnnotationKey],
+				ConfigHashAnnotationKey:   strconv.FormatUint(uint64(hash.Sum32()), 16),
+			},
+		},
+		Spec:   pod.Spec,
+		Status: api.PodStatus{Host: host},
+	}
+}
+
+// syncMirrorPods creates the mirrors of the pods from the sources of the kubelet, and
+// deletes the mirrors of the pods it no longer runs. A mirror is created again when its
+// pod changes.
+func (kl *Kubelet) syncMirrorPods(pods []api.BoundPod) {
+	kl.kubeLock.RLock()
+	c := kl.kubeClient
+	kl.kubeLock.RUnlock()
+	if c == nil {
+		return
+	}
+	if kl.mirrorPods == nil {
+		mirrors, err := c.MirrorPods(api.NamespaceAll).List(kl.hostname)
+		if err != nil {
+			glog.Errorf("Failed to list the mirror pods of %s: %v", kl.hostname, err)
+			return
+		}
+		kl.mirrorPods = make(map[string]*api.Pod)
+		for i := range mirrors.Items {
+			mirror := &mirrors.Items[i]
+			kl.mirrorPods[mirrorKey(mirror.Namespace, mirror.Name)] = mirror
+		}
+	}
+
+	desired := make(map[string]*api.Pod)
+	for i := range pods {
+		if mirrorSources.Has(pods[i].Annotations[ConfigSourceAnnotationKey]) {
+			mirror := makeMirrorPod(&pods[i], kl.hostname)
+			desired[mirrorKey(mirror.Namespace, mirror.Name)] = mirror
+		}
+	}
+	for key, mirror := range kl.mirrorPods {
+		if pod, found := desired[key]; found && pod.Annotations[ConfigHashAnnotationKey] == mirror.Annotations[ConfigHashAnnotationKey] {
+			continue
+		}
+		if err := c.MirrorPods(mirror.Namespace).Delete(mirror.Name); err != nil && !errors.IsNotFound(err) {
+			glog.Errorf("Failed to delete the mirror pod %s: %v", key, err)
+			continue
+		}
+		delete(kl.mirrorPods, key)
+	}
+	for key, mirror := range desired {
+		if _, found := kl.mirrorPods[key]; found {
+			continue
+		}
+		if _, err := c.MirrorPods(mirror.Namespace).Create(mirror); err != nil && !errors.IsAlreadyExists(err) {
+			glog.Errorf("Failed to create the mirror pod %s: %v", key, err)
+			continue
+		}
+		kl.mirrorPods[key] = mirror
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+)
+
+func newSourcePod(name, source, image string) api.BoundPod {
+	return api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        name,
+			Namespace:   api.NamespaceDefault,
+			Annotations: map[string]string{ConfigSourceAnnotationKey: source},
+		},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Name: "bar", Image: image}},
+		},
+	}
+}
+
+func mirrorActions(actions []client.FakeAction) []string {
+	result := []string{}
+	for _, action := range actions {
+		value := action.Value
+		if pod, ok := value.(*api.Pod); ok {
+			value = pod.Name
+		}
+		result = append(result, action.Action+" "+value.(string))
+	}
+	return result
+}
+
+func TestSyncMirrorPods(t *testing.T) {
+	kubelet, _, _ := newTestKubelet(t)
+	kubelet.hostname = "machine"
+	stale := makeMirrorPod(&api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "stale", Namespace: api.NamespaceDefault}}, "machine")
+	fakeClient := &client.Fake{MirrorPodsList: api.PodList{Items: []api.Pod{*stale}}}
+	kubelet.SetKubeClient(fakeClient)
+
+	pods := []api.BoundPod{newSourcePod("foo", "file", "test"), newSourcePod("bar", "etcd", "test")}
+	kubelet.syncMirrorPods(pods)
+	expected := []string{"list-mirror-pods machine", "delete-mirror-pod stale", "create-mirror-pod foo"}
+	if actions := mirrorActions(fakeClient.Actions); !reflect.DeepEqual(expected, actions) {
+		t.Errorf("Expected %v, got %v", expected, actions)
+	}
+	mirror := fakeClient.Actions[2].Value.(*api.Pod)
+	if mirror.Status.Host != "machine" || mirror.Annotations[ConfigSourceAnnotationKey] != "file" || !api.IsMirrorPod(mirror) {
+		t.Errorf("Unexpected mirror pod: %#v", mirror)
+	}
+
+	// an unchanged pod keeps its mirror
+	fakeClient.Actions = nil
+	kubelet.syncMirrorPods(pods)
+	if len(fakeClient.Actions) != 0 {
+		t.Errorf("Unexpected actions: %v", mirrorActions(fakeClient.Actions))
+	}
+
+	// a changed pod has its mirror created again, a removed one has it deleted
+	kubelet.syncMirrorPods([]api.BoundPod{newSourcePod("foo", "file", "test2")})
+	kubelet.syncMirrorPods([]api.BoundPod{})
+	expected = []string{"delete-mirror-pod foo", "create-mirror-pod foo", "delete-mirror-pod foo"}
+	if actions := mirrorActions(fakeClient.Actions); !reflect.DeepEqual(expected, actions) {
+		t.Errorf("Expected %v, got %v", expected, actions)
+	}
+}
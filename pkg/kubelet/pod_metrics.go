@@ -0,0 +1,208 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
+	"github.com/golang/glog"
+	"github.com/google/cadvisor/info"
+)
+
+// podMetricsCollector collects the usage, the restarts and the probe results of the
+// containers of the pods bound to the kubelet when the metrics are scraped.
+type podMetricsCollector struct {
+	kl *Kubelet
+}
+
+func newPodMetricsCollector(kl *Kubelet) metrics.Collector {
+	return &podMetricsCollector{kl}
+}
+
+// familyBuilder accumulates samples into families, in the order the families are first seen.
+type familyBuilder struct {
+	families []metrics.Family
+	index    map[string]int
+}
+
+func (b *familyBuilder) add(name, help string, t metrics.Type, labels []metrics.Label, value float64) {
+	if b.index == nil {
+		b.index = map[string]int{}
+	}
+	i, found := b.index[name]
+	if !found {
+		i = len(b.families)
+		b.index[name] = i
+		b.families = append(b.families, metrics.Family{Name: name, Help: help, Type: t})
+	}
+	b.families[i].Samples = append(b.families[i].Samples, metrics.Sample{Name: name, Labels: labels, Value: value})
+}
+
+func withLabel(labels []metrics.Label, name, value string) []metrics.Label {
+	return append(append([]metrics.Label{}, labels...), metrics.Label{Name: name, Value: value})
+}
+
+// Collect implements metrics.Collector.
+func (c *podMetricsCollector) Collect() []metrics.Family {
+	kl := c.kl
+	running, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	if err != nil {
+		glog.Errorf("Error listing containers: %v", err)
+		return nil
+	}
+	all, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, true)
+	if err != nil {
+		glog.Errorf("Error listing containers: %v", err)
+		return nil
+	}
+	instances := map[podContainer]int{}
+	for _, container := range all {
+		podFullName, uuid, containerName, _ := dockertools.ParseDockerName(container.Names[0])
+		instances[podContainer{podFullName, uuid, containerName}]++
+	}
+
+	b := &familyBuilder{}
+	pods := kl.getPods()
+	for i := range pods {
+		pod := &pods[i]
+		podFullName := GetPodFullName(pod)
+		podLabels := []metrics.Label{{Name: "namespace", Value: pod.Namespace}, {Name: "pod", Value: pod.Name}}
+		containers := []*info.ContainerStats{}
+		filesystems := []info.FsStats{}
+		for _, container := range pod.Spec.Containers {
+			labels := withLabel(podLabels, "container", container.Name)
+			if n := instances[podContainer{podFullName, pod.UID, container.Name}]; n > 0 {
+				b.add("kubelet_container_restarts", "Number of restarts of the container among its instances kept by docker.",
+					metrics.GaugeType, labels, float64(n-1))
+			}
+			if kl.prober != nil {
+				liveness, readiness := kl.prober.results(podFullName, pod.UID, container.Name)
+				addProbeResult(b, labels, livenessProbe, liveness)
+				addProbeResult(b, labels, readinessProbe, readiness)
+			}
+
+			dockerContainer, found, _ := running.FindPodContainer(podFullName, pod.UID, container.Name)
+			if !found {
+				continue
+			}
+			stats, err := kl.currentContainerStats(dockerContainer.ID)
+			if err != nil {
+				glog.V(4).Infof("Failed to get the stats of pod %s container %s: %v", podFullName, container.Name, err)
+				continue
+			}
+			containers = append(containers, stats)
+			addUsage(b, "container", labels, stats)
+			if container.Disk > 0 {
+				fs, err := getDiskQuotaUsage(container.Name)
+				if err != nil {
+					glog.V(4).Infof("Failed to get the disk usage of pod %s container %s: %v", podFullName, container.Name, err)
+					continue
+				}
+				filesystems = append(filesystems, *fs)
+				addDiskQuotaUsage(b, "container", labels, fs)
+			}
+		}
+		if len(containers) == 0 {
+			continue
+		}
+
+		var network *info.NetworkStats
+		if netContainer, found, _ := running.FindPodContainer(podFullName, pod.UID, networkContainerName); found {
+			if stats, err := kl.currentContainerStats(netContainer.ID); err == nil {
+				network = stats.Network
+			}
+		}
+		stats := aggregateContainerStats(time.Now(), containers, network, filesystems)
+		addUsage(b, "pod", podLabels, stats)
+		for i := range stats.Filesystem {
+			addDiskQuotaUsage(b, "pod", podLabels, &stats.Filesystem[i])
+		}
+		if stats.Network != nil {
+			b.add("pod_network_receive_bytes_total", "Bytes received by the network container of the pod.",
+				metrics.CounterType, podLabels, float64(stats.Network.RxBytes))
+			b.add("pod_network_transmit_bytes_total", "Bytes transmitted by the network container of the pod.",
+				metrics.CounterType, podLabels, float64(stats.Network.TxBytes))
+			b.add("pod_network_receive_errors_total", "Receive errors of the network container of the pod.",
+				metrics.CounterType, podLabels, float64(stats.Network.RxErrors))
+			b.add("pod_network_transmit_errors_total", "Transmit errors of the network container of the pod.",
+				metrics.CounterType, podLabels, float64(stats.Network.TxErrors))
+		}
+	}
+	return b.families
+}
+
+// addProbeResult adds 1 if the last run of a probe succeeded and 0 otherwise, nothing if the probe is not run.
+func addProbeResult(b *familyBuilder, labels []metrics.Label, kind probeKind, result *api.ProbeResult) {
+	if result == nil || result.Result == "" {
+		return
+	}
+	value := 0.0
+	if result.Result == api.ProbeSuccess {
+		value = 1
+	}
+	b.add("kubelet_container_probe_success", "Whether the last run of a probe of the container succeeded.",
+		metrics.GaugeType, withLabel(labels, "probe", string(kind)), value)
+}
+
+// addUsage adds the cpu, memory and blkio usage in stats to the <prefix>_* families.
+func addUsage(b *familyBuilder, prefix string, labels []metrics.Label, stats *info.ContainerStats) {
+	if stats.Cpu != nil {
+		b.add(prefix+"_cpu_usage_seconds_total", "Cumulative cpu time consumed.",
+			metrics.CounterType, labels, float64(stats.Cpu.Usage.Total)/float64(time.Second))
+		b.add(prefix+"_cpu_user_seconds_total", "Cumulative cpu time consumed in user space.",
+			metrics.CounterType, labels, float64(stats.Cpu.Usage.User)/float64(time.Second))
+		b.add(prefix+"_cpu_system_seconds_total", "Cumulative cpu time consumed in kernel space.",
+			metrics.CounterType, labels, float64(stats.Cpu.Usage.System)/float64(time.Second))
+	}
+	if stats.Memory != nil {
+		b.add(prefix+"_memory_usage_bytes", "Current memory usage, including the page cache.",
+			metrics.GaugeType, labels, float64(stats.Memory.Usage))
+		b.add(prefix+"_memory_working_set_bytes", "Current working set.",
+			metrics.GaugeType, labels, float64(stats.Memory.WorkingSet))
+		if stats.Memory.Limit != 0 {
+			b.add(prefix+"_memory_limit_bytes", "Memory limit.",
+				metrics.GaugeType, labels, float64(stats.Memory.Limit))
+		}
+	}
+	addPerDiskUsage(b, prefix+"_blkio_io_service_bytes_total", "Cumulative bytes transferred to and from the block devices.", labels, stats.DiskIo.IoServiceBytes)
+	addPerDiskUsage(b, prefix+"_blkio_io_serviced_total", "Cumulative io operations on the block devices.", labels, stats.DiskIo.IoServiced)
+}
+
+func addPerDiskUsage(b *familyBuilder, name, help string, labels []metrics.Label, disks []info.PerDiskStats) {
+	for _, disk := range disks {
+		device := withLabel(labels, "device", fmt.Sprintf("%d:%d", disk.Major, disk.Minor))
+		operations := make([]string, 0, len(disk.Stats))
+		for operation := range disk.Stats {
+			operations = append(operations, operation)
+		}
+		sort.Strings(operations)
+		for _, operation := range operations {
+			b.add(name, help, metrics.CounterType, withLabel(device, "operation", operation), float64(disk.Stats[operation]))
+		}
+	}
+}
+
+func addDiskQuotaUsage(b *familyBuilder, prefix string, labels []metrics.Label, fs *info.FsStats) {
+	device := withLabel(labels, "device", fs.Device)
+	b.add(prefix+"_fs_quota_usage_bytes", "Usage of the disk quota.", metrics.GaugeType, device, float64(fs.Usage))
+	b.add(prefix+"_fs_quota_limit_bytes", "Limit of the disk quota.", metrics.GaugeType, device, float64(fs.Limit))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/google/cadvisor/info"
+)
+
+func TestPodMetricsCollector(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	kubelet.pods = []api.BoundPod{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "bar", UID: "12345", Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"}},
+			Spec: api.PodSpec{
+				Containers: []api.Container{{Name: "web"}},
+			},
+		},
+	}
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1234", Names: []string{"/k8s_web.1_foo.bar.etcd_12345_1"}},
+		{ID: "9012", Names: []string{"/k8s_net.1_foo.bar.etcd_12345_3"}},
+	}
+	mockCadvisor := &mockCadvisorClient{}
+	req := &info.ContainerInfoRequest{NumStats: 1}
+	webStats := &info.ContainerStats{
+		Cpu:    newCpuStats(2000000000, 1500000000, 500000000, nil),
+		Memory: &info.MemoryStats{Usage: 100, WorkingSet: 50},
+		DiskIo: info.DiskIoStats{
+			IoServiceBytes: []info.PerDiskStats{{Major: 8, Minor: 0, Stats: map[string]uint64{"Read": 4096}}},
+		},
+	}
+	mockCadvisor.On("ContainerInfo", "/docker/1234", req).Return(&info.ContainerInfo{Stats: []*info.ContainerStats{webStats}}, nil)
+	mockCadvisor.On("ContainerInfo", "/docker/9012", req).Return(&info.ContainerInfo{
+		Stats: []*info.ContainerStats{{Network: &info.NetworkStats{RxBytes: 42}}},
+	}, nil)
+	kubelet.cadvisorClient = mockCadvisor
+	kubelet.prober = newProber(nil, kubelet.readiness)
+	kubelet.prober.containers[podContainer{"foo.bar.etcd", "12345", "web"}] = &containerProbes{
+		readiness: &api.ProbeResult{Result: api.ProbeFailure},
+	}
+
+	var buf bytes.Buffer
+	if err := metrics.WriteText(&buf, newPodMetricsCollector(kubelet).Collect()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		`kubelet_container_restarts{namespace="bar",pod="foo",container="web"} 0`,
+		`kubelet_container_probe_success{namespace="bar",pod="foo",container="web",probe="readiness"} 0`,
+		`container_cpu_usage_seconds_total{namespace="bar",pod="foo",container="web"} 2`,
+		`container_memory_working_set_bytes{namespace="bar",pod="foo",container="web"} 50`,
+		`container_blkio_io_service_bytes_total{namespace="bar",pod="foo",container="web",device="8:0",operation="Read"} 4096`,
+		`pod_memory_usage_bytes{namespace="bar",pod="foo"} 100`,
+		`pod_network_receive_bytes_total{namespace="bar",pod="foo"} 42`,
+	} {
+		if !strings.Contains(out, expected+"\n") {
+			t.Errorf("expected %s in\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, `probe="liveness"`) {
+		t.Errorf("unexpected result of a liveness probe which is not run in\n%s", out)
+	}
+	mockCadvisor.AssertExpectations(t)
+}
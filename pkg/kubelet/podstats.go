@@ -0,0 +1,334 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/golang/glog"
+	"github.com/google/cadvisor/info"
+)
+
+// Period between two samples of the pod stats history.
+const podStatsInterval = 10 * time.Second
+
+// podStatsHistory keeps the usage of every pod, aggregated over its containers, for a window of time.
+type podStatsHistory struct {
+	lock    sync.RWMutex
+	window  time.Duration
+	samples map[string][]*info.ContainerStats
+}
+
+func newPodStatsHistory(window time.Duration) *podStatsHistory {
+	return &podStatsHistory{
+		window:  window,
+		samples: map[string][]*info.ContainerStats{},
+	}
+}
+
+// add appends a sample of the pod uid and drops the ones which fell out of the window.
+func (h *podStatsHistory) add(uid string, stats *info.ContainerStats) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	samples := append(h.samples[uid], stats)
+	start := stats.Timestamp.Add(-h.window)
+	i := 0
+	for i < len(samples) && samples[i].Timestamp.Before(start) {
+		i++
+	}
+	h.samples[uid] = samples[i:]
+}
+
+// get returns the numStats most recent samples of the pod uid, oldest first, or all of them if numStats is not positive.
+func (h *podStatsHistory) get(uid string, numStats int) []*info.ContainerStats {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	samples := h.samples[uid]
+	if numStats > 0 && numStats < len(samples) {
+		samples = samples[len(samples)-numStats:]
+	}
+	return append([]*info.ContainerStats{}, samples...)
+}
+
+// retain forgets the pods whose uid is not in uids.
+func (h *podStatsHistory) retain(uids util.StringSet) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for uid := range h.samples {
+		if !uids.Has(uid) {
+			delete(h.samples, uid)
+		}
+	}
+}
+
+// collectPodStats adds a sample of every pod to the stats history.
+func (kl *Kubelet) collectPodStats() {
+	runningPods, err := kl.runtime.GetPods(false)
+	if err != nil {
+		glog.Errorf("Error listing containers: %v", err)
+		return
+	}
+	uids := util.NewStringSet()
+	pods := kl.getPods()
+	for i := range pods {
+		pod := &pods[i]
+		uids.Insert(pod.UID)
+		stats, err := kl.samplePodStats(pod, runningPods.FindPod(GetPodFullName(pod), pod.UID))
+		if err != nil {
+			glog.V(4).Infof("Failed to sample the stats of pod %s: %v", GetPodFullName(pod), err)
+			continue
+		}
+		kl.podStats.add(pod.UID, stats)
+	}
+	kl.podStats.retain(uids)
+}
+
+// samplePodStats returns the current usage of pod. The network usage is the one of the network container.
+func (kl *Kubelet) samplePodStats(pod *api.BoundPod, runningPod kubecontainer.Pod) (*info.ContainerStats, error) {
+	podFullName := GetPodFullName(pod)
+	containers := []*info.ContainerStats{}
+	filesystems := []info.FsStats{}
+	for _, container := range pod.Spec.Containers {
+		runningContainer := runningPod.FindContainerByName(container.Name)
+		if runningContainer == nil {
+			continue
+		}
+		stats, err := kl.currentContainerStats(runningContainer.ID)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, stats)
+		if container.Disk > 0 {
+			fs, err := getDiskQuotaUsage(container.Name)
+			if err != nil {
+				glog.V(4).Infof("Failed to get the disk usage of pod %s container %s: %v", podFullName, container.Name, err)
+				continue
+			}
+			filesystems = append(filesystems, *fs)
+		}
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no running container")
+	}
+
+	var network *info.NetworkStats
+	if netContainer := runningPod.FindContainerByName(networkContainerName); netContainer != nil {
+		if stats, err := kl.currentContainerStats(netContainer.ID); err == nil {
+			network = stats.Network
+		}
+	}
+	return aggregateContainerStats(time.Now(), containers, network, filesystems), nil
+}
+
+// currentContainerStats returns the latest usage of a docker container, from cadvisor if it is
+// connected or else from the cgroup of the container, which only tells the memory usage.
+func (kl *Kubelet) currentContainerStats(id string) (*info.ContainerStats, error) {
+	var cinfo *info.ContainerInfo
+	var err error
+	if cc := kl.GetCadvisorClient(); cc != nil {
+		cinfo, err = kl.statsFromContainerPath(cc, fmt.Sprintf("/docker/%s", id), &info.ContainerInfoRequest{NumStats: 1})
+	} else {
+		cinfo, err = docker.GetContainerInfo(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(cinfo.Stats) == 0 {
+		return nil, fmt.Errorf("no stats of container %s", id)
+	}
+	return cinfo.Stats[len(cinfo.Stats)-1], nil
+}
+
+// aggregateContainerStats sums the usage of the containers of a pod.
+func aggregateContainerStats(timestamp time.Time, containers []*info.ContainerStats, network *info.NetworkStats, filesystems []info.FsStats) *info.ContainerStats {
+	pod := &info.ContainerStats{Timestamp: timestamp}
+	for _, c := range containers {
+		if c.Cpu != nil {
+			if pod.Cpu == nil {
+				pod.Cpu = &info.CpuStats{}
+			}
+			pod.Cpu.Usage.Total += c.Cpu.Usage.Total
+			pod.Cpu.Usage.User += c.Cpu.Usage.User
+			pod.Cpu.Usage.System += c.Cpu.Usage.System
+			for i, usage := range c.Cpu.Usage.PerCpu {
+				if i == len(pod.Cpu.Usage.PerCpu) {
+					pod.Cpu.Usage.PerCpu = append(pod.Cpu.Usage.PerCpu, 0)
+				}
+				pod.Cpu.Usage.PerCpu[i] += usage
+			}
+			pod.Cpu.Load += c.Cpu.Load
+		}
+		if c.Memory != nil {
+			if pod.Memory == nil {
+				pod.Memory = &info.MemoryStats{Stats: map[string]uint64{}}
+			}
+			pod.Memory.Limit += c.Memory.Limit
+			pod.Memory.Usage += c.Memory.Usage
+			pod.Memory.WorkingSet += c.Memory.WorkingSet
+			pod.Memory.ContainerData.Pgfault += c.Memory.ContainerData.Pgfault
+			pod.Memory.ContainerData.Pgmajfault += c.Memory.ContainerData.Pgmajfault
+			pod.Memory.HierarchicalData.Pgfault += c.Memory.HierarchicalData.Pgfault
+			pod.Memory.HierarchicalData.Pgmajfault += c.Memory.HierarchicalData.Pgmajfault
+			for k, v := range c.Memory.Stats {
+				pod.Memory.Stats[k] += v
+			}
+		}
+		pod.DiskIo.IoServiceBytes = addPerDiskStats(pod.DiskIo.IoServiceBytes, c.DiskIo.IoServiceBytes)
+		pod.DiskIo.IoServiced = addPerDiskStats(pod.DiskIo.IoServiced, c.DiskIo.IoServiced)
+		pod.DiskIo.IoQueued = addPerDiskStats(pod.DiskIo.IoQueued, c.DiskIo.IoQueued)
+		pod.DiskIo.Sectors = addPerDiskStats(pod.DiskIo.Sectors, c.DiskIo.Sectors)
+	}
+	if network != nil {
+		stats := *network
+		pod.Network = &stats
+	}
+	for _, fs := range filesystems {
+		found := false
+		for i := range pod.Filesystem {
+			if pod.Filesystem[i].Device == fs.Device {
+				pod.Filesystem[i].Limit += fs.Limit
+				pod.Filesystem[i].Usage += fs.Usage
+				found = true
+				break
+			}
+		}
+		if !found {
+			pod.Filesystem = append(pod.Filesystem, fs)
+		}
+	}
+	return pod
+}
+
+// addPerDiskStats adds the counters of stats to the ones of the same device in sum.
+func addPerDiskStats(sum, stats []info.PerDiskStats) []info.PerDiskStats {
+	for _, disk := range stats {
+		found := false
+		for i := range sum {
+			if sum[i].Major == disk.Major && sum[i].Minor == disk.Minor {
+				for k, v := range disk.Stats {
+					sum[i].Stats[k] += v
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			counters := map[string]uint64{}
+			for k, v := range disk.Stats {
+				counters[k] = v
+			}
+			sum = append(sum, info.PerDiskStats{Major: disk.Major, Minor: disk.Minor, Stats: counters})
+		}
+	}
+	return sum
+}
+
+// getDiskQuotaUsage returns the usage of the xfs project quota set by addDiskQuota for a container.
+func getDiskQuotaUsage(name string) (*info.FsStats, error) {
+	out, err := exec.Command("xfs_quota", "-x", "-c", fmt.Sprintf("quota -p -N -b %s", name), "/data").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, string(out))
+	}
+	return parseDiskQuotaUsage(string(out))
+}
+
+// parseDiskQuotaUsage parses the output of "xfs_quota -c 'quota -p -N -b'", whose lines are
+// "<device> <used> <soft> <hard> <warn> <grace> <mount point>" in KiB.
+func parseDiskQuotaUsage(out string) (*info.FsStats, error) {
+	fields := strings.Fields(out)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("unexpected quota report %q", out)
+	}
+	used, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected quota report %q: %v", out, err)
+	}
+	hard, err := strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected quota report %q: %v", out, err)
+	}
+	return &info.FsStats{Device: fields[0], Usage: used * 1024, Limit: hard * 1024}, nil
+}
+
+// GetPodStats returns the usage of a pod aggregated over its containers. It holds the
+// req.NumStats most recent samples of the stats history, or a single current sample if
+// the history is disabled or the pod was not sampled yet.
+func (kl *Kubelet) GetPodStats(namespace, name string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	var pod *api.BoundPod
+	pods := kl.getPods()
+	for i := range pods {
+		p := &pods[i]
+		if p.Namespace == namespace && p.Name == name {
+			pod = p
+			break
+		}
+	}
+	if pod == nil {
+		glog.Errorf("Can't find pod: %s/%s", namespace, name)
+		return nil, dockertools.ErrNoContainersInPod
+	}
+
+	numStats := 0
+	if req != nil {
+		numStats = req.NumStats
+	}
+	var samples []*info.ContainerStats
+	if kl.podStats != nil {
+		samples = kl.podStats.get(pod.UID, numStats)
+	}
+	if len(samples) == 0 {
+		runningPods, err := kl.runtime.GetPods(false)
+		if err != nil {
+			glog.Errorf("Error listing containers: %v", err)
+			return nil, err
+		}
+		stats, err := kl.samplePodStats(pod, runningPods.FindPod(GetPodFullName(pod), pod.UID))
+		if err != nil {
+			return nil, err
+		}
+		samples = []*info.ContainerStats{stats}
+	}
+
+	last := samples[len(samples)-1]
+	cinfo := &info.ContainerInfo{
+		ContainerReference: info.ContainerReference{
+			Name:    GetPodFullName(pod),
+			Aliases: []string{pod.UID},
+		},
+		Spec: info.ContainerSpec{
+			HasCpu:        last.Cpu != nil,
+			HasMemory:     last.Memory != nil,
+			HasNetwork:    last.Network != nil,
+			HasFilesystem: len(last.Filesystem) != 0,
+		},
+		Stats: samples,
+	}
+	if last.Memory != nil {
+		cinfo.Spec.Memory.Limit = last.Memory.Limit
+	}
+	return cinfo, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/google/cadvisor/info"
+)
+
+func TestPodStatsHistory(t *testing.T) {
+	now := time.Now()
+	h := newPodStatsHistory(time.Minute)
+	for i := 0; i < 10; i++ {
+		h.add("12345", &info.ContainerStats{Timestamp: now.Add(time.Duration(i) * 10 * time.Second)})
+	}
+	h.add("67890", &info.ContainerStats{Timestamp: now})
+
+	samples := h.get("12345", 0)
+	if len(samples) != 7 {
+		t.Fatalf("expected the samples of the last minute, got %d", len(samples))
+	}
+	if !samples[0].Timestamp.Equal(now.Add(30 * time.Second)) {
+		t.Errorf("unexpected oldest sample %v", samples[0].Timestamp)
+	}
+	samples = h.get("12345", 2)
+	if len(samples) != 2 || !samples[1].Timestamp.Equal(now.Add(90*time.Second)) {
+		t.Errorf("expected the 2 most recent samples, got %#v", samples)
+	}
+
+	h.retain(util.NewStringSet("67890"))
+	if samples := h.get("12345", 0); len(samples) != 0 {
+		t.Errorf("expected the samples of a removed pod to be dropped, got %#v", samples)
+	}
+	if samples := h.get("67890", 0); len(samples) != 1 {
+		t.Errorf("expected the samples of a retained pod to be kept, got %#v", samples)
+	}
+}
+
+func newCpuStats(total, user, system uint64, perCpu []uint64) *info.CpuStats {
+	stats := &info.CpuStats{}
+	stats.Usage.Total = total
+	stats.Usage.User = user
+	stats.Usage.System = system
+	stats.Usage.PerCpu = perCpu
+	return stats
+}
+
+func TestAggregateContainerStats(t *testing.T) {
+	now := time.Now()
+	containers := []*info.ContainerStats{
+		{
+			Cpu:    newCpuStats(10, 6, 4, []uint64{10}),
+			Memory: &info.MemoryStats{Usage: 100, WorkingSet: 50, Limit: 1000},
+			DiskIo: info.DiskIoStats{
+				IoServiceBytes: []info.PerDiskStats{{Major: 8, Minor: 0, Stats: map[string]uint64{"Read": 1}}},
+			},
+		},
+		{
+			Cpu:    newCpuStats(20, 15, 5, []uint64{5, 15}),
+			Memory: &info.MemoryStats{Usage: 200, WorkingSet: 150, Limit: 2000},
+			DiskIo: info.DiskIoStats{
+				IoServiceBytes: []info.PerDiskStats{
+					{Major: 8, Minor: 0, Stats: map[string]uint64{"Read": 2}},
+					{Major: 8, Minor: 16, Stats: map[string]uint64{"Read": 4}},
+				},
+			},
+		},
+	}
+	network := &info.NetworkStats{RxBytes: 42}
+	filesystems := []info.FsStats{
+		{Device: "/dev/sdb", Usage: 1024, Limit: 4096},
+		{Device: "/dev/sdb", Usage: 2048, Limit: 4096},
+	}
+
+	stats := aggregateContainerStats(now, containers, network, filesystems)
+	expected := &info.ContainerStats{
+		Timestamp: now,
+		Cpu:       newCpuStats(30, 21, 9, []uint64{15, 15}),
+		Memory:    &info.MemoryStats{Usage: 300, WorkingSet: 200, Limit: 3000, Stats: map[string]uint64{}},
+		Network:   &info.NetworkStats{RxBytes: 42},
+		DiskIo: info.DiskIoStats{
+			IoServiceBytes: []info.PerDiskStats{
+				{Major: 8, Minor: 0, Stats: map[string]uint64{"Read": 3}},
+				{Major: 8, Minor: 16, Stats: map[string]uint64{"Read": 4}},
+			},
+		},
+		Filesystem: []info.FsStats{{Device: "/dev/sdb", Usage: 3072, Limit: 8192}},
+	}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Errorf("expected %#v, got %#v", expected, stats)
+	}
+	if containers[0].DiskIo.IoServiceBytes[0].Stats["Read"] != 1 {
+		t.Errorf("unexpected change of the container stats")
+	}
+}
+
+func TestParseDiskQuotaUsage(t *testing.T) {
+	fs, err := parseDiskQuotaUsage("/dev/sdb1    102400       0  10485760  00 [--------] /data\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &info.FsStats{Device: "/dev/sdb1", Usage: 102400 * 1024, Limit: 10485760 * 1024}
+	if !reflect.DeepEqual(fs, expected) {
+		t.Errorf("expected %#v, got %#v", expected, fs)
+	}
+	if _, err := parseDiskQuotaUsage(""); err == nil {
+		t.Errorf("expected an error on an empty report")
+	}
+}
+
+func TestGetPodStatsFromHistory(t *testing.T) {
+	kubelet, _, _ := newTestKubelet(t)
+	kubelet.pods = []api.BoundPod{
+		{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "bar", UID: "12345"}},
+	}
+	kubelet.podStats = newPodStatsHistory(time.Minute)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		kubelet.podStats.add("12345", &info.ContainerStats{
+			Timestamp: now.Add(time.Duration(i) * time.Second),
+			Memory:    &info.MemoryStats{Usage: uint64(i), Limit: 1000},
+		})
+	}
+
+	stats, err := kubelet.GetPodStats("bar", "foo", &info.ContainerInfoRequest{NumStats: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats.Stats) != 2 || stats.Stats[1].Memory.Usage != 2 {
+		t.Errorf("expected the 2 most recent samples, got %#v", stats.Stats)
+	}
+	if !reflect.DeepEqual(stats.Aliases, []string{"12345"}) {
+		t.Errorf("expected the pod uid as alias, got %v", stats.Aliases)
+	}
+	if !stats.Spec.HasMemory || stats.Spec.HasCpu || stats.Spec.Memory.Limit != 1000 {
+		t.Errorf("unexpected spec %#v", stats.Spec)
+	}
+
+	if _, err := kubelet.GetPodStats("default", "foo", nil); err == nil {
+		t.Errorf("expected an error for an unknown pod")
+	}
+}
+
+func TestGetPodStatsSamplesContainers(t *testing.T) {
+	kubelet, _, fakeDocker := newTestKubelet(t)
+	kubelet.pods = []api.BoundPod{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "bar", UID: "12345", Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"}},
+			Spec: api.PodSpec{
+				Containers: []api.Container{{Name: "web"}, {Name: "log"}},
+			},
+		},
+	}
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1234", Names: []string{"/k8s_web.1_foo.bar.etcd_12345_1"}},
+		{ID: "5678", Names: []string{"/k8s_log.1_foo.bar.etcd_12345_2"}},
+		{ID: "9012", Names: []string{"/k8s_net.1_foo.bar.etcd_12345_3"}},
+	}
+	mockCadvisor := &mockCadvisorClient{}
+	req := &info.ContainerInfoRequest{NumStats: 1}
+	mockCadvisor.On("ContainerInfo", "/docker/1234", req).Return(&info.ContainerInfo{
+		Stats: []*info.ContainerStats{{Memory: &info.MemoryStats{Usage: 100}}},
+	}, nil)
+	mockCadvisor.On("ContainerInfo", "/docker/5678", req).Return(&info.ContainerInfo{
+		Stats: []*info.ContainerStats{{Memory: &info.MemoryStats{Usage: 200}}},
+	}, nil)
+	mockCadvisor.On("ContainerInfo", "/docker/9012", req).Return(&info.ContainerInfo{
+		Stats: []*info.ContainerStats{{Network: &info.NetworkStats{TxBytes: 42}}},
+	}, nil)
+	kubelet.cadvisorClient = mockCadvisor
+
+	stats, err := kubelet.GetPodStats("bar", "foo", &info.ContainerInfoRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats.Stats) != 1 {
+		t.Fatalf("expected a single sample, got %#v", stats.Stats)
+	}
+	if stats.Stats[0].Memory.Usage != 300 {
+		t.Errorf("expected the memory usage of both containers, got %d", stats.Stats[0].Memory.Usage)
+	}
+	if stats.Stats[0].Network == nil || stats.Stats[0].Network.TxBytes != 42 {
+		t.Errorf("expected the network usage of the network container, got %#v", stats.Stats[0].Network)
+	}
+	if stats.Name != "foo.bar.etcd" {
+		t.Errorf("unexpected name %q", stats.Name)
+	}
+	mockCadvisor.AssertExpectations(t)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/health"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/golang/glog"
+)
+
+const (
+	defaultProbeTimeoutSeconds   = 1
+	defaultProbePeriodSeconds    = 10
+	defaultProbeSuccessThreshold = 1
+	defaultProbeFailureThreshold = 3
+)
+
+type probeKind string
+
+const (
+	livenessProbe  probeKind = "liveness"
+	readinessProbe probeKind = "readiness"
+)
+
+// containerProbes holds the state of the probes of one container instance.
+type containerProbes struct {
+	id        string
+	stop      chan struct{}
+	live      bool
+	liveness  *api.ProbeResult
+	readiness *api.ProbeResult
+}
+
+// prober runs the liveness and readiness probes of the running containers, each
+// probe on its own worker, and keeps their latest results.  Whether a container is
+// ready is recorded in readiness.
+type prober struct {
+	checker   health.HealthChecker
+	readiness *readinessStates
+
+	lock       sync.RWMutex
+	containers map[podContainer]*containerProbes
+}
+
+func newProber(checker health.HealthChecker, readiness *readinessStates) *prober {
+	return &prober{
+		checker:    checker,
+		readiness:  readiness,
+		containers: map[podContainer]*containerProbes{},
+	}
+}
+
+// withProbeDefaults returns a copy of probe with the unset fields defaulted.
+func withProbeDefaults(probe *api.LivenessProbe) *api.LivenessProbe {
+	p := *probe
+	if p.TimeoutSeconds == 0 {
+		p.TimeoutSeconds = defaultProbeTimeoutSeconds
+	}
+	if p.PeriodSeconds == 0 {
+		p.PeriodSeconds = defaultProbePeriodSeconds
+	}
+	if p.SuccessThreshold == 0 {
+		p.SuccessThreshold = defaultProbeSuccessThreshold
+	}
+	if p.FailureThreshold == 0 {
+		p.FailureThreshold = defaultProbeFailureThreshold
+	}
+	return &p
+}
+
+// start starts the probe workers of a running container, unless they already run for
+// this instance of the container. The workers of a previous instance are stopped.
+func (p *prober) start(podFullName, uuid string, podState api.PodState, container api.Container, runningContainer *kubecontainer.Container) {
+	if container.LivenessProbe == nil && container.ReadinessProbe == nil {
+		return
+	}
+	key := podContainer{podFullName, uuid, container.Name}
+	id := runningContainer.ID
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if c, found := p.containers[key]; found {
+		if c.id == id {
+			return
+		}
+		close(c.stop)
+		// A new container is not ready until its readiness probe passes.
+		p.readiness.remove(podFullName, uuid, container.Name)
+	}
+	c := &containerProbes{
+		id:   id,
+		stop: make(chan struct{}),
+		live: true,
+	}
+	p.containers[key] = c
+	created := time.Unix(runningContainer.Created, 0)
+	if container.LivenessProbe != nil {
+		c.liveness = &api.ProbeResult{}
+		go p.run(key, c, livenessProbe, withProbeDefaults(container.LivenessProbe), podState, container, created)
+	}
+	if container.ReadinessProbe != nil {
+		c.readiness = &api.ProbeResult{}
+		go p.run(key, c, readinessProbe, withProbeDefaults(container.ReadinessProbe), podState, container, created)
+	}
+}
+
+// stop stops the probe workers of a container and forgets their results.
+func (p *prober) stop(podFullName, uuid, containerName string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	key := podContainer{podFullName, uuid, containerName}
+	if c, found := p.containers[key]; found {
+		close(c.stop)
+		delete(p.containers, key)
+	}
+	p.readiness.remove(podFullName, uuid, containerName)
+}
+
+// retain stops the probe workers of the containers which are not desired anymore.
+func (p *prober) retain(desired map[podContainer]empty) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for key, c := range p.containers {
+		if _, found := desired[key]; !found {
+			close(c.stop)
+			delete(p.containers, key)
+			p.readiness.remove(key.podFullName, key.uuid, key.containerName)
+		}
+	}
+}
+
+// run probes the container every period of the probe until the container's workers are stopped.
+func (p *prober) run(key podContainer, c *containerProbes, kind probeKind, probe *api.LivenessProbe, podState api.PodState, container api.Container, created time.Time) {
+	// Wait out the initial delay, counted from the creation of the container.
+	if delay := created.Add(time.Duration(probe.InitialDelaySeconds) * time.Second).Sub(time.Now()); delay > 0 {
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(delay):
+		}
+	}
+	// The checkers look at the liveness probe, hand them the probe to run in its place.
+	probed := container
+	probed.LivenessProbe = probe
+	ticker := time.NewTicker(time.Duration(probe.PeriodSeconds) * time.Second)
+	defer ticker.Stop()
+	for {
+		status, err := p.checker.HealthCheck(key.podFullName, key.uuid, podState, probed)
+		p.record(key, c, kind, probe, status, err)
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record updates the results of a probe with the outcome of one run. A probe passes
+// after SuccessThreshold consecutive successes and fails after FailureThreshold
+// consecutive failures; an error counts as a failure.
+func (p *prober) record(key podContainer, c *containerProbes, kind probeKind, probe *api.LivenessProbe, status health.Status, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.containers[key] != c {
+		// The container has been replaced or forgotten meanwhile.
+		return
+	}
+	result, passed := c.liveness, c.live
+	if kind == readinessProbe {
+		result, passed = c.readiness, p.readiness.IsReady(key.podFullName, key.uuid, key.containerName)
+	}
+
+	result.LastProbeTime = time.Now()
+	result.Message = ""
+	if err != nil {
+		result.Message = err.Error()
+	}
+	switch status {
+	case health.Healthy:
+		result.Result = api.ProbeSuccess
+		result.ConsecutiveSuccesses++
+		result.ConsecutiveFailures = 0
+	case health.Unhealthy:
+		result.Result = api.ProbeFailure
+		result.ConsecutiveFailures++
+		result.ConsecutiveSuccesses = 0
+	default:
+		result.Result = api.ProbeUnknown
+		result.ConsecutiveFailures++
+		result.ConsecutiveSuccesses = 0
+	}
+
+	wasPassed := passed
+	if result.ConsecutiveSuccesses >= probe.SuccessThreshold {
+		passed = true
+	} else if result.ConsecutiveFailures >= probe.FailureThreshold {
+		passed = false
+	}
+	if passed != wasPassed {
+		glog.V(1).Infof("Pod %s container %s %s probe passing: %v (%s)", key.podFullName, key.containerName, kind, passed, result.Message)
+	}
+	if kind == readinessProbe {
+		p.readiness.set(key.podFullName, key.uuid, key.containerName, passed)
+	} else {
+		c.live = passed
+	}
+}
+
+// isLive returns false if the liveness probe of the container has failed.
+func (p *prober) isLive(podFullName, uuid, containerName string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	c, found := p.containers[podContainer{podFullName, uuid, containerName}]
+	return !found || c.live
+}
+
+// results returns copies of the latest results of the probes of the container, nil for
+// the probes which are not run.
+func (p *prober) results(podFullName, uuid, containerName string) (liveness, readiness *api.ProbeResult) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	c, found := p.containers[podContainer{podFullName, uuid, containerName}]
+	if !found {
+		return nil, nil
+	}
+	if c.liveness != nil {
+		l := *c.liveness
+		liveness = &l
+	}
+	if c.readiness != nil {
+		r := *c.readiness
+		readiness = &r
+	}
+	return liveness, readiness
+}
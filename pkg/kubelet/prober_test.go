@@ -0,0 +1,105 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/health"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+)
+
+func TestWithProbeDefaults(t *testing.T) {
+	probe := withProbeDefaults(&api.LivenessProbe{PeriodSeconds: 5})
+	if probe.PeriodSeconds != 5 || probe.TimeoutSeconds != defaultProbeTimeoutSeconds ||
+		probe.SuccessThreshold != defaultProbeSuccessThreshold || probe.FailureThreshold != defaultProbeFailureThreshold {
+		t.Errorf("unexpected defaults: %#v", probe)
+	}
+}
+
+func TestProberRecordThresholds(t *testing.T) {
+	p := newProber(nil, newReadinessStates())
+	key := podContainer{"foo.new.test", "12345678", "bar"}
+	c := &containerProbes{id: "1234", stop: make(chan struct{}), live: true, liveness: &api.ProbeResult{}}
+	p.containers[key] = c
+	probe := withProbeDefaults(&api.LivenessProbe{FailureThreshold: 2})
+
+	p.record(key, c, livenessProbe, probe, health.Unhealthy, nil)
+	if !p.isLive("foo.new.test", "12345678", "bar") {
+		t.Errorf("expected a single failure to be tolerated")
+	}
+	p.record(key, c, livenessProbe, probe, health.Unknown, errors.New("connection refused"))
+	if p.isLive("foo.new.test", "12345678", "bar") {
+		t.Errorf("expected the probe to fail after 2 failures")
+	}
+	liveness, readiness := p.results("foo.new.test", "12345678", "bar")
+	if readiness != nil {
+		t.Errorf("unexpected readiness result: %#v", readiness)
+	}
+	if liveness.Result != api.ProbeUnknown || liveness.ConsecutiveFailures != 2 || liveness.Message != "connection refused" {
+		t.Errorf("unexpected liveness result: %#v", liveness)
+	}
+	p.record(key, c, livenessProbe, probe, health.Healthy, nil)
+	if !p.isLive("foo.new.test", "12345678", "bar") {
+		t.Errorf("expected the probe to pass again after a success")
+	}
+}
+
+func TestProberIgnoresReplacedContainer(t *testing.T) {
+	p := newProber(nil, newReadinessStates())
+	key := podContainer{"foo.new.test", "12345678", "bar"}
+	old := &containerProbes{id: "1234", stop: make(chan struct{}), readiness: &api.ProbeResult{}}
+	p.containers[key] = &containerProbes{id: "5678", stop: make(chan struct{}), readiness: &api.ProbeResult{}}
+
+	p.record(key, old, readinessProbe, withProbeDefaults(&api.LivenessProbe{}), health.Healthy, nil)
+	if p.readiness.IsReady("foo.new.test", "12345678", "bar") {
+		t.Errorf("expected the result of a replaced container to be ignored")
+	}
+}
+
+func TestProberStartStop(t *testing.T) {
+	p := newProber(&FalseHealthChecker{}, newReadinessStates())
+	container := api.Container{
+		Name:           "bar",
+		ReadinessProbe: &api.LivenessProbe{Exec: &api.ExecAction{Command: []string{"ls"}}, InitialDelaySeconds: 100},
+	}
+	runningContainer := &kubecontainer.Container{ID: "1234", Created: time.Now().Unix()}
+	p.start("foo.new.test", "12345678", api.PodState{}, container, runningContainer)
+	p.start("foo.new.test", "12345678", api.PodState{}, api.Container{Name: "noprobe"}, runningContainer)
+
+	if len(p.containers) != 1 {
+		t.Fatalf("expected workers for 1 container, got %d", len(p.containers))
+	}
+	if p.readiness.IsReady("foo.new.test", "12345678", "bar") {
+		t.Errorf("expected bar to be unready before the initial delay")
+	}
+	first := p.containers[podContainer{"foo.new.test", "12345678", "bar"}]
+	p.start("foo.new.test", "12345678", api.PodState{}, container, &kubecontainer.Container{ID: "5678", Created: time.Now().Unix()})
+	select {
+	case <-first.stop:
+	default:
+		t.Errorf("expected the workers of the previous container to be stopped")
+	}
+
+	p.retain(map[podContainer]empty{})
+	if len(p.containers) != 0 {
+		t.Errorf("expected all workers to be stopped, got %d", len(p.containers))
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"sync"
+)
+
+// readinessStates keeps the result of the last readiness probe of every
+// container, keyed by pod full name, pod UID and container name.
+type readinessStates struct {
+	lock   sync.RWMutex
+	states map[string]bool
+}
+
+func newReadinessStates() *readinessStates {
+	return &readinessStates{states: map[string]bool{}}
+}
+
+func readinessKey(podFullName, uuid, containerName string) string {
+	return podFullName + "_" + uuid + "_" + containerName
+}
+
+// IsReady returns true if the last readiness probe of the container succeeded.
+// A container which has not been probed yet is not ready.
+func (r *readinessStates) IsReady(podFullName, uuid, containerName string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.states[readinessKey(podFullName, uuid, containerName)]
+}
+
+func (r *readinessStates) set(podFullName, uuid, containerName string, ready bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.states[readinessKey(podFullName, uuid, containerName)] = ready
+}
+
+func (r *readinessStates) remove(podFullName, uuid, containerName string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.states, readinessKey(podFullName, uuid, containerName))
+}
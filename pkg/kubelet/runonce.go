@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
 	"github.com/golang/glog"
 )
@@ -91,11 +92,12 @@ func (kl *Kubelet) runPod(pod api.BoundPod) error {
 	delay := RunOnceRetryDelay
 	retry := 0
 	for {
-		dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+		runningPods, err := kl.runtime.GetPods(false)
 		if err != nil {
-			return fmt.Errorf("failed to get kubelet docker containers: %v", err)
+			return fmt.Errorf("failed to get kubelet containers: %v", err)
 		}
-		running, err := kl.isPodRunning(pod, dockerContainers)
+		runningPod := runningPods.FindPod(GetPodFullName(&pod), pod.UID)
+		running, err := kl.isPodRunning(pod, runningPod)
 		if err != nil {
 			return fmt.Errorf("failed to check pod status: %v", err)
 		}
@@ -104,7 +106,7 @@ func (kl *Kubelet) runPod(pod api.BoundPod) error {
 			return nil
 		}
 		glog.Infof("pod %q containers not running: syncing", pod.Name)
-		if err = kl.syncPod(&pod, dockerContainers); err != nil {
+		if err = kl.syncPod(&pod, runningPod); err != nil {
 			return fmt.Errorf("error syncing pod: %v", err)
 		}
 		if retry >= RunOnceMaxRetries {
@@ -119,20 +121,20 @@ func (kl *Kubelet) runPod(pod api.BoundPod) error {
 }
 
 // isPodRunning returns true if all containers of a manifest are running.
-func (kl *Kubelet) isPodRunning(pod api.BoundPod, dockerContainers dockertools.DockerContainers) (bool, error) {
+func (kl *Kubelet) isPodRunning(pod api.BoundPod, runningPod kubecontainer.Pod) (bool, error) {
 	for _, container := range pod.Spec.Containers {
-		dockerContainer, found, _ := dockerContainers.FindPodContainer(GetPodFullName(&pod), pod.UID, container.Name)
-		if !found {
+		runningContainer := runningPod.FindContainerByName(container.Name)
+		if runningContainer == nil {
 			glog.Infof("container %q not found", container.Name)
 			return false, nil
 		}
-		inspectResult, err := kl.dockerClient.InspectContainer(dockerContainer.ID)
+		status, err := kl.runtime.GetContainerStatus(runningContainer.ID)
 		if err != nil {
 			glog.Infof("failed to inspect container %q: %v", container.Name, err)
 			return false, err
 		}
-		if !inspectResult.State.Running {
-			glog.Infof("container %q not running: %#v", container.Name, inspectResult.State)
+		if !status.Running {
+			glog.Infof("container %q not running: %#v", container.Name, status)
 			return false, nil
 		}
 	}
@@ -17,95 +17,16 @@ limitations under the License.
 package kubelet
 
 import (
-	"fmt"
-	"strconv"
+	"reflect"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
-	docker "github.com/fsouza/go-dockerclient"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 )
 
-type listContainersResult struct {
-	label      string
-	containers []docker.APIContainers
-	err        error
-}
-
-type inspectContainersResult struct {
-	label     string
-	container docker.Container
-	err       error
-}
-
-type testDocker struct {
-	listContainersResults    []listContainersResult
-	inspectContainersResults []inspectContainersResult
-	dockertools.FakeDockerClient
-	t *testing.T
-}
-
-func (d *testDocker) ListContainers(options docker.ListContainersOptions) ([]docker.APIContainers, error) {
-	if len(d.listContainersResults) > 0 {
-		result := d.listContainersResults[0]
-		d.listContainersResults = d.listContainersResults[1:]
-		d.t.Logf("ListContainers: %q, returning: (%v, %v)", result.label, result.containers, result.err)
-		return result.containers, result.err
-	}
-	return nil, fmt.Errorf("ListContainers error: no more test results")
-}
-
-func (d *testDocker) InspectContainer(id string) (*docker.Container, error) {
-	if len(d.inspectContainersResults) > 0 {
-		result := d.inspectContainersResults[0]
-		d.inspectContainersResults = d.inspectContainersResults[1:]
-		d.t.Logf("InspectContainers: %q, returning: (%v, %v)", result.label, result.container, result.err)
-		return &result.container, result.err
-	}
-	return nil, fmt.Errorf("InspectContainer error: no more test results")
-}
-
 func TestRunOnce(t *testing.T) {
-	kb := &Kubelet{}
-	podContainers := []docker.APIContainers{
-		{
-			Names:  []string{"/k8s_bar." + strconv.FormatUint(dockertools.HashContainer(&api.Container{Name: "bar"}), 16) + "_foo.new.test"},
-			ID:     "1234",
-			Status: "running",
-		},
-		{
-			Names:  []string{"/k8s_net_foo.new.test_"},
-			ID:     "9876",
-			Status: "running",
-		},
-	}
-	kb.dockerClient = &testDocker{
-		listContainersResults: []listContainersResult{
-			{label: "list pod container", containers: []docker.APIContainers{}},
-			{label: "syncPod", containers: []docker.APIContainers{}},
-			{label: "list pod container", containers: []docker.APIContainers{}},
-			{label: "syncPod", containers: podContainers},
-			{label: "list pod container", containers: podContainers},
-		},
-		inspectContainersResults: []inspectContainersResult{
-			{
-				label: "syncPod",
-				container: docker.Container{
-					Config: &docker.Config{Image: "someimage"},
-					State:  docker.State{Running: true},
-				},
-			},
-			{
-				label: "syncPod",
-				container: docker.Container{
-					Config: &docker.Config{Image: "someimage"},
-					State:  docker.State{Running: true},
-				},
-			},
-		},
-		t: t,
-	}
-	kb.dockerPuller = &dockertools.FakeDockerPuller{}
+	kb, fakeRuntime := newTestKubeletWithFakeRuntime()
+	kb.networkContainerImage = NetworkContainerImage
 	results, err := kb.runOnce([]api.BoundPod{
 		{
 			ObjectMeta: api.ObjectMeta{
@@ -115,7 +36,7 @@ func TestRunOnce(t *testing.T) {
 			},
 			Spec: api.PodSpec{
 				Containers: []api.Container{
-					{Name: "bar"},
+					{Name: "bar", Image: "someimage"},
 				},
 			},
 		},
@@ -129,4 +50,42 @@ func TestRunOnce(t *testing.T) {
 	if results[0].Pod.Name != "foo" {
 		t.Errorf("unexpected pod: %q", results[0].Pod.Name)
 	}
+	if !reflect.DeepEqual(fakeRuntime.StartedContainers, []string{networkContainerName, "bar"}) {
+		t.Errorf("unexpected started containers: %v", fakeRuntime.StartedContainers)
+	}
+	if !reflect.DeepEqual(fakeRuntime.PulledImages, []string{NetworkContainerImage, "someimage"}) {
+		t.Errorf("unexpected pulled images: %v", fakeRuntime.PulledImages)
+	}
+}
+
+func TestRunOnceRunningPod(t *testing.T) {
+	kb, fakeRuntime := newTestKubeletWithFakeRuntime()
+	fakeRuntime.RunningPods = kubecontainer.Pods{
+		{
+			FullName: "foo.new.test",
+			Containers: []*kubecontainer.Container{
+				{ID: "9876", Name: networkContainerName},
+				{ID: "1234", Name: "bar"},
+			},
+		},
+	}
+	results, err := kb.runOnce([]api.BoundPod{
+		{
+			ObjectMeta: api.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "new",
+				Annotations: map[string]string{ConfigSourceAnnotationKey: "test"},
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{{Name: "bar"}},
+			},
+		},
+	})
+	if err != nil || results[0].Err != nil {
+		t.Errorf("unexpected error: %v %v", err, results[0].Err)
+	}
+	// The running pod is only checked.
+	if err := fakeRuntime.AssertCalls([]string{"GetPods", "GetContainerStatus"}); err != nil {
+		t.Error(err)
+	}
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package kubelet
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -31,11 +32,16 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authenticator"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authorizer"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/healthz"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/httplog"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
 	"github.com/golang/glog"
 	"github.com/google/cadvisor/info"
+	"golang.org/x/net/websocket"
 	"gopkg.in/v1/yaml"
 )
 
@@ -43,13 +49,40 @@ import (
 type Server struct {
 	host    HostInterface
 	updates chan<- interface{}
+	source  string
+	auth    AuthInterface
 	mux     *http.ServeMux
 }
 
+// TLSOptions holds the TLS options the kubelet server is served with.
+type TLSOptions struct {
+	Config   *tls.Config
+	CertFile string
+	KeyFile  string
+}
+
+// AuthInterface contains all methods required by the auth filters of the server.
+type AuthInterface interface {
+	authenticator.Request
+	authorizer.Authorizer
+}
+
+type kubeletAuth struct {
+	authenticator.Request
+	authorizer.Authorizer
+}
+
+// NewKubeletAuth returns an AuthInterface composed of the given authenticator and authorizer.
+func NewKubeletAuth(authenticator authenticator.Request, authorizer authorizer.Authorizer) AuthInterface {
+	return &kubeletAuth{authenticator, authorizer}
+}
+
 // ListenAndServeKubeletServer initializes a server to respond to HTTP network requests on the Kubelet.
-func ListenAndServeKubeletServer(host HostInterface, updates chan<- interface{}, address net.IP, port uint, enableDebuggingHandlers bool) {
+// The server is served over TLS when tlsOptions is not nil, and requires authentication and
+// authorization of the requests when auth is not nil.
+func ListenAndServeKubeletServer(host HostInterface, updates chan<- interface{}, source string, address net.IP, port uint, tlsOptions *TLSOptions, auth AuthInterface, enableDebuggingHandlers bool) {
 	glog.V(1).Infof("Starting to listen on %s:%d", address, port)
-	handler := NewServer(host, updates, enableDebuggingHandlers)
+	handler := NewServer(host, updates, source, auth, enableDebuggingHandlers)
 	s := &http.Server{
 		Addr:           net.JoinHostPort(address.String(), strconv.FormatUint(uint64(port), 10)),
 		Handler:        &handler,
@@ -57,7 +90,12 @@ func ListenAndServeKubeletServer(host HostInterface, updates chan<- interface{},
 		WriteTimeout:   60 * time.Minute,
 		MaxHeaderBytes: 1 << 20,
 	}
-	glog.Fatal(s.ListenAndServe())
+	if tlsOptions != nil {
+		s.TLSConfig = tlsOptions.Config
+		glog.Fatal(s.ListenAndServeTLS(tlsOptions.CertFile, tlsOptions.KeyFile))
+	} else {
+		glog.Fatal(s.ListenAndServe())
+	}
 }
 
 // HostInterface contains all the kubelet methods required by the server.
@@ -66,9 +104,13 @@ type HostInterface interface {
 	GetContainerInfo(podFullName, uuid, containerName string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error)
 	GetRootInfo(req *info.ContainerInfoRequest) (*info.ContainerInfo, error)
 	GetMachineInfo() (*info.MachineInfo, error)
+	GetNodeConditions() []api.NodeCondition
 	GetBoundPods() ([]api.BoundPod, error)
 	GetPodInfo(name, uuid string) (api.PodInfo, error)
 	RunInContainer(name, uuid, container string, cmd []string) ([]byte, error)
+	ExecInContainer(podFullName, uuid, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error
+	AttachContainer(podFullName, uuid, container string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error
+	PortForward(podFullName, uuid string, port uint16, stream io.ReadWriter) error
 	GetKubeletContainerLogs(podFullName, containerName, tail string, follow bool, stdout, stderr io.Writer) error
 	ServeLogs(w http.ResponseWriter, req *http.Request)
 	OpPod(podFullName, podOp string) error
@@ -76,16 +118,20 @@ type HostInterface interface {
 	UpdatePodCgroup(podFullName string, podConfig *PodConfig) error
 	UpdatePodDisk(podFullName string, podConfig *PodConfig) error
 	UpdatePodConfig(podFullName string, attribute []KVPair) error
-	GetPodStats(podFullName string) (*info.ContainerInfo, error)
+	GetPodStats(namespace, name string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error)
 	MergeContainer(podFullName, image, op string) error
 	DockerPodCgroup(podFullName string, cgroups []CgroupData) ([]CgroupResponse, error)
 }
 
 // NewServer initializes and configures a kubelet.Server object to handle HTTP requests.
-func NewServer(host HostInterface, updates chan<- interface{}, enableDebuggingHandlers bool) Server {
+// The pods the kubelet does not run are named as pods from the given source.
+// If auth is nil, requests are served without authentication and authorization.
+func NewServer(host HostInterface, updates chan<- interface{}, source string, auth AuthInterface, enableDebuggingHandlers bool) Server {
 	server := Server{
 		host:    host,
 		updates: updates,
+		source:  source,
+		auth:    auth,
 		mux:     http.NewServeMux(),
 	}
 	server.InstallDefaultHandlers()
@@ -98,10 +144,12 @@ func NewServer(host HostInterface, updates chan<- interface{}, enableDebuggingHa
 // InstallDefaultHandlers registers the default set of supported HTTP request patterns with the mux.
 func (s *Server) InstallDefaultHandlers() {
 	healthz.InstallHandler(s.mux)
+	metrics.InstallHandler(s.mux)
 	s.mux.HandleFunc("/podInfo", s.handlePodInfo)
 	s.mux.HandleFunc("/boundPods", s.handleBoundPods)
 	s.mux.HandleFunc("/stats/", s.handleStats)
 	s.mux.HandleFunc("/spec/", s.handleSpec)
+	s.mux.HandleFunc("/conditions", s.handleConditions)
 	s.mux.HandleFunc("/podOp", s.handlePodOp)
 	s.mux.HandleFunc("/image/", s.handleImage)
 	s.mux.HandleFunc("/podUpgrade/", s.handlePodUpgrade)
@@ -114,6 +162,9 @@ func (s *Server) InstallDebuggingHandlers() {
 	s.mux.HandleFunc("/container", s.handleContainer)
 	s.mux.HandleFunc("/containers", s.handleContainers)
 	s.mux.HandleFunc("/run/", s.handleRun)
+	s.mux.HandleFunc("/exec/", s.handleExec)
+	s.mux.HandleFunc("/attach/", s.handleAttach)
+	s.mux.HandleFunc("/portForward/", s.handlePortForward)
 
 	s.mux.HandleFunc("/logs/", s.handleLogs)
 	s.mux.HandleFunc("/containerLogs/", s.handleContainerLogs)
@@ -217,13 +268,7 @@ func (s *Server) handleContainerLogs(w http.ResponseWriter, req *http.Request) {
 	follow, _ := strconv.ParseBool(uriValues.Get("follow"))
 	tail := uriValues.Get("tail")
 
-	podFullName := GetPodFullName(&api.BoundPod{
-		ObjectMeta: api.ObjectMeta{
-			Name:        podID,
-			Namespace:   podNamespace,
-			Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-		},
-	})
+	podFullName := s.podFullName(podNamespace, podID)
 
 	fw := FlushWriter{writer: w}
 	if flusher, ok := fw.writer.(http.Flusher); ok {
@@ -260,6 +305,26 @@ func (s *Server) handleBoundPods(w http.ResponseWriter, req *http.Request) {
 	w.Write(data)
 }
 
+// podFullName returns the full name of a pod of the kubelet, from whichever source it runs
+// the pod from. The pods it does not run are named as pods from the source of the server.
+func (s *Server) podFullName(namespace, name string) string {
+	if pods, err := s.host.GetBoundPods(); err == nil {
+		for i := range pods {
+			if pods[i].Name == name && pods[i].Namespace == namespace {
+				return GetPodFullName(&pods[i])
+			}
+		}
+	}
+	// TODO: backwards compatibility with existing API, needs API change
+	return GetPodFullName(&api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{ConfigSourceAnnotationKey: s.source},
+		},
+	})
+}
+
 // handlePodInfo handles podInfo requests against the Kubelet
 func (s *Server) handlePodInfo(w http.ResponseWriter, req *http.Request) {
 	u, err := url.ParseRequestURI(req.RequestURI)
@@ -280,15 +345,7 @@ func (s *Server) handlePodInfo(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, "Missing 'podNamespace=' query entry.", http.StatusBadRequest)
 		return
 	}
-	// TODO: backwards compatibility with existing API, needs API change
-	podFullName := GetPodFullName(&api.BoundPod{
-		ObjectMeta: api.ObjectMeta{
-			Name:        podID,
-			Namespace:   podNamespace,
-			Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-		},
-	})
-	info, err := s.host.GetPodInfo(podFullName, podUUID)
+	info, err := s.host.GetPodInfo(s.podFullName(podNamespace, podID), podUUID)
 	if err == dockertools.ErrNoContainersInPod {
 		http.Error(w, "api.BoundPod does not exist", http.StatusNotFound)
 		return
@@ -334,6 +391,17 @@ func (s *Server) handleSpec(w http.ResponseWriter, req *http.Request) {
 
 }
 
+// handleConditions handles requests for the pressure conditions of the node.
+func (s *Server) handleConditions(w http.ResponseWriter, req *http.Request) {
+	data, err := json.Marshal(s.host.GetNodeConditions())
+	if err != nil {
+		s.error(w, err)
+		return
+	}
+	w.Header().Add("Content-type", "application/json")
+	w.Write(data)
+}
+
 // handleRun handles requests to run a command inside a container.
 func (s *Server) handleRun(w http.ResponseWriter, req *http.Request) {
 	u, err := url.ParseRequestURI(req.RequestURI)
@@ -356,13 +424,7 @@ func (s *Server) handleRun(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, "Unexpected path for command running", http.StatusBadRequest)
 		return
 	}
-	podFullName := GetPodFullName(&api.BoundPod{
-		ObjectMeta: api.ObjectMeta{
-			Name:        podID,
-			Namespace:   podNamespace,
-			Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-		},
-	})
+	podFullName := s.podFullName(podNamespace, podID)
 	command := strings.Split(u.Query().Get("cmd"), " ")
 	data, err := s.host.RunInContainer(podFullName, uuid, container, command)
 	if err != nil {
@@ -373,20 +435,193 @@ func (s *Server) handleRun(w http.ResponseWriter, req *http.Request) {
 	w.Write(data)
 }
 
+// parseStreamRequest parses the path of an exec or attach request, /<verb>/<podNamespace>/<podID>
+// followed by an optional [<uuid>/]<container>. The container may also be set by a query parameter.
+func (s *Server) parseStreamRequest(req *http.Request) (podFullName, uuid, container string, err error) {
+	parts := strings.Split(strings.Trim(path.Clean(req.URL.Path), "/"), "/")
+	switch len(parts) {
+	case 3:
+		container = req.URL.Query().Get(remotecommand.ContainerParam)
+	case 4:
+		container = parts[3]
+	case 5:
+		uuid = parts[3]
+		container = parts[4]
+	default:
+		return "", "", "", fmt.Errorf("unexpected path %q", req.URL.Path)
+	}
+	podFullName = s.podFullName(parts[1], parts[2])
+	return podFullName, uuid, container, nil
+}
+
+// handleExec handles websocket requests to run a command inside a container with streamed
+// stdin, stdout and stderr. The command is given by the repeated "command" query parameter.
+func (s *Server) handleExec(w http.ResponseWriter, req *http.Request) {
+	podFullName, uuid, container, err := s.parseStreamRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	command := req.URL.Query()[remotecommand.CommandParam]
+	if len(command) == 0 {
+		http.Error(w, "missing command", http.StatusBadRequest)
+		return
+	}
+	opts := remotecommand.OptionsFromQuery(req.URL.Query())
+	remotecommand.NewWebSocketHandler(opts, func(stdin io.Reader, stdout, stderr io.Writer, resize <-chan remotecommand.TerminalSize) error {
+		return s.host.ExecInContainer(podFullName, uuid, container, command, stdin, stdout, stderr, opts.TTY, resize)
+	}).ServeHTTP(w, req)
+}
+
+// handleAttach handles websocket requests to attach to the main process of a container.
+func (s *Server) handleAttach(w http.ResponseWriter, req *http.Request) {
+	podFullName, uuid, container, err := s.parseStreamRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts := remotecommand.OptionsFromQuery(req.URL.Query())
+	remotecommand.NewWebSocketHandler(opts, func(stdin io.Reader, stdout, stderr io.Writer, resize <-chan remotecommand.TerminalSize) error {
+		return s.host.AttachContainer(podFullName, uuid, container, stdin, stdout, stderr, opts.TTY, resize)
+	}).ServeHTTP(w, req)
+}
+
+// handlePortForward handles websocket requests to /portForward/<podNamespace>/<podID>[/<uuid>]?port=<port>
+// which forward one connection to a port of a pod. The data of the connection is sent in binary frames
+// both ways until either side closes.
+func (s *Server) handlePortForward(w http.ResponseWriter, req *http.Request) {
+	parts := strings.Split(strings.Trim(path.Clean(req.URL.Path), "/"), "/")
+	if len(parts) != 3 && len(parts) != 4 {
+		http.Error(w, fmt.Sprintf("unexpected path %q", req.URL.Path), http.StatusBadRequest)
+		return
+	}
+	uuid := ""
+	if len(parts) == 4 {
+		uuid = parts[3]
+	}
+	port, err := strconv.ParseUint(req.URL.Query().Get("port"), 10, 16)
+	if err != nil || port == 0 {
+		http.Error(w, fmt.Sprintf("invalid port %q", req.URL.Query().Get("port")), http.StatusBadRequest)
+		return
+	}
+	podFullName := s.podFullName(parts[1], parts[2])
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+		ws.PayloadType = websocket.BinaryFrame
+		if err := s.host.PortForward(podFullName, uuid, uint16(port), ws); err != nil {
+			glog.Errorf("Error forwarding port %d of pod %s: %v", port, podFullName, err)
+		}
+	}).ServeHTTP(w, req)
+}
+
 // ServeHTTP responds to HTTP requests on the Kubelet.
 func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	defer httplog.NewLogged(req, &w).StacktraceWhen(
 		httplog.StatusIsNot(
 			http.StatusOK,
 			http.StatusNotFound,
+			http.StatusUnauthorized,
+			http.StatusForbidden,
 		),
 	).Log()
+	if s.auth != nil && !s.authorize(w, req) {
+		return
+	}
 	s.mux.ServeHTTP(w, req)
 }
 
+// handlerAttributes is the resource a group of handlers acts on, and whether the handlers
+// only read it.
+type handlerAttributes struct {
+	resource string
+	readOnly bool
+}
+
+// handlerAuthAttributes maps the path prefix of each handler to the attributes the request
+// is authorized with.  Stats are read-only, so that monitoring can be granted without the
+// rights to mutate pods or run commands in their containers.  A handler is only read-only
+// for GET and HEAD requests, see isReadOnlyMethod.
+var handlerAuthAttributes = map[string]handlerAttributes{
+	"/metrics":        {"stats", true},
+	"/stats/":         {"stats", true},
+	"/spec/":          {"stats", true},
+	"/conditions":     {"stats", true},
+	"/podInfo":        {"pods", true},
+	"/boundPods":      {"pods", true},
+	"/podOp":          {"pods", false},
+	"/image/":         {"pods", false},
+	"/podUpgrade/":    {"pods", false},
+	"/podCgroup":      {"pods", false},
+	"/container":      {"pods", false},
+	"/containers":     {"pods", false},
+	"/run/":           {"exec", false},
+	"/exec/":          {"exec", false},
+	"/attach/":        {"exec", false},
+	"/portForward/":   {"exec", false},
+	"/logs/":          {"logs", true},
+	"/containerLogs/": {"logs", true},
+}
+
+// authAttributes returns the attributes of the handler serving the path, and false if the
+// path is open to unauthenticated requests.
+func authAttributes(urlPath string) (handlerAttributes, bool) {
+	if urlPath == "/healthz" {
+		return handlerAttributes{}, false
+	}
+	if attrs, ok := handlerAuthAttributes[urlPath]; ok {
+		return attrs, true
+	}
+	// Match the longest subtree pattern, like http.ServeMux does.
+	var match string
+	for prefix := range handlerAuthAttributes {
+		if strings.HasSuffix(prefix, "/") && strings.HasPrefix(urlPath, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		// Unknown paths need an authenticated user with full rights on the node.
+		return handlerAttributes{"", false}, true
+	}
+	return handlerAuthAttributes[match], true
+}
+
+// isReadOnlyMethod returns true if a request with the method can not change anything.
+func isReadOnlyMethod(method string) bool {
+	return method == "GET" || method == "HEAD"
+}
+
+// authorize authenticates and authorizes the request, and writes the error response if it
+// is not allowed.
+func (s *Server) authorize(w http.ResponseWriter, req *http.Request) bool {
+	attrs, required := authAttributes(req.URL.Path)
+	if !required {
+		return true
+	}
+	u, ok, err := s.auth.AuthenticateRequest(req)
+	if err != nil {
+		glog.V(2).Infof("Unable to authenticate the request to %s: %v", req.URL.Path, err)
+	}
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	record := authorizer.AttributesRecord{
+		User:     u,
+		ReadOnly: attrs.readOnly && isReadOnlyMethod(req.Method),
+		Kind:     attrs.resource,
+	}
+	if err := s.auth.Authorize(record); err != nil {
+		glog.V(2).Infof("Forbidden request of %s to %s: %v", u.GetName(), req.URL.Path, err)
+		http.Error(w, fmt.Sprintf("Forbidden: %v", err), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
 // serveStats implements stats logic.
 func (s *Server) serveStats(w http.ResponseWriter, req *http.Request) {
 	// /stats/<podfullname>/<containerName> or /stats/<podfullname>/<uuid>/<containerName>
+	// or /stats/pod/<namespace>/<name>
 	components := strings.Split(strings.TrimPrefix(path.Clean(req.URL.Path), "/"), "/")
 	var stats *info.ContainerInfo
 	var err error
@@ -396,49 +631,42 @@ func (s *Server) serveStats(w http.ResponseWriter, req *http.Request) {
 		s.error(w, err)
 		return
 	}
+	if len(components) == 4 && components[1] == "pod" {
+		// pod stats aggregated over the containers of the pod
+		if numStats := req.URL.Query().Get("num_stats"); numStats != "" {
+			query.NumStats, err = strconv.Atoi(numStats)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid num_stats: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		stats, err = s.host.GetPodStats(components[2], components[3], &query)
+		s.writeStats(w, stats, err)
+		return
+	}
 	switch len(components) {
 	case 1:
 		// Machine stats
 		stats, err = s.host.GetRootInfo(&query)
 	case 2:
-		// pod stats
-		// TODO(monnand) Implement this
-		// errors.New("pod level status currently unimplemented") ## delete by hbo
-		// Get Pod memory usage
-		podFullName := GetPodFullName(&api.BoundPod{
-			ObjectMeta: api.ObjectMeta{
-				Name: components[1],
-				// TODO: I am broken
-				Namespace:   api.NamespaceDefault,
-				Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-			},
-		})
-		stats, err = s.host.GetPodStats(podFullName)
+		// pod stats of the default namespace
+		stats, err = s.host.GetPodStats(api.NamespaceDefault, components[1], &query)
 	case 3:
 		// Backward compatibility without uuid information
-		podFullName := GetPodFullName(&api.BoundPod{
-			ObjectMeta: api.ObjectMeta{
-				Name: components[1],
-				// TODO: I am broken
-				Namespace:   api.NamespaceDefault,
-				Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-			},
-		})
+		podFullName := s.podFullName(api.NamespaceDefault, components[1])
 		stats, err = s.host.GetContainerInfo(podFullName, "", components[2], &query)
 	case 4:
-		podFullName := GetPodFullName(&api.BoundPod{
-			ObjectMeta: api.ObjectMeta{
-				Name: components[1],
-				// TODO: I am broken
-				Namespace:   "",
-				Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-			},
-		})
+		podFullName := s.podFullName("", components[1])
 		stats, err = s.host.GetContainerInfo(podFullName, components[2], components[2], &query)
 	default:
 		http.Error(w, "unknown resource.", http.StatusNotFound)
 		return
 	}
+	s.writeStats(w, stats, err)
+}
+
+// writeStats writes stats as JSON, or err if it is not nil.
+func (s *Server) writeStats(w http.ResponseWriter, stats *info.ContainerInfo, err error) {
 	if err != nil {
 		s.error(w, err)
 		return
@@ -456,7 +684,6 @@ func (s *Server) serveStats(w http.ResponseWriter, req *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Header().Add("Content-type", "application/json")
 	w.Write(data)
-	return
 }
 
 // handlePodOp handles podOp requests against the Kubelet
@@ -480,13 +707,7 @@ func (s *Server) handlePodOp(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	// TODO: backwards compatibility with existing API, needs API change
-	podFullName := GetPodFullName(&api.BoundPod{
-		ObjectMeta: api.ObjectMeta{
-			Name:        podID,
-			Namespace:   podNamespace,
-			Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-		},
-	})
+	podFullName := s.podFullName(podNamespace, podID)
 	err = s.host.OpPod(podFullName, podOp)
 	if err == dockertools.ErrNoContainersInPod {
 		http.Error(w, "api.BoundPod does not exist", http.StatusNotFound)
@@ -606,13 +827,7 @@ func (s *Server) handlePodUpgrade(w http.ResponseWriter, req *http.Request) {
 			http.Error(w, "Missing 'writeSubsystem' post entry.", http.StatusBadRequest)
 			return
 		}
-		podFullName := GetPodFullName(&api.BoundPod{
-			ObjectMeta: api.ObjectMeta{
-				Name:        params.PodID,
-				Namespace:   params.PodNamespace,
-				Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-			},
-		})
+		podFullName := s.podFullName(params.PodNamespace, params.PodID)
 		if err = s.host.UpdatePodCgroup(podFullName, &params); err != nil {
 			result.Code = 1
 			result.ErrorMsg = fmt.Sprintf("%v", err)
@@ -638,13 +853,7 @@ func (s *Server) handlePodUpgrade(w http.ResponseWriter, req *http.Request) {
 			http.Error(w, "Missing 'writeSubsystem' post entry.", http.StatusBadRequest)
 			return
 		}
-		podFullName := GetPodFullName(&api.BoundPod{
-			ObjectMeta: api.ObjectMeta{
-				Name:        params.PodID,
-				Namespace:   params.PodNamespace,
-				Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-			},
-		})
+		podFullName := s.podFullName(params.PodNamespace, params.PodID)
 		if err = s.host.UpdatePodDisk(podFullName, &params); err != nil {
 			result.Code = 1
 			result.ErrorMsg = fmt.Sprintf("%v", err)
@@ -678,13 +887,7 @@ func (s *Server) handlePodUpgrade(w http.ResponseWriter, req *http.Request) {
 		if len(tmp.Op) == 0 {
 			tmp.Op = "pull"
 		}
-		podFullName := GetPodFullName(&api.BoundPod{
-			ObjectMeta: api.ObjectMeta{
-				Name:        tmp.PodID,
-				Namespace:   tmp.PodNamespace,
-				Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-			},
-		})
+		podFullName := s.podFullName(tmp.PodNamespace, tmp.PodID)
 		if err = s.host.MergeContainer(podFullName, tmp.Image, tmp.Op); err != nil {
 			result.Code = 1
 			result.ErrorMsg = fmt.Sprintf("%v", err)
@@ -714,13 +917,7 @@ func (s *Server) handlePodUpgrade(w http.ResponseWriter, req *http.Request) {
 			http.Error(w, "Missing 'Attribute' post entry.", http.StatusBadRequest)
 			return
 		}
-		podFullName := GetPodFullName(&api.BoundPod{
-			ObjectMeta: api.ObjectMeta{
-				Name:        setData.PodID,
-				Namespace:   setData.PodNamespace,
-				Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-			},
-		})
+		podFullName := s.podFullName(setData.PodNamespace, setData.PodID)
 		if err = s.host.UpdatePodConfig(podFullName, setData.Attribute); err != nil {
 			result.Code = 1
 			result.ErrorMsg = fmt.Sprintf("%v", err)
@@ -781,13 +978,7 @@ func (s *Server) handlePodCgroup(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	podFullName := GetPodFullName(&api.BoundPod{
-		ObjectMeta: api.ObjectMeta{
-			Name:        podCgroup.PodID,
-			Namespace:   podCgroup.PodNamespace,
-			Annotations: map[string]string{ConfigSourceAnnotationKey: "etcd"},
-		},
-	})
+	podFullName := s.podFullName(podCgroup.PodNamespace, podCgroup.PodID)
 	out, err := s.host.DockerPodCgroup(podFullName, podCgroup.Cgroups)
 	if err != nil {
 		result.Code = 1
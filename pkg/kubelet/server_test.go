@@ -19,6 +19,7 @@ package kubelet
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -30,17 +31,27 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authenticator"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authorizer"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/user"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/remotecommand"
 	"github.com/google/cadvisor/info"
+	"golang.org/x/net/websocket"
 )
 
 type fakeKubelet struct {
 	infoFunc          func(name string) (api.PodInfo, error)
 	containerInfoFunc func(podFullName, containerName string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error)
 	rootInfoFunc      func(query *info.ContainerInfoRequest) (*info.ContainerInfo, error)
+	podStatsFunc      func(namespace, name string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error)
 	machineInfoFunc   func() (*info.MachineInfo, error)
+	conditionsFunc    func() []api.NodeCondition
 	boundPodsFunc     func() ([]api.BoundPod, error)
 	logFunc           func(w http.ResponseWriter, req *http.Request)
 	runFunc           func(podFullName, uuid, containerName string, cmd []string) ([]byte, error)
+	execFunc          func(podFullName, uuid, containerName string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error
+	attachFunc        func(podFullName, uuid, containerName string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error
+	portForwardFunc   func(podFullName, uuid string, port uint16, stream io.ReadWriter) error
 	containerLogsFunc func(podFullName, containerName, tail string, follow bool, stdout, stderr io.Writer) error
 }
 
@@ -56,11 +67,22 @@ func (fk *fakeKubelet) GetRootInfo(req *info.ContainerInfoRequest) (*info.Contai
 	return fk.rootInfoFunc(req)
 }
 
+func (fk *fakeKubelet) GetPodStats(namespace, name string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	return fk.podStatsFunc(namespace, name, req)
+}
+
 func (fk *fakeKubelet) GetMachineInfo() (*info.MachineInfo, error) {
 	return fk.machineInfoFunc()
 }
 
+func (fk *fakeKubelet) GetNodeConditions() []api.NodeCondition {
+	return fk.conditionsFunc()
+}
+
 func (fk *fakeKubelet) GetBoundPods() ([]api.BoundPod, error) {
+	if fk.boundPodsFunc == nil {
+		return nil, nil
+	}
 	return fk.boundPodsFunc()
 }
 
@@ -76,6 +98,29 @@ func (fk *fakeKubelet) RunInContainer(podFullName, uuid, containerName string, c
 	return fk.runFunc(podFullName, uuid, containerName, cmd)
 }
 
+func (fk *fakeKubelet) ExecInContainer(podFullName, uuid, containerName string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	return fk.execFunc(podFullName, uuid, containerName, cmd, stdin, stdout, stderr, tty)
+}
+
+func (fk *fakeKubelet) AttachContainer(podFullName, uuid, containerName string, stdin io.Reader, stdout, stderr io.Writer, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	return fk.attachFunc(podFullName, uuid, containerName, stdin, stdout, stderr, tty)
+}
+
+func (fk *fakeKubelet) PortForward(podFullName, uuid string, port uint16, stream io.ReadWriter) error {
+	return fk.portForwardFunc(podFullName, uuid, port, stream)
+}
+
+// The pod operation handlers are not exercised by the server tests.
+func (fk *fakeKubelet) OpPod(podFullName, podOp string) error                          { return nil }
+func (fk *fakeKubelet) PushImage(params *PushImageParams) error                        { return nil }
+func (fk *fakeKubelet) UpdatePodCgroup(podFullName string, podConfig *PodConfig) error { return nil }
+func (fk *fakeKubelet) UpdatePodDisk(podFullName string, podConfig *PodConfig) error   { return nil }
+func (fk *fakeKubelet) UpdatePodConfig(podFullName string, attribute []KVPair) error   { return nil }
+func (fk *fakeKubelet) MergeContainer(podFullName, image, op string) error             { return nil }
+func (fk *fakeKubelet) DockerPodCgroup(podFullName string, cgroups []CgroupData) ([]CgroupResponse, error) {
+	return nil, nil
+}
+
 type serverTestFramework struct {
 	updateChan      chan interface{}
 	updateReader    *channelReader
@@ -85,12 +130,16 @@ type serverTestFramework struct {
 }
 
 func newServerTest() *serverTestFramework {
+	return newServerTestWithAuth(nil)
+}
+
+func newServerTestWithAuth(auth AuthInterface) *serverTestFramework {
 	fw := &serverTestFramework{
 		updateChan: make(chan interface{}),
 	}
 	fw.updateReader = startReading(fw.updateChan)
 	fw.fakeKubelet = &fakeKubelet{}
-	server := NewServer(fw.fakeKubelet, fw.updateChan, true)
+	server := NewServer(fw.fakeKubelet, fw.updateChan, "etcd", auth, true)
 	fw.serverUnderTest = &server
 	fw.testHTTPServer = httptest.NewServer(fw.serverUnderTest)
 	return fw
@@ -270,6 +319,9 @@ func TestPodInfo(t *testing.T) {
 	expected := api.PodInfo{
 		"goodpod": api.ContainerStatus{},
 	}
+	fw.fakeKubelet.boundPodsFunc = func() ([]api.BoundPod, error) {
+		return []api.BoundPod{}, nil
+	}
 	fw.fakeKubelet.infoFunc = func(name string) (api.PodInfo, error) {
 		if name == "goodpod.default.etcd" {
 			return expected, nil
@@ -293,6 +345,34 @@ func TestPodInfo(t *testing.T) {
 	}
 }
 
+func TestPodInfoStaticPod(t *testing.T) {
+	fw := newServerTest()
+	fw.fakeKubelet.boundPodsFunc = func() ([]api.BoundPod, error) {
+		return []api.BoundPod{
+			{
+				ObjectMeta: api.ObjectMeta{
+					Name:        "static",
+					Namespace:   "default",
+					Annotations: map[string]string{ConfigSourceAnnotationKey: "file"},
+				},
+			},
+		}, nil
+	}
+	fw.fakeKubelet.infoFunc = func(name string) (api.PodInfo, error) {
+		if name == "static.default.file" {
+			return api.PodInfo{}, nil
+		}
+		return nil, fmt.Errorf("bad pod %s", name)
+	}
+	resp, err := http.Get(fw.testHTTPServer.URL + "/podInfo?podID=static&podNamespace=default")
+	if err != nil {
+		t.Fatalf("Got error GETing: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the pod from the file to be found, got %d", resp.StatusCode)
+	}
+}
+
 func TestContainerInfo(t *testing.T) {
 	fw := newServerTest()
 	expectedInfo := &info.ContainerInfo{}
@@ -343,6 +423,45 @@ func TestRootInfo(t *testing.T) {
 	}
 }
 
+func TestPodStats(t *testing.T) {
+	fw := newServerTest()
+	expectedInfo := &info.ContainerInfo{
+		ContainerReference: info.ContainerReference{Name: "foo.bar.etcd", Aliases: []string{"12345"}},
+	}
+	fw.fakeKubelet.podStatsFunc = func(namespace, name string, req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+		if namespace != "bar" || name != "foo" {
+			t.Errorf("unexpected pod %s/%s", namespace, name)
+		}
+		if req.NumStats != 3 {
+			t.Errorf("expected 3 stats, got %d", req.NumStats)
+		}
+		return expectedInfo, nil
+	}
+
+	resp, err := http.Get(fw.testHTTPServer.URL + "/stats/pod/bar/foo?num_stats=3")
+	if err != nil {
+		t.Fatalf("Got error GETing: %v", err)
+	}
+	defer resp.Body.Close()
+	var receivedInfo info.ContainerInfo
+	err = json.NewDecoder(resp.Body).Decode(&receivedInfo)
+	if err != nil {
+		t.Fatalf("received invalid json data: %v", err)
+	}
+	if !reflect.DeepEqual(&receivedInfo, expectedInfo) {
+		t.Errorf("received wrong data: %#v", receivedInfo)
+	}
+
+	resp, err = http.Get(fw.testHTTPServer.URL + "/stats/pod/bar/foo?num_stats=x")
+	if err != nil {
+		t.Fatalf("Got error GETing: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
 func TestMachineInfo(t *testing.T) {
 	fw := newServerTest()
 	expectedInfo := &info.MachineInfo{
@@ -368,6 +487,34 @@ func TestMachineInfo(t *testing.T) {
 	}
 }
 
+func TestNodeConditions(t *testing.T) {
+	fw := newServerTest()
+	expected := []api.NodeCondition{
+		{
+			Kind:    api.NodeMemoryPressure,
+			Status:  api.ConditionTrue,
+			Reason:  "EvictionThresholdCrossed",
+			Message: "low on memory",
+		},
+	}
+	fw.fakeKubelet.conditionsFunc = func() []api.NodeCondition {
+		return expected
+	}
+
+	resp, err := http.Get(fw.testHTTPServer.URL + "/conditions")
+	if err != nil {
+		t.Fatalf("Got error GETing: %v", err)
+	}
+	defer resp.Body.Close()
+	var received []api.NodeCondition
+	if err := json.NewDecoder(resp.Body).Decode(&received); err != nil {
+		t.Fatalf("received invalid json data: %v", err)
+	}
+	if len(received) != 1 || received[0].Kind != expected[0].Kind || received[0].Status != expected[0].Status || received[0].Message != expected[0].Message {
+		t.Errorf("received wrong data: %#v", received)
+	}
+}
+
 func TestServeLogs(t *testing.T) {
 	fw := newServerTest()
 
@@ -436,6 +583,137 @@ func TestServeRunInContainer(t *testing.T) {
 	}
 }
 
+func dialStream(t *testing.T, fw *serverTestFramework, path string) *websocket.Conn {
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(fw.testHTTPServer.URL, "http")+path, "", fw.testHTTPServer.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return ws
+}
+
+// runFilePod makes the fake kubelet run the pod other/foo from a manifest file.
+func runFilePod(fw *serverTestFramework) {
+	fw.fakeKubelet.boundPodsFunc = func() ([]api.BoundPod, error) {
+		return []api.BoundPod{{
+			ObjectMeta: api.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "other",
+				Annotations: map[string]string{ConfigSourceAnnotationKey: "file"},
+			},
+		}}, nil
+	}
+}
+
+func TestServeExecInContainer(t *testing.T) {
+	fw := newServerTest()
+	runFilePod(fw)
+	fw.fakeKubelet.execFunc = func(podFullName, uuid, containerName string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+		if podFullName != "foo.other.file" || uuid != "12345" || containerName != "baz" {
+			t.Errorf("unexpected container %s %s %s", podFullName, uuid, containerName)
+		}
+		if !reflect.DeepEqual(cmd, []string{"cat", "-n"}) {
+			t.Errorf("unexpected command %v", cmd)
+		}
+		if !tty || stderr != nil {
+			t.Errorf("unexpected options: tty %v, stderr %v", tty, stderr)
+		}
+		io.Copy(stdout, stdin)
+		return errors.New("exit code 1")
+	}
+
+	ws := dialStream(t, fw, "/exec/other/foo/12345/baz?command=cat&command=-n&stdin=1&stdout=1&tty=1")
+	defer ws.Close()
+	var stdout bytes.Buffer
+	err := remotecommand.Stream(ws, strings.NewReader("hello"), &stdout, nil, nil)
+	if err == nil || err.Error() != "exit code 1" {
+		t.Errorf("expected the error of the command, got %v", err)
+	}
+	if stdout.String() != "hello" {
+		t.Errorf("expected the input echoed, got %q", stdout.String())
+	}
+}
+
+func TestServeExecInContainerWithoutCommand(t *testing.T) {
+	fw := newServerTest()
+	resp, err := http.Get(fw.testHTTPServer.URL + "/exec/other/foo/baz")
+	if err != nil {
+		t.Fatalf("Got error GETing: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func TestServeAttachContainer(t *testing.T) {
+	fw := newServerTest()
+	runFilePod(fw)
+	fw.fakeKubelet.attachFunc = func(podFullName, uuid, containerName string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+		if podFullName != "foo.other.file" || uuid != "" || containerName != "baz" {
+			t.Errorf("unexpected container %s %s %s", podFullName, uuid, containerName)
+		}
+		if stdin != nil || tty {
+			t.Errorf("unexpected options: stdin %v, tty %v", stdin, tty)
+		}
+		fmt.Fprint(stdout, "out")
+		fmt.Fprint(stderr, "err")
+		return nil
+	}
+
+	ws := dialStream(t, fw, "/attach/other/foo?container=baz&stdout=1&stderr=1")
+	defer ws.Close()
+	var stdout, stderr bytes.Buffer
+	if err := remotecommand.Stream(ws, nil, &stdout, &stderr, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if stdout.String() != "out" || stderr.String() != "err" {
+		t.Errorf("unexpected output %q %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestServePortForward(t *testing.T) {
+	fw := newServerTest()
+	runFilePod(fw)
+	fw.fakeKubelet.portForwardFunc = func(podFullName, uuid string, port uint16, stream io.ReadWriter) error {
+		if podFullName != "foo.other.file" || uuid != "12345" || port != 8080 {
+			t.Errorf("unexpected port forward to %s %s %d", podFullName, uuid, port)
+		}
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(stream, buf); err != nil {
+			return err
+		}
+		_, err := stream.Write(append([]byte("re:"), buf...))
+		return err
+	}
+
+	ws := dialStream(t, fw, "/portForward/other/foo/12345?port=8080")
+	defer ws.Close()
+	if _, err := ws.Write([]byte("ping")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := ioutil.ReadAll(ws)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if string(data) != "re:ping" {
+		t.Errorf("unexpected data %q", string(data))
+	}
+}
+
+func TestServePortForwardInvalidPort(t *testing.T) {
+	fw := newServerTest()
+	for _, port := range []string{"", "0", "http", "65536"} {
+		resp, err := http.Get(fw.testHTTPServer.URL + "/portForward/other/foo?port=" + port)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected bad request for port %q, got %d", port, resp.StatusCode)
+		}
+	}
+}
+
 func TestServeRunInContainerWithUUID(t *testing.T) {
 	fw := newServerTest()
 	output := "foo bar"
@@ -599,3 +877,91 @@ func TestContainerLogsWithFollow(t *testing.T) {
 		t.Errorf("Expected: '%v', got: '%v'", output, result)
 	}
 }
+
+type fakeAuthorizer func(a authorizer.Attributes) error
+
+func (f fakeAuthorizer) Authorize(a authorizer.Attributes) error {
+	return f(a)
+}
+
+func TestServeAuth(t *testing.T) {
+	// The bearer token is the name of the user: "admin" may do anything, "monitor"
+	// may only read stats.
+	authn := authenticator.RequestFunc(func(req *http.Request) (user.Info, bool, error) {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if token == "admin" || token == "monitor" {
+			return &user.DefaultInfo{Name: token}, true, nil
+		}
+		return nil, false, nil
+	})
+	var attrs []authorizer.Attributes
+	authz := fakeAuthorizer(func(a authorizer.Attributes) error {
+		attrs = append(attrs, a)
+		if a.GetUserName() == "admin" || (a.IsReadOnly() && a.GetKind() == "stats") {
+			return nil
+		}
+		return errors.New("forbidden")
+	})
+	fw := newServerTestWithAuth(NewKubeletAuth(authn, authz))
+	fw.fakeKubelet.rootInfoFunc = func(req *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+		return &info.ContainerInfo{}, nil
+	}
+	fw.fakeKubelet.machineInfoFunc = func() (*info.MachineInfo, error) {
+		return &info.MachineInfo{}, nil
+	}
+	fw.fakeKubelet.boundPodsFunc = func() ([]api.BoundPod, error) {
+		return []api.BoundPod{}, nil
+	}
+
+	tests := []struct {
+		user     string
+		method   string
+		path     string
+		code     int
+		kind     string
+		readOnly bool
+	}{
+		{user: "", method: "GET", path: "/healthz", code: http.StatusOK},
+		{user: "", method: "GET", path: "/stats/", code: http.StatusUnauthorized},
+		{user: "nobody", method: "GET", path: "/spec/", code: http.StatusUnauthorized},
+		{user: "monitor", method: "GET", path: "/stats/", code: http.StatusOK, kind: "stats", readOnly: true},
+		{user: "monitor", method: "GET", path: "/spec/", code: http.StatusOK, kind: "stats", readOnly: true},
+		{user: "monitor", method: "POST", path: "/stats/", code: http.StatusForbidden, kind: "stats"},
+		{user: "monitor", method: "GET", path: "/boundPods", code: http.StatusForbidden, kind: "pods", readOnly: true},
+		{user: "monitor", method: "POST", path: "/podOp", code: http.StatusForbidden, kind: "pods"},
+		{user: "monitor", method: "POST", path: "/exec/default/foo/bar", code: http.StatusForbidden, kind: "exec"},
+		{user: "monitor", method: "GET", path: "/containerLogs/default/foo/bar", code: http.StatusForbidden, kind: "logs", readOnly: true},
+		{user: "admin", method: "GET", path: "/boundPods", code: http.StatusOK, kind: "pods", readOnly: true},
+	}
+	for i, test := range tests {
+		attrs = nil
+		req, err := http.NewRequest(test.method, fw.testHTTPServer.URL+test.path, nil)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if test.user != "" {
+			req.Header.Set("Authorization", "Bearer "+test.user)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.code {
+			t.Errorf("%d: expected status %d for %s %s, got %d", i, test.code, test.method, test.path, resp.StatusCode)
+		}
+		if test.kind == "" {
+			if len(attrs) != 0 {
+				t.Errorf("%d: unexpected authorization: %#v", i, attrs)
+			}
+			continue
+		}
+		if len(attrs) != 1 {
+			t.Errorf("%d: expected one authorization, got %#v", i, attrs)
+			continue
+		}
+		if attrs[0].GetUserName() != test.user || attrs[0].GetKind() != test.kind || attrs[0].IsReadOnly() != test.readOnly {
+			t.Errorf("%d: unexpected attributes: %#v", i, attrs[0])
+		}
+	}
+}
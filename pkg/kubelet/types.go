@@ -17,11 +17,11 @@ limitations under the License.
 package kubelet
 
 import (
-	"fmt"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 )
 
-const ConfigSourceAnnotationKey = "kubernetes/config.source"
+const ConfigSourceAnnotationKey = api.ConfigSourceAnnotationKey
 
 // PodOperation defines what changes will be made on a pod configuration.
 type PodOperation int
@@ -97,5 +97,5 @@ type ApiResponse struct {
 
 // GetPodFullName returns a name that uniquely identifies a pod across all config sources.
 func GetPodFullName(pod *api.BoundPod) string {
-	return fmt.Sprintf("%s.%s.%s", pod.Name, pod.Namespace, pod.Annotations[ConfigSourceAnnotationKey])
+	return kubecontainer.GetPodFullName(pod)
 }
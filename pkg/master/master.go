@@ -39,6 +39,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/binding"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/boundpods"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/controller"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/endpoint"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/etcd"
@@ -46,6 +47,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/minion"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/pod"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/secret"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/service"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
@@ -98,7 +100,9 @@ type Master struct {
 	endpointRegistry      endpoint.Registry
 	minionRegistry        minion.Registry
 	bindingRegistry       binding.Registry
+	boundPodsRegistry     boundpods.Registry
 	eventRegistry         generic.Registry
+	secretRegistry        generic.Registry
 	storage               map[string]apiserver.RESTStorage
 	client                *client.Client
 	portalNet             *net.IPNet
@@ -229,7 +233,9 @@ func New(c *Config) *Master {
 		serviceRegistry:       serviceRegistry,
 		endpointRegistry:      etcd.NewRegistry(c.EtcdHelper, nil),
 		bindingRegistry:       etcd.NewRegistry(c.EtcdHelper, boundPodFactory),
+		boundPodsRegistry:     etcd.NewRegistry(c.EtcdHelper, nil),
 		eventRegistry:         event.NewEtcdRegistry(c.EtcdHelper, uint64(c.EventTTL.Seconds())),
+		secretRegistry:        secret.NewEtcdRegistry(c.EtcdHelper),
 		minionRegistry:        minionRegistry,
 		client:                c.Client,
 		portalNet:             c.PortalNet,
@@ -318,17 +324,24 @@ func (m *Master) init(c *Config) {
 	// TODO: Factor out the core API registration
 	m.storage = map[string]apiserver.RESTStorage{
 		"pods": pod.NewREST(&pod.RESTConfig{
-			PodCache: podCache,
-			Registry: m.podRegistry,
+			PodCache:    podCache,
+			Registry:    m.podRegistry,
+			KubeletInfo: c.KubeletClient,
 		}),
 		"replicationControllers": controller.NewREST(m.controllerRegistry, m.podRegistry),
 		"services":               service.NewREST(m.serviceRegistry, c.Cloud, m.minionRegistry, m.portalNet),
 		"endpoints":              endpoint.NewREST(m.endpointRegistry),
 		"minions":                minion.NewREST(m.minionRegistry),
 		"events":                 event.NewREST(m.eventRegistry),
+		"secrets":                secret.NewREST(m.secretRegistry),
 
 		// TODO: should appear only in scheduler API group.
 		"bindings": binding.NewREST(m.bindingRegistry),
+
+		// Read by the kubelets, the pods bound to each host.
+		"boundPods": boundpods.NewREST(m.boundPodsRegistry),
+		// Written by the kubelets, the mirrors of the pods they run from their own sources.
+		"mirrorPods": pod.NewMirrorREST(m.podRegistry),
 	}
 
 	apiserver.NewAPIGroupVersion(m.API_v1beta1()).InstallREST(m.handlerContainer, c.APIPrefix, "v1beta1")
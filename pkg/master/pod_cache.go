@@ -17,6 +17,7 @@ limitations under the License.
 package master
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -152,9 +153,15 @@ func (p *PodCache) computePodStatus(pod *api.Pod) (api.PodStatus, error) {
 		// TODO (hbo)
 		// Status PodUnknown is not defined, change it to PodFailed
 		newStatus.Phase = api.PodFailed
+		newStatus.Conditions = []api.PodCondition{{Kind: api.PodReady, Status: api.ConditionUnknown}}
 	} else {
 		newStatus.Info = info
 		newStatus.Phase = getPhase(&pod.Spec, newStatus.Info)
+		newStatus.Conditions = getPodConditions(&pod.Spec, newStatus.Info)
+		newStatus.Reason, newStatus.Message = "", ""
+		if newStatus.Phase == api.PodFailed {
+			newStatus.Reason, newStatus.Message = getFailureReason(&pod.Spec, newStatus.Info)
+		}
 		if netContainerInfo, ok := newStatus.Info["net"]; ok {
 			if netContainerInfo.PodIP != "" {
 				newStatus.PodIP = netContainerInfo.PodIP
@@ -209,6 +216,10 @@ func getPhase(spec *api.PodSpec, info api.PodInfo) api.PodPhase {
 	if info == nil {
 		return api.PodPending
 	}
+	if _, failed := failedInitContainer(spec, info); failed && spec.RestartPolicy.Never != nil {
+		// The containers never start once an init container failed for good.
+		return api.PodFailed
+	}
 	running := 0
 	stopped := 0
 	unknown := 0
@@ -236,3 +247,46 @@ func getPhase(spec *api.PodSpec, info api.PodInfo) api.PodPhase {
 		return api.PodPending
 	}
 }
+
+// getPodConditions returns the conditions of a pod given its container info.
+// The pod is ready when all of its containers report ready.
+func getPodConditions(spec *api.PodSpec, info api.PodInfo) []api.PodCondition {
+	status := api.ConditionTrue
+	for _, container := range spec.Containers {
+		if containerStatus, ok := info[container.Name]; !ok || !containerStatus.Ready {
+			status = api.ConditionFalse
+			break
+		}
+	}
+	return []api.PodCondition{{Kind: api.PodReady, Status: status}}
+}
+
+// getFailureReason returns the reason and the message of the termination of the first container
+// of a failed pod which reports one, e.g. its eviction by the kubelet.
+func getFailureReason(spec *api.PodSpec, info api.PodInfo) (string, string) {
+	if name, failed := failedInitContainer(spec, info); failed {
+		termination := info[name].State.Termination
+		return "InitContainerFailed", fmt.Sprintf("init container %s exited with code %d", name, termination.ExitCode)
+	}
+	for _, container := range spec.Containers {
+		if containerStatus, ok := info[container.Name]; ok {
+			if termination := containerStatus.State.Termination; termination != nil && termination.Reason != "" {
+				return termination.Reason, termination.Message
+			}
+		}
+	}
+	return "", ""
+}
+
+// failedInitContainer returns the name of the first init container of a pod whose last run
+// failed, if any.
+func failedInitContainer(spec *api.PodSpec, info api.PodInfo) (string, bool) {
+	for _, container := range spec.InitContainers {
+		if containerStatus, ok := info[container.Name]; ok {
+			if termination := containerStatus.State.Termination; termination != nil && termination.ExitCode != 0 {
+				return container.Name, true
+			}
+		}
+	}
+	return "", false
+}
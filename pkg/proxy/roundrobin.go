@@ -48,23 +48,24 @@ func NewLoadBalancerRR() *LoadBalancerRR {
 }
 
 // NextEndpoint returns a service endpoint.
-// The service endpoint is chosen using the round-robin algorithm.
+// The service endpoint is chosen using the round-robin algorithm among the
+// endpoints of the last update, which only holds the backends that are ready.
+// Established connections to a backend which went away are left alone.
 func (lb *LoadBalancerRR) NextEndpoint(service string, srcAddr net.Addr) (string, error) {
-	lb.lock.RLock()
+	// The index is read and advanced under the same lock so that an update
+	// which shrinks the endpoints in between cannot leave it out of range.
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
 	endpoints, exists := lb.endpointsMap[service]
-	index := lb.rrIndex[service]
-	lb.lock.RUnlock()
 	if !exists {
 		return "", ErrMissingServiceEntry
 	}
 	if len(endpoints) == 0 {
 		return "", ErrMissingEndpoints
 	}
-	endpoint := endpoints[index]
-	lb.lock.Lock()
+	index := lb.rrIndex[service] % len(endpoints)
 	lb.rrIndex[service] = (index + 1) % len(endpoints)
-	lb.lock.Unlock()
-	return endpoint, nil
+	return endpoints[index], nil
 }
 
 func isValidEndpoint(spec string) bool {
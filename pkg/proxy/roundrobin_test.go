@@ -192,3 +192,31 @@ func TestLoadBalanceWorksWithServiceRemoval(t *testing.T) {
 	expectEndpoint(t, loadBalancer, "bar", "endpoint:5")
 	expectEndpoint(t, loadBalancer, "bar", "endpoint:4")
 }
+
+func TestLoadBalanceSkipsRemovedEndpoint(t *testing.T) {
+	loadBalancer := NewLoadBalancerRR()
+	endpoints := make([]api.Endpoints, 1)
+	endpoints[0] = api.Endpoints{
+		ObjectMeta: api.ObjectMeta{Name: "foo"},
+		Endpoints:  []string{"endpoint:1", "endpoint:2", "endpoint:3"},
+	}
+	loadBalancer.OnUpdate(endpoints)
+	expectEndpoint(t, loadBalancer, "foo", "endpoint:1")
+	expectEndpoint(t, loadBalancer, "foo", "endpoint:2")
+
+	// endpoint:2 becomes unready and is dropped from the endpoints.
+	endpoints[0] = api.Endpoints{
+		ObjectMeta: api.ObjectMeta{Name: "foo"},
+		Endpoints:  []string{"endpoint:1", "endpoint:3"},
+	}
+	loadBalancer.OnUpdate(endpoints)
+	for i := 0; i < 4; i++ {
+		endpoint, err := loadBalancer.NextEndpoint("foo", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if endpoint == "endpoint:2" {
+			t.Errorf("unready endpoint was chosen")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package boundpods
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// REST implements the RESTStorage interface for the bound pods of the hosts. The bound
+// pods are read-only, they change with the bindings of the pods.
+type REST struct {
+	registry Registry
+}
+
+// NewREST creates a new REST backed by the given registry.
+func NewREST(registry Registry) *REST {
+	return &REST{
+		registry: registry,
+	}
+}
+
+// hostOf returns the host the field selector requires.
+func hostOf(field labels.Selector) (string, error) {
+	host, ok := field.RequiresExactMatch("host")
+	if !ok || host == "" {
+		return "", errors.NewBadRequest(fmt.Sprintf("bound pods must be selected by host, got %q", field.String()))
+	}
+	return host, nil
+}
+
+// Get returns the pods bound to the host id.
+func (rs *REST) Get(ctx api.Context, id string) (runtime.Object, error) {
+	return rs.registry.GetBoundPods(ctx, id)
+}
+
+// List returns the pods bound to the host the field selector requires.
+func (rs *REST) List(ctx api.Context, label, field labels.Selector) (runtime.Object, error) {
+	host, err := hostOf(field)
+	if err != nil {
+		return nil, err
+	}
+	return rs.registry.GetBoundPods(ctx, host)
+}
+
+// Watch watches the pods bound to the host the field selector requires.
+func (rs *REST) Watch(ctx api.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	host, err := hostOf(field)
+	if err != nil {
+		return nil, err
+	}
+	return rs.registry.WatchBoundPods(ctx, host, resourceVersion)
+}
+
+// New returns a new bound pods object.
+func (*REST) New() runtime.Object {
+	return &api.BoundPods{}
+}
+
+// Create returns an error because the bound pods change with the bindings.
+func (*REST) Create(ctx api.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	return nil, fmt.Errorf("bound pods may not be created, they change with the bindings of the pods")
+}
+
+// Update returns an error because the bound pods change with the bindings.
+func (*REST) Update(ctx api.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	return nil, fmt.Errorf("bound pods may not be changed, they change with the bindings of the pods")
+}
+
+// Delete returns an error because the bound pods change with the bindings.
+func (*REST) Delete(ctx api.Context, id string) (<-chan apiserver.RESTResult, error) {
+	return nil, fmt.Errorf("bound pods may not be deleted, they change with the bindings of the pods")
+}
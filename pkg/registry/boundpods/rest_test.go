@@ -0,0 +1,103 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package boundpods
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// fakeRegistry serves the bound pods of its hosts, and records the watches.
+type fakeRegistry struct {
+	boundPods map[string]*api.BoundPods
+	watches   []string
+	watcher   *watch.FakeWatcher
+}
+
+func (r *fakeRegistry) GetBoundPods(ctx api.Context, host string) (*api.BoundPods, error) {
+	if boundPods, ok := r.boundPods[host]; ok {
+		return boundPods, nil
+	}
+	return &api.BoundPods{Host: host}, nil
+}
+
+func (r *fakeRegistry) WatchBoundPods(ctx api.Context, host, resourceVersion string) (watch.Interface, error) {
+	r.watches = append(r.watches, host+"@"+resourceVersion)
+	return r.watcher, nil
+}
+
+func TestRESTGet(t *testing.T) {
+	machine := &api.BoundPods{Host: "machine", Items: []api.BoundPod{{ObjectMeta: api.ObjectMeta{Name: "foo"}}}}
+	rest := NewREST(&fakeRegistry{boundPods: map[string]*api.BoundPods{"machine": machine}})
+	ctx := api.NewContext()
+
+	obj, err := rest.Get(ctx, "machine")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(machine, obj) {
+		t.Errorf("expected %#v, got %#v", machine, obj)
+	}
+	obj, err = rest.List(ctx, labels.Everything(), labels.Set{"host": "machine"}.AsSelector())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(machine, obj) {
+		t.Errorf("expected %#v, got %#v", machine, obj)
+	}
+	if _, err := rest.List(ctx, labels.Everything(), labels.Everything()); err == nil {
+		t.Errorf("expected an error listing without a host")
+	}
+}
+
+func TestRESTWatch(t *testing.T) {
+	registry := &fakeRegistry{watcher: watch.NewFake()}
+	rest := NewREST(registry)
+	ctx := api.NewContext()
+
+	watching, err := rest.Watch(ctx, labels.Everything(), labels.Set{"host": "machine"}.AsSelector(), "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if watching != registry.watcher {
+		t.Errorf("expected the watch of the registry")
+	}
+	if e, a := []string{"machine@5"}, registry.watches; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected watches %v, got %v", e, a)
+	}
+	if _, err := rest.Watch(ctx, labels.Everything(), labels.Set{"name": "machine"}.AsSelector(), ""); err == nil {
+		t.Errorf("expected an error watching without a host")
+	}
+}
+
+func TestRESTUnsupported(t *testing.T) {
+	rest := NewREST(&fakeRegistry{})
+	ctx := api.NewContext()
+	if _, err := rest.Create(ctx, &api.BoundPods{}); err == nil {
+		t.Errorf("unexpected non-error")
+	}
+	if _, err := rest.Update(ctx, &api.BoundPods{}); err == nil {
+		t.Errorf("unexpected non-error")
+	}
+	if _, err := rest.Delete(ctx, "machine"); err == nil {
+		t.Errorf("unexpected non-error")
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/pod"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/golang/glog"
@@ -156,11 +157,44 @@ func makeBoundPodsKey(machine string) string {
 	return "/registry/nodes/" + machine + "/boundpods"
 }
 
+// setBoundPodsHost sets the host of the bound pods, which the bindings do not store.
+func setBoundPodsHost(boundPods *api.BoundPods, host string) {
+	boundPods.Name = host
+	boundPods.Host = host
+}
+
+// GetBoundPods gets the pods bound to the host, an empty list when none was ever bound.
+func (r *Registry) GetBoundPods(ctx api.Context, host string) (*api.BoundPods, error) {
+	boundPods := &api.BoundPods{}
+	if err := r.ExtractObj(makeBoundPodsKey(host), boundPods, true); err != nil {
+		return nil, etcderr.InterpretGetError(err, "boundPods", host)
+	}
+	setBoundPodsHost(boundPods, host)
+	return boundPods, nil
+}
+
+// WatchBoundPods begins watching the pods bound to the host, after the resourceVersion.
+func (r *Registry) WatchBoundPods(ctx api.Context, host, resourceVersion string) (watch.Interface, error) {
+	version, err := tools.ParseWatchResourceVersion(resourceVersion, "boundPods")
+	if err != nil {
+		return nil, err
+	}
+	return r.WatchAndTransform(makeBoundPodsKey(host), version, func(obj runtime.Object) (runtime.Object, error) {
+		if boundPods, ok := obj.(*api.BoundPods); ok {
+			setBoundPodsHost(boundPods, host)
+		}
+		return obj, nil
+	}), nil
+}
+
 // CreatePod creates a pod based on a specification.
 func (r *Registry) CreatePod(ctx api.Context, pod *api.Pod) error {
 	// Set current status to "Waiting".
 	pod.Status.Phase = api.PodPending
-	pod.Status.Host = ""
+	// Mirror pods already run on their host, without being bound to it.
+	if !api.IsMirrorPod(pod) {
+		pod.Status.Host = ""
+	}
 	pod.Status.SchedulerFailureCount = 0
 	key, err := makePodKey(ctx, pod.Name)
 	if err != nil {
@@ -275,6 +309,8 @@ func (r *Registry) UpdatePod(ctx api.Context, pod *api.Pod) error {
 	if err != nil {
 		return err
 	}
+	// Only a graceful deletion marks a pod as terminating.
+	pod.DeletionTimestamp = podOut.DeletionTimestamp
 	scheduled := podOut.Status.Host != ""
 	if scheduled {
 		pod.Status.Host = podOut.Status.Host
@@ -331,8 +367,8 @@ func (r *Registry) DeletePod(ctx api.Context, podID string) error {
 		return etcderr.InterpretDeleteError(err, "pod", podID)
 	}
 	machine := pod.Status.Host
-	if machine == "" {
-		// Pod was never scheduled anywhere, just return.
+	if machine == "" || api.IsMirrorPod(&pod) {
+		// Pod was never scheduled anywhere, or its kubelet runs it from its own source.
 		return nil
 	}
 	// Next, remove the pod from the machine atomically.
@@ -359,6 +395,52 @@ func (r *Registry) DeletePod(ctx api.Context, podID string) error {
 	})
 }
 
+// TerminatePod marks an existing pod for deletion at deletionTimestamp, in the pod and in the
+// bound pods of its machine so its kubelet stops its containers. A pod which is already
+// terminating keeps its deletion timestamp.
+func (r *Registry) TerminatePod(ctx api.Context, podID string, deletionTimestamp util.Time) error {
+	podKey, err := makePodKey(ctx, podID)
+	if err != nil {
+		return err
+	}
+	var machine string
+	err = r.AtomicUpdate(podKey, &api.Pod{}, func(obj runtime.Object) (runtime.Object, error) {
+		pod, ok := obj.(*api.Pod)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object: %#v", obj)
+		}
+		if len(pod.Name) == 0 {
+			// Don't create the pod which was already deleted.
+			return nil, errors.NewNotFound("pod", podID)
+		}
+		if pod.DeletionTimestamp == nil {
+			pod.DeletionTimestamp = &deletionTimestamp
+		}
+		deletionTimestamp = *pod.DeletionTimestamp
+		machine = pod.Status.Host
+		return pod, nil
+	})
+	if err != nil {
+		return err
+	}
+	if machine == "" {
+		return nil
+	}
+	contKey := makeBoundPodsKey(machine)
+	return r.AtomicUpdate(contKey, &api.BoundPods{}, func(in runtime.Object) (runtime.Object, error) {
+		boundPods := in.(*api.BoundPods)
+		for ix := range boundPods.Items {
+			if boundPods.Items[ix].Name == podID {
+				boundPods.Items[ix].DeletionTimestamp = &deletionTimestamp
+				return boundPods, nil
+			}
+		}
+		// The pod is terminating anyway, its kubelet will kill its containers once it is deleted.
+		glog.Warningf("Couldn't find: %s in %#v", podID, boundPods)
+		return boundPods, nil
+	})
+}
+
 // ListControllers obtains a list of ReplicationControllers.
 func (r *Registry) ListControllers(ctx api.Context) (*api.ReplicationControllerList, error) {
 	controllers := &api.ReplicationControllerList{}
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
@@ -30,6 +31,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/registrytest"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	"github.com/coreos/go-etcd/etcd"
 )
@@ -583,6 +585,128 @@ func TestEtcdDeletePod(t *testing.T) {
 	}
 }
 
+func TestEtcdTerminatePod(t *testing.T) {
+	ctx := api.NewDefaultContext()
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+
+	key, _ := makePodKey(ctx, "foo")
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "foo"},
+		Status:     api.PodStatus{Host: "machine"},
+	}), 1)
+	fakeClient.Set("/registry/nodes/machine/boundpods", runtime.EncodeOrDie(latest.Codec, &api.BoundPods{
+		Items: []api.BoundPod{
+			{ObjectMeta: api.ObjectMeta{Name: "foo"}},
+			{ObjectMeta: api.ObjectMeta{Name: "bar"}},
+		},
+	}), 1)
+	registry := NewTestEtcdRegistry(fakeClient)
+	deletionTimestamp := util.Date(2015, 1, 1, 0, 0, 30, 0, time.UTC)
+	if err := registry.TerminatePod(ctx, "foo", deletionTimestamp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Terminating the pod again keeps its first deletion timestamp.
+	if err := registry.TerminatePod(ctx, "foo", util.Date(2015, 1, 1, 0, 1, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fakeClient.DeletedKeys) != 0 {
+		t.Errorf("Expected no delete, found %#v", fakeClient.DeletedKeys)
+	}
+	pod, err := registry.GetPod(ctx, "foo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.DeletionTimestamp == nil || !pod.DeletionTimestamp.Equal(deletionTimestamp.Time) {
+		t.Errorf("Unexpected deletion timestamp: %v", pod.DeletionTimestamp)
+	}
+	response, err := fakeClient.Get("/registry/nodes/machine/boundpods", false, false)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	var boundPods api.BoundPods
+	latest.Codec.DecodeInto([]byte(response.Node.Value), &boundPods)
+	if len(boundPods.Items) != 2 {
+		t.Fatalf("Unexpected container set: %s", response.Node.Value)
+	}
+	if ts := boundPods.Items[0].DeletionTimestamp; ts == nil || !ts.Equal(deletionTimestamp.Time) {
+		t.Errorf("Unexpected deletion timestamp of the bound pod: %v", ts)
+	}
+	if ts := boundPods.Items[1].DeletionTimestamp; ts != nil {
+		t.Errorf("Unexpected deletion timestamp of the other bound pod: %v", ts)
+	}
+}
+
+func TestEtcdTerminatePodNotFound(t *testing.T) {
+	ctx := api.NewDefaultContext()
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	key, _ := makePodKey(ctx, "foo")
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: nil,
+		},
+		E: tools.EtcdErrorNotFound,
+	}
+	registry := NewTestEtcdRegistry(fakeClient)
+	err := registry.TerminatePod(ctx, "foo", util.Now())
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if _, found := fakeClient.Data[key]; found && fakeClient.Data[key].R.Node != nil {
+		t.Errorf("Unexpected pod created: %#v", fakeClient.Data[key])
+	}
+}
+
+func TestEtcdCreateDeleteMirrorPod(t *testing.T) {
+	ctx := api.NewDefaultContext()
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	key, _ := makePodKey(ctx, "foo")
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: nil,
+		},
+		E: tools.EtcdErrorNotFound,
+	}
+	boundPods := runtime.EncodeOrDie(latest.Codec, &api.BoundPods{})
+	fakeClient.Set("/registry/nodes/machine/boundpods", boundPods, 0)
+	registry := NewTestEtcdRegistry(fakeClient)
+	err := registry.CreatePod(ctx, &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Annotations: map[string]string{api.ConfigSourceAnnotationKey: "file"},
+		},
+		Status: api.PodStatus{Host: "machine"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pod, err := registry.GetPod(ctx, "foo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Status.Host != "machine" {
+		t.Errorf("Expected the mirror pod to keep its host, got %q", pod.Status.Host)
+	}
+
+	if err := registry.DeletePod(ctx, "foo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fakeClient.DeletedKeys) != 1 || fakeClient.DeletedKeys[0] != key {
+		t.Errorf("Unexpected deletes: %#v", fakeClient.DeletedKeys)
+	}
+	// The pods the kubelet runs from its own source are never bound to it.
+	response, err := fakeClient.Get("/registry/nodes/machine/boundpods", false, false)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if response.Node.Value != boundPods {
+		t.Errorf("Unexpected bound pods: %s", response.Node.Value)
+	}
+}
+
 func TestEtcdDeletePodMultipleContainers(t *testing.T) {
 	ctx := api.NewDefaultContext()
 	fakeClient := tools.NewFakeEtcdClient(t)
@@ -1219,6 +1343,67 @@ func TestEtcdUpdateEndpoints(t *testing.T) {
 	}
 }
 
+func TestEtcdGetBoundPods(t *testing.T) {
+	ctx := api.NewContext()
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.Set(makeBoundPodsKey("machine"), runtime.EncodeOrDie(latest.Codec, &api.BoundPods{
+		Items: []api.BoundPod{{ObjectMeta: api.ObjectMeta{Name: "foo"}}},
+	}), 0)
+	fakeClient.Data[makeBoundPodsKey("other")] = tools.EtcdResponseWithError{
+		R: &etcd.Response{Node: nil},
+		E: tools.EtcdErrorNotFound,
+	}
+	registry := NewTestEtcdRegistry(fakeClient)
+	boundPods, err := registry.GetBoundPods(ctx, "machine")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if boundPods.Name != "machine" || boundPods.Host != "machine" || len(boundPods.Items) != 1 || boundPods.Items[0].Name != "foo" {
+		t.Errorf("Unexpected bound pods: %#v", boundPods)
+	}
+
+	boundPods, err = registry.GetBoundPods(ctx, "other")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if boundPods.Host != "other" || len(boundPods.Items) != 0 {
+		t.Errorf("Unexpected bound pods: %#v", boundPods)
+	}
+}
+
+func TestEtcdWatchBoundPods(t *testing.T) {
+	ctx := api.NewContext()
+	fakeClient := tools.NewFakeEtcdClient(t)
+	registry := NewTestEtcdRegistry(fakeClient)
+	watching, err := registry.WatchBoundPods(ctx, "machine", "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeClient.WaitForWatchCompletion()
+	if fakeClient.WatchIndex != 6 {
+		t.Errorf("Expected the watch to start after the resource version, got %d", fakeClient.WatchIndex)
+	}
+
+	boundPods := &api.BoundPods{Items: []api.BoundPod{{ObjectMeta: api.ObjectMeta{Name: "foo"}}}}
+	fakeClient.WatchResponse <- &etcd.Response{
+		Action: "set",
+		Node: &etcd.Node{
+			Value:         runtime.EncodeOrDie(latest.Codec, boundPods),
+			ModifiedIndex: 7,
+		},
+	}
+	event := <-watching.ResultChan()
+	got, ok := event.Object.(*api.BoundPods)
+	if !ok || got.Host != "machine" || len(got.Items) != 1 || got.ResourceVersion != "7" {
+		t.Errorf("Unexpected event: %#v", event)
+	}
+	watching.Stop()
+
+	if _, err := registry.WatchBoundPods(ctx, "machine", "abc"); err == nil {
+		t.Errorf("unexpected non-error")
+	}
+}
+
 func TestEtcdWatchServices(t *testing.T) {
 	ctx := api.NewDefaultContext()
 	fakeClient := tools.NewFakeEtcdClient(t)
@@ -51,9 +51,24 @@ func (r *HealthyRegistry) GetMinion(ctx api.Context, minionID string) (*api.Mini
 	if status == health.Unhealthy {
 		return nil, ErrNotHealty
 	}
+	r.setConditions(minion)
 	return minion, nil
 }
 
+// setConditions fills the conditions of a minion in from its kubelet, if the client can get them.
+func (r *HealthyRegistry) setConditions(minion *api.Minion) {
+	getter, ok := r.client.(client.NodeConditionsGetter)
+	if !ok {
+		return
+	}
+	conditions, err := getter.GetNodeConditions(minion.Name)
+	if err != nil {
+		glog.V(1).Infof("Failed to get the conditions of minion %s: %v", minion.Name, err)
+		return
+	}
+	minion.Status.Conditions = conditions
+}
+
 func (r *HealthyRegistry) DeleteMinion(ctx api.Context, minionID string) error {
 	return r.delegate.DeleteMinion(ctx, minionID)
 }
@@ -79,6 +94,7 @@ func (r *HealthyRegistry) ListMinions(ctx api.Context) (currentMinions *api.Mini
 			continue
 		}
 		if status == health.Healthy {
+			r.setConditions(&minion)
 			result.Items = append(result.Items, minion)
 		} else {
 			glog.Errorf("%#v failed a health check, ignoring.", minion)
@@ -102,3 +102,44 @@ func TestFiltering(t *testing.T) {
 		t.Errorf("Unexpected presence of 'm1'")
 	}
 }
+
+type pressuredMinion struct {
+	alwaysYes
+	minion string
+}
+
+func (p *pressuredMinion) GetNodeConditions(host string) ([]api.NodeCondition, error) {
+	if host != p.minion {
+		return []api.NodeCondition{}, nil
+	}
+	return []api.NodeCondition{{Kind: api.NodeMemoryPressure, Status: api.ConditionTrue}}, nil
+}
+
+func TestConditions(t *testing.T) {
+	ctx := api.NewContext()
+	mockMinionRegistry := registrytest.NewMinionRegistry([]string{"m1", "m2"}, api.NodeResources{})
+	healthy := HealthyRegistry{
+		delegate: mockMinionRegistry,
+		client:   &pressuredMinion{minion: "m1"},
+	}
+	list, err := healthy.ListMinions(ctx)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("unexpected minions: %v", list)
+	}
+	if conditions := list.Items[0].Status.Conditions; len(conditions) != 1 || conditions[0].Kind != api.NodeMemoryPressure {
+		t.Errorf("unexpected conditions of m1: %v", conditions)
+	}
+	if conditions := list.Items[1].Status.Conditions; len(conditions) != 0 {
+		t.Errorf("unexpected conditions of m2: %v", conditions)
+	}
+	minion, err := healthy.GetMinion(ctx, "m1")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !api.IsNodeUnderPressure(minion, api.NodeMemoryPressure) {
+		t.Errorf("expected memory pressure on m1: %v", minion)
+	}
+}
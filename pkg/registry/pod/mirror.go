@@ -0,0 +1,114 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// MirrorREST implements the RESTStorage interface the kubelets mirror the pods they run
+// from their own files and URLs with. The mirror pods are read like any other pod, but
+// only created and deleted through it.
+type MirrorREST struct {
+	registry Registry
+}
+
+// NewMirrorREST returns a new MirrorREST.
+func NewMirrorREST(registry Registry) *MirrorREST {
+	return &MirrorREST{
+		registry: registry,
+	}
+}
+
+// Create creates the mirror of a pod run by the kubelet of the pod host.
+func (rs *MirrorREST) Create(ctx api.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	pod := obj.(*api.Pod)
+	if !api.ValidNamespace(ctx, &pod.ObjectMeta) {
+		return nil, errors.NewConflict("pod", pod.Namespace, fmt.Errorf("Pod.Namespace does not match the provided context"))
+	}
+	if !api.IsMirrorPod(pod) || len(pod.Status.Host) == 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("mirror pod %s must have the %s annotation and a host", pod.Name, api.ConfigSourceAnnotationKey))
+	}
+	api.FillObjectMetaSystemFields(ctx, &pod.ObjectMeta)
+	if pod.Spec.NetworkMode == "" {
+		pod.Spec.NetworkMode = api.PodNetworkModeBridge
+	}
+	if errs := validation.ValidatePod(pod); len(errs) > 0 {
+		return nil, errors.NewInvalid("pod", pod.Name, errs)
+	}
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if err := rs.registry.CreatePod(ctx, pod); err != nil {
+			return nil, err
+		}
+		return rs.registry.GetPod(ctx, pod.Name)
+	}), nil
+}
+
+// Delete deletes a mirror pod, other pods are deleted through the pods.
+func (rs *MirrorREST) Delete(ctx api.Context, id string) (<-chan apiserver.RESTResult, error) {
+	if _, err := rs.getMirror(ctx, id); err != nil {
+		return nil, err
+	}
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return &api.Status{Status: api.StatusSuccess}, rs.registry.DeletePod(ctx, id)
+	}), nil
+}
+
+// Get returns a mirror pod.
+func (rs *MirrorREST) Get(ctx api.Context, id string) (runtime.Object, error) {
+	return rs.getMirror(ctx, id)
+}
+
+// getMirror returns the pod id, or a not found error if it is not a mirror pod.
+func (rs *MirrorREST) getMirror(ctx api.Context, id string) (*api.Pod, error) {
+	pod, err := rs.registry.GetPod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pod == nil || !api.IsMirrorPod(pod) {
+		return nil, errors.NewNotFound("mirrorPod", id)
+	}
+	return pod, nil
+}
+
+// List returns the mirror pods of the host the field selector requires.
+func (rs *MirrorREST) List(ctx api.Context, label, field labels.Selector) (runtime.Object, error) {
+	host, ok := field.RequiresExactMatch("host")
+	if !ok || host == "" {
+		return nil, errors.NewBadRequest(fmt.Sprintf("mirror pods must be selected by host, got %q", field.String()))
+	}
+	return rs.registry.ListPodsPredicate(ctx, func(pod *api.Pod) bool {
+		return api.IsMirrorPod(pod) && pod.Status.Host == host && label.Matches(labels.Set(pod.Labels))
+	})
+}
+
+// New returns a new pod.
+func (*MirrorREST) New() runtime.Object {
+	return &api.Pod{}
+}
+
+// Update returns an error because the kubelets replace the mirror pods which change.
+func (*MirrorREST) Update(ctx api.Context, obj runtime.Object) (<-chan apiserver.RESTResult, error) {
+	return nil, fmt.Errorf("mirror pods may not be changed, they are deleted and created again")
+}
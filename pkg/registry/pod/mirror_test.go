@@ -0,0 +1,119 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/registrytest"
+)
+
+func newMirrorPod(name, host string) *api.Pod {
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        name,
+			Namespace:   api.NamespaceDefault,
+			Annotations: map[string]string{api.ConfigSourceAnnotationKey: "file"},
+		},
+		Spec: api.PodSpec{
+			RestartPolicy: api.RestartPolicy{Always: &api.RestartPolicyAlways{}},
+		},
+		Status: api.PodStatus{Host: host},
+	}
+}
+
+func TestMirrorCreate(t *testing.T) {
+	podRegistry := registrytest.NewPodRegistry(nil)
+	storage := NewMirrorREST(podRegistry)
+	ctx := api.NewDefaultContext()
+
+	channel, err := storage.Create(ctx, newMirrorPod("foo", "machine"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-channel:
+	case <-time.After(time.Millisecond * 100):
+		t.Fatalf("Unexpected timeout on async channel")
+	}
+	if !api.HasObjectMetaSystemFieldValues(&podRegistry.Pod.ObjectMeta) || podRegistry.Pod.Status.Host != "machine" {
+		t.Errorf("Unexpected pod: %#v", podRegistry.Pod)
+	}
+
+	pod := newMirrorPod("bar", "")
+	if _, err := storage.Create(ctx, pod); !errors.IsBadRequest(err) {
+		t.Errorf("Expected a bad request for a mirror pod without a host, got %v", err)
+	}
+	pod = newMirrorPod("bar", "machine")
+	pod.Annotations = nil
+	if _, err := storage.Create(ctx, pod); !errors.IsBadRequest(err) {
+		t.Errorf("Expected a bad request for a pod without the source annotation, got %v", err)
+	}
+}
+
+func TestMirrorDeleteAndList(t *testing.T) {
+	pod := newMirrorPod("foo", "machine")
+	other := newMirrorPod("bar", "other")
+	podRegistry := registrytest.NewPodRegistry(&api.PodList{
+		Items: []api.Pod{*pod, *other, {ObjectMeta: api.ObjectMeta{Name: "baz"}, Status: api.PodStatus{Host: "machine"}}},
+	})
+	storage := NewMirrorREST(podRegistry)
+	ctx := api.NewDefaultContext()
+
+	obj, err := storage.List(ctx, labels.Everything(), labels.Set{"host": "machine"}.AsSelector())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pods := obj.(*api.PodList); len(pods.Items) != 1 || pods.Items[0].Name != "foo" {
+		t.Errorf("Unexpected mirror pods: %#v", pods)
+	}
+	if _, err := storage.List(ctx, labels.Everything(), labels.Everything()); !errors.IsBadRequest(err) {
+		t.Errorf("Expected a bad request without a host, got %v", err)
+	}
+
+	podRegistry.Pod = &api.Pod{ObjectMeta: api.ObjectMeta{Name: "baz"}}
+	if _, err := storage.Delete(ctx, "baz"); !errors.IsNotFound(err) {
+		t.Errorf("Expected the pod which is not a mirror to be not found, got %v", err)
+	}
+	podRegistry.Pod = pod
+	if _, err := storage.Delete(ctx, "foo"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPodsRejectMirrorChanges(t *testing.T) {
+	podRegistry := registrytest.NewPodRegistry(nil)
+	podRegistry.Pod = newMirrorPod("foo", "machine")
+	storage := NewREST(&RESTConfig{Registry: podRegistry})
+	ctx := api.NewDefaultContext()
+
+	if _, err := storage.Delete(ctx, "foo"); !errors.IsConflict(err) {
+		t.Errorf("Expected a conflict deleting a mirror pod, got %v", err)
+	}
+	pod := newMirrorPod("foo", "")
+	pod.Annotations = nil
+	if _, err := storage.Update(ctx, pod); !errors.IsConflict(err) {
+		t.Errorf("Expected a conflict updating a mirror pod, got %v", err)
+	}
+	if _, err := storage.Create(ctx, newMirrorPod("bar", "")); !errors.IsInvalid(err) {
+		t.Errorf("Expected creating a mirror pod to be invalid, got %v", err)
+	}
+}
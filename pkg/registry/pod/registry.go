@@ -19,6 +19,7 @@ package pod
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 )
 
@@ -38,4 +39,7 @@ type Registry interface {
 	UpdatePod(ctx api.Context, pod *api.Pod) error
 	// Delete an existing pod
 	DeletePod(ctx api.Context, podID string) error
+	// TerminatePod marks an existing pod for deletion at deletionTimestamp, its kubelet stops
+	// its containers meanwhile.
+	TerminatePod(ctx api.Context, podID string, deletionTimestamp util.Time) error
 }
@@ -18,14 +18,23 @@ package pod
 
 import (
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta1"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/master/ports"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 )
 
@@ -35,20 +44,25 @@ type PodStatusGetter interface {
 
 // REST implements the RESTStorage interface in terms of a PodRegistry.
 type REST struct {
-	podCache PodStatusGetter
-	registry Registry
+	podCache    PodStatusGetter
+	registry    Registry
+	kubeletInfo client.ConnectionInfoGetter
 }
 
 type RESTConfig struct {
 	PodCache PodStatusGetter
 	Registry Registry
+	// KubeletInfo tells how the subresources served by the kubelets are reached. If nil, they
+	// are reached over HTTP on the default kubelet port.
+	KubeletInfo client.ConnectionInfoGetter
 }
 
 // NewREST returns a new REST.
 func NewREST(config *RESTConfig) *REST {
 	return &REST{
-		podCache: config.PodCache,
-		registry: config.Registry,
+		podCache:    config.PodCache,
+		registry:    config.Registry,
+		kubeletInfo: config.KubeletInfo,
 	}
 }
 
@@ -69,6 +83,9 @@ func (rs *REST) Create(ctx api.Context, obj runtime.Object) (<-chan apiserver.RE
 	if errs := validation.ValidatePod(pod); len(errs) > 0 {
 		return nil, errors.NewInvalid("pod", pod.Name, errs)
 	}
+	if err := forbidMirrorAnnotation(pod); err != nil {
+		return nil, err
+	}
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
 		if err := rs.registry.CreatePod(ctx, pod); err != nil {
 			return nil, err
@@ -77,12 +94,79 @@ func (rs *REST) Create(ctx api.Context, obj runtime.Object) (<-chan apiserver.RE
 	}), nil
 }
 
+// forbidMirrorAnnotation returns an error if the pod claims to be the mirror of a pod a
+// kubelet runs, only the kubelets create mirror pods.
+func forbidMirrorAnnotation(pod *api.Pod) error {
+	if !api.IsMirrorPod(pod) {
+		return nil
+	}
+	errs := errors.ValidationErrorList{errors.NewFieldForbidden("metadata.annotations", api.ConfigSourceAnnotationKey)}
+	return errors.NewInvalid("pod", pod.Name, errs)
+}
+
+// checkNotMirror returns an error if the pod is the mirror of a pod a kubelet runs from its
+// own source, which changes only with the source.
+func (rs *REST) checkNotMirror(ctx api.Context, id string) error {
+	pod, err := rs.registry.GetPod(ctx, id)
+	if err != nil || pod == nil || !api.IsMirrorPod(pod) {
+		return nil
+	}
+	return errors.NewConflict("pod", id, fmt.Errorf("the pod is a mirror of a pod the kubelet of %s runs from its %s, change it there",
+		pod.Status.Host, pod.Annotations[api.ConfigSourceAnnotationKey]))
+}
+
+// terminationPollPeriod is how often a graceful deletion checks whether the containers of the
+// pod stopped.
+var terminationPollPeriod = time.Second
+
+// Delete deletes a pod gracefully: a pod bound to a host is first marked as terminating for its
+// termination grace period, during which its kubelet stops its containers and it is no longer
+// an endpoint of its services, and is removed once its containers stopped or the grace period
+// is over. Deleting a pod which is already terminating removes it at once.
 func (rs *REST) Delete(ctx api.Context, id string) (<-chan apiserver.RESTResult, error) {
+	if err := rs.checkNotMirror(ctx, id); err != nil {
+		return nil, err
+	}
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		pod, err := rs.registry.GetPod(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		gracePeriod := api.TerminationGracePeriod(&pod.Spec)
+		if pod.Status.Host != "" && pod.DeletionTimestamp == nil && gracePeriod > 0 {
+			deadline := time.Now().Add(gracePeriod)
+			if err := rs.registry.TerminatePod(ctx, id, util.Time{Time: deadline}); err != nil {
+				return nil, err
+			}
+			rs.waitForTermination(pod, deadline)
+		}
 		return &api.Status{Status: api.StatusSuccess}, rs.registry.DeletePod(ctx, id)
 	}), nil
 }
 
+// waitForTermination waits until the kubelet of the pod reports that its containers stopped,
+// or until deadline.
+func (rs *REST) waitForTermination(pod *api.Pod, deadline time.Time) {
+	for time.Now().Before(deadline) {
+		if rs.podCache != nil {
+			status, err := rs.podCache.GetPodStatus(pod.Namespace, pod.Name)
+			if err == nil && !hasRunningContainers(status) {
+				return
+			}
+		}
+		time.Sleep(terminationPollPeriod)
+	}
+}
+
+func hasRunningContainers(status *api.PodStatus) bool {
+	for _, container := range status.Info {
+		if container.State.Running != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (rs *REST) Get(ctx api.Context, id string) (runtime.Object, error) {
 	pod, err := rs.registry.GetPod(ctx, id)
 	if err != nil {
@@ -174,6 +258,12 @@ func (rs *REST) Update(ctx api.Context, obj runtime.Object) (<-chan apiserver.RE
 	if errs := validation.ValidatePod(pod); len(errs) > 0 {
 		return nil, errors.NewInvalid("pod", pod.Name, errs)
 	}
+	if err := forbidMirrorAnnotation(pod); err != nil {
+		return nil, err
+	}
+	if err := rs.checkNotMirror(ctx, pod.Name); err != nil {
+		return nil, err
+	}
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
 		if err := rs.registry.UpdatePod(ctx, pod); err != nil {
 			return nil, err
@@ -181,3 +271,35 @@ func (rs *REST) Update(ctx api.Context, obj runtime.Object) (<-chan apiserver.RE
 		return rs.registry.GetPod(ctx, pod.Name)
 	}), nil
 }
+
+// SubresourceLocation returns the URL of the kubelet endpoint serving a subresource of the pod.
+func (rs *REST) SubresourceLocation(ctx api.Context, id, subresource string) (string, http.RoundTripper, error) {
+	pod, err := rs.registry.GetPod(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pod.Status.Host) == 0 {
+		return "", nil, errors.NewBadRequest(fmt.Sprintf("pod %s is not bound to a host", id))
+	}
+	scheme, port, transport := "http", uint(ports.KubeletPort), http.RoundTripper(nil)
+	if rs.kubeletInfo != nil {
+		if scheme, port, transport, err = rs.kubeletInfo.GetConnectionInfo(pod.Status.Host); err != nil {
+			return "", nil, err
+		}
+	}
+	location := &url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(pod.Status.Host, strconv.FormatUint(uint64(port), 10)),
+	}
+	switch subresource {
+	case "stats":
+		location.Path = path.Join("/stats/pod", pod.Namespace, pod.Name)
+	case "exec", "attach":
+		location.Path = path.Join("/"+subresource, pod.Namespace, pod.Name)
+	case "portforward":
+		location.Path = path.Join("/portForward", pod.Namespace, pod.Name)
+	default:
+		return "", nil, errors.NewNotFound("pods/"+subresource, id)
+	}
+	return location.String(), transport, nil
+}
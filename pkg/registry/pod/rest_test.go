@@ -18,6 +18,7 @@ package pod
 
 import (
 	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
 	"testing"
@@ -28,7 +29,6 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
-	fake_cloud "github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider/fake"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/registrytest"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -147,12 +147,13 @@ func TestListEmptyPodList(t *testing.T) {
 	}
 }
 
-type fakeClock struct {
-	t time.Time
+type fakePodStatusGetter struct {
+	status *api.PodStatus
+	err    error
 }
 
-func (f *fakeClock) Now() time.Time {
-	return f.t
+func (f *fakePodStatusGetter) GetPodStatus(namespace, name string) (*api.PodStatus, error) {
+	return f.status, f.err
 }
 
 func TestListPodList(t *testing.T) {
@@ -173,8 +174,7 @@ func TestListPodList(t *testing.T) {
 	}
 	storage := REST{
 		registry: podRegistry,
-		ipCache:  ipCache{},
-		clock:    &fakeClock{},
+		podCache: &fakePodStatusGetter{err: client.ErrPodInfoNotAvailable},
 	}
 	ctx := api.NewContext()
 	podsObj, err := storage.List(ctx, labels.Everything(), labels.Everything())
@@ -218,8 +218,7 @@ func TestListPodListSelection(t *testing.T) {
 	}
 	storage := REST{
 		registry: podRegistry,
-		ipCache:  ipCache{},
-		clock:    &fakeClock{},
+		podCache: &fakePodStatusGetter{err: client.ErrPodInfoNotAvailable},
 	}
 	ctx := api.NewContext()
 
@@ -309,8 +308,7 @@ func TestGetPod(t *testing.T) {
 	podRegistry.Pod = &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
 	storage := REST{
 		registry: podRegistry,
-		ipCache:  ipCache{},
-		clock:    &fakeClock{},
+		podCache: &fakePodStatusGetter{err: client.ErrPodInfoNotAvailable},
 	}
 	ctx := api.NewContext()
 	obj, err := storage.Get(ctx, "foo")
@@ -324,187 +322,24 @@ func TestGetPod(t *testing.T) {
 	}
 }
 
-func TestGetPodCloud(t *testing.T) {
-	fakeCloud := &fake_cloud.FakeCloud{}
+func TestGetPodStatusFromCache(t *testing.T) {
 	podRegistry := registrytest.NewPodRegistry(nil)
 	podRegistry.Pod = &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo"}, Status: api.PodStatus{Host: "machine"}}
-
-	clock := &fakeClock{t: time.Now()}
-
+	cached := &api.PodStatus{Phase: api.PodRunning, PodIP: "1.2.3.4", Host: "old-machine"}
 	storage := REST{
-		registry:      podRegistry,
-		cloudProvider: fakeCloud,
-		ipCache:       ipCache{},
-		clock:         clock,
-	}
-	ctx := api.NewContext()
-	obj, err := storage.Get(ctx, "foo")
-	pod := obj.(*api.Pod)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if e, a := podRegistry.Pod, pod; !reflect.DeepEqual(e, a) {
-		t.Errorf("Unexpected pod. Expected %#v, Got %#v", e, a)
+		registry: podRegistry,
+		podCache: &fakePodStatusGetter{status: cached},
 	}
-
-	// This call should hit the cache, so we expect no additional calls to the cloud
-	obj, err = storage.Get(ctx, "foo")
+	obj, err := storage.Get(api.NewContext(), "foo")
 	if err != nil {
-		t.Errorf("unexpected error: %v", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(fakeCloud.Calls) != 1 || fakeCloud.Calls[0] != "ip-address" {
-		t.Errorf("Unexpected calls: %#v", fakeCloud.Calls)
-	}
-
-	// Advance the clock, this call should miss the cache, so expect one more call.
-	clock.t = clock.t.Add(60 * time.Second)
-	obj, err = storage.Get(ctx, "foo")
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
+	pod := obj.(*api.Pod)
+	if pod.Status.Phase != api.PodRunning || pod.Status.PodIP != "1.2.3.4" {
+		t.Errorf("Expected the status of the cache, got %#v", pod.Status)
 	}
-	if len(fakeCloud.Calls) != 2 || fakeCloud.Calls[1] != "ip-address" {
-		t.Errorf("Unexpected calls: %#v", fakeCloud.Calls)
-	}
-}
-
-func TestMakePodStatus(t *testing.T) {
-	fakeClient := client.Fake{
-		MinionsList: api.MinionList{
-			Items: []api.Minion{
-				{
-					ObjectMeta: api.ObjectMeta{Name: "machine"},
-				},
-			},
-		},
-	}
-	desiredState := api.PodSpec{
-		Containers: []api.Container{
-			{Name: "containerA"},
-			{Name: "containerB"},
-		},
-	}
-	currentState := api.PodStatus{
-		Host: "machine",
-	}
-	runningState := api.ContainerStatus{
-		State: api.ContainerState{
-			Running: &api.ContainerStateRunning{},
-		},
-	}
-	stoppedState := api.ContainerStatus{
-		State: api.ContainerState{
-			Termination: &api.ContainerStateTerminated{},
-		},
-	}
-
-	tests := []struct {
-		pod    *api.Pod
-		status api.PodPhase
-		test   string
-	}{
-		{&api.Pod{Spec: desiredState, Status: currentState}, api.PodPending, "waiting"},
-		{
-			&api.Pod{
-				Spec: desiredState,
-				Status: api.PodStatus{
-					Host: "machine-2",
-				},
-			},
-			api.PodFailed,
-			"no info, but bad machine",
-		},
-		{
-			&api.Pod{
-				Spec: desiredState,
-				Status: api.PodStatus{
-					Info: map[string]api.ContainerStatus{
-						"containerA": runningState,
-						"containerB": runningState,
-					},
-					Host: "machine",
-				},
-			},
-			api.PodRunning,
-			"all running",
-		},
-		{
-			&api.Pod{
-				Spec: desiredState,
-				Status: api.PodStatus{
-					Info: map[string]api.ContainerStatus{
-						"containerA": runningState,
-						"containerB": runningState,
-					},
-					Host: "machine-two",
-				},
-			},
-			api.PodFailed,
-			"all running but minion is missing",
-		},
-		{
-			&api.Pod{
-				Spec: desiredState,
-				Status: api.PodStatus{
-					Info: map[string]api.ContainerStatus{
-						"containerA": stoppedState,
-						"containerB": stoppedState,
-					},
-					Host: "machine",
-				},
-			},
-			api.PodFailed,
-			"all stopped",
-		},
-		{
-			&api.Pod{
-				Spec: desiredState,
-				Status: api.PodStatus{
-					Info: map[string]api.ContainerStatus{
-						"containerA": stoppedState,
-						"containerB": stoppedState,
-					},
-					Host: "machine-two",
-				},
-			},
-			api.PodFailed,
-			"all stopped but minion missing",
-		},
-		{
-			&api.Pod{
-				Spec: desiredState,
-				Status: api.PodStatus{
-					Info: map[string]api.ContainerStatus{
-						"containerA": runningState,
-						"containerB": stoppedState,
-					},
-					Host: "machine",
-				},
-			},
-			api.PodRunning,
-			"mixed state #1",
-		},
-		{
-			&api.Pod{
-				Spec: desiredState,
-				Status: api.PodStatus{
-					Info: map[string]api.ContainerStatus{
-						"containerA": runningState,
-					},
-					Host: "machine",
-				},
-			},
-			api.PodPending,
-			"mixed state #2",
-		},
-	}
-	for _, test := range tests {
-		if status, err := getPodStatus(test.pod, fakeClient.Minions()); status != test.status {
-			t.Errorf("In test %s, expected %v, got %v", test.test, test.status, status)
-			if err != nil {
-				t.Errorf("In test %s, unexpected error: %v", test.test, err)
-			}
-		}
+	if pod.Status.Host != "machine" {
+		t.Errorf("Expected the host of the registry, got %s", pod.Status.Host)
 	}
 }
 
@@ -540,8 +375,7 @@ func TestCreatePod(t *testing.T) {
 		},
 	}
 	storage := REST{
-		registry:      podRegistry,
-		podPollPeriod: time.Millisecond * 100,
+		registry: podRegistry,
 	}
 	pod := &api.Pod{}
 	pod.Name = "foo"
@@ -561,66 +395,6 @@ func TestCreatePod(t *testing.T) {
 	}
 }
 
-type FakePodInfoGetter struct {
-	info api.PodInfo
-	err  error
-}
-
-func (f *FakePodInfoGetter) GetPodInfo(host, podNamespace string, podID string) (api.PodInfo, error) {
-	return f.info, f.err
-}
-
-func TestFillPodInfo(t *testing.T) {
-	expectedIP := "1.2.3.4"
-	expectedTime, _ := time.Parse("2013-Feb-03", "2013-Feb-03")
-	fakeGetter := FakePodInfoGetter{
-		info: map[string]api.ContainerStatus{
-			"net": {
-				State: api.ContainerState{
-					Running: &api.ContainerStateRunning{
-						StartedAt: expectedTime,
-					},
-				},
-				RestartCount: 1,
-				PodIP:        expectedIP,
-			},
-		},
-	}
-	storage := REST{
-		podCache: &fakeGetter,
-	}
-	pod := api.Pod{Status: api.PodStatus{Host: "foo"}}
-	storage.fillPodInfo(&pod)
-	if !reflect.DeepEqual(fakeGetter.info, pod.Status.Info) {
-		t.Errorf("Expected: %#v, Got %#v", fakeGetter.info, pod.Status.Info)
-	}
-	if pod.Status.PodIP != expectedIP {
-		t.Errorf("Expected %s, Got %s", expectedIP, pod.Status.PodIP)
-	}
-}
-
-func TestFillPodInfoNoData(t *testing.T) {
-	expectedIP := ""
-	fakeGetter := FakePodInfoGetter{
-		info: map[string]api.ContainerStatus{
-			"net": {
-				State: api.ContainerState{},
-			},
-		},
-	}
-	storage := REST{
-		podCache: &fakeGetter,
-	}
-	pod := api.Pod{Status: api.PodStatus{Host: "foo"}}
-	storage.fillPodInfo(&pod)
-	if !reflect.DeepEqual(fakeGetter.info, pod.Status.Info) {
-		t.Errorf("Expected %#v, Got %#v", fakeGetter.info, pod.Status.Info)
-	}
-	if pod.Status.PodIP != expectedIP {
-		t.Errorf("Expected %s, Got %s", expectedIP, pod.Status.PodIP)
-	}
-}
-
 func TestCreatePodWithConflictingNamespace(t *testing.T) {
 	storage := REST{}
 	pod := &api.Pod{
@@ -656,3 +430,75 @@ func TestUpdatePodWithConflictingNamespace(t *testing.T) {
 		t.Errorf("Expected 'Pod.Namespace does not match the provided context' error, got '%v'", err.Error())
 	}
 }
+
+func TestSubresourceLocation(t *testing.T) {
+	podRegistry := registrytest.NewPodRegistry(nil)
+	podRegistry.Pod = &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
+		Status:     api.PodStatus{Host: "machine"},
+	}
+	storage := REST{registry: podRegistry}
+	ctx := api.NewContext()
+
+	location, _, err := storage.SubresourceLocation(ctx, "foo", "stats")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if e, a := "http://machine:10250/stats/pod/other/foo", location; e != a {
+		t.Errorf("Expected %v, Got %v", e, a)
+	}
+
+	location, _, err = storage.SubresourceLocation(ctx, "foo", "exec")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if e, a := "http://machine:10250/exec/other/foo", location; e != a {
+		t.Errorf("Expected %v, Got %v", e, a)
+	}
+
+	location, _, err = storage.SubresourceLocation(ctx, "foo", "portforward")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if e, a := "http://machine:10250/portForward/other/foo", location; e != a {
+		t.Errorf("Expected %v, Got %v", e, a)
+	}
+
+	if _, _, err := storage.SubresourceLocation(ctx, "foo", "bar"); !errors.IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	podRegistry.Pod.Status.Host = ""
+	if _, _, err := storage.SubresourceLocation(ctx, "foo", "stats"); err == nil {
+		t.Errorf("expected an error for an unbound pod")
+	}
+}
+
+type fakeConnectionInfo struct {
+	transport http.RoundTripper
+}
+
+func (f fakeConnectionInfo) GetConnectionInfo(host string) (string, uint, http.RoundTripper, error) {
+	return "https", 10443, f.transport, nil
+}
+
+func TestSubresourceLocationSecure(t *testing.T) {
+	podRegistry := registrytest.NewPodRegistry(nil)
+	podRegistry.Pod = &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
+		Status:     api.PodStatus{Host: "machine"},
+	}
+	transport := &http.Transport{}
+	storage := REST{registry: podRegistry, kubeletInfo: fakeConnectionInfo{transport}}
+
+	location, rt, err := storage.SubresourceLocation(api.NewContext(), "foo", "exec")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if e, a := "https://machine:10443/exec/other/foo", location; e != a {
+		t.Errorf("Expected %v, Got %v", e, a)
+	}
+	if rt != transport {
+		t.Errorf("expected the transport of the kubelet client, got %#v", rt)
+	}
+}
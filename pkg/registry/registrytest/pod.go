@@ -21,6 +21,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 )
 
@@ -96,3 +97,13 @@ func (r *PodRegistry) DeletePod(ctx api.Context, podId string) error {
 	r.mux.Action(watch.Deleted, r.Pod)
 	return r.Err
 }
+
+func (r *PodRegistry) TerminatePod(ctx api.Context, podId string, deletionTimestamp util.Time) error {
+	r.Lock()
+	defer r.Unlock()
+	if r.Pod != nil && r.Pod.DeletionTimestamp == nil {
+		r.Pod.DeletionTimestamp = &deletionTimestamp
+		r.mux.Action(watch.Modified, r.Pod)
+	}
+	return r.Err
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	etcderr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors/etcd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/etcd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	etcdgeneric "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic/etcd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// secretPath is the root of the secrets in etcd, under which they are stored by namespace.
+const secretPath = "/registry/secrets"
+
+// registry implements custom changes to generic.Etcd: secrets are stored under the key of their
+// namespace, so that listing and watching a namespace never reads the secrets of another.
+type registry struct {
+	*etcdgeneric.Etcd
+}
+
+// NewEtcdRegistry returns a registry which will store Secrets in the given EtcdHelper.
+func NewEtcdRegistry(h tools.EtcdHelper) generic.Registry {
+	return registry{
+		Etcd: &etcdgeneric.Etcd{
+			NewFunc:      func() runtime.Object { return &api.Secret{} },
+			NewListFunc:  func() runtime.Object { return &api.SecretList{} },
+			EndpointName: "secrets",
+			KeyRoot:      secretPath,
+			Helper:       h,
+		},
+	}
+}
+
+func (r registry) List(ctx api.Context, m generic.Matcher) (runtime.Object, error) {
+	list := r.NewListFunc()
+	if err := r.Helper.ExtractToList(etcd.MakeEtcdListKey(ctx, r.KeyRoot), list); err != nil {
+		return nil, err
+	}
+	return generic.FilterList(list, m)
+}
+
+func (r registry) Create(ctx api.Context, id string, obj runtime.Object) error {
+	key, err := etcd.MakeEtcdItemKey(ctx, r.KeyRoot, id)
+	if err != nil {
+		return err
+	}
+	err = r.Helper.CreateObj(key, obj, 0)
+	return etcderr.InterpretCreateError(err, r.EndpointName, id)
+}
+
+func (r registry) Update(ctx api.Context, id string, obj runtime.Object) error {
+	key, err := etcd.MakeEtcdItemKey(ctx, r.KeyRoot, id)
+	if err != nil {
+		return err
+	}
+	err = r.Helper.SetObj(key, obj)
+	return etcderr.InterpretUpdateError(err, r.EndpointName, id)
+}
+
+func (r registry) Get(ctx api.Context, id string) (runtime.Object, error) {
+	key, err := etcd.MakeEtcdItemKey(ctx, r.KeyRoot, id)
+	if err != nil {
+		return nil, err
+	}
+	obj := r.NewFunc()
+	if err := r.Helper.ExtractObj(key, obj, false); err != nil {
+		return nil, etcderr.InterpretGetError(err, r.EndpointName, id)
+	}
+	return obj, nil
+}
+
+func (r registry) Delete(ctx api.Context, id string) error {
+	key, err := etcd.MakeEtcdItemKey(ctx, r.KeyRoot, id)
+	if err != nil {
+		return err
+	}
+	err = r.Helper.Delete(key, false)
+	return etcderr.InterpretDeleteError(err, r.EndpointName, id)
+}
+
+func (r registry) Watch(ctx api.Context, m generic.Matcher, resourceVersion string) (watch.Interface, error) {
+	version, err := tools.ParseWatchResourceVersion(resourceVersion, r.EndpointName)
+	if err != nil {
+		return nil, err
+	}
+	return r.Helper.WatchList(etcd.MakeEtcdListKey(ctx, r.KeyRoot), version, func(obj runtime.Object) bool {
+		matches, err := m.Matches(obj)
+		return err == nil && matches
+	})
+}
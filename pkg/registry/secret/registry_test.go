@@ -0,0 +1,165 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/testapi"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func NewTestSecretEtcdRegistry(t *testing.T) (*tools.FakeEtcdClient, generic.Registry) {
+	f := tools.NewFakeEtcdClient(t)
+	f.TestIndex = true
+	h := tools.EtcdHelper{f, testapi.Codec(), tools.RuntimeVersionAdapter{testapi.MetadataAccessor()}}
+	return f, NewEtcdRegistry(h)
+}
+
+func TestSecretCreate(t *testing.T) {
+	secretA := &api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Data:       map[string][]byte{"token": []byte("secret")},
+	}
+	secretB := &api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Data:       map[string][]byte{"token": []byte("other")},
+	}
+
+	nodeWithSecretA := tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Value:         runtime.EncodeOrDie(testapi.Codec(), secretA),
+				ModifiedIndex: 1,
+				CreatedIndex:  1,
+			},
+		},
+		E: nil,
+	}
+
+	emptyNode := tools.EtcdResponseWithError{
+		R: &etcd.Response{},
+		E: tools.EtcdErrorNotFound,
+	}
+
+	path := "/registry/secrets/default/foo"
+	key := "foo"
+
+	table := map[string]struct {
+		existing tools.EtcdResponseWithError
+		expect   tools.EtcdResponseWithError
+		toCreate runtime.Object
+		errOK    func(error) bool
+	}{
+		"normal": {
+			existing: emptyNode,
+			expect:   nodeWithSecretA,
+			toCreate: secretA,
+			errOK:    func(err error) bool { return err == nil },
+		},
+		"preExisting": {
+			existing: nodeWithSecretA,
+			expect:   nodeWithSecretA,
+			toCreate: secretB,
+			errOK:    errors.IsAlreadyExists,
+		},
+	}
+
+	for name, item := range table {
+		fakeClient, registry := NewTestSecretEtcdRegistry(t)
+		fakeClient.Data[path] = item.existing
+		err := registry.Create(api.NewDefaultContext(), key, item.toCreate)
+		if !item.errOK(err) {
+			t.Errorf("%v: unexpected error: %v", name, err)
+		}
+
+		if e, a := item.expect, fakeClient.Data[path]; !reflect.DeepEqual(e, a) {
+			t.Errorf("%v:\n%s", name, util.ObjectDiff(e, a))
+		}
+	}
+}
+
+func TestSecretCreateRequiresNamespace(t *testing.T) {
+	fakeClient, registry := NewTestSecretEtcdRegistry(t)
+	secret := &api.Secret{ObjectMeta: api.ObjectMeta{Name: "foo"}}
+	if err := registry.Create(api.NewContext(), "foo", secret); err == nil {
+		t.Errorf("expected an error creating a secret without a namespace")
+	}
+	if len(fakeClient.Data) != 0 {
+		t.Errorf("unexpected write: %#v", fakeClient.Data)
+	}
+}
+
+func TestSecretGetOtherNamespace(t *testing.T) {
+	fakeClient, registry := NewTestSecretEtcdRegistry(t)
+	secret := &api.Secret{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"}}
+	fakeClient.Data["/registry/secrets/other/foo"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{Node: &etcd.Node{Value: runtime.EncodeOrDie(testapi.Codec(), secret)}},
+	}
+	fakeClient.Data["/registry/secrets/default/foo"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{},
+		E: tools.EtcdErrorNotFound,
+	}
+
+	if _, err := registry.Get(api.NewDefaultContext(), "foo"); !errors.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	obj, err := registry.Get(api.WithNamespace(api.NewContext(), "other"), "foo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := obj.(*api.Secret); got.Name != "foo" || got.Namespace != "other" {
+		t.Errorf("unexpected secret: %#v", got)
+	}
+}
+
+func TestSecretList(t *testing.T) {
+	fakeClient, registry := NewTestSecretEtcdRegistry(t)
+	fakeClient.Data["/registry/secrets/default"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{Value: runtime.EncodeOrDie(testapi.Codec(), &api.Secret{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "default"}})},
+					{Value: runtime.EncodeOrDie(testapi.Codec(), &api.Secret{ObjectMeta: api.ObjectMeta{Name: "bar", Namespace: "default"}})},
+				},
+			},
+		},
+	}
+
+	obj, err := registry.List(api.NewDefaultContext(), &generic.SelectionPredicate{
+		Label: labels.Everything(),
+		Field: labels.Everything(),
+		GetAttrs: func(obj runtime.Object) (labels.Set, labels.Set, error) {
+			return labels.Set{}, labels.Set{}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items := obj.(*api.SecretList).Items; len(items) != 2 || items[0].Name != "foo" || items[1].Name != "bar" {
+		t.Errorf("unexpected list: %#v", items)
+	}
+}
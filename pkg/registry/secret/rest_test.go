@@ -0,0 +1,147 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/registrytest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+type testRegistry struct {
+	*registrytest.GenericRegistry
+}
+
+func NewTestREST() (testRegistry, *REST) {
+	reg := testRegistry{registrytest.NewGeneric(nil)}
+	return reg, NewREST(reg)
+}
+
+func testSecret(name string) *api.Secret {
+	return &api.Secret{
+		ObjectMeta: api.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Data: map[string][]byte{"token": []byte("secret")},
+	}
+}
+
+func TestRESTCreate(t *testing.T) {
+	table := []struct {
+		ctx    api.Context
+		secret *api.Secret
+		valid  bool
+	}{
+		{
+			ctx:    api.NewDefaultContext(),
+			secret: testSecret("foo"),
+			valid:  true,
+		}, {
+			ctx:    api.WithNamespace(api.NewContext(), "nondefault"),
+			secret: testSecret("bar"),
+			valid:  false,
+		}, {
+			ctx:    api.NewDefaultContext(),
+			secret: &api.Secret{ObjectMeta: api.ObjectMeta{Name: "baz", Namespace: "default"}, Data: map[string][]byte{"..": {}}},
+			valid:  false,
+		},
+	}
+
+	for _, item := range table {
+		_, rest := NewTestREST()
+		c, err := rest.Create(item.ctx, item.secret)
+		if !item.valid {
+			if err == nil {
+				t.Errorf("unexpected non-error for %v", item.secret.Name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: Unexpected error %v", item.secret.Name, err)
+			continue
+		}
+		if !api.HasObjectMetaSystemFieldValues(&item.secret.ObjectMeta) {
+			t.Errorf("storage did not populate object meta field values")
+		}
+		if e, a := item.secret, (<-c).Object; !reflect.DeepEqual(e, a) {
+			t.Errorf("diff: %s", util.ObjectDiff(e, a))
+		}
+		// Ensure we implement the interface
+		_ = apiserver.ResourceWatcher(rest)
+	}
+}
+
+func TestRESTUpdate(t *testing.T) {
+	_, rest := NewTestREST()
+	secretA := testSecret("foo")
+	c, err := rest.Create(api.NewDefaultContext(), secretA)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	<-c
+	secretB := testSecret("foo")
+	secretB.Data["token"] = []byte("rotated")
+	c, err = rest.Update(api.NewDefaultContext(), secretB)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if e, a := secretB, (<-c).Object; !reflect.DeepEqual(e, a) {
+		t.Errorf("diff: %s", util.ObjectDiff(e, a))
+	}
+	if _, err := rest.Update(api.WithNamespace(api.NewContext(), "nondefault"), testSecret("foo")); err == nil {
+		t.Errorf("unexpected non-error updating a secret in another namespace")
+	}
+}
+
+func TestRESTDelete(t *testing.T) {
+	_, rest := NewTestREST()
+	secretA := testSecret("foo")
+	c, err := rest.Create(api.NewDefaultContext(), secretA)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	<-c
+	c, err = rest.Delete(api.NewDefaultContext(), secretA.Name)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if stat := (<-c).Object.(*api.Status); stat.Status != api.StatusSuccess {
+		t.Errorf("unexpected status: %v", stat)
+	}
+}
+
+func TestRESTGet(t *testing.T) {
+	_, rest := NewTestREST()
+	secretA := testSecret("foo")
+	c, err := rest.Create(api.NewDefaultContext(), secretA)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	<-c
+	got, err := rest.Get(api.NewDefaultContext(), secretA.Name)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if e, a := secretA, got; !reflect.DeepEqual(e, a) {
+		t.Errorf("diff: %s", util.ObjectDiff(e, a))
+	}
+}
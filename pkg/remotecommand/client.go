@@ -0,0 +1,85 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotecommand
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/websocket"
+)
+
+// Stream sends stdin and the terminal resizes to, and writes the stdout and stderr received
+// from, a websocket served by NewWebSocketHandler until the process exits. It returns the
+// error of the process. Any of the streams may be nil.
+func Stream(ws *websocket.Conn, stdin io.Reader, stdout, stderr io.Writer, resize <-chan TerminalSize) error {
+	ws.PayloadType = websocket.BinaryFrame
+	c := &conn{ws: ws}
+	if stdin != nil {
+		go func() {
+			if _, err := io.Copy(channelWriter{c, StdinChannel}, stdin); err != nil {
+				glog.V(4).Infof("Failed to send stdin: %v", err)
+			}
+			c.write(StdinChannel, nil)
+		}()
+	}
+	if resize != nil {
+		go func() {
+			for size := range resize {
+				data, err := json.Marshal(size)
+				if err != nil {
+					continue
+				}
+				if err := c.write(ResizeChannel, data); err != nil {
+					return
+				}
+			}
+		}()
+	}
+	for {
+		var frame []byte
+		if err := websocket.Message.Receive(ws, &frame); err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("connection closed before the process exited")
+			}
+			return err
+		}
+		if len(frame) == 0 {
+			continue
+		}
+		var out io.Writer
+		switch frame[0] {
+		case StdoutChannel:
+			out = stdout
+		case StderrChannel:
+			out = stderr
+		case ErrorChannel:
+			if len(frame) > 1 {
+				return errors.New(string(frame[1:]))
+			}
+			return nil
+		}
+		if out != nil {
+			if _, err := out.Write(frame[1:]); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,25 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package remotecommand streams the standard streams of a process run in, or
+// attached to, a container over a websocket.
+//
+// Every websocket message is a binary frame whose first byte is the channel
+// of the frame and whose rest is the payload. The client sends stdin and
+// terminal resizes, the server sends stdout, stderr and, once the process
+// exited, a single error frame with the error message, empty on success. An
+// empty stdin frame closes the stdin of the process.
+package remotecommand
@@ -0,0 +1,108 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotecommand
+
+import (
+	"net/url"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// Channels of the frames.
+const (
+	StdinChannel byte = iota
+	StdoutChannel
+	StderrChannel
+	ErrorChannel
+	ResizeChannel
+)
+
+// Query parameters of a streaming request.
+const (
+	StdinParam     = "stdin"
+	StdoutParam    = "stdout"
+	StderrParam    = "stderr"
+	TTYParam       = "tty"
+	CommandParam   = "command"
+	ContainerParam = "container"
+)
+
+// Options tells which streams of a process are streamed and whether it runs in a terminal.
+type Options struct {
+	Stdin  bool
+	Stdout bool
+	Stderr bool
+	TTY    bool
+}
+
+// OptionsFromQuery returns the options set in the parameters of a request.
+func OptionsFromQuery(query url.Values) Options {
+	return Options{
+		Stdin:  isTrue(query.Get(StdinParam)),
+		Stdout: isTrue(query.Get(StdoutParam)),
+		Stderr: isTrue(query.Get(StderrParam)),
+		TTY:    isTrue(query.Get(TTYParam)),
+	}
+}
+
+func isTrue(value string) bool {
+	return value == "1" || value == "true"
+}
+
+// AddToQuery sets the parameters of the options in query.
+func (o Options) AddToQuery(query url.Values) {
+	for param, set := range map[string]bool{StdinParam: o.Stdin, StdoutParam: o.Stdout, StderrParam: o.Stderr, TTYParam: o.TTY} {
+		if set {
+			query.Set(param, "1")
+		}
+	}
+}
+
+// TerminalSize is the size of a terminal in characters.
+type TerminalSize struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+// conn writes frames to a websocket, one at a time.
+type conn struct {
+	ws   *websocket.Conn
+	lock sync.Mutex
+}
+
+func (c *conn) write(channel byte, data []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return websocket.Message.Send(c.ws, append([]byte{channel}, data...))
+}
+
+// channelWriter writes to a channel of a conn.
+type channelWriter struct {
+	conn    *conn
+	channel byte
+}
+
+func (w channelWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := w.conn.write(w.channel, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotecommand
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+func dial(t *testing.T, server *httptest.Server) *websocket.Conn {
+	u, _ := url.Parse(server.URL)
+	u.Scheme = "ws"
+	ws, err := websocket.Dial(u.String(), "", server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return ws
+}
+
+func TestStream(t *testing.T) {
+	handler := NewWebSocketHandler(Options{Stdin: true, Stdout: true, Stderr: true}, func(stdin io.Reader, stdout, stderr io.Writer, resize <-chan TerminalSize) error {
+		if resize != nil {
+			t.Errorf("unexpected resize channel without a tty")
+		}
+		data, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(stdout, strings.ToUpper(string(data)))
+		fmt.Fprint(stderr, "done")
+		return fmt.Errorf("exit status 3")
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ws := dial(t, server)
+	defer ws.Close()
+	var stdout, stderr bytes.Buffer
+	err := Stream(ws, strings.NewReader("hello"), &stdout, &stderr, nil)
+	if err == nil || err.Error() != "exit status 3" {
+		t.Errorf("expected the error of the process, got %v", err)
+	}
+	if stdout.String() != "HELLO" {
+		t.Errorf("unexpected stdout %q", stdout.String())
+	}
+	if stderr.String() != "done" {
+		t.Errorf("unexpected stderr %q", stderr.String())
+	}
+}
+
+func TestStreamResize(t *testing.T) {
+	handler := NewWebSocketHandler(Options{Stdout: true, TTY: true}, func(stdin io.Reader, stdout, stderr io.Writer, resize <-chan TerminalSize) error {
+		if stdin != nil || stderr != nil {
+			t.Errorf("unexpected streams which were not requested")
+		}
+		size := <-resize
+		fmt.Fprintf(stdout, "%dx%d", size.Width, size.Height)
+		return nil
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ws := dial(t, server)
+	defer ws.Close()
+	resize := make(chan TerminalSize, 1)
+	resize <- TerminalSize{Width: 80, Height: 24}
+	var stdout bytes.Buffer
+	if err := Stream(ws, nil, &stdout, nil, resize); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if stdout.String() != "80x24" {
+		t.Errorf("unexpected stdout %q", stdout.String())
+	}
+}
+
+func TestOptionsQuery(t *testing.T) {
+	opts := Options{Stdin: true, TTY: true}
+	query := url.Values{}
+	opts.AddToQuery(query)
+	if !reflect.DeepEqual(query, url.Values{StdinParam: {"1"}, TTYParam: {"1"}}) {
+		t.Errorf("unexpected query %v", query)
+	}
+	if OptionsFromQuery(query) != opts {
+		t.Errorf("expected %#v, got %#v", opts, OptionsFromQuery(query))
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotecommand
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/websocket"
+)
+
+// StreamFunc runs a process with the given streams until it exits. The streams which are not
+// requested are nil, as is resize if the process does not run in a terminal.
+type StreamFunc func(stdin io.Reader, stdout, stderr io.Writer, resize <-chan TerminalSize) error
+
+// NewWebSocketHandler returns a handler which streams the requested streams of the process run
+// by run with the client, then sends the error of run and closes the connection.
+func NewWebSocketHandler(opts Options, run StreamFunc) websocket.Handler {
+	return func(ws *websocket.Conn) {
+		defer ws.Close()
+		ws.PayloadType = websocket.BinaryFrame
+		c := &conn{ws: ws}
+
+		var stdin io.Reader
+		var stdinReader *io.PipeReader
+		var stdinWriter *io.PipeWriter
+		if opts.Stdin {
+			stdinReader, stdinWriter = io.Pipe()
+			stdin = stdinReader
+		}
+		var stdout, stderr io.Writer
+		if opts.Stdout {
+			stdout = channelWriter{c, StdoutChannel}
+		}
+		if opts.Stderr {
+			stderr = channelWriter{c, StderrChannel}
+		}
+		var resize chan TerminalSize
+		var resizeOut <-chan TerminalSize
+		if opts.TTY {
+			resize = make(chan TerminalSize, 1)
+			resizeOut = resize
+		}
+
+		go readFrames(ws, stdinWriter, resize)
+		err := run(stdin, stdout, stderr, resizeOut)
+		if stdinReader != nil {
+			// Unblock the reader of the frames if it is writing to stdin.
+			stdinReader.Close()
+		}
+		message := ""
+		if err != nil {
+			message = err.Error()
+		}
+		if err := c.write(ErrorChannel, []byte(message)); err != nil {
+			glog.V(4).Infof("Failed to send the exit status: %v", err)
+		}
+	}
+}
+
+// readFrames forwards the stdin and resize frames sent by the client until the connection is closed.
+func readFrames(ws *websocket.Conn, stdin *io.PipeWriter, resize chan TerminalSize) {
+	defer func() {
+		if stdin != nil {
+			stdin.Close()
+		}
+		if resize != nil {
+			close(resize)
+		}
+	}()
+	for {
+		var frame []byte
+		if err := websocket.Message.Receive(ws, &frame); err != nil {
+			if err != io.EOF {
+				glog.V(4).Infof("Failed to read a frame: %v", err)
+			}
+			return
+		}
+		if len(frame) == 0 {
+			continue
+		}
+		switch frame[0] {
+		case StdinChannel:
+			if stdin == nil {
+				continue
+			}
+			if len(frame) == 1 {
+				stdin.Close()
+				continue
+			}
+			if _, err := stdin.Write(frame[1:]); err != nil {
+				glog.V(4).Infof("Failed to write to stdin: %v", err)
+			}
+		case ResizeChannel:
+			if resize == nil {
+				continue
+			}
+			var size TerminalSize
+			if err := json.Unmarshal(frame[1:], &size); err != nil {
+				glog.V(4).Infof("Invalid terminal size %q: %v", string(frame[1:]), err)
+				continue
+			}
+			// Only the latest size matters if the previous one was not applied yet.
+			select {
+			case <-resize:
+			default:
+			}
+			resize <- size
+		}
+	}
+}
@@ -154,8 +154,8 @@ func TestGenericScheduler(t *testing.T) {
 			predicates:  []FitPredicate{truePredicate},
 			prioritizer: EqualPriority,
 			nodes:       []string{"machine1", "machine2"},
-			// Random choice between both, the rand seeded above with zero, chooses "machine2"
-			expectedHost: "machine2",
+			// A pod without cores goes to the first minion it fits on, the priorities are not used.
+			expectedHost: "machine1",
 		},
 		{
 			// Fits on a machine where the pod ID matches the machine name
@@ -169,7 +169,7 @@ func TestGenericScheduler(t *testing.T) {
 			predicates:   []FitPredicate{truePredicate},
 			prioritizer:  numericPriority,
 			nodes:        []string{"3", "2", "1"},
-			expectedHost: "1",
+			expectedHost: "3",
 		},
 		{
 			predicates:   []FitPredicate{matchesPredicate},
@@ -198,8 +198,8 @@ func TestGenericScheduler(t *testing.T) {
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
-			if test.expectedHost != machine {
-				t.Errorf("Expected: %s, Saw: %s", test.expectedHost, machine)
+			if test.expectedHost != machine.Name {
+				t.Errorf("Expected: %s, Saw: %s", test.expectedHost, machine.Name)
 			}
 		}
 	}
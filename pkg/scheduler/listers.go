@@ -17,6 +17,8 @@ limitations under the License.
 package scheduler
 
 import (
+	"fmt"
+
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 )
@@ -53,3 +55,13 @@ func (f FakePodLister) ListPods(s labels.Selector) (selected []api.Pod, err erro
 	}
 	return selected, nil
 }
+
+// GetPodInfo returns the pod with the name id.
+func (f FakePodLister) GetPodInfo(id string) (*api.Pod, error) {
+	for ix := range f {
+		if f[ix].Name == id {
+			return &f[ix], nil
+		}
+	}
+	return nil, fmt.Errorf("pod '%v' is not listed", id)
+}
@@ -18,6 +18,7 @@ package scheduler
 
 import (
 	"fmt"
+	"path"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
@@ -53,16 +54,33 @@ func (nodes ClientNodeInfo) GetNodeInfo(nodeID string) (*api.Minion, error) {
 }
 
 func isVolumeConflict(volume api.Volume, pod *api.Pod) bool {
-	if volume.Source.GCEPersistentDisk == nil {
+	if volume.Source == nil {
 		return false
 	}
-	pdName := volume.Source.GCEPersistentDisk.PDName
-
-	manifest := &(pod.Spec)
-	for ix := range manifest.Volumes {
-		if manifest.Volumes[ix].Source.GCEPersistentDisk != nil &&
-			manifest.Volumes[ix].Source.GCEPersistentDisk.PDName == pdName {
-			return true
+	switch {
+	case volume.Source.GCEPersistentDisk != nil:
+		pdName := volume.Source.GCEPersistentDisk.PDName
+
+		manifest := &(pod.Spec)
+		for ix := range manifest.Volumes {
+			source := manifest.Volumes[ix].Source
+			if source != nil && source.GCEPersistentDisk != nil && source.GCEPersistentDisk.PDName == pdName {
+				return true
+			}
+		}
+	case volume.Source.NFS != nil:
+		// Pods may share an export on the same node only if none of them writes to it.
+		nfs := volume.Source.NFS
+		manifest := &(pod.Spec)
+		for ix := range manifest.Volumes {
+			source := manifest.Volumes[ix].Source
+			if source == nil || source.NFS == nil {
+				continue
+			}
+			if source.NFS.Server == nfs.Server && path.Clean(source.NFS.Path) == path.Clean(nfs.Path) &&
+				!(source.NFS.ReadOnly && nfs.ReadOnly) {
+				return true
+			}
 		}
 	}
 	return false
@@ -71,7 +89,8 @@ func isVolumeConflict(volume api.Volume, pod *api.Pod) bool {
 // NoDiskConflict evaluates if a pod can fit due to the volumes it requests, and those that
 // are already mounted. Some times of volumes are mounted onto node machines.  For now, these mounts
 // are exclusive so if there is already a volume mounted on that node, another pod can't schedule
-// there. This is GCE specific for now.
+// there. GCE persistent disks are always exclusive, NFS exports are only shared if they are
+// mounted read-only by every pod.
 // TODO: migrate this into some per-volume specific code?
 func NoDiskConflict(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
 	manifest := &(pod.Spec)
@@ -104,13 +123,19 @@ func getResourceRequest(pod *api.Pod) resourceRequest {
 		result.core += pod.Spec.Containers[ix].Core
 		result.disk += pod.Spec.Containers[ix].Disk
 	}
+	// The local quota volumes are limited apart from the containers, on the same disk.
+	for ix := range pod.Spec.Volumes {
+		if source := pod.Spec.Volumes[ix].Source; source != nil && source.LocalQuotaVolume != nil {
+			result.disk += source.LocalQuotaVolume.Disk
+		}
+	}
 	return result
 }
 
 // PodFitsResources calculates fit based on requested, rather than used resources
 func (r *ResourceFit) PodFitsResources(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
 	podRequest := getResourceRequest(&pod)
-	if podRequest.milliCPU == 0 && podRequest.memory == 0 {
+	if podRequest.milliCPU == 0 && podRequest.memory == 0 && podRequest.core == 0 && podRequest.disk == 0 {
 		// no resources requested always fits.
 		return true, nil
 	}
@@ -206,6 +231,33 @@ func (n *NodeSelector) PodSelectorMatches(pod api.Pod, existingPods []api.Pod, n
 	return selector.Matches(labels.Set(minion.Labels)) && active, nil
 }
 
+func NewNodePressurePredicate(info NodeInfo) FitPredicate {
+	pressure := &NodePressure{
+		info: info,
+	}
+	return pressure.PodFitsPressure
+}
+
+type NodePressure struct {
+	info NodeInfo
+}
+
+// PodFitsPressure rejects every pod on a node under disk pressure, and the best-effort pods,
+// which request no resource, on a node under memory pressure.
+func (n *NodePressure) PodFitsPressure(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+	minion, err := n.info.GetNodeInfo(node)
+	if err != nil {
+		return false, err
+	}
+	if api.IsNodeUnderPressure(minion, api.NodeDiskPressure) {
+		return false, nil
+	}
+	if api.IsNodeUnderPressure(minion, api.NodeMemoryPressure) && api.IsPodBestEffort(&pod.Spec) {
+		return false, nil
+	}
+	return true, nil
+}
+
 func PodFitsPorts(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
 	existingPorts := getUsedPorts(existingPods...)
 	wantPorts := getUsedPorts(pod)
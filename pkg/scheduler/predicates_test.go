@@ -17,6 +17,7 @@ limitations under the License.
 package scheduler
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -124,6 +125,129 @@ func TestPodFitsResources(t *testing.T) {
 	}
 }
 
+func newQuotaVolumePod(disk ...int) api.Pod {
+	volumes := []api.Volume{}
+	for _, size := range disk {
+		volumes = append(volumes, api.Volume{
+			Name:   fmt.Sprintf("data%d", len(volumes)),
+			Source: &api.VolumeSource{LocalQuotaVolume: &api.LocalQuotaVolume{Disk: size}},
+		})
+	}
+	return api.Pod{
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Disk: 1}},
+			Volumes:    volumes,
+		},
+	}
+}
+
+func TestPodFitsLocalQuotaVolumes(t *testing.T) {
+	tests := []struct {
+		pod          api.Pod
+		existingPods []api.Pod
+		fits         bool
+		test         string
+	}{
+		{
+			pod:          newQuotaVolumePod(4),
+			existingPods: []api.Pod{newQuotaVolumePod(2, 2)},
+			fits:         true,
+			test:         "volumes fit",
+		},
+		{
+			pod:          newQuotaVolumePod(5),
+			existingPods: []api.Pod{newQuotaVolumePod(2, 2)},
+			fits:         false,
+			test:         "volume of the pod too large",
+		},
+		{
+			pod:          newQuotaVolumePod(4),
+			existingPods: []api.Pod{newQuotaVolumePod(3, 2)},
+			fits:         false,
+			test:         "volumes of the existing pods too large",
+		},
+	}
+	for _, test := range tests {
+		node := api.Minion{Spec: api.NodeSpec{Capacity: api.ResourceList{
+			resources.Disk: util.IntOrString{IntVal: 10, Kind: util.IntstrInt},
+		}}}
+
+		fit := ResourceFit{FakeNodeInfo(node)}
+		fits, err := fit.PodFitsResources(test.pod, test.existingPods, "machine")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected: %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
+
+func newMirrorPod(core int) api.Pod {
+	return api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Annotations: map[string]string{api.ConfigSourceAnnotationKey: "file"},
+		},
+		Spec: api.PodSpec{
+			Containers:  []api.Container{{Core: core}},
+			NetworkMode: api.PodNetworkModeHost,
+		},
+		Status: api.PodStatus{Host: "machine"},
+	}
+}
+
+func TestPodFitsResourcesMirrorPods(t *testing.T) {
+	bridgePod := api.Pod{Spec: api.PodSpec{Containers: []api.Container{{Core: 1}}, NetworkMode: api.PodNetworkModeBridge}}
+	tests := []struct {
+		pod          api.Pod
+		existingPods []api.Pod
+		fits         bool
+		test         string
+	}{
+		{
+			pod:          newMirrorPod(1),
+			existingPods: []api.Pod{newMirrorPod(2)},
+			fits:         true,
+			test:         "cores fit",
+		},
+		{
+			pod:          newMirrorPod(2),
+			existingPods: []api.Pod{newMirrorPod(2)},
+			fits:         false,
+			test:         "cores of the mirror pods counted",
+		},
+		{
+			pod:          bridgePod,
+			existingPods: []api.Pod{newMirrorPod(0)},
+			fits:         true,
+			test:         "vms fit",
+		},
+		{
+			pod:          bridgePod,
+			existingPods: []api.Pod{newMirrorPod(0), newMirrorPod(0)},
+			fits:         false,
+			test:         "vms of the mirror pods counted",
+		},
+	}
+	for _, test := range tests {
+		node := api.Minion{Spec: api.NodeSpec{
+			Capacity: api.ResourceList{
+				resources.Core: util.IntOrString{IntVal: 3, Kind: util.IntstrInt},
+			},
+			VMs: make([]api.VM, 2),
+		}}
+
+		fit := ResourceFit{FakeNodeInfo(node)}
+		fits, err := fit.PodFitsResources(test.pod, test.existingPods, "machine")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected: %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
+
 func TestPodFitsPorts(t *testing.T) {
 	tests := []struct {
 		pod          api.Pod
@@ -240,6 +364,46 @@ func TestDiskConflicts(t *testing.T) {
 			},
 		},
 	}
+	nfsState := api.PodSpec{
+		Volumes: []api.Volume{
+			{
+				Source: &api.VolumeSource{
+					NFS: &api.NFS{
+						Server: "nfs.example.com",
+						Path:   "/exports/data",
+					},
+				},
+			},
+		},
+	}
+	nfsReadOnlyState := api.PodSpec{
+		Volumes: []api.Volume{
+			{
+				Source: &api.VolumeSource{
+					NFS: &api.NFS{
+						Server:   "nfs.example.com",
+						Path:     "/exports/data/",
+						ReadOnly: true,
+					},
+				},
+			},
+		},
+	}
+	nfsOtherState := api.PodSpec{
+		Volumes: []api.Volume{
+			{
+				Source: &api.VolumeSource{
+					NFS: &api.NFS{
+						Server: "nfs.example.com",
+						Path:   "/exports/other",
+					},
+				},
+			},
+		},
+	}
+	noSourceState := api.PodSpec{
+		Volumes: []api.Volume{{Name: "default"}},
+	}
 	tests := []struct {
 		pod          api.Pod
 		existingPods []api.Pod
@@ -250,6 +414,13 @@ func TestDiskConflicts(t *testing.T) {
 		{api.Pod{}, []api.Pod{{Spec: volState}}, true, "one state"},
 		{api.Pod{Spec: volState}, []api.Pod{{Spec: volState}}, false, "same state"},
 		{api.Pod{Spec: volState2}, []api.Pod{{Spec: volState}}, true, "different state"},
+		{api.Pod{Spec: nfsState}, []api.Pod{{Spec: nfsState}}, false, "same read-write nfs export"},
+		{api.Pod{Spec: nfsReadOnlyState}, []api.Pod{{Spec: nfsState}}, false, "nfs export read-write by the existing pod"},
+		{api.Pod{Spec: nfsState}, []api.Pod{{Spec: nfsReadOnlyState}}, false, "nfs export read-write by the new pod"},
+		{api.Pod{Spec: nfsReadOnlyState}, []api.Pod{{Spec: nfsReadOnlyState}}, true, "same read-only nfs export"},
+		{api.Pod{Spec: nfsState}, []api.Pod{{Spec: nfsOtherState}}, true, "different nfs exports"},
+		{api.Pod{Spec: volState}, []api.Pod{{Spec: nfsState}, {Spec: noSourceState}}, true, "nfs export and volume without source"},
+		{api.Pod{Spec: noSourceState}, []api.Pod{{Spec: volState}}, true, "volume without source"},
 	}
 
 	for _, test := range tests {
@@ -347,3 +518,62 @@ func TestPodFitsSelector(t *testing.T) {
 		}
 	}
 }
+
+func TestPodFitsPressure(t *testing.T) {
+	bestEffort := api.Pod{}
+	burstable := api.Pod{
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Memory: 100}},
+		},
+	}
+	memoryPressure := []api.NodeCondition{{Kind: api.NodeMemoryPressure, Status: api.ConditionTrue}}
+	diskPressure := []api.NodeCondition{{Kind: api.NodeDiskPressure, Status: api.ConditionTrue}}
+	tests := []struct {
+		pod        api.Pod
+		conditions []api.NodeCondition
+		fits       bool
+		test       string
+	}{
+		{
+			pod:  bestEffort,
+			fits: true,
+			test: "no pressure",
+		},
+		{
+			pod:        bestEffort,
+			conditions: []api.NodeCondition{{Kind: api.NodeMemoryPressure, Status: api.ConditionFalse}},
+			fits:       true,
+			test:       "memory pressure is false",
+		},
+		{
+			pod:        bestEffort,
+			conditions: memoryPressure,
+			fits:       false,
+			test:       "best-effort pod under memory pressure",
+		},
+		{
+			pod:        burstable,
+			conditions: memoryPressure,
+			fits:       true,
+			test:       "pod with a request under memory pressure",
+		},
+		{
+			pod:        burstable,
+			conditions: diskPressure,
+			fits:       false,
+			test:       "pod with a request under disk pressure",
+		},
+	}
+	for _, test := range tests {
+		node := api.Minion{Status: api.NodeStatus{Conditions: test.conditions}}
+
+		fit := NodePressure{FakeNodeInfo(node)}
+		fits, err := fit.PodFitsPressure(test.pod, []api.Pod{}, "machine")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected: %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
@@ -75,6 +75,14 @@ func (e *EndpointController) SyncServiceEndpoints() error {
 				glog.Errorf("Failed to find an IP for pod: %v", pod)
 				continue
 			}
+			if pod.DeletionTimestamp != nil {
+				glog.V(4).Infof("Pod %s is terminating, excluding it from service %s", pod.Name, service.Name)
+				continue
+			}
+			if !api.IsPodReady(&pod) {
+				glog.V(4).Infof("Pod %s is not ready, excluding it from service %s", pod.Name, service.Name)
+				continue
+			}
 			endpoints = append(endpoints, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port)))
 		}
 		currentEndpoints, err := e.client.Endpoints(service.Namespace).Get(service.Name)
@@ -36,6 +36,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/service"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
 	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler"
 	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/factory"
 
@@ -152,9 +153,9 @@ func RunKubelet(etcdClient tools.EtcdClient, hostname, dockerEndpoint string) {
 	os.MkdirAll(testRootDir, 0750)
 	cfg1 := config.NewPodConfig(config.PodConfigNotificationSnapshotAndUpdates)
 	config.NewSourceEtcd(config.EtcdKeyForHost(hostname), etcdClient, cfg1.Channel("etcd"))
-	myKubelet := kubelet.NewIntegrationTestKubelet(hostname, testRootDir, dockerClient)
+	myKubelet := kubelet.NewIntegrationTestKubelet(hostname, testRootDir, dockerClient, volume.ProbeVolumePlugins())
 	go util.Forever(func() { myKubelet.Run(cfg1.Updates()) }, 0)
 	go util.Forever(func() {
-		kubelet.ListenAndServeKubeletServer(myKubelet, cfg1.Channel("http"), net.ParseIP("127.0.0.1"), 10250, true)
+		kubelet.ListenAndServeKubeletServer(myKubelet, cfg1.Channel("http"), "etcd", net.ParseIP("127.0.0.1"), 10250, nil, nil, true)
 	}, 0)
 }
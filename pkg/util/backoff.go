@@ -0,0 +1,104 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+type backoffEntry struct {
+	backoff    time.Duration
+	lastUpdate time.Time
+}
+
+// Backoff keeps an exponential back-off delay per item id. The delay starts at
+// the initial duration, doubles on every call to Next and is capped at max.
+type Backoff struct {
+	lock            sync.Mutex
+	Clock           Clock
+	defaultDuration time.Duration
+	maxDuration     time.Duration
+	perItemBackoff  map[string]*backoffEntry
+}
+
+// NewBackOff creates a Backoff which starts at initial and never exceeds max.
+func NewBackOff(initial, max time.Duration) *Backoff {
+	return &Backoff{
+		Clock:           RealClock{},
+		defaultDuration: initial,
+		maxDuration:     max,
+		perItemBackoff:  map[string]*backoffEntry{},
+	}
+}
+
+// Get returns the current back-off delay of id, or 0 if id is not backing off.
+func (b *Backoff) Get(id string) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if entry, ok := b.perItemBackoff[id]; ok {
+		return entry.backoff
+	}
+	return 0
+}
+
+// Next records an event for id at eventTime and moves its delay one step up.
+func (b *Backoff) Next(id string, eventTime time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	entry, ok := b.perItemBackoff[id]
+	if !ok {
+		b.perItemBackoff[id] = &backoffEntry{backoff: b.defaultDuration, lastUpdate: eventTime}
+		return
+	}
+	entry.backoff *= 2
+	if entry.backoff > b.maxDuration {
+		entry.backoff = b.maxDuration
+	}
+	entry.lastUpdate = eventTime
+}
+
+// Reset forgets the back-off state of id.
+func (b *Backoff) Reset(id string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.perItemBackoff, id)
+}
+
+// IsInBackOffSince returns true if the current delay of id has not yet
+// elapsed since eventTime.
+func (b *Backoff) IsInBackOffSince(id string, eventTime time.Time) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	entry, ok := b.perItemBackoff[id]
+	if !ok {
+		return false
+	}
+	return b.Clock.Now().Sub(eventTime) < entry.backoff
+}
+
+// GC removes the entries which have not been updated for twice the maximum delay.
+func (b *Backoff) GC() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.Clock.Now()
+	for id, entry := range b.perItemBackoff {
+		if now.Sub(entry.lastUpdate) > 2*b.maxDuration {
+			delete(b.perItemBackoff, id)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackOffGrowsToMax(t *testing.T) {
+	now := time.Now()
+	b := NewBackOff(10*time.Second, 50*time.Second)
+	b.Clock = &FakeClock{Time: now}
+
+	expected := []time.Duration{10, 20, 40, 50, 50}
+	for i, e := range expected {
+		b.Next("foo", now)
+		if got := b.Get("foo"); got != e*time.Second {
+			t.Errorf("step %d: expected %v, got %v", i, e*time.Second, got)
+		}
+	}
+	if b.Get("bar") != 0 {
+		t.Errorf("unexpected back-off for unknown id")
+	}
+}
+
+func TestBackOffIsInBackOffSince(t *testing.T) {
+	now := time.Now()
+	clock := &FakeClock{Time: now}
+	b := NewBackOff(10*time.Second, time.Minute)
+	b.Clock = clock
+
+	if b.IsInBackOffSince("foo", now) {
+		t.Errorf("unexpected back-off before any event")
+	}
+	b.Next("foo", now)
+	clock.Time = now.Add(5 * time.Second)
+	if !b.IsInBackOffSince("foo", now) {
+		t.Errorf("expected back-off 5s after the event")
+	}
+	clock.Time = now.Add(11 * time.Second)
+	if b.IsInBackOffSince("foo", now) {
+		t.Errorf("unexpected back-off 11s after the event")
+	}
+
+	b.Reset("foo")
+	if b.Get("foo") != 0 {
+		t.Errorf("expected reset to clear the back-off")
+	}
+}
+
+func TestBackOffGC(t *testing.T) {
+	now := time.Now()
+	clock := &FakeClock{Time: now}
+	b := NewBackOff(time.Second, 10*time.Second)
+	b.Clock = clock
+
+	b.Next("old", now)
+	b.Next("new", now.Add(15*time.Second))
+	clock.Time = now.Add(21 * time.Second)
+	b.GC()
+	if b.Get("old") != 0 {
+		t.Errorf("expected old entry to be collected")
+	}
+	if b.Get("new") == 0 {
+		t.Errorf("expected new entry to be kept")
+	}
+}
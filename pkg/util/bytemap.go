@@ -0,0 +1,76 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/base64"
+)
+
+// ByteMap maps keys to byte values. Like []byte, the values are base64 encoded in JSON
+// and nil values are encoded as null. Unlike []byte, they are also decoded from YAML.
+type ByteMap map[string][]byte
+
+// SetYAML implements the yaml.Setter interface.
+func (m *ByteMap) SetYAML(tag string, value interface{}) bool {
+	if value == nil {
+		*m = nil
+		return true
+	}
+	in, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return false
+	}
+	out := make(ByteMap, len(in))
+	for k, v := range in {
+		key, ok := k.(string)
+		if !ok {
+			return false
+		}
+		if v == nil {
+			out[key] = nil
+			continue
+		}
+		str, ok := v.(string)
+		if !ok {
+			return false
+		}
+		data, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			return false
+		}
+		out[key] = data
+	}
+	*m = out
+	return true
+}
+
+// GetYAML implements the yaml.Getter interface.
+func (m ByteMap) GetYAML() (tag string, value interface{}) {
+	if m == nil {
+		return
+	}
+	out := make(map[string]interface{}, len(m))
+	for key, data := range m {
+		if data == nil {
+			out[key] = nil
+			continue
+		}
+		out[key] = base64.StdEncoding.EncodeToString(data)
+	}
+	value = out
+	return
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/v1/yaml"
+)
+
+type ByteMapHolder struct {
+	M ByteMap `json:"m" yaml:"m"`
+}
+
+func TestByteMapMarshalJSONUnmarshalYAML(t *testing.T) {
+	cases := []ByteMap{
+		nil,
+		{},
+		{"a": []byte("secret"), "b": {}, "c": nil},
+	}
+
+	for _, c := range cases {
+		input := ByteMapHolder{c}
+		jsonMarshalled, err := json.Marshal(&input)
+		if err != nil {
+			t.Errorf("1: Failed to marshal input: '%v': %v", input, err)
+		}
+
+		var result ByteMapHolder
+		if err := yaml.Unmarshal(jsonMarshalled, &result); err != nil {
+			t.Errorf("2: Failed to unmarshal '%+v': %v", string(jsonMarshalled), err)
+		}
+		if !reflect.DeepEqual(input, result) {
+			t.Errorf("3: Failed to marshal input '%#v': got %#v", input, result)
+		}
+	}
+}
+
+func TestByteMapMarshalYAMLUnmarshalYAML(t *testing.T) {
+	cases := []ByteMap{
+		nil,
+		{"a": []byte("secret"), "b": {}, "c": nil},
+	}
+
+	for _, c := range cases {
+		input := ByteMapHolder{c}
+		yamlMarshalled, err := yaml.Marshal(&input)
+		if err != nil {
+			t.Errorf("1: Failed to marshal input: '%v': %v", input, err)
+		}
+
+		var result ByteMapHolder
+		if err := yaml.Unmarshal(yamlMarshalled, &result); err != nil {
+			t.Errorf("2: Failed to unmarshal '%+v': %v", string(yamlMarshalled), err)
+		}
+		if !reflect.DeepEqual(input, result) {
+			t.Errorf("3: Failed to marshal input '%#v': got %#v", input, result)
+		}
+	}
+}
+
+func TestByteMapUnmarshalYAMLInvalid(t *testing.T) {
+	var result ByteMapHolder
+	if err := yaml.Unmarshal([]byte("m:\n  a: \"not base64!\"\n"), &result); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if result.M != nil {
+		t.Errorf("Expected invalid base64 values not to be decoded, got %#v", result.M)
+	}
+}
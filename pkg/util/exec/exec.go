@@ -37,6 +37,8 @@ type Cmd interface {
 	// and standard error.  This follows the pattern of package os/exec.
 	CombinedOutput() ([]byte, error)
 	SetDir(dir string)
+	// SetEnv sets the environment of the command, in the form "key=value".
+	SetEnv(env []string)
 }
 
 // ExitError is an interface that presents an API similar to os.ProcessState, which is
@@ -69,6 +71,10 @@ func (cmd *cmdWrapper) SetDir(dir string) {
 	cmd.Dir = dir
 }
 
+func (cmd *cmdWrapper) SetEnv(env []string) {
+	cmd.Env = env
+}
+
 // CombinedOutput is part of the Cmd interface.
 func (cmd *cmdWrapper) CombinedOutput() ([]byte, error) {
 	out, err := (*osexec.Cmd)(cmd).CombinedOutput()
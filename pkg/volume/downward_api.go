@@ -0,0 +1,137 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fieldpath"
+)
+
+// DownwardAPI volumes hold fields of the pod in files. The files are rewritten on every SetUp,
+// so that they follow the updates of the labels and annotations of the pod.
+type DownwardAPI struct {
+	Name    string
+	PodID   string
+	RootDir string
+	Items   []api.DownwardAPIVolumeFile
+	pod     *api.BoundPod
+}
+
+// downwardAPIPlugin builds and cleans the DownwardAPI volumes.
+type downwardAPIPlugin struct {
+	host Host
+}
+
+func (p *downwardAPIPlugin) Init(host Host) {
+	p.host = host
+}
+
+func (p *downwardAPIPlugin) Name() string {
+	return "downward-api"
+}
+
+func (p *downwardAPIPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.Source != nil && spec.Source.DownwardAPI != nil
+}
+
+func (p *downwardAPIPlugin) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	return &DownwardAPI{
+		Name:    spec.Name,
+		PodID:   pod.Name,
+		RootDir: p.host.GetRootDir(),
+		Items:   spec.Source.DownwardAPI.Items,
+		pod:     pod,
+	}, nil
+}
+
+func (p *downwardAPIPlugin) NewCleaner(volName, podID string) (Cleaner, error) {
+	return &DownwardAPI{
+		Name:    volName,
+		PodID:   podID,
+		RootDir: p.host.GetRootDir(),
+	}, nil
+}
+
+// SetUp writes the fields of the pod to the files. Each file is replaced atomically, so that
+// containers never read a partially written file.
+func (d *DownwardAPI) SetUp() error {
+	volumePath := d.GetPath()
+	if err := os.MkdirAll(volumePath, 0750); err != nil {
+		return err
+	}
+	for _, item := range d.Items {
+		value, err := fieldpath.ExtractFieldPathAsString(d.pod, item.FieldRef.FieldPath)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomically(path.Join(volumePath, item.Path), []byte(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFileAtomically replaces the file at filePath with data unless it already holds it.
+func writeFileAtomically(filePath string, data []byte) error {
+	if current, err := ioutil.ReadFile(filePath); err == nil && string(current) == string(data) {
+		return nil
+	}
+	dir := path.Dir(filePath)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, "."+path.Base(filePath))
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), filePath); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+func (d *DownwardAPI) GetPath() string {
+	return path.Join(d.RootDir, d.PodID, "volumes", "downward-api", d.Name)
+}
+
+// TearDown simply deletes everything in the directory.
+func (d *DownwardAPI) TearDown() error {
+	tmpDir, err := renameDirectory(d.GetPath(), d.Name+".deleting~")
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(tmpDir)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+func TestDownwardAPI(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "DownwardAPI")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	pod := &api.BoundPod{
+		ObjectMeta: api.ObjectMeta{
+			Name:   "foo",
+			Labels: map[string]string{"app": "web"},
+		},
+		Res: api.BoundResource{
+			Network: api.Network{Address: "10.0.0.5/24"},
+		},
+	}
+	vol := &api.Volume{
+		Name: "podinfo",
+		Source: &api.VolumeSource{
+			DownwardAPI: &api.DownwardAPIVolumeSource{
+				Items: []api.DownwardAPIVolumeFile{
+					{Path: "labels", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+					{Path: "net/address", FieldRef: api.ObjectFieldSelector{FieldPath: "res.network.address"}},
+				},
+			},
+		},
+	}
+	builder, err := newTestPluginMgr(t, &fakeHost{rootDir: tempDir}).NewBuilder(vol, pod)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	volumePath := builder.GetPath()
+	if expected := path.Join(tempDir, "foo", "volumes", "downward-api", "podinfo"); volumePath != expected {
+		t.Errorf("Expected path %s, got %s", expected, volumePath)
+	}
+	if err := builder.SetUp(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectFile := func(name, expected string) {
+		data, err := ioutil.ReadFile(path.Join(volumePath, name))
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if string(data) != expected {
+			t.Errorf("Expected %s to hold %q, got %q", name, expected, string(data))
+		}
+	}
+	expectFile("labels", `app="web"`)
+	expectFile("net/address", "10.0.0.5/24")
+
+	// The labels follow the updates of the pod.
+	pod.Labels["tier"] = "front"
+	if err := builder.SetUp(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectFile("labels", "app=\"web\"\ntier=\"front\"")
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/credentialprovider"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
+	"github.com/golang/glog"
+)
+
+// gitCredentialHelper answers the credential requests of git with the credentials
+// of its environment, which keeps them out of the arguments and of the repository config.
+const gitCredentialHelper = `credential.helper=!f() { echo "username=$KUBE_GIT_USERNAME"; echo "password=$KUBE_GIT_PASSWORD"; }; f`
+
+var commitHashRegexp = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// GitDir volumes are checkouts of a git repository. Every commit is checked out to
+// its own hidden directory of the volume, and the volume exposes the checkout through
+// a symlink named after the repository, which is swapped atomically on refresh.
+type GitDir struct {
+	Source   string
+	Revision string
+	// Subdirectory of the repository the symlink points to.
+	Directory string
+	// Number of commits of a shallow clone, all of the history when 0.
+	Depth      int
+	Submodules bool
+	// Seconds between the fetches of the repository, never refreshed when 0.
+	RefreshSeconds int
+	// Name of the secret holding the credentials of the repository.
+	SecretName string
+	PodID      string
+	RootDir    string
+	Name       string
+	// The pod of the volume, which the sync events are about.
+	pod     *api.BoundPod
+	secrets SecretGetter
+	keyring credentialprovider.DockerKeyring
+	exec    exec.Interface
+}
+
+// gitRepoPlugin builds and cleans the GitDir volumes.
+type gitRepoPlugin struct {
+	host    Host
+	keyring credentialprovider.DockerKeyring
+	exec    exec.Interface
+}
+
+func (p *gitRepoPlugin) Init(host Host) {
+	p.host = host
+}
+
+func (p *gitRepoPlugin) Name() string {
+	return "git"
+}
+
+func (p *gitRepoPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.Source != nil && spec.Source.GitRepo != nil
+}
+
+func (p *gitRepoPlugin) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	gitRepo := spec.Source.GitRepo
+	return &GitDir{
+		Source:         gitRepo.Repository,
+		Revision:       gitRepo.Revision,
+		Directory:      gitRepo.Directory,
+		Depth:          gitRepo.Depth,
+		Submodules:     gitRepo.Submodules,
+		RefreshSeconds: gitRepo.RefreshSeconds,
+		SecretName:     gitRepo.SecretName,
+		PodID:          pod.Name,
+		RootDir:        p.host.GetRootDir(),
+		Name:           spec.Name,
+		pod:            pod,
+		secrets:        p.host,
+		keyring:        p.keyring,
+		exec:           p.exec,
+	}, nil
+}
+
+func (p *gitRepoPlugin) NewCleaner(volName, podID string) (Cleaner, error) {
+	return &GitDir{
+		Name:    volName,
+		PodID:   podID,
+		RootDir: p.host.GetRootDir(),
+	}, nil
+}
+
+// SetUp checks out the repository the first time, and the new commit of the revision
+// once the refresh interval elapsed. A failed refresh keeps the current checkout.
+func (g *GitDir) SetUp() error {
+	volumePath := g.GetPath()
+	if err := os.MkdirAll(volumePath, 0750); err != nil {
+		return err
+	}
+	current := g.currentCommit()
+	if current != "" {
+		if g.RefreshSeconds <= 0 {
+			return nil
+		}
+		info, err := os.Stat(path.Join(volumePath, "."+current))
+		if err != nil {
+			return err
+		}
+		if time.Since(info.ModTime()) < time.Duration(g.RefreshSeconds)*time.Second {
+			return nil
+		}
+	}
+	commit, err := g.sync(current)
+	if err != nil {
+		g.event("failed", "syncFailed", "Failed to sync volume %s at commit %q: %v", g.Name, current, err)
+		if current != "" {
+			glog.Errorf("Failed to refresh git volume %s of pod %s: %v", g.Name, g.PodID, err)
+			return nil
+		}
+		return err
+	}
+	if commit != current {
+		g.event("running", "synced", "Synced volume %s to commit %s", g.Name, commit)
+	}
+	return nil
+}
+
+// sync checks out the commit of the revision, unless it is the current one, and swaps
+// the symlink of the volume to it. It returns the commit of the volume.
+func (g *GitDir) sync(current string) (string, error) {
+	volumePath := g.GetPath()
+	env, err := g.credentials()
+	if err != nil {
+		return "", err
+	}
+	commit := g.Revision
+	if !commitHashRegexp.MatchString(g.Revision) {
+		ref := g.Revision
+		if ref == "" {
+			ref = "HEAD"
+		}
+		out, err := g.git(env, volumePath, "ls-remote", g.Source, ref)
+		if err != nil {
+			return "", err
+		}
+		if commit = parseLsRemote(string(out)); commit == "" {
+			return "", fmt.Errorf("revision %q not found in %s", ref, g.Source)
+		}
+	}
+	checkout := path.Join(volumePath, "."+commit)
+	if commit == current {
+		now := time.Now()
+		return commit, os.Chtimes(checkout, now, now)
+	}
+
+	if err := os.RemoveAll(checkout); err != nil {
+		return "", err
+	}
+	args := []string{"clone", "--no-checkout"}
+	if g.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(g.Depth))
+	}
+	if g.Revision != "" && g.Revision != commit {
+		args = append(args, "--branch", g.Revision)
+	}
+	if _, err := g.git(env, volumePath, append(args, g.Source, "."+commit)...); err != nil {
+		return "", err
+	}
+	if _, err := g.git(env, checkout, "checkout", commit); err != nil {
+		return "", err
+	}
+	if g.Submodules {
+		if _, err := g.git(env, checkout, "submodule", "update", "--init", "--recursive"); err != nil {
+			return "", err
+		}
+	}
+	target := path.Join("."+commit, g.Directory)
+	if info, err := os.Stat(path.Join(volumePath, target)); err != nil {
+		return "", err
+	} else if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory of %s", g.Directory, g.Source)
+	}
+
+	// The rename of the new symlink replaces the old one atomically.
+	link := path.Join(volumePath, gitRepoName(g.Source))
+	os.Remove(link + ".tmp")
+	if err := os.Symlink(target, link+".tmp"); err != nil {
+		return "", err
+	}
+	if err := os.Rename(link+".tmp", link); err != nil {
+		return "", err
+	}
+	if current != "" {
+		if err := os.RemoveAll(path.Join(volumePath, "."+current)); err != nil {
+			glog.Errorf("Failed to remove the checkout of commit %s: %v", current, err)
+		}
+	}
+	return commit, nil
+}
+
+// currentCommit returns the commit the symlink of the volume points to, or "" before
+// the first checkout.
+func (g *GitDir) currentCommit() string {
+	target, err := os.Readlink(path.Join(g.GetPath(), gitRepoName(g.Source)))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.SplitN(target, "/", 2)[0], ".")
+}
+
+// credentials returns the environment of the git commands holding the credentials of
+// the repository, from the secret of the volume or else from the node keyring.
+func (g *GitDir) credentials() ([]string, error) {
+	var username, password string
+	if g.SecretName != "" {
+		secret, err := g.secrets.GetSecret(g.pod.Namespace, g.SecretName)
+		if err != nil {
+			return nil, err
+		}
+		username, password = string(secret.Data["username"]), string(secret.Data["password"])
+	} else if g.keyring != nil {
+		auth, ok := g.keyring.Lookup(gitRepoLocation(g.Source))
+		if !ok {
+			return nil, nil
+		}
+		username, password = auth.Username, auth.Password
+	} else {
+		return nil, nil
+	}
+	return append(os.Environ(), "KUBE_GIT_USERNAME="+username, "KUBE_GIT_PASSWORD="+password), nil
+}
+
+// git runs a git command in dir, with the credential helper when env holds credentials.
+func (g *GitDir) git(env []string, dir string, args ...string) ([]byte, error) {
+	if env != nil {
+		args = append([]string{"-c", gitCredentialHelper}, args...)
+	}
+	cmd := g.exec.Command("git", args...)
+	cmd.SetDir(dir)
+	if env != nil {
+		cmd.SetEnv(env)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return out, fmt.Errorf("git %s failed: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return out, nil
+}
+
+func (g *GitDir) event(status, reason, messageFmt string, args ...interface{}) {
+	if g.pod == nil {
+		return
+	}
+	ref, err := api.GetReference(g.pod)
+	if err != nil {
+		glog.V(4).Infof("Couldn't make a ref to pod %v: %v", g.PodID, err)
+		return
+	}
+	record.Eventf(ref, status, reason, messageFmt, args...)
+}
+
+func (g *GitDir) GetPath() string {
+	return path.Join(g.RootDir, g.PodID, "volumes", "git", g.Name)
+}
+
+// TearDown simply deletes everything in the directory.
+func (g *GitDir) TearDown() error {
+	tmpDir, err := renameDirectory(g.GetPath(), g.Name+"~deleting")
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(tmpDir)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseLsRemote returns the commit of the output of git ls-remote, which is the one
+// of the peeled tag when the revision is an annotated tag.
+func parseLsRemote(out string) string {
+	commit := ""
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.HasSuffix(fields[1], "^{}") {
+			return fields[0]
+		}
+		if commit == "" {
+			commit = fields[0]
+		}
+	}
+	return commit
+}
+
+// gitRepoName returns the name of the directory git clone would check out the repository to.
+func gitRepoName(source string) string {
+	name := strings.TrimSuffix(source, "/")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(name, ".git")
+}
+
+// gitRepoLocation returns the repository URL without its scheme and user, the way
+// the keyring indexes the registries.
+func gitRepoLocation(source string) string {
+	if i := strings.Index(source, "://"); i >= 0 {
+		source = source[i+3:]
+	}
+	if i := strings.Index(source, "@"); i >= 0 && i < strings.IndexAny(source+"/", "/") {
+		source = source[i+1:]
+	}
+	return source
+}
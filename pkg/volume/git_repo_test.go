@@ -0,0 +1,290 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
+)
+
+const (
+	commit1 = "2a30ce65c5ab586b98916d83385c5983edd353a1"
+	commit2 = "6f8c1fcd6f6c6e4ad9f2f3a34cbd5f8dc3b1a6a2"
+)
+
+// gitStep scripts the output of a git command, after running its action in its directory.
+type gitStep struct {
+	out    string
+	err    error
+	action func(dir string)
+}
+
+func scriptGit(steps ...gitStep) (*exec.FakeExec, []*exec.FakeCmd) {
+	fake := &exec.FakeExec{}
+	cmds := []*exec.FakeCmd{}
+	for i := range steps {
+		step := steps[i]
+		fcmd := &exec.FakeCmd{}
+		fcmd.CombinedOutputScript = []exec.FakeCombinedOutputAction{
+			func() ([]byte, error) {
+				if step.action != nil {
+					step.action(fcmd.Dirs[0])
+				}
+				return []byte(step.out), step.err
+			},
+		}
+		cmds = append(cmds, fcmd)
+		fake.CommandScript = append(fake.CommandScript, func(cmd string, args ...string) exec.Cmd { return exec.InitFakeCmd(fcmd, cmd, args...) })
+	}
+	return fake, cmds
+}
+
+// cloneTo creates the checkout of the clone run in dir, with its docs directory.
+func cloneTo(commit string) func(dir string) {
+	return func(dir string) {
+		os.MkdirAll(path.Join(dir, "."+commit, "docs"), 0750)
+	}
+}
+
+func argvs(cmds []*exec.FakeCmd) [][]string {
+	result := [][]string{}
+	for _, cmd := range cmds {
+		result = append(result, cmd.Argv)
+	}
+	return result
+}
+
+func TestGitVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "git")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fake, cmds := scriptGit(
+		gitStep{out: commit1 + "\trefs/heads/master\n"},
+		gitStep{action: cloneTo(commit1)},
+		gitStep{},
+		gitStep{},
+	)
+	g := GitDir{
+		Source:     "https://github.com/GoogleCloudPlatform/kubernetes.git",
+		Revision:   "master",
+		Directory:  "docs",
+		Depth:      1,
+		Submodules: true,
+		PodID:      "foo",
+		RootDir:    dir,
+		Name:       "test-pod",
+		exec:       fake,
+	}
+	if err := g.SetUp(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expectedCmds := [][]string{
+		{"git", "ls-remote", g.Source, "master"},
+		{"git", "clone", "--no-checkout", "--depth", "1", "--branch", "master", g.Source, "." + commit1},
+		{"git", "checkout", commit1},
+		{"git", "submodule", "update", "--init", "--recursive"},
+	}
+	if !reflect.DeepEqual(expectedCmds, argvs(cmds)) {
+		t.Errorf("unexpected commands: %v, expected: %v", argvs(cmds), expectedCmds)
+	}
+	checkout := path.Join(g.GetPath(), "."+commit1)
+	expectedDirs := []string{g.GetPath(), g.GetPath(), checkout, checkout}
+	for i := range expectedDirs {
+		if !reflect.DeepEqual([]string{expectedDirs[i]}, cmds[i].Dirs) {
+			t.Errorf("unexpected directories of command %d: %v, expected: %v", i, cmds[i].Dirs, expectedDirs[i])
+		}
+	}
+	link := path.Join(g.GetPath(), "kubernetes")
+	if target, err := os.Readlink(link); err != nil || target != "."+commit1+"/docs" {
+		t.Errorf("unexpected symlink target: %q, %v", target, err)
+	}
+
+	// The checkout is kept without a refresh interval.
+	if err := g.SetUp(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if fake.CommandCalls != len(expectedCmds) {
+		t.Errorf("unexpected command calls: expected %d, saw: %d", len(expectedCmds), fake.CommandCalls)
+	}
+}
+
+func TestGitVolumeRefresh(t *testing.T) {
+	dir, err := ioutil.TempDir("", "git")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fake, cmds := scriptGit(
+		gitStep{out: commit1 + "\tHEAD\n"},
+		gitStep{action: cloneTo(commit1)},
+		gitStep{},
+		gitStep{out: commit2 + "\tHEAD\n"},
+		gitStep{action: cloneTo(commit2)},
+		gitStep{},
+		gitStep{err: errors.New("unreachable")},
+	)
+	g := GitDir{
+		Source:         "git@github.com:GoogleCloudPlatform/kubernetes.git",
+		RefreshSeconds: 60,
+		PodID:          "foo",
+		RootDir:        dir,
+		Name:           "test-pod",
+		exec:           fake,
+	}
+	link := path.Join(g.GetPath(), "kubernetes")
+	expectLink := func(commit string) {
+		if target, err := os.Readlink(link); err != nil || target != "."+commit {
+			t.Errorf("unexpected symlink target: %q, %v, expected: %q", target, err, "."+commit)
+		}
+	}
+	// Makes the last sync of the checkout of commit older than the refresh interval.
+	expire := func(commit string) {
+		past := time.Now().Add(-2 * time.Minute)
+		os.Chtimes(path.Join(g.GetPath(), "."+commit), past, past)
+	}
+
+	if err := g.SetUp(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expectLink(commit1)
+	if err := g.SetUp(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if fake.CommandCalls != 3 {
+		t.Errorf("expected no refresh within the interval, saw %d command calls", fake.CommandCalls)
+	}
+
+	expire(commit1)
+	if err := g.SetUp(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expectLink(commit2)
+	if _, err := os.Stat(path.Join(g.GetPath(), "."+commit1)); !os.IsNotExist(err) {
+		t.Errorf("expected the old checkout to be removed, got %v", err)
+	}
+	if e, a := []string{"git", "clone", "--no-checkout", g.Source, "." + commit2}, cmds[4].Argv; !reflect.DeepEqual(e, a) {
+		t.Errorf("unexpected clone: %v, expected: %v", a, e)
+	}
+
+	// A failed refresh keeps the checkout.
+	expire(commit2)
+	if err := g.SetUp(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expectLink(commit2)
+	if fake.CommandCalls != len(cmds) {
+		t.Errorf("unexpected command calls: expected %d, saw: %d", len(cmds), fake.CommandCalls)
+	}
+}
+
+func TestGitVolumeCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "git")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fake, cmds := scriptGit(
+		gitStep{action: cloneTo(commit1)},
+		gitStep{},
+	)
+	secrets := &fakeSecretGetter{secrets: map[string]*api.Secret{
+		"ns/creds": {Data: map[string][]byte{"username": []byte("user"), "password": []byte("s3cr3t")}},
+	}}
+	plugins := &PluginMgr{}
+	if err := plugins.InitPlugins([]VolumePlugin{&gitRepoPlugin{exec: fake}}, &fakeHost{rootDir: dir, secrets: secrets}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pod := &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "ns"}}
+	vol := &api.Volume{Name: "repo", Source: &api.VolumeSource{GitRepo: &api.GitRepo{
+		Repository: "https://example.com/private.git",
+		Revision:   commit1,
+		SecretName: "creds",
+	}}}
+	builder, err := plugins.NewBuilder(vol, pod)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := builder.SetUp(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if e, a := []string{"ns/creds"}, secrets.gets; !reflect.DeepEqual(e, a) {
+		t.Errorf("unexpected secret gets: %v, expected: %v", a, e)
+	}
+	for _, cmd := range cmds {
+		if len(cmd.Argv) < 3 || cmd.Argv[1] != "-c" || cmd.Argv[2] != gitCredentialHelper {
+			t.Errorf("expected the credential helper in %v", cmd.Argv)
+		}
+		if len(cmd.Envs) != 1 || !containsString(cmd.Envs[0], "KUBE_GIT_USERNAME=user") || !containsString(cmd.Envs[0], "KUBE_GIT_PASSWORD=s3cr3t") {
+			t.Errorf("expected the credentials in the environment of %v", cmd.Argv)
+		}
+		if strings.Contains(strings.Join(cmd.Argv, " "), "s3cr3t") {
+			t.Errorf("unexpected password in %v", cmd.Argv)
+		}
+	}
+	if target, err := os.Readlink(path.Join(builder.GetPath(), "private")); err != nil || target != "."+commit1 {
+		t.Errorf("unexpected symlink target: %q, %v", target, err)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseLsRemote(t *testing.T) {
+	tests := map[string]string{
+		"":                                "",
+		commit1 + "\trefs/heads/master\n": commit1,
+		commit1 + "\trefs/tags/v1\n" + commit2 + "\trefs/tags/v1^{}\n": commit2,
+	}
+	for out, expected := range tests {
+		if commit := parseLsRemote(out); commit != expected {
+			t.Errorf("expected %q for %q, got %q", expected, out, commit)
+		}
+	}
+}
+
+func TestGitRepoName(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/GoogleCloudPlatform/kubernetes.git": "kubernetes",
+		"https://example.com/repo/":                             "repo",
+		"git@github.com:kubernetes.git":                         "kubernetes",
+	}
+	for source, expected := range tests {
+		if name := gitRepoName(source); name != expected {
+			t.Errorf("expected %q for %q, got %q", expected, source, name)
+		}
+	}
+	if e, a := "github.com/org/repo.git", gitRepoLocation("https://user@github.com/org/repo.git"); e != a {
+		t.Errorf("expected %q, got %q", e, a)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
+	"github.com/golang/glog"
+)
+
+// quotaUtil sets the project quotas of the directories of the data filesystem.
+type quotaUtil interface {
+	// SetQuota makes dir the project projectID, limited to disk GB.
+	SetQuota(dir string, projectID uint32, disk int) error
+	// ClearQuota removes the limit of the project projectID and clears it from dir.
+	ClearQuota(dir string, projectID uint32) error
+}
+
+// LocalQuota volumes are directories of the data filesystem of the host, limited by
+// their own project quota. The directory is bind mounted to the volume path, and kept until
+// the volume is torn down once the pod is deleted.
+type LocalQuota struct {
+	Name    string
+	PodID   string
+	RootDir string
+	// Mount point of the data filesystem, which holds the directories of the volumes.
+	DataDir string
+	// Disk space size in GB.
+	Disk int
+	// Utility interface that sets the project quotas.
+	quota quotaUtil
+	// Mounter interface that provides system calls to bind mount the directories.
+	mounter mounter
+}
+
+// localQuotaPlugin builds and cleans the LocalQuota volumes.
+type localQuotaPlugin struct {
+	host    Host
+	dataDir string
+	quota   quotaUtil
+	mounter mounter
+}
+
+func (p *localQuotaPlugin) Init(host Host) {
+	p.host = host
+}
+
+func (p *localQuotaPlugin) Name() string {
+	return "local-quota"
+}
+
+func (p *localQuotaPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.Source != nil && spec.Source.LocalQuotaVolume != nil
+}
+
+func (p *localQuotaPlugin) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	return &LocalQuota{
+		Name:    spec.Name,
+		PodID:   pod.Name,
+		RootDir: p.host.GetRootDir(),
+		DataDir: p.dataDir,
+		Disk:    spec.Source.LocalQuotaVolume.Disk,
+		quota:   p.quota,
+		mounter: p.mounter,
+	}, nil
+}
+
+func (p *localQuotaPlugin) NewCleaner(volName, podID string) (Cleaner, error) {
+	return &LocalQuota{
+		Name:    volName,
+		PodID:   podID,
+		RootDir: p.host.GetRootDir(),
+		DataDir: p.dataDir,
+		quota:   p.quota,
+		mounter: p.mounter,
+	}, nil
+}
+
+func (v *LocalQuota) GetPath() string {
+	return path.Join(v.RootDir, v.PodID, "volumes", "local-quota", v.Name)
+}
+
+// dataPath returns the directory of the volume in the data filesystem.
+func (v *LocalQuota) dataPath() string {
+	return path.Join(v.DataDir, "pod-volumes", v.PodID, v.Name)
+}
+
+// projectID returns the project of the quota of the volume. The projects of the volumes are
+// above 0xFFFF, which the projects of the containers are below.
+func (v *LocalQuota) projectID() uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(v.PodID + "/" + v.Name))
+	return 0x10000 + h.Sum32()%(0x7FFFFFFF-0x10000)
+}
+
+// SetUp creates the directory in the data filesystem, limits it with its quota and bind
+// mounts it to the volume path. The limit is updated when the volume is already set up.
+func (v *LocalQuota) SetUp() error {
+	dataPath := v.dataPath()
+	if err := os.MkdirAll(dataPath, 0750); err != nil {
+		return err
+	}
+	if err := v.quota.SetQuota(dataPath, v.projectID(), v.Disk); err != nil {
+		return err
+	}
+	volumePath := v.GetPath()
+	if _, err := os.Stat(volumePath); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(volumePath, 0750); err != nil {
+		return err
+	}
+	if err := v.mounter.Mount(dataPath, volumePath, "", MOUNT_MS_BIND, ""); err != nil {
+		os.Remove(volumePath)
+		return err
+	}
+	return nil
+}
+
+// TearDown unmounts the volume path, then clears the quota and deletes the directory
+// in the data filesystem.
+func (v *LocalQuota) TearDown() error {
+	volumePath := v.GetPath()
+	if err := v.mounter.Unmount(volumePath, 0); err != nil && !os.IsNotExist(err) {
+		glog.Warningf("Failed to unmount %s: %v", volumePath, err)
+	}
+	// Only an empty directory is removed, so that the data is never deleted through
+	// a mount which failed to unmount.
+	if err := os.Remove(volumePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	dataPath := v.dataPath()
+	if err := v.quota.ClearQuota(dataPath, v.projectID()); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dataPath); err != nil {
+		return err
+	}
+	// The directory of the pod is removed with its last volume.
+	os.Remove(path.Dir(dataPath))
+	return nil
+}
+
+// xfsQuota sets the project quotas with xfs_quota, without the /etc/projects and
+// /etc/projid files, since the projects of the volumes are numeric.
+type xfsQuota struct {
+	// Mount point of the xfs filesystem.
+	mountPoint string
+	exec       exec.Interface
+}
+
+func (q *xfsQuota) run(command string) error {
+	out, err := q.exec.Command("xfs_quota", "-x", "-c", command, q.mountPoint).CombinedOutput()
+	glog.V(3).Infof("Exec Command %s out: %s", command, string(out))
+	if err != nil {
+		return fmt.Errorf("xfs_quota %q failed: %v: %s", command, err, out)
+	}
+	return nil
+}
+
+func (q *xfsQuota) SetQuota(dir string, projectID uint32, disk int) error {
+	if err := q.run(fmt.Sprintf("project -s -p %s %d", dir, projectID)); err != nil {
+		return err
+	}
+	return q.run(fmt.Sprintf("limit -p bhard=%dg %d", disk, projectID))
+}
+
+func (q *xfsQuota) ClearQuota(dir string, projectID uint32) error {
+	if err := q.run(fmt.Sprintf("limit -p bhard=0 %d", projectID)); err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return q.run(fmt.Sprintf("project -C -p %s %d", dir, projectID))
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
+)
+
+// fakeQuota records the limits of the projects.
+type fakeQuota struct {
+	limits map[uint32]int
+	dirs   map[uint32]string
+}
+
+func (f *fakeQuota) SetQuota(dir string, projectID uint32, disk int) error {
+	if f.limits == nil {
+		f.limits, f.dirs = map[uint32]int{}, map[uint32]string{}
+	}
+	f.limits[projectID] = disk
+	f.dirs[projectID] = dir
+	return nil
+}
+
+func (f *fakeQuota) ClearQuota(dir string, projectID uint32) error {
+	if f.dirs[projectID] != dir {
+		return fmt.Errorf("project %d is not set on %s", projectID, dir)
+	}
+	delete(f.limits, projectID)
+	delete(f.dirs, projectID)
+	return nil
+}
+
+func TestLocalQuotaSetUpAndTearDown(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "LocalQuota")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	rootDir, dataDir := path.Join(tempDir, "root"), path.Join(tempDir, "data")
+	quota, mounter := &fakeQuota{}, &fakeMountTable{}
+	plugins := &PluginMgr{}
+	if err := plugins.InitPlugins([]VolumePlugin{&localQuotaPlugin{dataDir: dataDir, quota: quota, mounter: mounter}}, &fakeHost{rootDir: rootDir}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pod := &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
+	vol := &api.Volume{Name: "data", Source: &api.VolumeSource{LocalQuotaVolume: &api.LocalQuotaVolume{Disk: 10}}}
+	builder, err := plugins.NewBuilder(vol, pod)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := builder.SetUp(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// A second set up of the volume mounts nothing.
+	if err := builder.SetUp(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	volumePath := path.Join(rootDir, "foo/volumes/local-quota/data")
+	dataPath := path.Join(dataDir, "pod-volumes/foo/data")
+	if e, a := volumePath, builder.GetPath(); e != a {
+		t.Errorf("Expected path %s, got %s", e, a)
+	}
+	if e, a := []string{"mount " + dataPath + " " + volumePath}, mounter.log; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected mounts %v, got %v", e, a)
+	}
+	if _, err := os.Stat(dataPath); err != nil {
+		t.Errorf("Expected %s to exist, got %v", dataPath, err)
+	}
+	projectID := builder.(*LocalQuota).projectID()
+	if projectID <= 0xFFFF {
+		t.Errorf("Expected the project above 0xFFFF, got %d", projectID)
+	}
+	if e, a := map[uint32]int{projectID: 10}, quota.limits; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected limits %v, got %v", e, a)
+	}
+
+	cleaner, err := plugins.NewCleaner("local-quota", "data", "foo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := cleaner.TearDown(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mounter.mounts) != 0 {
+		t.Errorf("Expected no mounts, got %v", mounter.mounts)
+	}
+	if len(quota.limits) != 0 {
+		t.Errorf("Expected no limits, got %v", quota.limits)
+	}
+	for _, p := range []string{volumePath, dataPath, path.Dir(dataPath)} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed, got %v", p, err)
+		}
+	}
+}
+
+func TestXFSQuota(t *testing.T) {
+	var cmds []*exec.FakeCmd
+	fake := exec.FakeExec{}
+	for i := 0; i < 2; i++ {
+		fcmd := &exec.FakeCmd{
+			CombinedOutputScript: []exec.FakeCombinedOutputAction{
+				func() ([]byte, error) { return []byte{}, nil },
+			},
+		}
+		cmds = append(cmds, fcmd)
+		fake.CommandScript = append(fake.CommandScript, func(cmd string, args ...string) exec.Cmd { return exec.InitFakeCmd(fcmd, cmd, args...) })
+	}
+	quota := &xfsQuota{mountPoint: "/data", exec: &fake}
+	if err := quota.SetQuota("/data/pod-volumes/foo/data", 70000, 10); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := [][]string{
+		{"xfs_quota", "-x", "-c", "project -s -p /data/pod-volumes/foo/data 70000", "/data"},
+		{"xfs_quota", "-x", "-c", "limit -p bhard=10g 70000", "/data"},
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(expected[i], cmds[i].Argv) {
+			t.Errorf("Expected %v, got %v", expected[i], cmds[i].Argv)
+		}
+	}
+}
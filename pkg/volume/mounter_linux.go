@@ -87,3 +87,31 @@ func (mounter *DiskMounter) RefCount(mount Interface) (string, int, error) {
 	}
 	return deviceName, refCount, nil
 }
+
+// List parses /proc/mounts into the mount points of the host.
+func (mounter *DiskMounter) List() ([]MountPoint, error) {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseMounts(file)
+}
+
+func parseMounts(r io.Reader) ([]MountPoint, error) {
+	mounts := []MountPoint{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		mounts = append(mounts, MountPoint{
+			Device: fields[0],
+			Path:   fields[1],
+			Type:   fields[2],
+			Opts:   strings.Split(fields[3], ","),
+		})
+	}
+	return mounts, scanner.Err()
+}
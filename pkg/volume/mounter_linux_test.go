@@ -0,0 +1,41 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMounts(t *testing.T) {
+	mounts, err := parseMounts(strings.NewReader(`rootfs / rootfs rw 0 0
+/dev/sda1 /var/lib/kubelet ext4 rw,relatime,data=ordered 0 0
+nfs.example.com:/exports /var/lib/kubelet/foo/volumes/nfs/data nfs4 ro,relatime,vers=4.0 0 0
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []MountPoint{
+		{Device: "rootfs", Path: "/", Type: "rootfs", Opts: []string{"rw"}},
+		{Device: "/dev/sda1", Path: "/var/lib/kubelet", Type: "ext4", Opts: []string{"rw", "relatime", "data=ordered"}},
+		{Device: "nfs.example.com:/exports", Path: "/var/lib/kubelet/foo/volumes/nfs/data", Type: "nfs4", Opts: []string{"ro", "relatime", "vers=4.0"}},
+	}
+	if !reflect.DeepEqual(expected, mounts) {
+		t.Errorf("Expected %#v, got %#v", expected, mounts)
+	}
+}
@@ -34,3 +34,7 @@ func (mounter *DiskMounter) Unmount(target string, flags int) error {
 func (mounter *DiskMounter) RefCount(PD Interface) (string, int, error) {
 	return "", 0, nil
 }
+
+func (mounter *DiskMounter) List() ([]MountPoint, error) {
+	return []MountPoint{}, nil
+}
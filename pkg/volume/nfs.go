@@ -0,0 +1,203 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
+	"github.com/golang/glog"
+)
+
+// NFS volumes are NFS exports mounted on the host for the lifetime of the pod. An export
+// is mounted once per pod: the other volumes of the pod which name it bind mount it.
+type NFS struct {
+	Name    string
+	PodID   string
+	RootDir string
+	// Hostname or IP address of the NFS server.
+	Server string
+	// Path exported by the NFS server.
+	ExportPath string
+	// Specifies whether the export is mounted read-only.
+	ReadOnly bool
+	// Mounter interface that provides system calls to mount the exports.
+	mounter mounter
+}
+
+// nfsPlugin builds and cleans the NFS volumes.
+type nfsPlugin struct {
+	host    Host
+	mounter mounter
+}
+
+func (p *nfsPlugin) Init(host Host) {
+	p.host = host
+}
+
+func (p *nfsPlugin) Name() string {
+	return "nfs"
+}
+
+func (p *nfsPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.Source != nil && spec.Source.NFS != nil
+}
+
+func (p *nfsPlugin) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	return &NFS{
+		Name:       spec.Name,
+		PodID:      pod.Name,
+		RootDir:    p.host.GetRootDir(),
+		Server:     spec.Source.NFS.Server,
+		ExportPath: path.Clean(spec.Source.NFS.Path),
+		ReadOnly:   spec.Source.NFS.ReadOnly,
+		mounter:    p.mounter,
+	}, nil
+}
+
+func (p *nfsPlugin) NewCleaner(volName, podID string) (Cleaner, error) {
+	return &NFS{
+		Name:    volName,
+		PodID:   podID,
+		RootDir: p.host.GetRootDir(),
+		mounter: p.mounter,
+	}, nil
+}
+
+func (n *NFS) GetPath() string {
+	return path.Join(n.RootDir, n.PodID, "volumes", "nfs", n.Name)
+}
+
+// source returns the device of the mounts of the export.
+func (n *NFS) source() string {
+	return n.Server + ":" + n.ExportPath
+}
+
+// SetUp mounts the export, or bind mounts the mount of the export by another volume of
+// the pod with the same access mode.
+func (n *NFS) SetUp() error {
+	volumePath := n.GetPath()
+	mounts, err := n.mounter.List()
+	if err != nil {
+		return err
+	}
+	if findMountPoint(mounts, volumePath) != nil {
+		return nil
+	}
+	if err := os.MkdirAll(volumePath, 0750); err != nil {
+		return err
+	}
+	flags := uintptr(0)
+	if n.ReadOnly {
+		flags = MOUNT_MS_RDONLY
+	}
+	if shared := n.findPodMount(mounts); shared != nil {
+		glog.V(3).Infof("Sharing the mount of %s at %s with %s", n.source(), shared.Path, volumePath)
+		err = n.mounter.Mount(shared.Path, volumePath, "", MOUNT_MS_BIND|flags, "")
+	} else {
+		err = n.mounter.Mount(n.source(), volumePath, "nfs", flags, "")
+	}
+	if err != nil {
+		os.Remove(volumePath)
+		return err
+	}
+	return nil
+}
+
+// findPodMount returns a mount of the export by another volume of the pod with the same
+// access mode, or nil.
+func (n *NFS) findPodMount(mounts []MountPoint) *MountPoint {
+	podDir := path.Dir(n.GetPath())
+	for i := range mounts {
+		mount := &mounts[i]
+		if mount.Device != n.source() || path.Dir(mount.Path) != podDir {
+			continue
+		}
+		if mount.Type != "nfs" && mount.Type != "nfs4" {
+			continue
+		}
+		if isReadOnlyMount(mount) == n.ReadOnly {
+			return mount
+		}
+	}
+	return nil
+}
+
+// TearDown unmounts the export and removes the mount point. The export stays mounted
+// by the other volumes of the pod which share it.
+func (n *NFS) TearDown() error {
+	volumePath := n.GetPath()
+	mounts, err := n.mounter.List()
+	if err != nil {
+		return err
+	}
+	if findMountPoint(mounts, volumePath) != nil {
+		if err := n.mounter.Unmount(volumePath, 0); err != nil {
+			return err
+		}
+	}
+	// Only an empty directory is removed, so that an export which failed to
+	// unmount is never deleted.
+	if err := os.Remove(volumePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func findMountPoint(mounts []MountPoint, mountPath string) *MountPoint {
+	for i := range mounts {
+		if mounts[i].Path == mountPath {
+			return &mounts[i]
+		}
+	}
+	return nil
+}
+
+func isReadOnlyMount(mount *MountPoint) bool {
+	for _, opt := range mount.Opts {
+		if opt == "ro" {
+			return true
+		}
+	}
+	return false
+}
+
+// nfsMounter mounts the NFS exports with mount(8), which resolves the server and
+// negotiates the protocol, and the other mounts with DiskMounter.
+type nfsMounter struct {
+	DiskMounter
+	exec exec.Interface
+}
+
+func (m *nfsMounter) Mount(source string, target string, fstype string, flags uintptr, data string) error {
+	if fstype != "nfs" {
+		return m.DiskMounter.Mount(source, target, fstype, flags, data)
+	}
+	options := "rw"
+	if flags&MOUNT_MS_RDONLY != 0 {
+		options = "ro"
+	}
+	glog.V(5).Infof("Mounting %s %s nfs %s", source, target, options)
+	output, err := m.exec.Command("mount", "-t", "nfs", "-o", options, source, target).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to mount %s at %s: %v: %s", source, target, err, output)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
+)
+
+// fakeMountTable is a mounter which tracks the mount points, as the kernel would.
+type fakeMountTable struct {
+	MockMounter
+	mounts []MountPoint
+	log    []string
+}
+
+func (f *fakeMountTable) Mount(source string, target string, fstype string, flags uintptr, data string) error {
+	mount := MountPoint{Device: source, Path: target, Type: fstype, Opts: []string{"rw"}}
+	if flags&MOUNT_MS_BIND != 0 {
+		if bound := findMountPoint(f.mounts, source); bound != nil {
+			mount.Device, mount.Type = bound.Device, bound.Type
+		}
+	}
+	if flags&MOUNT_MS_RDONLY != 0 {
+		mount.Opts = []string{"ro"}
+	}
+	f.mounts = append(f.mounts, mount)
+	f.log = append(f.log, "mount "+source+" "+target)
+	return nil
+}
+
+func (f *fakeMountTable) Unmount(target string, flags int) error {
+	for i := range f.mounts {
+		if f.mounts[i].Path == target {
+			f.mounts = append(f.mounts[:i], f.mounts[i+1:]...)
+			break
+		}
+	}
+	f.log = append(f.log, "unmount "+target)
+	return nil
+}
+
+func (f *fakeMountTable) List() ([]MountPoint, error) {
+	return append([]MountPoint{}, f.mounts...), nil
+}
+
+func nfsVolume(name string, readOnly bool) *api.Volume {
+	return &api.Volume{
+		Name:   name,
+		Source: &api.VolumeSource{NFS: &api.NFS{Server: "nfs.example.com", Path: "/exports/data/", ReadOnly: readOnly}},
+	}
+}
+
+func TestNFSSharesExportWithinPod(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "NFS")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	mounter := &fakeMountTable{}
+	plugins := &PluginMgr{}
+	if err := plugins.InitPlugins([]VolumePlugin{&nfsPlugin{mounter: mounter}}, &fakeHost{rootDir: tempDir}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pod := &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
+	other := &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "bar"}}
+
+	setUp := func(vol *api.Volume, pod *api.BoundPod) Builder {
+		builder, err := plugins.NewBuilder(vol, pod)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := builder.SetUp(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return builder
+	}
+	data := setUp(nfsVolume("data", false), pod)
+	setUp(nfsVolume("data", false), pod)
+	data2 := setUp(nfsVolume("data2", false), pod)
+	dataRO := setUp(nfsVolume("data-ro", true), pod)
+	otherData := setUp(nfsVolume("data", false), other)
+
+	expected := []string{
+		"mount nfs.example.com:/exports/data " + data.GetPath(),
+		"mount " + data.GetPath() + " " + data2.GetPath(),
+		"mount nfs.example.com:/exports/data " + dataRO.GetPath(),
+		"mount nfs.example.com:/exports/data " + otherData.GetPath(),
+	}
+	if !reflect.DeepEqual(expected, mounter.log) {
+		t.Errorf("Expected mounts %v, got %v", expected, mounter.log)
+	}
+
+	cleaner, err := plugins.NewCleaner("nfs", "data", "foo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := cleaner.TearDown(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(data.GetPath()); !os.IsNotExist(err) {
+		t.Errorf("Expected the volume to be removed, got %v", err)
+	}
+	if findMountPoint(mounter.mounts, data2.GetPath()) == nil {
+		t.Errorf("Expected %s to stay mounted", data2.GetPath())
+	}
+	// The remaining mount of the export is shared with the volumes set up later.
+	data3 := setUp(nfsVolume("data3", false), pod)
+	if e, a := "mount "+data2.GetPath()+" "+data3.GetPath(), mounter.log[len(mounter.log)-1]; e != a {
+		t.Errorf("Expected %q, got %q", e, a)
+	}
+}
+
+func TestNFSMounter(t *testing.T) {
+	var fcmd exec.FakeCmd
+	fcmd = exec.FakeCmd{
+		CombinedOutputScript: []exec.FakeCombinedOutputAction{
+			func() ([]byte, error) { return []byte{}, nil },
+		},
+	}
+	fake := exec.FakeExec{
+		CommandScript: []exec.FakeCommandAction{
+			func(cmd string, args ...string) exec.Cmd { return exec.InitFakeCmd(&fcmd, cmd, args...) },
+		},
+	}
+	mounter := &nfsMounter{exec: &fake}
+	if err := mounter.Mount("nfs.example.com:/exports", "/var/lib/kubelet/foo/volumes/nfs/data", "nfs", MOUNT_MS_RDONLY, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"mount", "-t", "nfs", "-o", "ro", "nfs.example.com:/exports", "/var/lib/kubelet/foo/volumes/nfs/data"}
+	if !reflect.DeepEqual(expected, fcmd.Argv) {
+		t.Errorf("Expected %v, got %v", expected, fcmd.Argv)
+	}
+}
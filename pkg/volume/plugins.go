@@ -0,0 +1,213 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/credentialprovider"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
+	"github.com/golang/glog"
+)
+
+// VolumePlugin is the interface of the volume types. A kubelet finds the plugin of a volume
+// of a pod by its specification, and the plugin of a volume on disk by the name of the kind
+// directory it is under: (ROOT_DIR)/(POD_ID)/volumes/(PLUGIN_NAME)/(VOLUME_NAME).
+type VolumePlugin interface {
+	// Init initializes the plugin with the host of its volumes. It is called once, before
+	// any volume is built or cleaned by the plugin.
+	Init(host Host)
+
+	// Name returns the name of the plugin, which must be unique among the plugins of a host.
+	Name() string
+
+	// CanSupport tests whether the plugin supports the volume. Exactly one plugin of a host
+	// may support any volume.
+	CanSupport(spec *api.Volume) bool
+
+	// NewBuilder returns a Builder of the volume of the pod.
+	NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error)
+
+	// NewCleaner returns a Cleaner of the volume with the given name of the pod with the given ID.
+	NewCleaner(volName, podID string) (Cleaner, error)
+}
+
+// Host is the interface the plugins use to access the kubelet their volumes are set up by.
+type Host interface {
+	// GetRootDir returns the directory the volumes of the pods are set up under.
+	GetRootDir() string
+
+	// SecretGetter fetches the secrets of the secret volumes.
+	SecretGetter
+}
+
+// PluginMgr tracks the plugins of a host. The zero value supports no volume.
+type PluginMgr struct {
+	mutex   sync.Mutex
+	plugins map[string]VolumePlugin
+}
+
+// InitPlugins initializes the plugins with the host and registers them. The plugins must have
+// distinct names, which must not be registered yet.
+func (pm *PluginMgr) InitPlugins(plugins []VolumePlugin, host Host) error {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	if pm.plugins == nil {
+		pm.plugins = map[string]VolumePlugin{}
+	}
+	for _, plugin := range plugins {
+		name := plugin.Name()
+		if len(name) == 0 {
+			return fmt.Errorf("volume plugin %#v has no name", plugin)
+		}
+		if _, found := pm.plugins[name]; found {
+			return fmt.Errorf("volume plugin %q was registered more than once", name)
+		}
+		plugin.Init(host)
+		pm.plugins[name] = plugin
+		glog.V(1).Infof("Loaded volume plugin %q", name)
+	}
+	return nil
+}
+
+// FindPluginBySpec returns the plugin which supports the volume, ErrUnsupportedVolumeType if
+// none does, or an error if several do.
+func (pm *PluginMgr) FindPluginBySpec(spec *api.Volume) (VolumePlugin, error) {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	var match VolumePlugin
+	for _, plugin := range pm.plugins {
+		if !plugin.CanSupport(spec) {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("volume %q is supported by the plugins %q and %q", spec.Name, match.Name(), plugin.Name())
+		}
+		match = plugin
+	}
+	if match == nil {
+		return nil, ErrUnsupportedVolumeType
+	}
+	return match, nil
+}
+
+// FindPluginByName returns the plugin with the given name, or ErrUnsupportedVolumeType.
+func (pm *PluginMgr) FindPluginByName(name string) (VolumePlugin, error) {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	plugin, found := pm.plugins[name]
+	if !found {
+		return nil, ErrUnsupportedVolumeType
+	}
+	return plugin, nil
+}
+
+// NewBuilder returns a Builder of the volume of the pod, from the plugin which supports it.
+// A volume without source has no Builder.
+func (pm *PluginMgr) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	// TODO(jonesdl) We will want to throw an error here when we no longer
+	// support the default behavior.
+	if spec.Source == nil {
+		return nil, nil
+	}
+	plugin, err := pm.FindPluginBySpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.NewBuilder(spec, pod)
+}
+
+// NewCleaner returns a Cleaner of the volume of the pod, from the plugin with the given name.
+func (pm *PluginMgr) NewCleaner(pluginName, volName, podID string) (Cleaner, error) {
+	plugin, err := pm.FindPluginByName(pluginName)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.NewCleaner(volName, podID)
+}
+
+// GetCurrentVolumes examines directory structure to determine volumes that are
+// presently active and mounted. Returns a map of Cleaner types.
+func (pm *PluginMgr) GetCurrentVolumes(rootDirectory string) map[string]Cleaner {
+	currentVolumes := make(map[string]Cleaner)
+	podIDDirs, err := ioutil.ReadDir(rootDirectory)
+	if err != nil {
+		glog.Errorf("Could not read directory %s: %v", rootDirectory, err)
+	}
+	// Volume information is extracted from the directory structure:
+	// (ROOT_DIR)/(POD_ID)/volumes/(PLUGIN_NAME)/(VOLUME_NAME)
+	for _, podIDDir := range podIDDirs {
+		if !podIDDir.IsDir() {
+			continue
+		}
+		podID := podIDDir.Name()
+		podIDPath := path.Join(rootDirectory, podID, "volumes")
+		if _, err := os.Stat(podIDPath); os.IsNotExist(err) {
+			continue
+		}
+		pluginDirs, err := ioutil.ReadDir(podIDPath)
+		if err != nil {
+			glog.Errorf("Could not read directory %s: %v", podIDPath, err)
+		}
+		for _, pluginDir := range pluginDirs {
+			pluginName := pluginDir.Name()
+			pluginPath := path.Join(podIDPath, pluginName)
+			volumeNameDirs, err := ioutil.ReadDir(pluginPath)
+			if err != nil {
+				glog.Errorf("Could not read directory %s: %v", pluginPath, err)
+			}
+			for _, volumeNameDir := range volumeNameDirs {
+				volumeName := volumeNameDir.Name()
+				identifier := path.Join(podID, volumeName)
+				// TODO(thockin) This should instead return a reference to an extant volume object
+				cleaner, err := pm.NewCleaner(pluginName, volumeName, podID)
+				if err != nil {
+					glog.Errorf("Could not create volume cleaner for %s: %v", volumeNameDir.Name(), err)
+					continue
+				}
+				currentVolumes[identifier] = cleaner
+			}
+		}
+	}
+	return currentVolumes
+}
+
+// ProbeVolumePlugins returns the volume plugins built into the kubelet.
+func ProbeVolumePlugins() []VolumePlugin {
+	return []VolumePlugin{
+		&hostDirPlugin{},
+		&emptyDirPlugin{},
+		&gcePersistentDiskPlugin{util: &GCEDiskUtil{}, mounter: &DiskMounter{}},
+		&gitRepoPlugin{keyring: credentialprovider.NewDockerKeyring(), exec: exec.New()},
+		&downwardAPIPlugin{},
+		&secretPlugin{mounter: &DiskMounter{}},
+		&nfsPlugin{mounter: &nfsMounter{exec: exec.New()}},
+		&localQuotaPlugin{
+			dataDir: "/data",
+			quota:   &xfsQuota{mountPoint: "/data", exec: exec.New()},
+			mounter: &DiskMounter{},
+		},
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+type fakeHost struct {
+	rootDir string
+	secrets SecretGetter
+}
+
+func (f *fakeHost) GetRootDir() string {
+	return f.rootDir
+}
+
+func (f *fakeHost) GetSecret(namespace, name string) (*api.Secret, error) {
+	if f.secrets == nil {
+		return nil, fmt.Errorf("no secret %s/%s", namespace, name)
+	}
+	return f.secrets.GetSecret(namespace, name)
+}
+
+// newTestPluginMgr returns a PluginMgr of the built-in plugins, which mount nothing.
+func newTestPluginMgr(t *testing.T, host Host) *PluginMgr {
+	plugins := &PluginMgr{}
+	err := plugins.InitPlugins([]VolumePlugin{
+		&hostDirPlugin{},
+		&emptyDirPlugin{},
+		&gcePersistentDiskPlugin{util: &MockDiskUtil{}, mounter: &MockMounter{}},
+		&gitRepoPlugin{},
+		&downwardAPIPlugin{},
+		&secretPlugin{mounter: &MockMounter{}},
+		&nfsPlugin{mounter: &MockMounter{}},
+		&localQuotaPlugin{dataDir: "/data", quota: &fakeQuota{}, mounter: &MockMounter{}},
+	}, host)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return plugins
+}
+
+// fakePlugin supports the volumes with the given name.
+type fakePlugin struct {
+	name       string
+	volumeName string
+	host       Host
+}
+
+func (p *fakePlugin) Init(host Host) {
+	p.host = host
+}
+
+func (p *fakePlugin) Name() string {
+	return p.name
+}
+
+func (p *fakePlugin) CanSupport(spec *api.Volume) bool {
+	return spec.Name == p.volumeName
+}
+
+func (p *fakePlugin) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	return &EmptyDir{spec.Name, pod.Name, p.host.GetRootDir()}, nil
+}
+
+func (p *fakePlugin) NewCleaner(volName, podID string) (Cleaner, error) {
+	return &EmptyDir{volName, podID, p.host.GetRootDir()}, nil
+}
+
+func TestInitPlugins(t *testing.T) {
+	host := &fakeHost{rootDir: "/root"}
+	plugins := &PluginMgr{}
+	site := &fakePlugin{name: "site", volumeName: "vol"}
+	if err := plugins.InitPlugins([]VolumePlugin{site}, host); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if site.host != host {
+		t.Errorf("Expected the plugin to be initialized with the host")
+	}
+	if err := plugins.InitPlugins([]VolumePlugin{&fakePlugin{name: "site"}}, host); err == nil {
+		t.Errorf("Expected an error registering a plugin twice")
+	}
+	if err := plugins.InitPlugins([]VolumePlugin{&fakePlugin{}}, host); err == nil {
+		t.Errorf("Expected an error registering a plugin without name")
+	}
+}
+
+func TestFindPlugin(t *testing.T) {
+	plugins := &PluginMgr{}
+	err := plugins.InitPlugins([]VolumePlugin{
+		&fakePlugin{name: "a", volumeName: "vol-a"},
+		&fakePlugin{name: "b", volumeName: "vol-b"},
+		&fakePlugin{name: "c", volumeName: "vol-b"},
+	}, &fakeHost{rootDir: "/root"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	plugin, err := plugins.FindPluginBySpec(&api.Volume{Name: "vol-a"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if plugin.Name() != "a" {
+		t.Errorf("Expected plugin a, got %s", plugin.Name())
+	}
+	if _, err := plugins.FindPluginBySpec(&api.Volume{Name: "vol-b"}); err == nil {
+		t.Errorf("Expected an error for a volume supported by two plugins")
+	}
+	if _, err := plugins.FindPluginBySpec(&api.Volume{Name: "vol-c"}); err != ErrUnsupportedVolumeType {
+		t.Errorf("Expected ErrUnsupportedVolumeType, got %v", err)
+	}
+
+	plugin, err = plugins.FindPluginByName("c")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if plugin.Name() != "c" {
+		t.Errorf("Expected plugin c, got %s", plugin.Name())
+	}
+	if _, err := plugins.FindPluginByName("d"); err != ErrUnsupportedVolumeType {
+		t.Errorf("Expected ErrUnsupportedVolumeType, got %v", err)
+	}
+}
+
+func TestSitePluginBuilder(t *testing.T) {
+	plugins := newTestPluginMgr(t, &fakeHost{rootDir: "/root"})
+	if err := plugins.InitPlugins([]VolumePlugin{&fakePlugin{name: "site", volumeName: "scratch"}}, &fakeHost{rootDir: "/root"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	builder, err := plugins.NewBuilder(&api.Volume{Name: "scratch", Source: &api.VolumeSource{}}, &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "foo"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e, a := "/root/foo/volumes/empty/scratch", builder.GetPath(); e != a {
+		t.Errorf("Expected path %s, got %s", e, a)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/golang/glog"
+)
+
+// SecretGetter fetches the secrets mounted by the secret volumes.
+type SecretGetter interface {
+	GetSecret(namespace, name string) (*api.Secret, error)
+}
+
+// Secret volumes hold the data of a secret of the namespace of the pod, one file per key.
+// The files are written to a tmpfs, so that the secret never reaches the disk of the host.
+type Secret struct {
+	Name       string
+	PodID      string
+	RootDir    string
+	SecretName string
+	Namespace  string
+	getter     SecretGetter
+	mounter    mounter
+}
+
+// secretPlugin builds and cleans the Secret volumes, with the secrets of its host.
+type secretPlugin struct {
+	host    Host
+	mounter mounter
+}
+
+func (p *secretPlugin) Init(host Host) {
+	p.host = host
+}
+
+func (p *secretPlugin) Name() string {
+	return "secret"
+}
+
+func (p *secretPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.Source != nil && spec.Source.Secret != nil
+}
+
+func (p *secretPlugin) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	return &Secret{
+		Name:       spec.Name,
+		PodID:      pod.Name,
+		RootDir:    p.host.GetRootDir(),
+		SecretName: spec.Source.Secret.SecretName,
+		Namespace:  pod.Namespace,
+		getter:     p.host,
+		mounter:    p.mounter,
+	}, nil
+}
+
+func (p *secretPlugin) NewCleaner(volName, podID string) (Cleaner, error) {
+	return &Secret{
+		Name:    volName,
+		PodID:   podID,
+		RootDir: p.host.GetRootDir(),
+		mounter: p.mounter,
+	}, nil
+}
+
+// SetUp mounts a tmpfs only accessible by its owner and writes the data of the secret to it.
+// A volume whose tmpfs is mounted is left as is, one whose directory lost its tmpfs, like
+// after a reboot of the host, is mounted and written again.
+func (s *Secret) SetUp() error {
+	volumePath := s.GetPath()
+	mounts, err := s.mounter.List()
+	if err != nil {
+		return err
+	}
+	if findMountPoint(mounts, volumePath) != nil {
+		return nil
+	}
+	if s.getter == nil {
+		return fmt.Errorf("no source of secrets to set up the secret %q", s.SecretName)
+	}
+	secret, err := s.getter.GetSecret(s.Namespace, s.SecretName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(volumePath, 0700); err != nil {
+		return err
+	}
+	if err := s.mounter.Mount("tmpfs", volumePath, "tmpfs", 0, "mode=0700"); err != nil {
+		os.Remove(volumePath)
+		return err
+	}
+	for key, value := range secret.Data {
+		if err := ioutil.WriteFile(path.Join(volumePath, key), value, 0400); err != nil {
+			if err := s.TearDown(); err != nil {
+				glog.Errorf("Failed to tear down the secret volume %s: %v", volumePath, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Secret) GetPath() string {
+	return path.Join(s.RootDir, s.PodID, "volumes", "secret", s.Name)
+}
+
+// TearDown unmounts the tmpfs, which discards the secret, and removes its mount point.
+// A volume whose tmpfs is not mounted anymore only has its directory removed.
+func (s *Secret) TearDown() error {
+	volumePath := s.GetPath()
+	mounts, err := s.mounter.List()
+	if err != nil {
+		return err
+	}
+	if findMountPoint(mounts, volumePath) != nil {
+		if err := s.mounter.Unmount(volumePath, 0); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(volumePath)
+}
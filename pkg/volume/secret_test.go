@@ -0,0 +1,210 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+type fakeSecretGetter struct {
+	secrets map[string]*api.Secret
+	gets    []string
+}
+
+func (f *fakeSecretGetter) GetSecret(namespace, name string) (*api.Secret, error) {
+	key := namespace + "/" + name
+	f.gets = append(f.gets, key)
+	secret, ok := f.secrets[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s not found", key)
+	}
+	return secret, nil
+}
+
+type recordingMounter struct {
+	fakeMountTable
+	mounts   []string
+	unmounts []string
+}
+
+func (m *recordingMounter) Mount(source string, target string, fstype string, flags uintptr, data string) error {
+	m.mounts = append(m.mounts, fmt.Sprintf("%s %s %s %s", source, target, fstype, data))
+	return m.fakeMountTable.Mount(source, target, fstype, flags, data)
+}
+
+func (m *recordingMounter) Unmount(target string, flags int) error {
+	m.unmounts = append(m.unmounts, target)
+	return m.fakeMountTable.Unmount(target, flags)
+}
+
+func TestSecretVolume(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "Secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	getter := &fakeSecretGetter{
+		secrets: map[string]*api.Secret{
+			"ns/creds": {Data: map[string][]byte{"username": []byte("admin"), "password": []byte("hunter2")}},
+		},
+	}
+	pod := &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "ns"}}
+	vol := &api.Volume{
+		Name:   "creds",
+		Source: &api.VolumeSource{Secret: &api.SecretSource{SecretName: "creds"}},
+	}
+	builder, err := newTestPluginMgr(t, &fakeHost{rootDir: tempDir, secrets: getter}).NewBuilder(vol, pod)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	secret, ok := builder.(*Secret)
+	if !ok {
+		t.Fatalf("Expected a Secret, got %#v", builder)
+	}
+	mounter := &recordingMounter{}
+	secret.mounter = mounter
+
+	volumePath := secret.GetPath()
+	if expected := path.Join(tempDir, "foo", "volumes", "secret", "creds"); volumePath != expected {
+		t.Errorf("Expected path %s, got %s", expected, volumePath)
+	}
+	if err := secret.SetUp(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e, a := []string{"tmpfs " + volumePath + " tmpfs mode=0700"}, mounter.mounts; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected mounts %v, got %v", e, a)
+	}
+	info, err := os.Stat(volumePath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("Expected mode 0700, got %v", info.Mode())
+	}
+	for key, expected := range map[string]string{"username": "admin", "password": "hunter2"} {
+		file := path.Join(volumePath, key)
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		if string(data) != expected {
+			t.Errorf("Expected %q in %s, got %q", expected, key, data)
+		}
+		if info, err := os.Stat(file); err != nil || info.Mode().Perm() != 0400 {
+			t.Errorf("Expected mode 0400 for %s, got %v (%v)", key, info.Mode(), err)
+		}
+	}
+
+	// A volume which is already set up is neither fetched nor mounted again.
+	if err := secret.SetUp(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(getter.gets) != 1 || len(mounter.mounts) != 1 {
+		t.Errorf("Unexpected gets %v and mounts %v", getter.gets, mounter.mounts)
+	}
+
+	if err := secret.TearDown(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e, a := []string{volumePath}, mounter.unmounts; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected unmounts %v, got %v", e, a)
+	}
+	if _, err := os.Stat(volumePath); !os.IsNotExist(err) {
+		t.Errorf("Expected the volume to be removed, got %v", err)
+	}
+}
+
+func TestSecretVolumeMissingSecret(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "Secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	mounter := &recordingMounter{}
+	secret := &Secret{
+		Name:       "creds",
+		PodID:      "foo",
+		RootDir:    tempDir,
+		SecretName: "missing",
+		Namespace:  "ns",
+		getter:     &fakeSecretGetter{},
+		mounter:    mounter,
+	}
+	if err := secret.SetUp(); err == nil {
+		t.Errorf("Expected an error for a missing secret")
+	}
+	if len(mounter.mounts) != 0 {
+		t.Errorf("Unexpected mounts: %v", mounter.mounts)
+	}
+	if _, err := os.Stat(secret.GetPath()); !os.IsNotExist(err) {
+		t.Errorf("Expected no volume directory, got %v", err)
+	}
+}
+
+func TestSecretVolumeNotMounted(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "Secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	mounter := &recordingMounter{}
+	secret := &Secret{
+		Name:       "creds",
+		PodID:      "foo",
+		RootDir:    tempDir,
+		SecretName: "creds",
+		Namespace:  "ns",
+		getter: &fakeSecretGetter{
+			secrets: map[string]*api.Secret{"ns/creds": {Data: map[string][]byte{"token": []byte("abc")}}},
+		},
+		mounter: mounter,
+	}
+	// The directory of the volume outlived its tmpfs, like after a reboot of the host.
+	volumePath := secret.GetPath()
+	if err := os.MkdirAll(volumePath, 0700); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := secret.SetUp(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e, a := []string{"tmpfs " + volumePath + " tmpfs mode=0700"}, mounter.mounts; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected mounts %v, got %v", e, a)
+	}
+	if data, err := ioutil.ReadFile(path.Join(volumePath, "token")); err != nil || string(data) != "abc" {
+		t.Errorf("Expected the secret to be written again, got %q (%v)", data, err)
+	}
+
+	// A volume whose tmpfs is gone is torn down without unmounting it.
+	mounter.fakeMountTable.Unmount(volumePath, 0)
+	if err := secret.TearDown(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mounter.unmounts) != 0 {
+		t.Errorf("Unexpected unmounts: %v", mounter.unmounts)
+	}
+	if _, err := os.Stat(volumePath); !os.IsNotExist(err) {
+		t.Errorf("Expected the volume to be removed, got %v", err)
+	}
+}
@@ -25,8 +25,6 @@ import (
 	"strconv"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
-	"github.com/golang/glog"
 )
 
 var ErrUnsupportedVolumeType = errors.New("unsupported volume type")
@@ -66,6 +64,16 @@ type mounter interface {
 	// RefCount returns the device path for the source disk of a volume, and
 	// the number of references to that target disk.
 	RefCount(vol Interface) (string, int, error)
+	// List returns the mount points of the host.
+	List() ([]MountPoint, error)
+}
+
+// MountPoint is a mount point of the host, as listed in /proc/mounts.
+type MountPoint struct {
+	Device string
+	Path   string
+	Type   string
+	Opts   []string
 }
 
 // HostDir volumes represent a bare host directory mount.
@@ -84,80 +92,29 @@ func (hostVol *HostDir) GetPath() string {
 	return hostVol.Path
 }
 
-type execInterface interface {
-	ExecCommand(cmd []string, dir string) ([]byte, error)
+// hostDirPlugin builds the HostDir volumes. They are never torn down.
+type hostDirPlugin struct {
+	host Host
 }
 
-type GitDir struct {
-	Source   string
-	Revision string
-	PodID    string
-	RootDir  string
-	Name     string
-	exec     exec.Interface
+func (p *hostDirPlugin) Init(host Host) {
+	p.host = host
 }
 
-func newGitRepo(volume *api.Volume, podID, rootDir string) *GitDir {
-	return &GitDir{
-		Source:   volume.Source.GitRepo.Repository,
-		Revision: volume.Source.GitRepo.Revision,
-		PodID:    podID,
-		RootDir:  rootDir,
-		Name:     volume.Name,
-		exec:     exec.New(),
-	}
+func (p *hostDirPlugin) Name() string {
+	return "host-dir"
 }
 
-func (g *GitDir) ExecCommand(command string, args []string, dir string) ([]byte, error) {
-	cmd := g.exec.Command(command, args...)
-	cmd.SetDir(dir)
-	return cmd.CombinedOutput()
+func (p *hostDirPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.Source != nil && spec.Source.HostDir != nil
 }
 
-func (g *GitDir) SetUp() error {
-	volumePath := g.GetPath()
-	if err := os.MkdirAll(volumePath, 0750); err != nil {
-		return err
-	}
-	if _, err := g.ExecCommand("git", []string{"clone", g.Source}, g.GetPath()); err != nil {
-		return err
-	}
-	files, err := ioutil.ReadDir(g.GetPath())
-	if err != nil {
-		return err
-	}
-	if len(g.Revision) == 0 {
-		return nil
-	}
-
-	if len(files) != 1 {
-		return fmt.Errorf("unexpected directory contents: %v", files)
-	}
-	dir := path.Join(g.GetPath(), files[0].Name())
-	if _, err := g.ExecCommand("git", []string{"checkout", g.Revision}, dir); err != nil {
-		return err
-	}
-	if _, err := g.ExecCommand("git", []string{"reset", "--hard"}, dir); err != nil {
-		return err
-	}
-	return nil
-}
-
-func (g *GitDir) GetPath() string {
-	return path.Join(g.RootDir, g.PodID, "volumes", "git", g.Name)
+func (p *hostDirPlugin) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	return &HostDir{spec.Source.HostDir.Path}, nil
 }
 
-// TearDown simply deletes everything in the directory.
-func (g *GitDir) TearDown() error {
-	tmpDir, err := renameDirectory(g.GetPath(), g.Name+"~deleting")
-	if err != nil {
-		return err
-	}
-	err = os.RemoveAll(tmpDir)
-	if err != nil {
-		return err
-	}
-	return nil
+func (p *hostDirPlugin) NewCleaner(volName, podID string) (Cleaner, error) {
+	return nil, fmt.Errorf("host directory %s of pod %s has nothing to tear down", volName, podID)
 }
 
 // EmptyDir volumes are temporary directories exposed to the pod.
@@ -178,6 +135,31 @@ func (emptyDir *EmptyDir) GetPath() string {
 	return path.Join(emptyDir.RootDir, emptyDir.PodID, "volumes", "empty", emptyDir.Name)
 }
 
+// emptyDirPlugin builds and cleans the EmptyDir volumes.
+type emptyDirPlugin struct {
+	host Host
+}
+
+func (p *emptyDirPlugin) Init(host Host) {
+	p.host = host
+}
+
+func (p *emptyDirPlugin) Name() string {
+	return "empty"
+}
+
+func (p *emptyDirPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.Source != nil && spec.Source.EmptyDir != nil
+}
+
+func (p *emptyDirPlugin) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	return &EmptyDir{spec.Name, pod.Name, p.host.GetRootDir()}, nil
+}
+
+func (p *emptyDirPlugin) NewCleaner(volName, podID string) (Cleaner, error) {
+	return &EmptyDir{volName, podID, p.host.GetRootDir()}, nil
+}
+
 func renameDirectory(oldPath, newName string) (string, error) {
 	newPath, err := ioutil.TempDir(path.Dir(oldPath), newName)
 	if err != nil {
@@ -203,11 +185,6 @@ func (emptyDir *EmptyDir) TearDown() error {
 	return nil
 }
 
-// createHostDir interprets API volume as a HostDir.
-func createHostDir(volume *api.Volume) *HostDir {
-	return &HostDir{volume.Source.HostDir.Path}
-}
-
 // GCEPersistentDisk volumes are disk resources provided by Google Compute Engine
 // that are attached to the kubelet's host machine and exposed to the pod.
 type GCEPersistentDisk struct {
@@ -228,6 +205,54 @@ type GCEPersistentDisk struct {
 	mounter mounter
 }
 
+// gcePersistentDiskPlugin builds and cleans the GCEPersistentDisk volumes.
+type gcePersistentDiskPlugin struct {
+	host Host
+	// Utility interface that provides API calls to the provider to attach/detach disks.
+	util gcePersistentDiskUtil
+	// Mounter interface that provides system calls to mount the disks.
+	mounter mounter
+}
+
+func (p *gcePersistentDiskPlugin) Init(host Host) {
+	p.host = host
+}
+
+func (p *gcePersistentDiskPlugin) Name() string {
+	return "gce-pd"
+}
+
+func (p *gcePersistentDiskPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.Source != nil && spec.Source.GCEPersistentDisk != nil
+}
+
+// NewBuilder interprets API volume as a PersistentDisk
+func (p *gcePersistentDiskPlugin) NewBuilder(spec *api.Volume, pod *api.BoundPod) (Builder, error) {
+	partition := strconv.Itoa(spec.Source.GCEPersistentDisk.Partition)
+	if partition == "0" {
+		partition = ""
+	}
+	return &GCEPersistentDisk{
+		Name:      spec.Name,
+		PodID:     pod.Name,
+		RootDir:   p.host.GetRootDir(),
+		PDName:    spec.Source.GCEPersistentDisk.PDName,
+		FSType:    spec.Source.GCEPersistentDisk.FSType,
+		Partition: partition,
+		ReadOnly:  spec.Source.GCEPersistentDisk.ReadOnly,
+		util:      p.util,
+		mounter:   p.mounter}, nil
+}
+
+func (p *gcePersistentDiskPlugin) NewCleaner(volName, podID string) (Cleaner, error) {
+	return &GCEPersistentDisk{
+		Name:    volName,
+		PodID:   podID,
+		RootDir: p.host.GetRootDir(),
+		util:    p.util,
+		mounter: p.mounter}, nil
+}
+
 func (PD *GCEPersistentDisk) GetPath() string {
 	return path.Join(PD.RootDir, PD.PodID, "volumes", "gce-pd", PD.Name)
 }
@@ -300,131 +325,3 @@ func makeGlobalPDName(rootDir, devName string, readOnly bool) string {
 	}
 	return path.Join(rootDir, "global", "pd", mode, devName)
 }
-
-// createEmptyDir interprets API volume as an EmptyDir.
-func createEmptyDir(volume *api.Volume, podID string, rootDir string) *EmptyDir {
-	return &EmptyDir{volume.Name, podID, rootDir}
-}
-
-// Interprets API volume as a PersistentDisk
-func createGCEPersistentDisk(volume *api.Volume, podID string, rootDir string) (*GCEPersistentDisk, error) {
-	PDName := volume.Source.GCEPersistentDisk.PDName
-	FSType := volume.Source.GCEPersistentDisk.FSType
-	partition := strconv.Itoa(volume.Source.GCEPersistentDisk.Partition)
-	if partition == "0" {
-		partition = ""
-	}
-	readOnly := volume.Source.GCEPersistentDisk.ReadOnly
-	// TODO: move these up into the Kubelet.
-	util := &GCEDiskUtil{}
-	mounter := &DiskMounter{}
-	return &GCEPersistentDisk{
-		Name:      volume.Name,
-		PodID:     podID,
-		RootDir:   rootDir,
-		PDName:    PDName,
-		FSType:    FSType,
-		Partition: partition,
-		ReadOnly:  readOnly,
-		util:      util,
-		mounter:   mounter}, nil
-}
-
-// CreateVolumeBuilder returns a Builder capable of mounting a volume described by an
-// *api.Volume, or an error.
-func CreateVolumeBuilder(volume *api.Volume, podID string, rootDir string) (Builder, error) {
-	source := volume.Source
-	// TODO(jonesdl) We will want to throw an error here when we no longer
-	// support the default behavior.
-	if source == nil {
-		return nil, nil
-	}
-	var vol Builder
-	var err error
-	// TODO(jonesdl) We should probably not check every pointer and directly
-	// resolve these types instead.
-	if source.HostDir != nil {
-		vol = createHostDir(volume)
-	} else if source.EmptyDir != nil {
-		vol = createEmptyDir(volume, podID, rootDir)
-	} else if source.GCEPersistentDisk != nil {
-		vol, err = createGCEPersistentDisk(volume, podID, rootDir)
-		if err != nil {
-			return nil, err
-		}
-	} else if source.GitRepo != nil {
-		vol = newGitRepo(volume, podID, rootDir)
-	} else {
-		return nil, ErrUnsupportedVolumeType
-	}
-	return vol, nil
-}
-
-// CreateVolumeCleaner returns a Cleaner capable of tearing down a volume.
-func CreateVolumeCleaner(kind string, name string, podID string, rootDir string) (Cleaner, error) {
-	switch kind {
-	case "empty":
-		return &EmptyDir{name, podID, rootDir}, nil
-	case "gce-pd":
-		return &GCEPersistentDisk{
-			Name:    name,
-			PodID:   podID,
-			RootDir: rootDir,
-			util:    &GCEDiskUtil{},
-			mounter: &DiskMounter{}}, nil
-	case "git":
-		return &GitDir{
-			Name:    name,
-			PodID:   podID,
-			RootDir: rootDir,
-		}, nil
-	default:
-		return nil, ErrUnsupportedVolumeType
-	}
-}
-
-// GetCurrentVolumes examines directory structure to determine volumes that are
-// presently active and mounted. Returns a map of Cleaner types.
-func GetCurrentVolumes(rootDirectory string) map[string]Cleaner {
-	currentVolumes := make(map[string]Cleaner)
-	podIDDirs, err := ioutil.ReadDir(rootDirectory)
-	if err != nil {
-		glog.Errorf("Could not read directory %s: %v", rootDirectory, err)
-	}
-	// Volume information is extracted from the directory structure:
-	// (ROOT_DIR)/(POD_ID)/volumes/(VOLUME_KIND)/(VOLUME_NAME)
-	for _, podIDDir := range podIDDirs {
-		if !podIDDir.IsDir() {
-			continue
-		}
-		podID := podIDDir.Name()
-		podIDPath := path.Join(rootDirectory, podID, "volumes")
-		if _, err := os.Stat(podIDPath); os.IsNotExist(err) {
-			continue
-		}
-		volumeKindDirs, err := ioutil.ReadDir(podIDPath)
-		if err != nil {
-			glog.Errorf("Could not read directory %s: %v", podIDPath, err)
-		}
-		for _, volumeKindDir := range volumeKindDirs {
-			volumeKind := volumeKindDir.Name()
-			volumeKindPath := path.Join(podIDPath, volumeKind)
-			volumeNameDirs, err := ioutil.ReadDir(volumeKindPath)
-			if err != nil {
-				glog.Errorf("Could not read directory %s: %v", volumeKindPath, err)
-			}
-			for _, volumeNameDir := range volumeNameDirs {
-				volumeName := volumeNameDir.Name()
-				identifier := path.Join(podID, volumeName)
-				// TODO(thockin) This should instead return a reference to an extant volume object
-				cleaner, err := CreateVolumeCleaner(volumeKind, volumeName, podID, rootDirectory)
-				if err != nil {
-					glog.Errorf("Could not create volume cleaner for %s: %v", volumeNameDir.Name(), err)
-					continue
-				}
-				currentVolumes[identifier] = cleaner
-			}
-		}
-	}
-	return currentVolumes
-}
@@ -24,7 +24,6 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
 )
 
 type MockDiskUtil struct{}
@@ -61,8 +60,13 @@ func (mounter *MockMounter) RefCount(vol Interface) (string, int, error) {
 	return "", 0, nil
 }
 
-func TestCreateVolumeBuilders(t *testing.T) {
+func (mounter *MockMounter) List() ([]MountPoint, error) {
+	return []MountPoint{}, nil
+}
+
+func TestNewBuilders(t *testing.T) {
 	tempDir := "CreateVolumes"
+	plugins := newTestPluginMgr(t, &fakeHost{rootDir: tempDir})
 	createVolumesTests := []struct {
 		volume api.Volume
 		path   string
@@ -98,6 +102,16 @@ func TestCreateVolumeBuilders(t *testing.T) {
 			path.Join(tempDir, "/my-id/volumes/gce-pd/gce-pd"),
 			"my-id",
 		},
+		{
+			api.Volume{
+				Name: "git-repo",
+				Source: &api.VolumeSource{
+					GitRepo: &api.GitRepo{Repository: "https://example.com/repo.git"},
+				},
+			},
+			path.Join(tempDir, "/my-id/volumes/git/git-repo"),
+			"my-id",
+		},
 		{api.Volume{}, "", ""},
 		{
 			api.Volume{
@@ -110,14 +124,15 @@ func TestCreateVolumeBuilders(t *testing.T) {
 	}
 	for _, createVolumesTest := range createVolumesTests {
 		tt := createVolumesTest
-		vb, err := CreateVolumeBuilder(&tt.volume, tt.podID, tempDir)
+		pod := &api.BoundPod{ObjectMeta: api.ObjectMeta{Name: tt.podID}}
+		vb, err := plugins.NewBuilder(&tt.volume, pod)
 		if tt.volume.Source == nil {
 			if vb != nil {
 				t.Errorf("Expected volume to be nil")
 			}
 			continue
 		}
-		if tt.volume.Source.HostDir == nil && tt.volume.Source.EmptyDir == nil && tt.volume.Source.GCEPersistentDisk == nil {
+		if tt.volume.Source.HostDir == nil && tt.volume.Source.EmptyDir == nil && tt.volume.Source.GCEPersistentDisk == nil && tt.volume.Source.GitRepo == nil {
 			if err != ErrUnsupportedVolumeType {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -133,8 +148,9 @@ func TestCreateVolumeBuilders(t *testing.T) {
 	}
 }
 
-func TestCreateVolumeCleaners(t *testing.T) {
+func TestNewCleaners(t *testing.T) {
 	tempDir := "CreateVolumeCleaners"
+	plugins := newTestPluginMgr(t, &fakeHost{rootDir: tempDir})
 	createVolumeCleanerTests := []struct {
 		kind  string
 		name  string
@@ -143,21 +159,36 @@ func TestCreateVolumeCleaners(t *testing.T) {
 		{"empty", "empty-vol", "my-id"},
 		{"", "", ""},
 		{"gce-pd", "gce-pd-vol", "my-id"},
+		{"git", "git-vol", "my-id"},
+		{"downward-api", "downward-vol", "my-id"},
+		{"secret", "secret-vol", "my-id"},
+		{"nfs", "nfs-vol", "my-id"},
+		{"local-quota", "quota-vol", "my-id"},
+	}
+	expectedKinds := map[string]string{
+		"empty":        "EmptyDir",
+		"gce-pd":       "GCEPersistentDisk",
+		"git":          "GitDir",
+		"downward-api": "DownwardAPI",
+		"secret":       "Secret",
+		"nfs":          "NFS",
+		"local-quota":  "LocalQuota",
 	}
 	for _, tt := range createVolumeCleanerTests {
-		vol, err := CreateVolumeCleaner(tt.kind, tt.name, tt.podID, tempDir)
+		vol, err := plugins.NewCleaner(tt.kind, tt.name, tt.podID)
 		if tt.kind == "" && err != nil && vol == nil {
 			continue
 		}
 		if err != nil {
 			t.Errorf("Unexpected error occured: %v", err)
+			continue
 		}
 		actualKind := reflect.TypeOf(vol).Elem().Name()
-		if tt.kind == "empty" && actualKind != "EmptyDir" {
-			t.Errorf("CreateVolumeCleaner returned invalid type. Expected EmptyDirectory, got %v, %v", tt.kind, actualKind)
+		if expected := expectedKinds[tt.kind]; actualKind != expected {
+			t.Errorf("NewCleaner returned invalid type for %v. Expected %v, got %v", tt.kind, expected, actualKind)
 		}
-		if tt.kind == "gce-pd" && actualKind != "GCEPersistentDisk" {
-			t.Errorf("CreateVolumeCleaner returned invalid type. Expected PersistentDisk, got %v, %v", tt.kind, actualKind)
+		if expected := path.Join(tempDir, tt.podID, "volumes", tt.kind, tt.name); vol.(Interface).GetPath() != expected {
+			t.Errorf("Unexpected path for %v. Expected %v, got %v", tt.kind, expected, vol.(Interface).GetPath())
 		}
 	}
 }
@@ -218,78 +249,11 @@ func TestGetActiveVolumes(t *testing.T) {
 		os.MkdirAll(volumeDir, 0750)
 		expectedIdentifiers = append(expectedIdentifiers, test.identifier)
 	}
-	volumeMap := GetCurrentVolumes(tempDir)
+	plugins := newTestPluginMgr(t, &fakeHost{rootDir: tempDir})
+	volumeMap := plugins.GetCurrentVolumes(tempDir)
 	for _, name := range expectedIdentifiers {
 		if _, ok := volumeMap[name]; !ok {
 			t.Errorf("Expected volume map entry not found: %v", name)
 		}
 	}
 }
-
-type fakeExec struct {
-	cmds   [][]string
-	dirs   []string
-	data   []byte
-	err    error
-	action func([]string, string)
-}
-
-func (f *fakeExec) ExecCommand(cmd []string, dir string) ([]byte, error) {
-	f.cmds = append(f.cmds, cmd)
-	f.dirs = append(f.dirs, dir)
-	f.action(cmd, dir)
-	return f.data, f.err
-}
-
-func TestGitVolume(t *testing.T) {
-	var fcmd exec.FakeCmd
-	fcmd = exec.FakeCmd{
-		CombinedOutputScript: []exec.FakeCombinedOutputAction{
-			func() ([]byte, error) {
-				os.MkdirAll(path.Join(fcmd.Dirs[0], "kubernetes"), 0750)
-				return []byte{}, nil
-			},
-			func() ([]byte, error) { return []byte{}, nil },
-			func() ([]byte, error) { return []byte{}, nil },
-		},
-	}
-	fake := exec.FakeExec{
-		CommandScript: []exec.FakeCommandAction{
-			func(cmd string, args ...string) exec.Cmd { return exec.InitFakeCmd(&fcmd, cmd, args...) },
-			func(cmd string, args ...string) exec.Cmd { return exec.InitFakeCmd(&fcmd, cmd, args...) },
-			func(cmd string, args ...string) exec.Cmd { return exec.InitFakeCmd(&fcmd, cmd, args...) },
-		},
-	}
-	dir := os.TempDir() + "/git"
-	g := GitDir{
-		Source:   "https://github.com/GoogleCloudPlatform/kubernetes.git",
-		Revision: "2a30ce65c5ab586b98916d83385c5983edd353a1",
-		PodID:    "foo",
-		RootDir:  dir,
-		Name:     "test-pod",
-		exec:     &fake,
-	}
-	err := g.SetUp()
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-	expectedCmds := [][]string{
-		{"git", "clone", g.Source},
-		{"git", "checkout", g.Revision},
-		{"git", "reset", "--hard"},
-	}
-	if fake.CommandCalls != len(expectedCmds) {
-		t.Errorf("unexpected command calls: expected 3, saw: %d", fake.CommandCalls)
-	}
-	if !reflect.DeepEqual(expectedCmds, fcmd.CombinedOutputLog) {
-		t.Errorf("unexpected commands: %v, expected: %v", fcmd.CombinedOutputLog, expectedCmds)
-	}
-	expectedDirs := []string{g.GetPath(), g.GetPath() + "/kubernetes", g.GetPath() + "/kubernetes"}
-	if len(fcmd.Dirs) != 3 || !reflect.DeepEqual(expectedDirs, fcmd.Dirs) {
-		t.Errorf("unexpected directories: %v, expected: %v", fcmd.Dirs, expectedDirs)
-	}
-	err = g.TearDown()
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-}
@@ -0,0 +1,71 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package union
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authenticator"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/user"
+)
+
+func authFunc(info user.Info, ok bool, err error) authenticator.Request {
+	return authenticator.RequestFunc(func(req *http.Request) (user.Info, bool, error) {
+		return info, ok, err
+	})
+}
+
+func TestAuthenticateRequestFirstMatch(t *testing.T) {
+	auth := New(
+		authFunc(nil, false, nil),
+		authFunc(&user.DefaultInfo{Name: "second"}, true, nil),
+		authFunc(&user.DefaultInfo{Name: "third"}, true, nil),
+	)
+	info, ok, err := auth.AuthenticateRequest(&http.Request{})
+	if !ok || err != nil {
+		t.Fatalf("expected authenticated user, got %v %v", ok, err)
+	}
+	if info.GetName() != "second" {
+		t.Errorf("expected the first match, got %s", info.GetName())
+	}
+}
+
+func TestAuthenticateRequestErrorIgnoredOnMatch(t *testing.T) {
+	auth := New(
+		authFunc(nil, false, errors.New("bad token")),
+		authFunc(&user.DefaultInfo{Name: "user"}, true, nil),
+	)
+	info, ok, err := auth.AuthenticateRequest(&http.Request{})
+	if !ok || err != nil || info.GetName() != "user" {
+		t.Errorf("expected authenticated user, got %v %v %v", info, ok, err)
+	}
+}
+
+func TestAuthenticateRequestNoMatch(t *testing.T) {
+	auth := New(authFunc(nil, false, nil), authFunc(nil, false, nil))
+	info, ok, err := auth.AuthenticateRequest(&http.Request{})
+	if ok || info != nil || err != nil {
+		t.Errorf("expected not authenticated user, got %v %v %v", info, ok, err)
+	}
+
+	auth = New(authFunc(nil, false, nil), authFunc(nil, false, errors.New("bad token")))
+	if _, ok, err := auth.AuthenticateRequest(&http.Request{}); ok || err == nil {
+		t.Errorf("expected error, got %v %v", ok, err)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package x509
+
+import (
+	"crypto/x509"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/user"
+)
+
+// Authenticator authenticates requests using the client certificate presented over TLS
+type Authenticator struct {
+	opts x509.VerifyOptions
+}
+
+// New returns a request authenticator that validates client certificates using the provided verify options.
+// The common name of the certificate subject is the name of the user.
+func New(opts x509.VerifyOptions) *Authenticator {
+	return &Authenticator{opts}
+}
+
+// AuthenticateRequest authenticates the request using the first certificate of the TLS connection,
+// verified against the roots of the authenticator for client authentication
+func (a *Authenticator) AuthenticateRequest(req *http.Request) (user.Info, bool, error) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil, false, nil
+	}
+
+	// Use intermediates, if provided
+	opts := a.opts
+	if opts.Intermediates == nil && len(req.TLS.PeerCertificates) > 1 {
+		opts.Intermediates = x509.NewCertPool()
+		for _, intermediate := range req.TLS.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(intermediate)
+		}
+	}
+	if len(opts.KeyUsages) == 0 {
+		opts.KeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+
+	chains, err := req.TLS.PeerCertificates[0].Verify(opts)
+	if err != nil {
+		return nil, false, err
+	}
+	name := chains[0][0].Subject.CommonName
+	if len(name) == 0 {
+		return nil, false, nil
+	}
+	return &user.DefaultInfo{Name: name}, true, nil
+}
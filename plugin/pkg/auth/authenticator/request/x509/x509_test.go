@@ -0,0 +1,119 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package x509
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newCert signs a certificate for commonName with the parent, or self-signs it when parent is nil.
+func newCert(t *testing.T, commonName string, isCA bool, usages []x509.ExtKeyUsage, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           usages,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return cert, key
+}
+
+func requestWithCerts(certs ...*x509.Certificate) *http.Request {
+	return &http.Request{TLS: &tls.ConnectionState{PeerCertificates: certs}}
+}
+
+func TestAuthenticateRequest(t *testing.T) {
+	ca, caKey := newCert(t, "ca", true, nil, nil, nil)
+	otherCA, otherCAKey := newCert(t, "other", true, nil, nil, nil)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	auth := New(x509.VerifyOptions{Roots: roots})
+
+	client, _ := newCert(t, "kubelet-client", false, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, ca, caKey)
+	serving, _ := newCert(t, "server", false, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, ca, caKey)
+	noName, _ := newCert(t, "", false, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, ca, caKey)
+	untrusted, _ := newCert(t, "intruder", false, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, otherCA, otherCAKey)
+
+	tests := []struct {
+		req       *http.Request
+		user      string
+		expectOK  bool
+		expectErr bool
+	}{
+		{req: &http.Request{}},
+		{req: requestWithCerts()},
+		{req: requestWithCerts(client), user: "kubelet-client", expectOK: true},
+		{req: requestWithCerts(serving), expectErr: true},
+		{req: requestWithCerts(noName)},
+		{req: requestWithCerts(untrusted), expectErr: true},
+	}
+	for i, test := range tests {
+		info, ok, err := auth.AuthenticateRequest(test.req)
+		if ok != test.expectOK {
+			t.Errorf("%d: expected ok %v, got %v", i, test.expectOK, ok)
+		}
+		if (err != nil) != test.expectErr {
+			t.Errorf("%d: expected error %v, got %v", i, test.expectErr, err)
+		}
+		if ok && info.GetName() != test.user {
+			t.Errorf("%d: expected user %s, got %s", i, test.user, info.GetName())
+		}
+	}
+}
+
+func TestAuthenticateRequestIntermediates(t *testing.T) {
+	ca, caKey := newCert(t, "ca", true, nil, nil, nil)
+	intermediate, intermediateKey := newCert(t, "intermediate", true, nil, ca, caKey)
+	client, _ := newCert(t, "kubelet-client", false, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, intermediate, intermediateKey)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	auth := New(x509.VerifyOptions{Roots: roots})
+
+	if _, _, err := auth.AuthenticateRequest(requestWithCerts(client)); err == nil {
+		t.Errorf("expected error without the intermediate")
+	}
+	info, ok, err := auth.AuthenticateRequest(requestWithCerts(client, intermediate))
+	if !ok || err != nil || info.GetName() != "kubelet-client" {
+		t.Errorf("expected authenticated user, got %v %v %v", info, ok, err)
+	}
+}
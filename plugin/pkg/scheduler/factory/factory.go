@@ -78,6 +78,8 @@ func (factory *ConfigFactory) Create() *scheduler.Config {
 			algorithm.NewResourceFitPredicate(minionLister),
 			// Fit is determined by non-conflicting disk volumes
 			algorithm.NoDiskConflict,
+			// Fit is determined by the memory and disk pressure of the node
+			algorithm.NewNodePressurePredicate(minionLister),
 		},
 		// Prioritize nodes by least requested utilization.
 		algorithm.LeastRequestedPriority,